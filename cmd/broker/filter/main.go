@@ -157,6 +157,9 @@ func main() {
 	if err != nil {
 		logger.Fatal("Error creating Handler", zap.Error(err))
 	}
+	circuitBreakerRecorder := filter.NewCircuitBreakerRecorder(logger, kubeClient, system.Namespace(), env.PodName)
+	handler.CircuitBreakerRecorder = circuitBreakerRecorder
+	go circuitBreakerRecorder.Run(ctx.Done())
 	serverManager, err := filter.NewServerManager(ctx, logger, configMapWatcher, env.HTTPPort, env.HTTPSPort, handler)
 	if err != nil {
 		logger.Fatal("Error creating server manager", zap.Error(err))
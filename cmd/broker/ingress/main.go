@@ -29,6 +29,7 @@ import (
 	"go.uber.org/zap"
 	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/filtered"
 
+	"knative.dev/pkg/apis"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	configmap "knative.dev/pkg/configmap/informer"
@@ -77,6 +78,11 @@ type envConfig struct {
 	MaxTTL        int    `envconfig:"MAX_TTL" default:"255"`
 	HTTPPort      int    `envconfig:"INGRESS_PORT" default:"8080"`
 	HTTPSPort     int    `envconfig:"INGRESS_PORT_HTTPS" default:"8443"`
+	// SchedulingDir is where the events scheduled for a later delivery are stored.
+	SchedulingDir string `envconfig:"SCHEDULING_DIR"`
+	// SchedulerURL is the ingress the events scheduled for a later delivery are forwarded to,
+	// when they aren't stored in the SchedulingDir.
+	SchedulerURL string `envconfig:"SCHEDULER_URL"`
 }
 
 func main() {
@@ -174,6 +180,11 @@ func main() {
 		logger.Fatal("Error creating Handler", zap.Error(err))
 	}
 
+	handler.EventTypeValidator, err = eventtype.NewValidator(eventtypeinformer.Get(ctx))
+	if err != nil {
+		logger.Fatal("Error creating EventType validator", zap.Error(err))
+	}
+
 	serverManager, err := ingress.NewServerManager(ctx, logger, configMapWatcher, env.HTTPPort, env.HTTPSPort, handler)
 	if err != nil {
 		logger.Fatal("Error creating server manager", zap.Error(err))
@@ -201,6 +212,18 @@ func main() {
 		logger.Fatal("Failed to start informers", zap.Error(err))
 	}
 
+	// The scheduled events are sent once the Brokers are known.
+	if env.SchedulingDir != "" {
+		if err := handler.StartScheduler(ctx, env.SchedulingDir); err != nil {
+			logger.Fatal("Failed to start the scheduler", zap.Error(err))
+		}
+	} else if env.SchedulerURL != "" {
+		handler.SchedulerURL, err = apis.ParseURL(env.SchedulerURL)
+		if err != nil {
+			logger.Fatal("Invalid scheduler URL", zap.String("url", env.SchedulerURL), zap.Error(err))
+		}
+	}
+
 	// Start the servers
 	logger.Info("Ingress starting...")
 	err = serverManager.StartServers(ctx)
@@ -36,7 +36,9 @@ import (
 	"knative.dev/eventing/pkg/eventingtls"
 	"knative.dev/eventing/pkg/reconciler/eventpolicy"
 	"knative.dev/eventing/pkg/reconciler/jobsink"
+	"knative.dev/eventing/pkg/reconciler/replaysink"
 
+	"knative.dev/eventing/pkg/reconciler/aggregator"
 	"knative.dev/eventing/pkg/reconciler/apiserversource"
 	"knative.dev/eventing/pkg/reconciler/channel"
 	"knative.dev/eventing/pkg/reconciler/containersource"
@@ -109,6 +111,8 @@ func main() {
 
 		// Sinks
 		jobsink.NewController,
+		replaysink.NewController,
+		aggregator.NewController,
 
 		// Sugar
 		sugarnamespace.NewController,
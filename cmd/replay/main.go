@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	configmap "knative.dev/pkg/configmap/informer"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracing"
+	tracingconfig "knative.dev/pkg/tracing/config"
+	"knative.dev/pkg/tracker"
+
+	cmdbroker "knative.dev/eventing/cmd/broker"
+	"knative.dev/eventing/pkg/apis/feature"
+	"knative.dev/eventing/pkg/auth"
+	"knative.dev/eventing/pkg/client/injection/informers/sinks/v1alpha1/replaysink"
+	"knative.dev/eventing/pkg/eventingtls"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/replay"
+)
+
+const (
+	component = "replay"
+
+	// defaultDataDir is where the events are stored, when the DATA_DIR env variable isn't set.
+	defaultDataDir = "/var/lib/knative/replay"
+
+	// compactionPeriod is how often the events older than the retention of their ReplaySink
+	// are dropped.
+	compactionPeriod = time.Minute
+)
+
+func main() {
+
+	ctx := signals.NewContext()
+
+	cfg := injection.ParseAndGetRESTConfigOrDie()
+	ctx = injection.WithConfig(ctx, cfg)
+
+	ctx, informers := injection.Default.SetupInformers(ctx, cfg)
+	loggingConfig, err := cmdbroker.GetLoggingConfig(ctx, system.Namespace(), logging.ConfigMapName())
+	if err != nil {
+		log.Fatal("Error loading/parsing logging configuration:", err)
+	}
+	sl, atomicLevel := logging.NewLoggerFromConfig(loggingConfig, component)
+	logger := sl.Desugar()
+	defer flush(sl)
+	ctx = logging.WithLogger(ctx, sl)
+
+	// Watch the logging config map and dynamically update logging levels.
+	configMapWatcher := configmap.NewInformedWatcher(kubeclient.Get(ctx), system.Namespace())
+	// Watch the observability config map and dynamically update metrics exporter.
+	updateFunc, err := metrics.UpdateExporterFromConfigMapWithOpts(ctx, metrics.ExporterOptions{
+		Component:      component,
+		PrometheusPort: 9092,
+	}, sl)
+	if err != nil {
+		logger.Fatal("Failed to create metrics exporter update function", zap.Error(err))
+	}
+	configMapWatcher.Watch(metrics.ConfigMapName(), updateFunc)
+	// Watch the observability config map and dynamically update request logs.
+	configMapWatcher.Watch(logging.ConfigMapName(), logging.UpdateLevelFromConfigMap(sl, atomicLevel, component))
+
+	bin := fmt.Sprintf("%s.%s", component, system.Namespace())
+
+	tracer, err := tracing.SetupPublishingWithDynamicConfig(sl, configMapWatcher, bin, tracingconfig.ConfigName)
+	if err != nil {
+		logger.Fatal("Error setting up trace publishing", zap.Error(err))
+	}
+
+	logger.Info("Starting the Replay component")
+
+	featureStore := feature.NewStore(logging.FromContext(ctx).Named("feature-config-store"), func(name string, value interface{}) {
+		logger.Info("Updated", zap.String("name", name), zap.Any("value", value))
+	})
+	featureStore.WatchConfigs(configMapWatcher)
+
+	// Decorate contexts with the current state of the feature config.
+	ctxFunc := func(ctx context.Context) context.Context {
+		return logging.WithLogger(featureStore.ToContext(ctx), sl)
+	}
+
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = defaultDataDir
+	}
+	store, err := replay.NewStore(dataDir)
+	if err != nil {
+		logger.Fatal("Failed to load the stored events", zap.String("dir", dataDir), zap.Error(err))
+	}
+	defer store.Close()
+
+	h := replay.NewHandler(
+		store,
+		replaysink.Get(ctx).Lister(),
+		kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx)),
+		kubeclient.Get(ctx),
+		resolver.NewURIResolverFromTracker(ctx, tracker.New(func(types.NamespacedName) {}, controller.GetTrackerLease(ctx))),
+		auth.NewOIDCTokenVerifier(ctx),
+		ctxFunc,
+	)
+
+	// configMapWatcher does not block, so start it first.
+	logger.Info("Starting ConfigMap watcher")
+	if err = configMapWatcher.Start(ctx.Done()); err != nil {
+		logger.Fatal("Failed to start ConfigMap watcher", zap.Error(err))
+	}
+
+	// Start informers and wait for them to sync.
+	logger.Info("Starting informers.")
+	if err := controller.StartInformers(ctx.Done(), informers...); err != nil {
+		logger.Fatal("Failed to start informers", zap.Error(err))
+	}
+
+	go func() {
+		ticker := time.NewTicker(compactionPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.Compact(ctx)
+			}
+		}
+	}()
+
+	// Start the server
+	logger.Info("Starting...")
+	if err = kncloudevents.NewHTTPEventReceiver(8080).StartListen(ctx, h); err != nil {
+		logger.Fatal("StartListen() returned an error", zap.Error(err))
+	}
+	tracer.Shutdown(context.Background())
+	logger.Info("Exiting...")
+}
+
+func flush(logger *zap.SugaredLogger) {
+	_ = logger.Sync()
+	metrics.FlushExporter()
+}
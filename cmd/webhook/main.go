@@ -99,7 +99,9 @@ var ourTypes = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 
 	// For group sinks.knative.dev.
 	// v1alpha1
-	sinksv1alpha1.SchemeGroupVersion.WithKind("JobSink"): &sinksv1alpha1.JobSink{},
+	sinksv1alpha1.SchemeGroupVersion.WithKind("JobSink"):    &sinksv1alpha1.JobSink{},
+	sinksv1alpha1.SchemeGroupVersion.WithKind("ReplaySink"): &sinksv1alpha1.ReplaySink{},
+	sinksv1alpha1.SchemeGroupVersion.WithKind("Aggregator"): &sinksv1alpha1.Aggregator{},
 
 	// For group flows.knative.dev
 	// v1
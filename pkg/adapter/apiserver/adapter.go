@@ -30,11 +30,11 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"knative.dev/eventing/pkg/adapter/v2"
 	v1 "knative.dev/eventing/pkg/apis/sources/v1"
-	brokerfilter "knative.dev/eventing/pkg/broker/filter"
 	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
 )
 
@@ -53,8 +53,11 @@ type apiServerAdapter struct {
 
 	discover discovery.DiscoveryInterface
 	k8s      dynamic.Interface
-	source   string // TODO: who dis?
-	name     string // TODO: who dis?
+	// kube and namespace are used to persist the watch state in the namespace of the source.
+	kube      kubernetes.Interface
+	namespace string
+	source    string // TODO: who dis?
+	name      string // TODO: who dis?
 }
 
 func (a *apiServerAdapter) Start(ctx context.Context) error {
@@ -67,23 +70,50 @@ func (a *apiServerAdapter) start(ctx context.Context, stopCh <-chan struct{}) er
 
 	resyncPeriod := 10 * time.Hour
 
-	var delegate cache.Store = &resourceDelegate{
-		ce:                  a.ce,
-		source:              a.source,
-		logger:              a.logger,
-		ref:                 a.config.EventMode == v1.ReferenceMode,
-		apiServerSourceName: a.name,
-		filter:              subscriptionsapi.NewAllFilter(brokerfilter.MaterializeFiltersList(a.logger.Desugar(), a.config.Filters)...),
+	filter := subscriptionsapi.NewAllFilter(subscriptionsapi.MaterializeFiltersList(a.logger.Desugar(), a.config.Filters)...)
+	var diff *v1.ApiServerSourceDiff
+	if a.config.EventMode == v1.DiffMode {
+		diff = &v1.ApiServerSourceDiff{}
+		if a.config.Diff != nil {
+			diff = a.config.Diff
+		}
+	}
+	// The delegates of a watch share the objects seen, to diff the updates against them.
+	newDelegate := func(objects cache.Store, extensions map[string]string) cache.Store {
+		var delegate cache.Store = &resourceDelegate{
+			ce:                  a.ce,
+			source:              a.source,
+			logger:              a.logger,
+			ref:                 a.config.EventMode == v1.ReferenceMode,
+			apiServerSourceName: a.name,
+			filter:              filter,
+			extensions:          extensions,
+			diff:                diff,
+			objects:             objects,
+		}
+		if a.config.ResourceOwner != nil {
+			delegate = &controllerFilter{
+				apiVersion: a.config.ResourceOwner.APIVersion,
+				kind:       a.config.ResourceOwner.Kind,
+				delegate:   delegate,
+			}
+		}
+		return delegate
 	}
 	if a.config.ResourceOwner != nil {
 		a.logger.Infow("will be filtered",
 			zap.String("APIVersion", a.config.ResourceOwner.APIVersion),
 			zap.String("Kind", a.config.ResourceOwner.Kind))
-		delegate = &controllerFilter{
-			apiVersion: a.config.ResourceOwner.APIVersion,
-			kind:       a.config.ResourceOwner.Kind,
-			delegate:   delegate,
+	}
+
+	var state *watchState
+	if a.config.WatchStateConfigMap != "" {
+		var err error
+		state, err = loadWatchState(ctx, a.kube.CoreV1().ConfigMaps(a.namespace), a.config.WatchStateConfigMap, a.logger)
+		if err != nil {
+			return fmt.Errorf("failed to load the watch state: %w", err)
 		}
+		go state.run(stop)
 	}
 
 	a.logger.Infof("STARTING -- %#v", a.config)
@@ -98,21 +128,37 @@ func (a *apiServerAdapter) start(ctx context.Context, stopCh <-chan struct{}) er
 		exists := false
 		for _, apires := range resources.APIResources {
 			if apires.Name == configRes.GVR.Resource {
-				var resources []dynamic.ResourceInterface
+				resources := make(map[string]dynamic.ResourceInterface)
 				if apires.Namespaced && !a.config.AllNamespaces {
 					for _, ns := range a.config.Namespaces {
-						resources = append(resources, a.k8s.Resource(configRes.GVR).Namespace(ns))
+						resources[watchStateKey(configRes.GVR, ns)] = a.k8s.Resource(configRes.GVR).Namespace(ns)
 					}
 				} else {
-					resources = append(resources, a.k8s.Resource(configRes.GVR))
+					resources[watchStateKey(configRes.GVR, "")] = a.k8s.Resource(configRes.GVR)
 				}
 
-				for _, res := range resources {
+				for key, res := range resources {
 					lw := &cache.ListWatch{
 						ListFunc:  asUnstructuredLister(ctx, res.List, configRes.LabelSelector),
 						WatchFunc: asUnstructuredWatcher(ctx, res.Watch, configRes.LabelSelector),
 					}
 
+					objects := cache.NewStore(cache.MetaNamespaceKeyFunc)
+					delegate := newDelegate(objects, nil)
+
+					if state != nil {
+						w := &resumableWatch{
+							key:      key,
+							lw:       lw,
+							state:    state,
+							delegate: delegate,
+							relisted: newDelegate(objects, map[string]string{relistExtension: "true"}),
+							logger:   a.logger,
+						}
+						go w.run(stop)
+						continue
+					}
+
 					reflector := cache.NewReflector(lw, &unstructured.Unstructured{}, delegate, resyncPeriod)
 					go reflector.Run(stop)
 				}
@@ -139,8 +185,12 @@ func (a *apiServerAdapter) start(ctx context.Context, stopCh <-chan struct{}) er
 	go srv.ListenAndServe()
 
 	<-stopCh
-	stop <- struct{}{}
+	close(stop)
 	srv.Shutdown(ctx)
+	if state != nil {
+		// Persist the latest resource versions, as the context is done.
+		state.flush(context.Background())
+	}
 	return nil
 }
 
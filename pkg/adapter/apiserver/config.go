@@ -55,10 +55,16 @@ type Config struct {
 	// EventMode controls the format of the event.
 	// `Reference` sends a dataref event type for the resource under watch.
 	// `Resource` send the full resource lifecycle event.
+	// `Diff` sends the full resource for additions and deletions, and the patch
+	// from the old to the new resource for updates.
 	// Defaults to `Reference`
 	// +optional
 	EventMode string `json:"mode,omitempty"`
 
+	// Diff configures the patches sent for updates in `Diff` mode.
+	// +optional
+	Diff *v1.ApiServerSourceDiff `json:"diff,omitempty"`
+
 	// Filters is an experimental field that conforms to the CNCF CloudEvents Subscriptions
 	// API. It's an array of filter expressions that evaluate to true or false.
 	// If any filter expression in the array evaluates to false, the event MUST
@@ -68,4 +74,10 @@ type Config struct {
 	//
 	// +optional
 	Filters []eventingv1.SubscriptionsAPIFilter `json:"filters,omitempty"`
+
+	// WatchStateConfigMap is the name of the ConfigMap, in the namespace of the source, where
+	// the last resource version seen for each watch is persisted, so that the watches resume
+	// from it after a restart.
+	// +optional
+	WatchStateConfigMap string `json:"watchStateConfigMap,omitempty"`
 }
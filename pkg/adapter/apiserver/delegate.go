@@ -22,9 +22,13 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/eventing/pkg/adapter/apiserver/events"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
 	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
 )
 
 type resourceDelegate struct {
@@ -34,24 +38,69 @@ type resourceDelegate struct {
 	apiServerSourceName string
 	filter              eventfilter.Filter
 
+	// extensions are set on the events, e.g. to flag the events of a relist.
+	extensions map[string]string
+
+	// diff, when set, sends the patches from the old to the new objects for the updates.
+	diff *v1.ApiServerSourceDiff
+	// objects holds the last objects seen in diff mode, to diff the updates against them.
+	objects cache.Store
+
 	logger *zap.SugaredLogger
 }
 
 var _ cache.Store = (*resourceDelegate)(nil)
 
 func (a *resourceDelegate) Add(obj interface{}) error {
+	if a.diff != nil {
+		_ = a.objects.Add(obj)
+	}
 	return a.handleKubernetesObject(events.MakeAddEvent, obj)
 }
 
 func (a *resourceDelegate) Update(obj interface{}) error {
+	if a.diff != nil {
+		return a.handleDiff(obj)
+	}
 	return a.handleKubernetesObject(events.MakeUpdateEvent, obj)
 }
 
 func (a *resourceDelegate) Delete(obj interface{}) error {
+	if a.diff != nil {
+		_ = a.objects.Delete(obj)
+	}
 	return a.handleKubernetesObject(events.MakeDeleteEvent, obj)
 
 }
 
+// handleDiff sends the patch from the last object seen to the updated one.
+func (a *resourceDelegate) handleDiff(obj interface{}) error {
+	old, _, _ := a.objects.Get(obj)
+	_ = a.objects.Update(obj)
+
+	if a.diff.IgnoreStatusChanges && old != nil && onlyStatusChanged(old.(*unstructured.Unstructured), obj.(*unstructured.Unstructured)) {
+		a.logger.Debug("ignoring a status change")
+		return nil
+	}
+
+	return a.handleKubernetesObject(func(source, apiServerSourceName string, obj interface{}, _ bool) (context.Context, cloudevents.Event, error) {
+		return events.MakeDiffEvent(source, apiServerSourceName, old, obj, a.diff.Format)
+	}, obj)
+}
+
+// onlyStatusChanged returns whether the objects only differ by their status, managed fields
+// or resource version.
+func onlyStatusChanged(old, obj *unstructured.Unstructured) bool {
+	strip := func(u *unstructured.Unstructured) map[string]interface{} {
+		u = u.DeepCopy()
+		unstructured.RemoveNestedField(u.Object, "status")
+		unstructured.RemoveNestedField(u.Object, "metadata", "managedFields")
+		unstructured.RemoveNestedField(u.Object, "metadata", "resourceVersion")
+		return u.Object
+	}
+	return equality.Semantic.DeepEqual(strip(old), strip(obj))
+}
+
 // makeEventFunc represents the signature of the functions `events.Make*Event` so they can
 // be passed as a parameter
 type makeEventFunc func(string, string, interface{}, bool) (context.Context, cloudevents.Event, error)
@@ -63,8 +112,11 @@ func (a *resourceDelegate) handleKubernetesObject(makeEvent makeEventFunc, obj i
 		a.logger.Infow("event creation failed", zap.Error(err))
 		return err
 	}
+	for name, value := range a.extensions {
+		event.SetExtension(name, value)
+	}
 
-	filterResult := a.filter.Filter(ctx, event)
+	filterResult := a.filter.Filter(subscriptionsapi.WithParsedPayload(ctx, event), event)
 	if filterResult == eventfilter.FailFilter {
 		a.logger.Debugf("event type %s filtered out", event.Type())
 		return nil
@@ -113,7 +165,11 @@ func (a *resourceDelegate) GetByKey(key string) (item interface{}, exists bool,
 }
 
 // Implements cache.Store
-func (a *resourceDelegate) Replace([]interface{}, string) error {
+func (a *resourceDelegate) Replace(list []interface{}, resourceVersion string) error {
+	if a.diff != nil {
+		// The listed objects are the base of the next diffs.
+		return a.objects.Replace(list, resourceVersion)
+	}
 	return nil
 }
 
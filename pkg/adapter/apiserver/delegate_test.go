@@ -19,10 +19,12 @@ import (
 	"testing"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
 	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	"knative.dev/eventing/pkg/apis/sources"
-	brokerfilter "knative.dev/eventing/pkg/broker/filter"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
 	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
 )
 
@@ -87,7 +89,7 @@ func TestFilterFails(t *testing.T) {
 		source:              "unit-test",
 		apiServerSourceName: apiServerSourceNameTest,
 		logger:              logger,
-		filter:              subscriptionsapi.NewAllFilter(brokerfilter.MaterializeFiltersList(logger.Desugar(), filters)...),
+		filter:              subscriptionsapi.NewAllFilter(subscriptionsapi.MaterializeFiltersList(logger.Desugar(), filters)...),
 	}
 
 	delegate.Update(simplePod("unit", "test"))
@@ -104,9 +106,101 @@ func TestEmptyFiltersList(t *testing.T) {
 		source:              "unit-test",
 		apiServerSourceName: apiServerSourceNameTest,
 		logger:              logger,
-		filter:              subscriptionsapi.NewAllFilter(brokerfilter.MaterializeFiltersList(logger.Desugar(), filters)...),
+		filter:              subscriptionsapi.NewAllFilter(subscriptionsapi.MaterializeFiltersList(logger.Desugar(), filters)...),
 	}
 
 	delegate.Update(simplePod("unit", "test"))
 	validateSent(t, ce, sources.ApiServerSourceUpdateEventType)
 }
+
+func makeDiffAndTestingClient(diff *v1.ApiServerSourceDiff) (*resourceDelegate, *adaptertest.TestCloudEventsClient) {
+	d, ce := makeResourceAndTestingClient()
+	d.diff = diff
+	d.objects = cache.NewStore(cache.MetaNamespaceKeyFunc)
+	return d, ce
+}
+
+func TestDiffUpdateEvent(t *testing.T) {
+	d, ce := makeDiffAndTestingClient(&v1.ApiServerSourceDiff{Format: v1.MergePatchDiffFormat})
+
+	old := simplePod("unit", "test")
+	if err := d.Replace([]interface{}{old}, "1"); err != nil {
+		t.Fatal("Replace() =", err)
+	}
+	pod := old.DeepCopy()
+	pod.SetLabels(map[string]string{"app": "unit"})
+	d.Update(pod)
+
+	validateSent(t, ce, sources.ApiServerSourceUpdateDiffEventType)
+	want := `{"metadata":{"labels":{"app":"unit"}}}`
+	if got := string(ce.Sent()[0].Data()); got != want {
+		t.Errorf("Expected the patch %s, got %s", want, got)
+	}
+
+	if got, _, _ := d.objects.Get(pod); got != pod {
+		t.Error("Expected the updated object to be the base of the next diff")
+	}
+}
+
+func TestDiffAddAndDeleteEvents(t *testing.T) {
+	d, ce := makeDiffAndTestingClient(&v1.ApiServerSourceDiff{})
+
+	pod := simplePod("unit", "test")
+	d.Add(pod)
+	if _, exists, _ := d.objects.Get(pod); !exists {
+		t.Error("Expected the added object to be kept")
+	}
+	d.Delete(pod)
+	if _, exists, _ := d.objects.Get(pod); exists {
+		t.Error("Expected the deleted object to be forgotten")
+	}
+
+	sent := ce.Sent()
+	if len(sent) != 2 || sent[0].Type() != sources.ApiServerSourceAddEventType || sent[1].Type() != sources.ApiServerSourceDeleteEventType {
+		t.Errorf("Expected an add and a delete event, got %v", sent)
+	}
+}
+
+func TestDiffIgnoreStatusChanges(t *testing.T) {
+	withStatus := func(pod *unstructured.Unstructured, phase string) *unstructured.Unstructured {
+		pod = pod.DeepCopy()
+		unstructured.SetNestedField(pod.Object, phase, "status", "phase")
+		pod.SetResourceVersion(phase)
+		return pod
+	}
+	old := withStatus(simplePod("unit", "test"), "Pending")
+	statusChange := withStatus(old, "Running")
+	specChange := withStatus(old, "Running")
+	specChange.SetLabels(map[string]string{"app": "unit"})
+
+	for _, tc := range []struct {
+		name   string
+		ignore bool
+		obj    *unstructured.Unstructured
+		sent   bool
+	}{{
+		name:   "status change ignored",
+		ignore: true,
+		obj:    statusChange,
+	}, {
+		name:   "status change sent",
+		ignore: false,
+		obj:    statusChange,
+		sent:   true,
+	}, {
+		name:   "spec change sent",
+		ignore: true,
+		obj:    specChange,
+		sent:   true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ce := makeDiffAndTestingClient(&v1.ApiServerSourceDiff{IgnoreStatusChanges: tc.ignore})
+			d.Add(old)
+			d.Update(tc.obj)
+
+			if got := len(ce.Sent()) == 2; got != tc.sent {
+				t.Errorf("Expected the update to be sent: %v, got %v", tc.sent, got)
+			}
+		})
+	}
+}
@@ -18,23 +18,29 @@ package events
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	ceobs "github.com/cloudevents/sdk-go/v2/observability"
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kncloudevents "knative.dev/eventing/pkg/adapter/v2"
 	sources "knative.dev/eventing/pkg/apis/sources"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
 	"knative.dev/eventing/pkg/observability"
 )
 
 const (
 	resourceGroup = "apiserversources.sources.knative.dev"
+
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
 )
 
 // MakeAddEvent returns a cloudevent when a k8s api event is created.
@@ -54,7 +60,7 @@ func MakeAddEvent(source string, apiServerSourceName string, obj interface{}, re
 		eventType = sources.ApiServerSourceAddEventType
 	}
 
-	return makeEvent(source, apiServerSourceName, eventType, object, data)
+	return makeEvent(source, apiServerSourceName, eventType, cloudevents.ApplicationJSON, object, data)
 }
 
 // MakeUpdateEvent returns a cloudevent when a k8s api event is updated.
@@ -74,7 +80,46 @@ func MakeUpdateEvent(source string, apiServerSourceName string, obj interface{},
 		eventType = sources.ApiServerSourceUpdateEventType
 	}
 
-	return makeEvent(source, apiServerSourceName, eventType, object, data)
+	return makeEvent(source, apiServerSourceName, eventType, cloudevents.ApplicationJSON, object, data)
+}
+
+// MakeDiffEvent returns a cloudevent when a k8s api event is updated, with the patch from the
+// old to the new resource as data, in the given format. The patch adds the whole resource when
+// the old one is unknown.
+func MakeDiffEvent(source string, apiServerSourceName string, oldObj, obj interface{}, format string) (context.Context, cloudevents.Event, error) {
+	if obj == nil {
+		return nil, cloudevents.Event{}, fmt.Errorf("resource can not be nil")
+	}
+	object := obj.(*unstructured.Unstructured)
+
+	original := []byte("{}")
+	if oldObj != nil {
+		var err error
+		if original, err = json.Marshal(oldObj.(*unstructured.Unstructured)); err != nil {
+			return nil, cloudevents.Event{}, err
+		}
+	}
+	modified, err := json.Marshal(object)
+	if err != nil {
+		return nil, cloudevents.Event{}, err
+	}
+
+	var data []byte
+	var contentType string
+	switch format {
+	case v1.JSONPatchDiffFormat:
+		if data, err = createJSONPatch(original, modified); err != nil {
+			return nil, cloudevents.Event{}, err
+		}
+		contentType = jsonPatchContentType
+	default:
+		if data, err = jsonpatch.CreateMergePatch(original, modified); err != nil {
+			return nil, cloudevents.Event{}, err
+		}
+		contentType = mergePatchContentType
+	}
+
+	return makeEvent(source, apiServerSourceName, sources.ApiServerSourceUpdateDiffEventType, contentType, object, data)
 }
 
 // MakeDeleteEvent returns a cloudevent when a k8s api event is deleted.
@@ -94,7 +139,7 @@ func MakeDeleteEvent(source string, apiServerSourceName string, obj interface{},
 		eventType = sources.ApiServerSourceDeleteEventType
 	}
 
-	return makeEvent(source, apiServerSourceName, eventType, object, data)
+	return makeEvent(source, apiServerSourceName, eventType, cloudevents.ApplicationJSON, object, data)
 }
 
 func getRef(object *unstructured.Unstructured) corev1.ObjectReference {
@@ -106,7 +151,7 @@ func getRef(object *unstructured.Unstructured) corev1.ObjectReference {
 	}
 }
 
-func makeEvent(source, apiServerSourceName, eventType string, obj *unstructured.Unstructured, data interface{}) (context.Context, cloudevents.Event, error) {
+func makeEvent(source, apiServerSourceName, eventType, contentType string, obj *unstructured.Unstructured, data interface{}) (context.Context, cloudevents.Event, error) {
 	resourceName := obj.GetName()
 	kind := obj.GetKind()
 	namespace := obj.GetNamespace()
@@ -126,7 +171,7 @@ func makeEvent(source, apiServerSourceName, eventType string, obj *unstructured.
 	event.SetExtension("apiversion", obj.GetAPIVersion())
 	event.SetExtension("name", resourceName)
 	event.SetExtension("namespace", namespace)
-	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+	if err := event.SetData(contentType, data); err != nil {
 		return nil, event, err
 	}
 
@@ -135,6 +135,93 @@ func TestMakeUpdateEvent(t *testing.T) {
 	}
 }
 
+func TestMakeDiffEvent(t *testing.T) {
+	labeledPod := simplePod("unit", "test")
+	labeledPod.SetLabels(map[string]string{"app": "unit"})
+	mergePatchContentType := "application/merge-patch+json"
+	jsonPatchContentType := "application/json-patch+json"
+
+	testCases := map[string]struct {
+		old    interface{}
+		obj    interface{}
+		format string
+
+		want     *cloudevents.Event
+		wantData string
+		wantErr  string
+	}{
+		"nil object": {
+			old:     simplePod("unit", "test"),
+			want:    nil,
+			wantErr: "resource can not be nil",
+		},
+		"merge patch": {
+			old:    simplePod("unit", "test"),
+			obj:    labeledPod,
+			format: "MergePatch",
+			want: &cloudevents.Event{
+				Context: cloudevents.EventContextV1{
+					Type:            "dev.knative.apiserver.diff.update",
+					Source:          *cloudevents.ParseURIRef("unit-test"),
+					Subject:         simpleSubject("unit", "test"),
+					DataContentType: &mergePatchContentType,
+					Extensions: map[string]interface{}{
+						"apiversion": "v1",
+						"kind":       "Pod",
+						"name":       "unit",
+						"namespace":  "test",
+					},
+				}.AsV1(),
+			},
+			wantData: `{"metadata":{"labels":{"app":"unit"}}}`,
+		},
+		"json patch": {
+			old:    simplePod("unit", "test"),
+			obj:    labeledPod,
+			format: "JSONPatch",
+			want: &cloudevents.Event{
+				Context: cloudevents.EventContextV1{
+					Type:            "dev.knative.apiserver.diff.update",
+					Source:          *cloudevents.ParseURIRef("unit-test"),
+					Subject:         simpleSubject("unit", "test"),
+					DataContentType: &jsonPatchContentType,
+					Extensions: map[string]interface{}{
+						"apiversion": "v1",
+						"kind":       "Pod",
+						"name":       "unit",
+						"namespace":  "test",
+					},
+				}.AsV1(),
+			},
+			wantData: `[{"op":"add","path":"/metadata/labels","value":{"app":"unit"}}]`,
+		},
+		"unknown old object": {
+			obj: simplePod("unit", "test"),
+			want: &cloudevents.Event{
+				Context: cloudevents.EventContextV1{
+					Type:            "dev.knative.apiserver.diff.update",
+					Source:          *cloudevents.ParseURIRef("unit-test"),
+					Subject:         simpleSubject("unit", "test"),
+					DataContentType: &mergePatchContentType,
+					Extensions: map[string]interface{}{
+						"apiversion": "v1",
+						"kind":       "Pod",
+						"name":       "unit",
+						"namespace":  "test",
+					},
+				}.AsV1(),
+			},
+			wantData: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"unit","namespace":"test"}}`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			_, got, err := events.MakeDiffEvent("unit-test", apiServerSourceNameTest, tc.old, tc.obj, tc.format)
+			validate(t, got, err, tc.want, tc.wantData, tc.wantErr)
+		})
+	}
+}
+
 func TestMakeDeleteEvent(t *testing.T) {
 	testCases := map[string]struct {
 		obj    interface{}
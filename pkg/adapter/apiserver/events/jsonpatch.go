@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPatchOperation is an operation of a JSON patch (RFC 6902).
+type jsonPatchOperation struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	// Value is left out of the remove operations, a JSON null is kept.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// createJSONPatch returns the JSON patch transforming the original JSON
+// document into the modified one. Objects are compared key by key and
+// arrays index by index, any other value which differs is replaced.
+func createJSONPatch(original, modified []byte) ([]byte, error) {
+	var from, to interface{}
+	if err := unmarshalJSON(original, &from); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSON(modified, &to); err != nil {
+		return nil, err
+	}
+	ops := []jsonPatchOperation{}
+	if err := diffJSON("", from, to, &ops); err != nil {
+		return nil, err
+	}
+	return json.Marshal(ops)
+}
+
+// unmarshalJSON keeps the numbers as they are written so that they are
+// compared and copied without going through a float64.
+func unmarshalJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+func diffJSON(path string, from, to interface{}, ops *[]jsonPatchOperation) error {
+	switch from := from.(type) {
+	case map[string]interface{}:
+		if to, ok := to.(map[string]interface{}); ok {
+			return diffJSONObjects(path, from, to, ops)
+		}
+	case []interface{}:
+		if to, ok := to.([]interface{}); ok {
+			return diffJSONArrays(path, from, to, ops)
+		}
+	}
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+	return appendJSONPatchOperation(ops, "replace", path, to)
+}
+
+func diffJSONObjects(path string, from, to map[string]interface{}, ops *[]jsonPatchOperation) error {
+	for _, key := range sortedKeys(from) {
+		keyPath := path + "/" + jsonPointerEscaper.Replace(key)
+		value, ok := to[key]
+		if !ok {
+			*ops = append(*ops, jsonPatchOperation{Op: "remove", Path: keyPath})
+			continue
+		}
+		if err := diffJSON(keyPath, from[key], value, ops); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(to) {
+		if _, ok := from[key]; ok {
+			continue
+		}
+		if err := appendJSONPatchOperation(ops, "add", path+"/"+jsonPointerEscaper.Replace(key), to[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diffJSONArrays(path string, from, to []interface{}, ops *[]jsonPatchOperation) error {
+	for i := 0; i < len(from) && i < len(to); i++ {
+		if err := diffJSON(path+"/"+strconv.Itoa(i), from[i], to[i], ops); err != nil {
+			return err
+		}
+	}
+	for i := len(from); i < len(to); i++ {
+		if err := appendJSONPatchOperation(ops, "add", path+"/"+strconv.Itoa(i), to[i]); err != nil {
+			return err
+		}
+	}
+	// The elements are removed from the end so that the indexes of the
+	// following operations stay valid.
+	for i := len(from) - 1; i >= len(to); i-- {
+		*ops = append(*ops, jsonPatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+	}
+	return nil
+}
+
+func appendJSONPatchOperation(ops *[]jsonPatchOperation, op, path string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	*ops = append(*ops, jsonPatchOperation{Op: op, Path: path, Value: data})
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+func TestCreateJSONPatch(t *testing.T) {
+	testCases := map[string]struct {
+		original string
+		modified string
+		want     string
+	}{
+		"no changes": {
+			original: `{"a":{"b":[1,2]}}`,
+			modified: `{"a":{"b":[1,2]}}`,
+			want:     `[]`,
+		},
+		"added, removed and replaced keys": {
+			original: `{"a":1,"b":{"c":"x","d":true}}`,
+			modified: `{"b":{"c":"y","e":null},"f":[1]}`,
+			want:     `[{"op":"remove","path":"/a"},{"op":"replace","path":"/b/c","value":"y"},{"op":"remove","path":"/b/d"},{"op":"add","path":"/b/e","value":null},{"op":"add","path":"/f","value":[1]}]`,
+		},
+		"escaped keys": {
+			original: `{"metadata":{"annotations":{}}}`,
+			modified: `{"metadata":{"annotations":{"example.com/a~b":"c"}}}`,
+			want:     `[{"op":"add","path":"/metadata/annotations/example.com~1a~0b","value":"c"}]`,
+		},
+		"grown array": {
+			original: `{"a":[{"b":1}]}`,
+			modified: `{"a":[{"b":2},{"b":3},4]}`,
+			want:     `[{"op":"replace","path":"/a/0/b","value":2},{"op":"add","path":"/a/1","value":{"b":3}},{"op":"add","path":"/a/2","value":4}]`,
+		},
+		"shrunk array": {
+			original: `{"a":[1,2,3]}`,
+			modified: `{"a":[0]}`,
+			want:     `[{"op":"replace","path":"/a/0","value":0},{"op":"remove","path":"/a/2"},{"op":"remove","path":"/a/1"}]`,
+		},
+		"changed type": {
+			original: `{"a":{"b":1}}`,
+			modified: `{"a":[1.50]}`,
+			want:     `[{"op":"replace","path":"/a","value":[1.50]}]`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := createJSONPatch([]byte(tc.original), []byte(tc.modified))
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Unexpected patch, want: %s, got: %s", tc.want, got)
+			}
+
+			patch, err := jsonpatch.DecodePatch(got)
+			if err != nil {
+				t.Fatal("Failed to decode the patch:", err)
+			}
+			patched, err := patch.Apply([]byte(tc.original))
+			if err != nil {
+				t.Fatal("Failed to apply the patch:", err)
+			}
+			if !jsonpatch.Equal(patched, []byte(tc.modified)) {
+				t.Errorf("Unexpected patched document, want: %s, got: %s", tc.modified, patched)
+			}
+		})
+	}
+}
+
+func TestCreateJSONPatchInvalidDocument(t *testing.T) {
+	if _, err := createJSONPatch([]byte(`{}`), []byte(`{`)); err == nil {
+		t.Error("Expected an error for an invalid document")
+	}
+}
@@ -87,8 +87,14 @@ func (c *controllerFilter) GetByKey(key string) (item interface{}, exists bool,
 }
 
 // Implements cache.Store
-func (c *controllerFilter) Replace([]interface{}, string) error {
-	return nil
+func (c *controllerFilter) Replace(list []interface{}, resourceVersion string) error {
+	items := make([]interface{}, 0, len(list))
+	for _, obj := range list {
+		if !c.filtered(obj) {
+			items = append(items, obj)
+		}
+	}
+	return c.delegate.Replace(items, resourceVersion)
 }
 
 // Implements cache.Store
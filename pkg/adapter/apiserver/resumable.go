@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// relistExtension flags the events sent for the objects listed again when a watch can't
+	// resume from its last resource version because it expired.
+	relistExtension = "relist"
+
+	// watchStateFlushPeriod is how often the last resource versions are persisted.
+	watchStateFlushPeriod = 10 * time.Second
+
+	// watchRetryPeriod is how long to wait before retrying a failed list or watch.
+	watchRetryPeriod = time.Second
+)
+
+// watchState holds the last resource version seen for each watch, persisted in a ConfigMap.
+type watchState struct {
+	configMaps corev1client.ConfigMapInterface
+	name       string
+	logger     *zap.SugaredLogger
+
+	mu       sync.Mutex
+	versions map[string]string
+	dirty    bool
+}
+
+func loadWatchState(ctx context.Context, configMaps corev1client.ConfigMapInterface, name string, logger *zap.SugaredLogger) (*watchState, error) {
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string, len(cm.Data))
+	maps.Copy(versions, cm.Data)
+	return &watchState{
+		configMaps: configMaps,
+		name:       name,
+		logger:     logger,
+		versions:   versions,
+	}, nil
+}
+
+// watchStateKey returns the key of the watch of the resources in the namespace, or of all of
+// them when the namespace is empty.
+func watchStateKey(gvr schema.GroupVersionResource, namespace string) string {
+	key := gvr.Resource + "." + gvr.Version + "." + gvr.Group
+	if namespace != "" {
+		key += "_" + namespace
+	}
+	return key
+}
+
+func (s *watchState) get(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[key]
+}
+
+func (s *watchState) set(key, resourceVersion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.versions[key] != resourceVersion {
+		s.versions[key] = resourceVersion
+		s.dirty = true
+	}
+}
+
+// run persists the resource versions periodically until stop is closed.
+func (s *watchState) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(watchStateFlushPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.flush(context.Background())
+		}
+	}
+}
+
+// flush persists the resource versions changed since the last flush.
+func (s *watchState) flush(ctx context.Context) {
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return
+	}
+	versions := maps.Clone(s.versions)
+	s.dirty = false
+	s.mu.Unlock()
+
+	cm, err := s.configMaps.Get(ctx, s.name, metav1.GetOptions{})
+	if err == nil {
+		cm.Data = versions
+		_, err = s.configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		s.logger.Warnw("Failed to persist the watch state", zap.String("configMap", s.name), zap.Error(err))
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+	}
+}
+
+// resumableWatch watches resources from the last resource version seen, so that only the
+// changes are sent after a restart. The resources are listed again, and sent as flagged
+// events, only when that version expired.
+type resumableWatch struct {
+	key   string
+	lw    cache.ListerWatcher
+	state *watchState
+
+	// delegate handles the watched changes, and relisted the objects listed again.
+	delegate cache.Store
+	relisted cache.Store
+
+	logger *zap.SugaredLogger
+}
+
+func (w *resumableWatch) run(stop <-chan struct{}) {
+	resourceVersion := w.state.get(w.key)
+	// The first list doesn't send any event, as the reflectors do.
+	var send cache.Store
+	for {
+		var err error
+		if resourceVersion == "" {
+			resourceVersion, err = w.list(send)
+			if err == nil {
+				send = nil
+			}
+		} else {
+			resourceVersion, err = w.watch(resourceVersion, stop)
+			if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+				w.logger.Infow("The resource version expired, listing the resources again", zap.String("watch", w.key))
+				resourceVersion, send, err = "", w.relisted, nil
+			}
+		}
+
+		if err != nil {
+			w.logger.Warnw("Failed to watch the resources", zap.String("watch", w.key), zap.Error(err))
+			select {
+			case <-stop:
+				return
+			case <-time.After(watchRetryPeriod):
+			}
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+	}
+}
+
+// list returns the resource version of the list of the resources, sending them to send when
+// set.
+func (w *resumableWatch) list(send cache.Store) (string, error) {
+	list, err := w.lw.List(metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	listMeta, err := meta.ListAccessor(list)
+	if err != nil {
+		return "", err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return "", err
+	}
+	objects := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if send != nil {
+			_ = send.Add(item)
+		}
+		objects = append(objects, item)
+	}
+	resourceVersion := listMeta.GetResourceVersion()
+	// The list replaces the objects seen by the delegate, as for the reflectors.
+	_ = w.delegate.Replace(objects, resourceVersion)
+	w.state.set(w.key, resourceVersion)
+	return resourceVersion, nil
+}
+
+// watch sends the changes of the resources after the resource version, until the watch ends,
+// and returns the last resource version seen.
+func (w *resumableWatch) watch(resourceVersion string, stop <-chan struct{}) (string, error) {
+	watcher, err := w.lw.Watch(metav1.ListOptions{
+		ResourceVersion:     resourceVersion,
+		AllowWatchBookmarks: true,
+	})
+	if err != nil {
+		return resourceVersion, err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return resourceVersion, nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+			switch event.Type {
+			case watch.Error:
+				return resourceVersion, apierrors.FromObject(event.Object)
+			case watch.Added:
+				_ = w.delegate.Add(event.Object)
+			case watch.Modified:
+				_ = w.delegate.Update(event.Object)
+			case watch.Deleted:
+				_ = w.delegate.Delete(event.Object)
+			case watch.Bookmark:
+				// Bookmarks only move the resource version forward.
+			}
+			if m, err := meta.Accessor(event.Object); err == nil && m.GetResourceVersion() != "" {
+				resourceVersion = m.GetResourceVersion()
+				w.state.set(w.key, resourceVersion)
+			}
+		}
+	}
+}
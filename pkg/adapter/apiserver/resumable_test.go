@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/logging"
+	pkgtesting "knative.dev/pkg/reconciler/testing"
+)
+
+type recordingStore struct {
+	cache.Store
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (s *recordingStore) record(op string, obj interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, op+" "+obj.(*unstructured.Unstructured).GetName())
+	return nil
+}
+
+func (s *recordingStore) Add(obj interface{}) error           { return s.record("add", obj) }
+func (s *recordingStore) Update(obj interface{}) error        { return s.record("update", obj) }
+func (s *recordingStore) Delete(obj interface{}) error        { return s.record("delete", obj) }
+func (s *recordingStore) Replace([]interface{}, string) error { return nil }
+
+func (s *recordingStore) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.events...)
+}
+
+func versionedPod(name, resourceVersion string) *unstructured.Unstructured {
+	pod := simplePod(name, "default")
+	pod.SetResourceVersion(resourceVersion)
+	return pod
+}
+
+func TestWatchStateKey(t *testing.T) {
+	for _, tc := range []struct {
+		gvr       schema.GroupVersionResource
+		namespace string
+		want      string
+	}{{
+		gvr:       schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+		namespace: "default",
+		want:      "pods.v1._default",
+	}, {
+		gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		want: "deployments.v1.apps",
+	}} {
+		if got := watchStateKey(tc.gvr, tc.namespace); got != tc.want {
+			t.Errorf("watchStateKey(%v, %q) = %q, want %q", tc.gvr, tc.namespace, got, tc.want)
+		}
+	}
+}
+
+func TestResumableWatch(t *testing.T) {
+	ctx, _ := pkgtesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+
+	const key = "pods.v1._default"
+	kube := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "watch-state", Namespace: "default"},
+		Data:       map[string]string{key: "5"},
+	})
+	configMaps := kube.CoreV1().ConfigMaps("default")
+	state, err := loadWatchState(ctx, configMaps, "watch-state", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watches := make(chan metav1.ListOptions)
+	watchers := make(chan *watch.FakeWatcher)
+	lw := &cache.ListWatch{
+		ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+			list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*versionedPod("a", "8")}}
+			list.SetResourceVersion("10")
+			return list, nil
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			w := watch.NewFake()
+			watches <- opts
+			watchers <- w
+			return w, nil
+		},
+	}
+
+	delegate, relisted := &recordingStore{}, &recordingStore{}
+	w := &resumableWatch{
+		key:      key,
+		lw:       lw,
+		state:    state,
+		delegate: delegate,
+		relisted: relisted,
+		logger:   logger,
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		w.run(stop)
+		close(done)
+	}()
+
+	// The watch resumes from the persisted resource version.
+	if opts := <-watches; opts.ResourceVersion != "5" || !opts.AllowWatchBookmarks {
+		t.Fatalf("Expected a watch from the resource version 5 with bookmarks, got %+v", opts)
+	}
+	fw := <-watchers
+	fw.Add(versionedPod("b", "6"))
+	fw.Modify(versionedPod("b", "7"))
+	bookmark := &unstructured.Unstructured{}
+	bookmark.SetResourceVersion("9")
+	fw.Action(watch.Bookmark, bookmark)
+
+	// The version expired, so that the resources are listed again.
+	fw.Error(&metav1.Status{Status: metav1.StatusFailure, Code: 410, Reason: metav1.StatusReasonExpired})
+	if opts := <-watches; opts.ResourceVersion != "10" {
+		t.Fatalf("Expected a watch from the resource version 10 of the list, got %+v", opts)
+	}
+	fw = <-watchers
+	fw.Delete(versionedPod("b", "11"))
+
+	close(stop)
+	<-done
+	state.flush(context.Background())
+
+	if got, want := delegate.recorded(), []string{"add b", "update b", "delete b"}; !slices.Equal(got, want) {
+		t.Errorf("Expected the changes %v, got %v", want, got)
+	}
+	if got, want := relisted.recorded(), []string{"add a"}; !slices.Equal(got, want) {
+		t.Errorf("Expected the relisted objects %v, got %v", want, got)
+	}
+	cm, err := configMaps.Get(ctx, "watch-state", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cm.Data[key]; got != "11" {
+		t.Errorf("Expected the resource version 11 to be persisted, got %q", got)
+	}
+}
+
+func TestResumableWatchFirstList(t *testing.T) {
+	ctx, _ := pkgtesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+
+	kube := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "watch-state", Namespace: "default"},
+	})
+	state, err := loadWatchState(ctx, kube.CoreV1().ConfigMaps("default"), "watch-state", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watches := make(chan metav1.ListOptions, 1)
+	lw := &cache.ListWatch{
+		ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+			list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*versionedPod("a", "8")}}
+			list.SetResourceVersion("10")
+			return list, nil
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			watches <- opts
+			return watch.NewFake(), nil
+		},
+	}
+
+	delegate, relisted := &recordingStore{}, &recordingStore{}
+	w := &resumableWatch{key: "pods.v1._default", lw: lw, state: state, delegate: delegate, relisted: relisted, logger: logger}
+	stop := make(chan struct{})
+	go w.run(stop)
+	defer close(stop)
+
+	select {
+	case opts := <-watches:
+		if opts.ResourceVersion != "10" {
+			t.Errorf("Expected a watch from the resource version 10 of the list, got %+v", opts)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a watch")
+	}
+	if got := append(delegate.recorded(), relisted.recorded()...); len(got) != 0 {
+		t.Errorf("Expected no events for the first list, got %v", got)
+	}
+}
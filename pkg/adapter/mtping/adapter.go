@@ -29,6 +29,7 @@ import (
 	"go.uber.org/zap"
 
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/filtered"
 	"knative.dev/pkg/logging"
 
 	"knative.dev/eventing/pkg/adapter/v2"
@@ -59,11 +60,10 @@ func NewEnvConfig() adapter.EnvConfigAccessor {
 func NewAdapter(ctx context.Context, env adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
 	logger := logging.FromContext(ctx)
 
-	opts := cron.WithParser(cron.NewParser(
-		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
-	))
+	opts := cron.WithParser(scheduleParser)
 
-	runner := NewCronJobsRunner(adapter.GetClientConfig(ctx), kubeclient.Get(ctx), logging.FromContext(ctx), opts)
+	runner := NewCronJobsRunner(adapter.GetClientConfig(ctx), kubeclient.Get(ctx),
+		configmapinformer.Get(ctx, sourcesv1.PingSourceExcludeDatesLabelSelector).Lister(), logging.FromContext(ctx), opts)
 
 	return &mtpingAdapter{
 		logger:    logger,
@@ -109,6 +109,10 @@ func (a *mtpingAdapter) Update(ctx context.Context, source *sourcesv1.PingSource
 
 	if ok {
 		a.runner.RemoveSchedule(id)
+	} else {
+		// The source wasn't scheduled since the adapter started or became leader, so that it
+		// may have missed schedules.
+		go a.runner.CatchUp(source)
 	}
 
 	id = a.runner.AddSchedule(source)
@@ -120,6 +124,7 @@ func (a *mtpingAdapter) Update(ctx context.Context, source *sourcesv1.PingSource
 
 func (a *mtpingAdapter) Remove(source *sourcesv1.PingSource) {
 	key := fmt.Sprintf("%s/%s", source.Namespace, source.Name)
+	a.runner.Forget(source)
 
 	a.entryidMu.RLock()
 	id, ok := a.entryids[key]
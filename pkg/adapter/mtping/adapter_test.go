@@ -29,6 +29,9 @@ import (
 	"github.com/robfig/cron/v3"
 
 	_ "knative.dev/pkg/client/injection/kube/client/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/filtered/fake"
+	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
+	_ "knative.dev/pkg/client/injection/kube/informers/factory/filtered/fake"
 	"knative.dev/pkg/logging"
 	rectesting "knative.dev/pkg/reconciler/testing"
 
@@ -36,7 +39,7 @@ import (
 )
 
 func TestStartStopAdapter(t *testing.T) {
-	ctx, _ := rectesting.SetupFakeContext(t)
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
 	ctx, cancel := context.WithCancel(ctx)
 	envCfg := NewEnvConfig()
 
@@ -62,7 +65,7 @@ func TestStartStopAdapter(t *testing.T) {
 }
 
 func TestUpdateRemoveAdapter(t *testing.T) {
-	ctx, _ := rectesting.SetupFakeContext(t)
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
 	adapter := mtpingAdapter{
 		logger:    logging.FromContext(ctx),
 		runner:    &testRunner{},
@@ -99,4 +102,12 @@ type testRunner struct {
 func (*testRunner) AddSchedule(*sourcesv1.PingSource) cron.EntryID {
 	return cron.EntryID(1)
 }
-func (*testRunner) RemoveSchedule(cron.EntryID) {}
+func (*testRunner) RemoveSchedule(cron.EntryID)   {}
+func (*testRunner) CatchUp(*sourcesv1.PingSource) {}
+
+func (*testRunner) Forget(*sourcesv1.PingSource) {}
+
+// setUpInformerSelector sets the label selector of the ConfigMaps watched by the adapter.
+func setUpInformerSelector(ctx context.Context) context.Context {
+	return filteredFactory.WithSelectors(ctx, sourcesv1.PingSourceExcludeDatesLabelSelector)
+}
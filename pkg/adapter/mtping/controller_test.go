@@ -51,7 +51,7 @@ func (testAdapter) RemoveAll(context.Context) {
 }
 
 func TestNew(t *testing.T) {
-	ctx, _ := SetupFakeContext(t)
+	ctx, _ := SetupFakeContext(t, setUpInformerSelector)
 
 	if c := NewController(ctx, testAdapter{}); c == nil {
 		t.Fatal("Expected NewController to return a non-nil value")
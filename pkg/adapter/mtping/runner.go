@@ -19,24 +19,36 @@ package mtping
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
+	"github.com/rickb777/date/period"
 	"github.com/robfig/cron/v3"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/system"
 
 	"knative.dev/eventing/pkg/adapter/v2"
 	kncloudevents "knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/eventing/pkg/adapter/v2/util/crstatusevent"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
 	"knative.dev/eventing/pkg/observability"
+	"knative.dev/eventing/pkg/utils"
 )
 
 type CronJobRunner interface {
@@ -44,6 +56,11 @@ type CronJobRunner interface {
 	Stop()
 	AddSchedule(source *sourcesv1.PingSource) cron.EntryID
 	RemoveSchedule(id cron.EntryID)
+	// CatchUp sends the events of the schedules missed since the last schedule recorded for
+	// the source, according to its missed schedule policy.
+	CatchUp(source *sourcesv1.PingSource)
+	// Forget drops the last schedule recorded for the source.
+	Forget(source *sourcesv1.PingSource)
 }
 
 type cronJobsRunner struct {
@@ -56,23 +73,121 @@ type cronJobsRunner struct {
 	// kubeClient for sending k8s events
 	kubeClient kubernetes.Interface
 
+	// configMapLister lists the ConfigMaps with the excluded dates of the sources
+	configMapLister corev1listers.ConfigMapLister
+
 	clientConfig kncloudevents.ClientConfig
+
+	// namespace of the checkpoints ConfigMap
+	namespace string
+
+	// lastSchedules are the last schedules recorded for the sources, by checkpoint key, so that
+	// they only move forward. The checkpoints are the recorded schedules, or nil for the
+	// forgotten sources, not written to the checkpoints ConfigMap yet.
+	lastSchedulesMu sync.Mutex
+	lastSchedules   map[string]time.Time
+	checkpoints     map[string]*string
+
+	// checkpointsMu serializes the writes of the checkpoints, so that they are applied in order.
+	checkpointsMu sync.Mutex
+
+	// sequences are the latest sequence numbers of the sources with templated data, by
+	// namespace/name, so that they are counted from there rather than from the creation of
+	// the sources.
+	sequencesMu sync.Mutex
+	sequences   map[string]sequence
+}
+
+// sequence is the number of the schedules of a source up to a time.
+type sequence struct {
+	schedule string
+	at       time.Time
+	n        int64
 }
 
 const (
 	resourceGroup = "pingsources.sources.knative.dev"
+
+	// scheduledTimeExtension is set on the events of the missed schedules to the time they were
+	// scheduled at.
+	scheduledTimeExtension = "scheduledtime"
+
+	// CheckpointsConfigMapName is the name of the ConfigMap in the system namespace where the
+	// adapter records the time of the latest schedule of the PingSources with a missed schedule
+	// policy or firing once, so that the schedules missed while it was unavailable can be caught
+	// up and the completion of the PingSources firing once can be reported.
+	CheckpointsConfigMapName = "pingsource-mt-adapter-checkpoints"
+
+	// checkpointInterval is how often the recorded schedules are written to the checkpoints
+	// ConfigMap. The schedules recorded since the last write may be sent again after a restart.
+	checkpointInterval = 30 * time.Second
+)
+
+// scheduleParser parses the schedules of the PingSources.
+var scheduleParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
 )
 
-func NewCronJobsRunner(cfg adapter.ClientConfig, kubeClient kubernetes.Interface, logger *zap.SugaredLogger, opts ...cron.Option) *cronJobsRunner {
+func NewCronJobsRunner(cfg adapter.ClientConfig, kubeClient kubernetes.Interface, configMapLister corev1listers.ConfigMapLister, logger *zap.SugaredLogger, opts ...cron.Option) *cronJobsRunner {
 	return &cronJobsRunner{
-		cron:         *cron.New(opts...),
-		Logger:       logger,
-		kubeClient:   kubeClient,
-		clientConfig: cfg,
+		cron:            *cron.New(opts...),
+		Logger:          logger,
+		kubeClient:      kubeClient,
+		configMapLister: configMapLister,
+		clientConfig:    cfg,
+		namespace:       system.Namespace(),
+		lastSchedules:   make(map[string]time.Time),
+		checkpoints:     make(map[string]*string),
+		sequences:       make(map[string]sequence),
 	}
 }
 
+// CheckpointKey returns the key of the latest schedule of the PingSource in the checkpoints
+// ConfigMap. Namespaces can't contain dots, so that the keys are unique.
+func CheckpointKey(namespace, name string) string {
+	return namespace + "." + name
+}
+
+// LastSchedule returns the time of the latest schedule of the source recorded in the
+// checkpoints ConfigMap, or the zero time.
+func LastSchedule(cm *corev1.ConfigMap, source *sourcesv1.PingSource) (time.Time, error) {
+	recorded, ok := cm.Data[CheckpointKey(source.Namespace, source.Name)]
+	if !ok {
+		return time.Time{}, nil
+	}
+	last, err := time.Parse(time.RFC3339, recorded)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid last schedule time %q: %w", recorded, err)
+	}
+	return last, nil
+}
+
 func (a *cronJobsRunner) AddSchedule(source *sourcesv1.PingSource) cron.EntryID {
+	ctx, client, event, err := a.sender(source)
+	if err != nil {
+		a.Logger.Desugar().Error("Failed to create client",
+			zap.String("name", source.GetName()),
+			zap.String("namespace", source.GetNamespace()),
+			zap.Error(err),
+		)
+		return -1
+	}
+
+	schedule, err := scheduleFor(source)
+	if err != nil {
+		a.Logger.Desugar().Error("Failed to parse the schedule",
+			zap.String("name", source.GetName()),
+			zap.String("namespace", source.GetNamespace()),
+			zap.Error(err),
+		)
+		return -1
+	}
+
+	return a.cron.Schedule(schedule, cron.FuncJob(a.cronTick(ctx, client, source, schedule, event)))
+}
+
+// sender returns the context and client to send the events of the source with, and its event.
+func (a *cronJobsRunner) sender(source *sourcesv1.PingSource) (context.Context, kncloudevents.Client, cloudevents.Event, error) {
 	event, err := makeEvent(source)
 	if err != nil {
 		a.Logger.Error("failed to makeEvent: ", zap.Error(err))
@@ -100,25 +215,27 @@ func (a *cronJobsRunner) AddSchedule(source *sourcesv1.PingSource) cron.EntryID
 	ctx = observability.WithSpanData(ctx, spanName, int(trace.SpanKindProducer),
 		observability.K8sAttributes(source.Name, source.Namespace, sourcesv1.Resource("pingsource").String()))
 
-	schedule := source.Spec.Schedule
-	if source.Spec.Timezone != "" {
-		schedule = "CRON_TZ=" + source.Spec.Timezone + " " + schedule
-	}
-
 	ctx = kncloudevents.ContextWithMetricTag(ctx, metricTag)
 
 	client, err := a.newPingSourceClient(source)
-	if err != nil {
-		a.Logger.Desugar().Error("Failed to create client",
-			zap.String("name", source.GetName()),
-			zap.String("namespace", source.GetNamespace()),
-			zap.Error(err),
-		)
-		return -1
+	return ctx, client, event, err
+}
+
+// scheduleFor returns the schedule of the source, which fires once for the sources with an
+// instant.
+func scheduleFor(source *sourcesv1.PingSource) (cron.Schedule, error) {
+	if source.Spec.At != nil {
+		return utils.OnceSchedule(source.Spec.At.Time), nil
 	}
+	return scheduleParser.Parse(scheduleOf(source))
+}
 
-	id, _ := a.cron.AddFunc(schedule, a.cronTick(ctx, client, source, event))
-	return id
+func scheduleOf(source *sourcesv1.PingSource) string {
+	schedule := source.Spec.Schedule
+	if source.Spec.Timezone != "" {
+		schedule = "CRON_TZ=" + source.Spec.Timezone + " " + schedule
+	}
+	return schedule
 }
 
 func (a *cronJobsRunner) RemoveSchedule(id cron.EntryID) {
@@ -127,7 +244,17 @@ func (a *cronJobsRunner) RemoveSchedule(id cron.EntryID) {
 
 func (a *cronJobsRunner) Start(stopCh <-chan struct{}) {
 	a.cron.Start()
-	<-stopCh
+
+	ticker := time.NewTicker(checkpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.writeCheckpoints()
+		case <-stopCh:
+			return
+		}
+	}
 }
 
 func (a *cronJobsRunner) Stop() {
@@ -136,14 +263,33 @@ func (a *cronJobsRunner) Stop() {
 		// Wait for all jobs to be done.
 		<-ctx.Done()
 	}
+	a.writeCheckpoints()
 }
 
-func (a *cronJobsRunner) cronTick(ctx context.Context, client kncloudevents.Client, src *sourcesv1.PingSource, event cloudevents.Event) func() {
+func (a *cronJobsRunner) cronTick(ctx context.Context, client kncloudevents.Client, src *sourcesv1.PingSource, schedule cron.Schedule, event cloudevents.Event) func() {
 	target := src.Status.SinkURI.String()
 
 	return func() {
-		event := event.Clone()
-		event.SetID(uuid.New().String()) // provide an ID here so we can track it with logging
+		scheduled := scheduledAt(schedule, time.Now())
+		if scheduled.IsZero() {
+			a.Logger.Desugar().Warn("Skipping the tick too late after its schedule",
+				zap.String("name", src.GetName()),
+				zap.String("namespace", src.GetNamespace()),
+			)
+			return
+		}
+		if !a.onCalendar(src, scheduled) {
+			a.Logger.Debugf("skipping the schedule at %s outside of the calendar", scheduled)
+			return
+		}
+		defer a.recordLastSchedule(src, scheduled)
+
+		event, err := a.eventAt(src, event, scheduled)
+		if err != nil {
+			a.Logger.Error("failed to expand the templated data: ", zap.Error(err),
+				zap.String("name", src.GetName()), zap.String("namespace", src.GetNamespace()))
+			return
+		}
 		defer a.Logger.Debug("Finished sending cloudevent id: ", event.ID())
 		source := event.Context.GetSource()
 
@@ -162,6 +308,361 @@ func (a *cronJobsRunner) cronTick(ctx context.Context, client kncloudevents.Clie
 	}
 }
 
+func (a *cronJobsRunner) CatchUp(source *sourcesv1.PingSource) {
+	logger := a.Logger.Desugar().With(zap.String("name", source.GetName()), zap.String("namespace", source.GetNamespace()))
+
+	switch source.Spec.MissedSchedulePolicy {
+	case sourcesv1.MissedScheduleFireOnce, sourcesv1.MissedScheduleFireAll:
+	default:
+		return
+	}
+	last, err := a.lastSchedule(source)
+	if err != nil {
+		logger.Warn("Failed to get the last schedule", zap.Error(err))
+		return
+	}
+	ctx, client, event, err := a.sender(source)
+	if err != nil {
+		logger.Error("Failed to create client", zap.Error(err))
+		return
+	}
+	defer client.CloseIdleConnections()
+
+	count := 0
+	err = missedSchedules(source, last, time.Now(), func(scheduled time.Time) bool {
+		return a.onCalendar(source, scheduled)
+	}, func(scheduled time.Time) {
+		if count == 0 {
+			logger.Info("Catching up the missed schedules")
+		}
+		count++
+
+		event, err := a.eventAt(source, event, scheduled)
+		if err != nil {
+			logger.Error("failed to expand the templated data: ", zap.Error(err), zap.Time("scheduled", scheduled))
+			return
+		}
+		event.SetExtension(scheduledTimeExtension, scheduled)
+
+		if result := client.Send(ctx, event); !cloudevents.IsACK(result) {
+			logger.Error("failed to send cloudevent result: ", zap.Any("result", result),
+				zap.Time("scheduled", scheduled), zap.String("id", event.ID()))
+		}
+		a.recordLastSchedule(source, scheduled)
+	})
+	if err != nil {
+		logger.Warn("Failed to compute the missed schedules", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		logger.Info("Caught up the missed schedules", zap.Int("count", count))
+	}
+}
+
+// eventAt returns the event of the source for the given schedule, with an ID stable across
+// the replicas of the adapter and its data expanded when templated.
+func (a *cronJobsRunner) eventAt(source *sourcesv1.PingSource, event cloudevents.Event, scheduled time.Time) (cloudevents.Event, error) {
+	event = event.Clone()
+	event.SetID(eventID(source, scheduled))
+	if !source.Spec.TemplatedData {
+		return event, nil
+	}
+
+	n, err := a.sequenceAt(source, scheduled)
+	if err != nil {
+		return event, err
+	}
+	data, err := sourcesv1.ExpandTemplatedData(source.Spec.Data, sourcesv1.PingSourceTemplateData{
+		ScheduledTime:   scheduled,
+		Sequence:        n,
+		SourceName:      source.Name,
+		SourceNamespace: source.Namespace,
+	}, sourcesv1.PingSourceTemplatedDataMaxSize)
+	if err != nil {
+		return event, err
+	}
+	return event, event.SetData(source.Spec.ContentType, data)
+}
+
+// eventID derives the ID of the event of the source for the given schedule from the UID of
+// the source, so that the events sent for the same schedule can be deduplicated.
+func eventID(source *sourcesv1.PingSource, scheduled time.Time) string {
+	space, err := uuid.Parse(string(source.UID))
+	if err != nil {
+		space = uuid.NewSHA1(uuid.Nil, []byte(source.Namespace+"/"+source.Name))
+	}
+	return uuid.NewSHA1(space, []byte(scheduled.UTC().Format(time.RFC3339))).String()
+}
+
+// sequenceAt returns the number of the schedules of the source from its creation up to the
+// given one included, counted with its current schedule from the latest counted one.
+func (a *cronJobsRunner) sequenceAt(source *sourcesv1.PingSource, scheduled time.Time) (int64, error) {
+	schedule, err := scheduleFor(source)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	key := source.Namespace + "/" + source.Name
+	from := sequence{schedule: scheduleOf(source), at: source.CreationTimestamp.Time}
+	if from.at.IsZero() {
+		// Without a creation time, the schedules are counted from the first one.
+		from.at = scheduled.Add(-time.Nanosecond)
+	}
+	a.sequencesMu.Lock()
+	if cached, ok := a.sequences[key]; ok && cached.schedule == from.schedule && !scheduled.Before(cached.at) {
+		from = cached
+	}
+	a.sequencesMu.Unlock()
+
+	n := from.n + countSchedules(schedule, from.at, scheduled)
+
+	a.sequencesMu.Lock()
+	defer a.sequencesMu.Unlock()
+	if cached, ok := a.sequences[key]; !ok || cached.schedule != from.schedule || scheduled.After(cached.at) {
+		a.sequences[key] = sequence{schedule: from.schedule, at: scheduled, n: n}
+	}
+	return n, nil
+}
+
+// missedSchedules calls fire, in order, for the times the source was scheduled at after its last
+// recorded schedule and before now, within its max lookback, for which include returns true.
+// Only the latest of them is fired with the fireOnce policy, and the latest
+// MissedSchedulesMaxCatchUp with the fireAll policy. There are none for the sources without a
+// missed schedule policy, or without a recorded schedule unless they fire once.
+func missedSchedules(source *sourcesv1.PingSource, last, now time.Time, include func(time.Time) bool, fire func(time.Time)) error {
+	limit := sourcesv1.MissedSchedulesMaxCatchUp
+	switch source.Spec.MissedSchedulePolicy {
+	case sourcesv1.MissedScheduleFireOnce:
+		limit = 1
+	case sourcesv1.MissedScheduleFireAll:
+	default:
+		return nil
+	}
+	if last.IsZero() {
+		if source.Spec.At == nil {
+			return nil
+		}
+		// The sources firing once that never fired are caught up from their creation.
+		last = source.CreationTimestamp.Time
+	}
+
+	lookback := sourcesv1.DefaultMissedScheduleMaxLookback
+	if source.Spec.MissedScheduleMaxLookback != nil {
+		lookback = *source.Spec.MissedScheduleMaxLookback
+	}
+	p, err := period.Parse(lookback)
+	if err != nil {
+		return fmt.Errorf("invalid max lookback %q: %w", lookback, err)
+	}
+	if maxLookback := period.MustParse(sourcesv1.MissedScheduleMaxLookbackLimit); p.DurationApprox() > maxLookback.DurationApprox() {
+		// The sources created before the limit was validated are caught up within it.
+		p = maxLookback
+	}
+	if earliest := now.Add(-p.DurationApprox()); last.Before(earliest) {
+		// Next returns the times after the given one, so that the earliest is included.
+		last = earliest.Add(-time.Nanosecond)
+	}
+
+	schedule, err := scheduleFor(source)
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	// The missed schedules are counted first, so that the earlier ones beyond the limit are
+	// skipped without being held.
+	missed := 0
+	for t := schedule.Next(last); !t.IsZero() && t.Before(now); t = schedule.Next(t) {
+		if include(t) {
+			missed++
+		}
+	}
+	skipped := 0
+	for t := schedule.Next(last); !t.IsZero() && t.Before(now); t = schedule.Next(t) {
+		if !include(t) {
+			continue
+		}
+		if skipped < missed-limit {
+			skipped++
+			continue
+		}
+		fire(t)
+	}
+	return nil
+}
+
+// onCalendar returns whether the source fires at the given schedule, within its start and end
+// times and outside of its excluded dates.
+func (a *cronJobsRunner) onCalendar(source *sourcesv1.PingSource, scheduled time.Time) bool {
+	spec := source.Spec
+	if spec.StartTime != nil && scheduled.Before(spec.StartTime.Time) {
+		return false
+	}
+	if spec.EndTime != nil && scheduled.After(spec.EndTime.Time) {
+		return false
+	}
+	if len(spec.ExcludeDates) == 0 && spec.ExcludeDatesConfigMap == nil {
+		return true
+	}
+
+	loc := time.Local
+	if spec.Timezone != "" {
+		if l, err := time.LoadLocation(spec.Timezone); err == nil {
+			loc = l
+		}
+	}
+	date := scheduled.In(loc).Format(time.DateOnly)
+	if slices.Contains(spec.ExcludeDates, date) {
+		return false
+	}
+	if ref := spec.ExcludeDatesConfigMap; ref != nil {
+		cm, err := a.configMapLister.ConfigMaps(source.Namespace).Get(ref.Name)
+		if err != nil {
+			// The source doesn't fire on the dates which may be excluded.
+			a.Logger.Desugar().Warn("Failed to get the excluded dates, the ConfigMap must exist and be labeled "+sourcesv1.PingSourceExcludeDatesLabelSelector,
+				zap.String("name", source.GetName()),
+				zap.String("namespace", source.GetNamespace()),
+				zap.String("configMap", ref.Name),
+				zap.Error(err),
+			)
+			return false
+		}
+		if slices.Contains(strings.Fields(cm.Data[ref.Key]), date) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkpointed returns whether the latest schedule of the source is recorded, when it has a
+// missed schedule policy or fires once.
+func checkpointed(source *sourcesv1.PingSource) bool {
+	switch source.Spec.MissedSchedulePolicy {
+	case sourcesv1.MissedScheduleFireOnce, sourcesv1.MissedScheduleFireAll:
+		return true
+	default:
+		return source.Spec.At != nil
+	}
+}
+
+// lastSchedule returns the time of the latest schedule recorded for the source, or the zero
+// time.
+func (a *cronJobsRunner) lastSchedule(source *sourcesv1.PingSource) (time.Time, error) {
+	a.lastSchedulesMu.Lock()
+	last, ok := a.lastSchedules[CheckpointKey(source.Namespace, source.Name)]
+	a.lastSchedulesMu.Unlock()
+	if ok {
+		return last, nil
+	}
+
+	cm, err := a.kubeClient.CoreV1().ConfigMaps(a.namespace).Get(context.Background(), CheckpointsConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+	return LastSchedule(cm, source)
+}
+
+// recordLastSchedule records the time of the latest schedule of the source, when it has a
+// missed schedule policy or fires once. It is written with the next checkpoints, right away
+// for the sources firing once so that their completion is reported.
+func (a *cronJobsRunner) recordLastSchedule(source *sourcesv1.PingSource, scheduled time.Time) {
+	if !checkpointed(source) {
+		return
+	}
+
+	key := CheckpointKey(source.Namespace, source.Name)
+	a.lastSchedulesMu.Lock()
+	if last, ok := a.lastSchedules[key]; ok && !scheduled.After(last) {
+		a.lastSchedulesMu.Unlock()
+		return
+	}
+	a.lastSchedules[key] = scheduled
+	recorded := scheduled.UTC().Format(time.RFC3339)
+	a.checkpoints[key] = &recorded
+	a.lastSchedulesMu.Unlock()
+
+	if source.Spec.At != nil {
+		a.writeCheckpoints()
+	}
+}
+
+func (a *cronJobsRunner) Forget(source *sourcesv1.PingSource) {
+	a.sequencesMu.Lock()
+	delete(a.sequences, source.Namespace+"/"+source.Name)
+	a.sequencesMu.Unlock()
+
+	key := CheckpointKey(source.Namespace, source.Name)
+	a.lastSchedulesMu.Lock()
+	defer a.lastSchedulesMu.Unlock()
+	delete(a.lastSchedules, key)
+	a.checkpoints[key] = nil
+}
+
+// writeCheckpoints writes the schedules recorded since the last write to the checkpoints
+// ConfigMap, and removes the forgotten sources from it.
+func (a *cronJobsRunner) writeCheckpoints() {
+	a.checkpointsMu.Lock()
+	defer a.checkpointsMu.Unlock()
+
+	a.lastSchedulesMu.Lock()
+	checkpoints := a.checkpoints
+	a.checkpoints = make(map[string]*string)
+	a.lastSchedulesMu.Unlock()
+	if len(checkpoints) == 0 {
+		return
+	}
+
+	if err := a.patchCheckpoints(checkpoints); err != nil {
+		a.Logger.Desugar().Warn("Failed to record the last schedules", zap.Error(err))
+
+		// The checkpoints are written again with the next ones, unless they were superseded.
+		a.lastSchedulesMu.Lock()
+		for key, recorded := range checkpoints {
+			if _, ok := a.checkpoints[key]; !ok {
+				a.checkpoints[key] = recorded
+			}
+		}
+		a.lastSchedulesMu.Unlock()
+	}
+}
+
+// patchCheckpoints merges the checkpoints into the checkpoints ConfigMap, creating it when it
+// doesn't exist.
+func (a *cronJobsRunner) patchCheckpoints(checkpoints map[string]*string) error {
+	ctx := context.Background()
+	configMaps := a.kubeClient.CoreV1().ConfigMaps(a.namespace)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": checkpoints,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = configMaps.Patch(ctx, CheckpointsConfigMapName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CheckpointsConfigMapName,
+			Namespace: a.namespace,
+		},
+		Data: make(map[string]string, len(checkpoints)),
+	}
+	for key, recorded := range checkpoints {
+		if recorded != nil {
+			cm.Data[key] = *recorded
+		}
+	}
+	_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// Created concurrently by another replica.
+		_, err = configMaps.Patch(ctx, CheckpointsConfigMapName, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
+
 func makeEvent(source *sourcesv1.PingSource) (cloudevents.Event, error) {
 	event := cloudevents.NewEvent()
 	event.SetType(sourcesv1.PingSourceEventType)
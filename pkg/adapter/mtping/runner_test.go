@@ -31,9 +31,13 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	bindingshttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
+	"k8s.io/utils/ptr"
 
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -41,9 +45,12 @@ import (
 	_ "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/logging"
 	rectesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
 
 	"knative.dev/eventing/pkg/adapter/v2"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	_ "knative.dev/eventing/pkg/client/injection/client/fake"
 	"knative.dev/eventing/pkg/eventingtls/eventingtlstesting"
 )
 
@@ -175,7 +182,7 @@ func TestAddRunRemoveSchedules(t *testing.T) {
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
-			ctx, _ := rectesting.SetupFakeContext(t)
+			ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
 			logger := logging.FromContext(ctx)
 
 			h, events := eventsAccumulator()
@@ -184,7 +191,7 @@ func TestAddRunRemoveSchedules(t *testing.T) {
 			defer s.Close()
 			url, _ := apis.ParseURL(s.URL)
 
-			runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), logger)
+			runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), configMapLister(t), logger)
 			tc.src.Status.SinkURI = url
 			entryId := runner.AddSchedule(tc.src)
 
@@ -209,7 +216,7 @@ func TestAddRunRemoveSchedules(t *testing.T) {
 
 func TestSendEventsTLS(t *testing.T) {
 
-	ctx, _ := rectesting.SetupFakeContext(t)
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
 	eventsChan := make(chan cloudevents.Event, 10)
 	handler := eventingtlstesting.EventChannelHandler(eventsChan)
 	events := make([]cloudevents.Event, 0, 8)
@@ -285,7 +292,7 @@ func TestSendEventsTLS(t *testing.T) {
 			cc := adapter.ClientConfig{
 				CeOverrides: tc.src.Spec.CloudEventOverrides,
 			}
-			runner := NewCronJobsRunner(cc, kubeclient.Get(ctx), logger)
+			runner := NewCronJobsRunner(cc, kubeclient.Get(ctx), configMapLister(t), logger)
 			entryId := runner.AddSchedule(tc.src)
 
 			entry := runner.cron.Entry(entryId)
@@ -309,10 +316,10 @@ func TestSendEventsTLS(t *testing.T) {
 }
 
 func TestStartStopCron(t *testing.T) {
-	ctx, _ := rectesting.SetupFakeContext(t)
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
 	logger := logging.FromContext(ctx)
 
-	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), logger)
+	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), configMapLister(t), logger)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	wctx, wcancel := context.WithCancel(context.Background())
@@ -337,7 +344,7 @@ func TestStartStopCronDelayWait(t *testing.T) {
 	if seconds > threeSecondsTillNextMinCronJob {
 		time.Sleep(time.Second * 4) // ward off edge cases
 	}
-	ctx, _ := rectesting.SetupFakeContext(t)
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
 	logger := logging.FromContext(ctx)
 
 	h, events := eventsAccumulator()
@@ -349,7 +356,7 @@ func TestStartStopCronDelayWait(t *testing.T) {
 	defer s.Close()
 	url, _ := apis.ParseURL(s.URL)
 
-	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), logger)
+	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), configMapLister(t), logger)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -388,6 +395,421 @@ func TestStartStopCronDelayWait(t *testing.T) {
 	validateSent(t, *events, []byte("some delayed data"), cloudevents.TextPlain, nil)
 }
 
+func TestEventAt(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
+	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), configMapLister(t), logging.FromContext(ctx))
+
+	created := time.Date(2024, 5, 1, 12, 0, 30, 0, time.UTC)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-name",
+			Namespace:         "test-ns",
+			UID:               "5f9cc22e-3a06-4b8a-9b43-4c7c4e7e6d2a",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			Schedule:      "* * * * *",
+			ContentType:   cloudevents.ApplicationJSON,
+			Data:          `{"seq":{{.Sequence}},"at":"{{.ScheduledTime.Format "15:04"}}","src":"{{.SourceNamespace}}/{{.SourceName}}"}`,
+			TemplatedData: true,
+		},
+	}
+	event, err := makeEvent(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		minute   int
+		wantData string
+	}{
+		{minute: 3, wantData: `{"seq":3,"at":"12:03","src":"test-ns/test-name"}`},
+		{minute: 5, wantData: `{"seq":5,"at":"12:05","src":"test-ns/test-name"}`},
+		// Counted again from the creation of the source.
+		{minute: 1, wantData: `{"seq":1,"at":"12:01","src":"test-ns/test-name"}`},
+	} {
+		scheduled := created.Truncate(time.Minute).Add(time.Duration(tc.minute) * time.Minute)
+		got, err := runner.eventAt(src, event, scheduled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data := string(got.Data()); data != tc.wantData {
+			t.Errorf("Expected data %s, got %s", tc.wantData, data)
+		}
+		if id := eventID(src, scheduled); got.ID() != id {
+			t.Errorf("Expected ID %q, got %q", id, got.ID())
+		}
+	}
+}
+
+func TestEventID(t *testing.T) {
+	src := &sourcesv1.PingSource{ObjectMeta: metav1.ObjectMeta{UID: "5f9cc22e-3a06-4b8a-9b43-4c7c4e7e6d2a"}}
+	other := &sourcesv1.PingSource{ObjectMeta: metav1.ObjectMeta{UID: "0d6b7b43-8f3e-4f43-9b7a-0e5f0f1c2b3d"}}
+	scheduled := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	if eventID(src, scheduled) != eventID(src, scheduled.In(time.FixedZone("CEST", 2*60*60))) {
+		t.Error("Expected the same ID for the same schedule")
+	}
+	if eventID(src, scheduled) == eventID(src, scheduled.Add(time.Minute)) {
+		t.Error("Expected different IDs for different schedules")
+	}
+	if eventID(src, scheduled) == eventID(other, scheduled) {
+		t.Error("Expected different IDs for different sources")
+	}
+}
+
+func TestOnCalendar(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
+	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), configMapLister(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "holidays", Namespace: "test-ns"},
+		Data:       map[string]string{"dates": "2024-05-01\n2024-12-25\n"},
+	}), logging.FromContext(ctx))
+
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"},
+		Spec: sourcesv1.PingSourceSpec{
+			Schedule:     "0 * * * *",
+			Timezone:     "Europe/Paris",
+			StartTime:    ptr.To(metav1.NewTime(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))),
+			EndTime:      ptr.To(metav1.NewTime(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))),
+			ExcludeDates: []string{"2024-07-14"},
+			ExcludeDatesConfigMap: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "holidays"},
+				Key:                  "dates",
+			},
+		},
+	}
+
+	missing := src.DeepCopy()
+	missing.Spec.ExcludeDatesConfigMap.Name = "missing"
+
+	testCases := map[string]struct {
+		src       *sourcesv1.PingSource
+		scheduled time.Time
+		want      bool
+	}{
+		"before start time": {
+			scheduled: time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+		},
+		"after end time": {
+			scheduled: time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC),
+		},
+		"excluded date": {
+			scheduled: time.Date(2024, 7, 14, 12, 0, 0, 0, time.UTC),
+		},
+		"excluded date in the timezone of the schedule": {
+			scheduled: time.Date(2024, 7, 13, 23, 0, 0, 0, time.UTC),
+		},
+		"excluded date from the config map": {
+			scheduled: time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC),
+		},
+		"on calendar": {
+			scheduled: time.Date(2024, 7, 13, 12, 0, 0, 0, time.UTC),
+			want:      true,
+		},
+		"missing config map": {
+			src:       missing,
+			scheduled: time.Date(2024, 7, 13, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			src := src
+			if tc.src != nil {
+				src = tc.src
+			}
+			if got := runner.onCalendar(src, tc.scheduled); got != tc.want {
+				t.Errorf("onCalendar(%s) = %v, want %v", tc.scheduled, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleForOneShot(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	schedule, err := scheduleFor(&sourcesv1.PingSource{
+		Spec: sourcesv1.PingSourceSpec{At: ptr.To(metav1.NewTime(at))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := schedule.Next(at.Add(-time.Hour)); !got.Equal(at) {
+		t.Errorf("Expected the next schedule to be %s, got %s", at, got)
+	}
+	if got := schedule.Next(at); !got.IsZero() {
+		t.Errorf("Expected no next schedule, got %s", got)
+	}
+}
+
+func TestMissedSchedules(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	hourly := func(policy sourcesv1.MissedSchedulePolicy, lookback *string) *sourcesv1.PingSource {
+		return &sourcesv1.PingSource{
+			Spec: sourcesv1.PingSourceSpec{
+				Schedule:                  "0 * * * *",
+				MissedSchedulePolicy:      policy,
+				MissedScheduleMaxLookback: lookback,
+			},
+		}
+	}
+	hour := func(h int) time.Time {
+		return time.Date(2024, 5, 1, h, 0, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		src     *sourcesv1.PingSource
+		last    time.Time
+		exclude time.Time
+		want    []time.Time
+		wantErr bool
+	}{
+		"no policy": {
+			src:  hourly("", nil),
+			last: hour(8),
+		},
+		"skip": {
+			src:  hourly(sourcesv1.MissedScheduleSkip, nil),
+			last: hour(8),
+		},
+		"no last schedule": {
+			src: hourly(sourcesv1.MissedScheduleFireAll, nil),
+		},
+		"nothing missed": {
+			src:  hourly(sourcesv1.MissedScheduleFireAll, nil),
+			last: hour(12),
+		},
+		"default lookback": {
+			src:  hourly(sourcesv1.MissedScheduleFireAll, nil),
+			last: hour(8),
+			want: []time.Time{hour(12)},
+		},
+		"lookback": {
+			src:  hourly(sourcesv1.MissedScheduleFireAll, ptr.To("PT3H")),
+			last: hour(8),
+			want: []time.Time{hour(10), hour(11), hour(12)},
+		},
+		"lookback includes the earliest schedule": {
+			src:  hourly(sourcesv1.MissedScheduleFireAll, ptr.To("PT2H30M")),
+			last: hour(8),
+			want: []time.Time{hour(10), hour(11), hour(12)},
+		},
+		"last schedule within lookback": {
+			src:  hourly(sourcesv1.MissedScheduleFireAll, ptr.To("P1D")),
+			last: hour(10),
+			want: []time.Time{hour(11), hour(12)},
+		},
+		"fire once": {
+			src:  hourly(sourcesv1.MissedScheduleFireOnce, ptr.To("P1D")),
+			last: hour(10),
+			want: []time.Time{hour(12)},
+		},
+		"lookback beyond the limit": {
+			src:  hourly(sourcesv1.MissedScheduleFireAll, ptr.To("P7D")),
+			last: hour(12).AddDate(0, 0, -7),
+			want: schedules(hour(13).AddDate(0, 0, -1), hour(12), time.Hour),
+		},
+		"latest missed schedules": {
+			src: &sourcesv1.PingSource{
+				Spec: sourcesv1.PingSourceSpec{
+					Schedule:                  "* * * * *",
+					MissedSchedulePolicy:      sourcesv1.MissedScheduleFireAll,
+					MissedScheduleMaxLookback: ptr.To("PT3H"),
+				},
+			},
+			last: hour(8),
+			want: schedules(now.Add(-sourcesv1.MissedSchedulesMaxCatchUp*time.Minute), now.Add(-time.Minute), time.Minute),
+		},
+		"excluded schedules": {
+			src:     hourly(sourcesv1.MissedScheduleFireAll, ptr.To("PT3H")),
+			last:    hour(8),
+			exclude: hour(11),
+			want:    []time.Time{hour(10), hour(12)},
+		},
+		"one-shot never fired": {
+			src: &sourcesv1.PingSource{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(hour(9))},
+				Spec: sourcesv1.PingSourceSpec{
+					At:                   ptr.To(metav1.NewTime(hour(12))),
+					MissedSchedulePolicy: sourcesv1.MissedScheduleFireOnce,
+				},
+			},
+			want: []time.Time{hour(12)},
+		},
+		"invalid lookback": {
+			src:     hourly(sourcesv1.MissedScheduleFireAll, ptr.To("yesterday")),
+			last:    hour(8),
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var got []time.Time
+			err := missedSchedules(tc.src, tc.last, now, func(t time.Time) bool {
+				return !t.Equal(tc.exclude)
+			}, func(t time.Time) {
+				got = append(got, t)
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("missedSchedules() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("missedSchedules() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// schedules returns the times from the first to the last one included, at the given interval.
+func schedules(first, last time.Time, interval time.Duration) []time.Time {
+	var times []time.Time
+	for t := first; !t.After(last); t = t.Add(interval) {
+		times = append(times, t)
+	}
+	return times
+}
+
+func TestLastSchedule(t *testing.T) {
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"},
+	}
+	testCases := map[string]struct {
+		data    map[string]string
+		want    time.Time
+		wantErr bool
+	}{
+		"no checkpoint": {
+			data: map[string]string{"other-ns.test-name": "2024-05-01T08:00:00Z"},
+		},
+		"checkpoint": {
+			data: map[string]string{"test-ns.test-name": "2024-05-01T08:00:00Z"},
+			want: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+		},
+		"invalid checkpoint": {
+			data:    map[string]string{"test-ns.test-name": "yesterday"},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := LastSchedule(&corev1.ConfigMap{Data: tc.data}, src)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LastSchedule() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("LastSchedule() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
+	logger := logging.FromContext(ctx)
+
+	h, events := eventsAccumulator()
+	s := httptest.NewServer(h)
+	defer s.Close()
+	url, _ := apis.ParseURL(s.URL)
+
+	last := time.Now().UTC().Truncate(time.Minute).Add(-3 * time.Minute)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			SourceSpec: duckv1.SourceSpec{
+				CloudEventOverrides: &duckv1.CloudEventOverrides{},
+			},
+			Schedule:             "* * * * *",
+			ContentType:          cloudevents.TextPlain,
+			Data:                 sampleData,
+			MissedSchedulePolicy: sourcesv1.MissedScheduleFireOnce,
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: url,
+			},
+		},
+	}
+	configMaps := kubeclient.Get(ctx).CoreV1().ConfigMaps(system.Namespace())
+	_, err := configMaps.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: CheckpointsConfigMapName, Namespace: system.Namespace()},
+		Data: map[string]string{
+			"test-ns.test-name":  last.Format(time.RFC3339),
+			"test-ns.other-name": last.Format(time.RFC3339),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), configMapLister(t), logger)
+	runner.CatchUp(src)
+
+	var latest time.Time
+	_ = missedSchedules(src, last, time.Now(), func(time.Time) bool { return true }, func(t time.Time) {
+		latest = t
+	})
+	validateSent(t, *events, []byte(sampleData), cloudevents.TextPlain, map[string]string{
+		scheduledTimeExtension: cloudevents.Timestamp{Time: latest}.String(),
+	})
+
+	// The checkpoints are only written periodically.
+	cm, err := configMaps.Get(ctx, CheckpointsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := LastSchedule(cm, src); !got.Equal(last) {
+		t.Errorf("Expected the last schedule time %s to be kept until the checkpoints are written, got %s", last, got)
+	}
+
+	runner.Forget(&sourcesv1.PingSource{ObjectMeta: metav1.ObjectMeta{Name: "other-name", Namespace: "test-ns"}})
+	runner.writeCheckpoints()
+
+	cm, err = configMaps.Get(ctx, CheckpointsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"test-ns.test-name": latest.Format(time.RFC3339)}
+	if !reflect.DeepEqual(cm.Data, want) {
+		t.Errorf("Expected the checkpoints %v, got %v", want, cm.Data)
+	}
+}
+
+func TestWriteCheckpointsCreatesConfigMap(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t, setUpInformerSelector)
+	runner := NewCronJobsRunner(adapter.ClientConfig{}, kubeclient.Get(ctx), configMapLister(t), logging.FromContext(ctx))
+
+	at := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"},
+		Spec:       sourcesv1.PingSourceSpec{At: ptr.To(metav1.NewTime(at))},
+	}
+	// The sources firing once are written right away.
+	runner.recordLastSchedule(src, at)
+
+	cm, err := kubeclient.Get(ctx).CoreV1().ConfigMaps(system.Namespace()).Get(ctx, CheckpointsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := LastSchedule(cm, src); !got.Equal(at) {
+		t.Errorf("Expected the last schedule time %s, got %s", at, got)
+	}
+}
+
+// configMapLister lists the given ConfigMaps.
+func configMapLister(t *testing.T, cms ...*corev1.ConfigMap) corev1listers.ConfigMapLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, cm := range cms {
+		if err := indexer.Add(cm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return corev1listers.NewConfigMapLister(indexer)
+}
+
 func validateSent(t *testing.T, events []cloudevents.Event, wantData []byte, wantContentType string, extensions map[string]string) {
 	err := wait.PollUntilContextTimeout(context.Background(), time.Second, time.Minute, true, func(ctx context.Context) (done bool, err error) {
 		return len(events) == 1, nil
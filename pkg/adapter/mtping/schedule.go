@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtping
+
+import (
+	"math/bits"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// tickDelay is how long after their schedule the ticks happen at most.
+const tickDelay = time.Minute
+
+// starBit is set in the fields of the cron specs which are *.
+const starBit = 1 << 63
+
+// scheduledAt returns the time of the latest schedule up to now, which a tick happening at now
+// is for, or the zero time when there is none within the tick delay.
+func scheduledAt(schedule cron.Schedule, now time.Time) time.Time {
+	var scheduled time.Time
+	for t := schedule.Next(now.Add(-tickDelay)); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		scheduled = t
+	}
+	return scheduled
+}
+
+// countSchedules returns the number of the schedules after from and up to to included. The
+// schedules of the whole days in between are counted at once for the cron specs, so that it
+// doesn't depend on their frequency.
+func countSchedules(schedule cron.Schedule, from, to time.Time) int64 {
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return iterateSchedules(schedule, from, to)
+	}
+	from, to = from.In(spec.Location), to.In(spec.Location)
+
+	// The days are counted from the first midnight after from, until the last one before to.
+	first := startOfDay(from).AddDate(0, 0, 1)
+	last := startOfDay(to)
+	if !first.Before(last) {
+		return iterateSchedules(schedule, from, to)
+	}
+
+	perDay := int64(bits.OnesCount64(spec.Second&^starBit) *
+		bits.OnesCount64(spec.Minute&^starBit) *
+		bits.OnesCount64(spec.Hour&^starBit))
+	n := iterateSchedules(schedule, from, first.Add(-time.Nanosecond))
+	for day := first; day.Before(last); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		if next.Sub(day) != 24*time.Hour {
+			// The days with a daylight saving time transition are counted one by one.
+			n += iterateSchedules(schedule, day.Add(-time.Nanosecond), next.Add(-time.Nanosecond))
+		} else if dayMatches(spec, day) {
+			n += perDay
+		}
+	}
+	return n + iterateSchedules(schedule, last.Add(-time.Nanosecond), to)
+}
+
+// iterateSchedules counts the schedules after from and up to to included one by one.
+func iterateSchedules(schedule cron.Schedule, from, to time.Time) int64 {
+	var n int64
+	for t := schedule.Next(from); !t.IsZero() && !t.After(to); t = schedule.Next(t) {
+		n++
+	}
+	return n
+}
+
+// dayMatches returns whether the cron spec fires on the day, like the cron library.
+func dayMatches(spec *cron.SpecSchedule, day time.Time) bool {
+	if 1<<uint(day.Month())&spec.Month == 0 {
+		return false
+	}
+	domMatch := 1<<uint(day.Day())&spec.Dom > 0
+	dowMatch := 1<<uint(day.Weekday())&spec.Dow > 0
+	if spec.Dom&starBit > 0 || spec.Dow&starBit > 0 {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtping
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduledAt(t *testing.T) {
+	schedule, err := scheduleParser.Parse("*/10 * * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot := time.Date(2024, 5, 1, 12, 0, 20, 0, time.UTC)
+
+	testCases := map[string]struct {
+		now  time.Time
+		want time.Time
+	}{
+		"on time": {
+			now:  slot,
+			want: slot,
+		},
+		"late": {
+			now:  slot.Add(1500 * time.Millisecond),
+			want: slot,
+		},
+		"after the next schedule": {
+			now:  slot.Add(tickDelay + time.Second),
+			want: slot.Add(tickDelay),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := scheduledAt(schedule, tc.now); !got.Equal(tc.want) {
+				t.Errorf("scheduledAt() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	hourly, err := scheduleParser.Parse("0 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scheduledAt(hourly, slot.Add(tickDelay+time.Second)); !got.IsZero() {
+		t.Errorf("Expected no schedule within the tick delay, got %s", got)
+	}
+}
+
+func TestCountSchedules(t *testing.T) {
+	testCases := map[string]struct {
+		schedule string
+		from     time.Time
+		to       time.Time
+	}{
+		"every second within a day": {
+			schedule: "* * * * * *",
+			from:     time.Date(2024, 5, 1, 12, 0, 30, 0, time.UTC),
+			to:       time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC),
+		},
+		"every minute over months": {
+			schedule: "* * * * *",
+			from:     time.Date(2024, 1, 15, 7, 3, 30, 0, time.UTC),
+			to:       time.Date(2024, 4, 2, 9, 30, 0, 0, time.UTC),
+		},
+		"weekdays": {
+			schedule: "0,30 9-17 * * MON-FRI",
+			from:     time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC),
+		},
+		"day of month or week": {
+			schedule: "15 8 1,15 * SUN",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+		"months": {
+			schedule: "0 0 12 29 2,6 *",
+			from:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"daylight saving time": {
+			schedule: "CRON_TZ=Europe/Paris */15 1-3 * * *",
+			from:     time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC),
+		},
+		"ends on a schedule": {
+			schedule: "0 * * * *",
+			from:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			schedule, err := scheduleParser.Parse(tc.schedule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := iterateSchedules(schedule, tc.from, tc.to)
+			if got := countSchedules(schedule, tc.from, tc.to); got != want {
+				t.Errorf("countSchedules() = %d, want %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/apimachinery/pkg/types"
+
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+)
+
+// The reasons a group completes, set on the aggregate events as the CompletionExtension.
+const (
+	CompletionCount   = "count"
+	CompletionTimeout = "timeout"
+	CompletionCESQL   = "cesql"
+)
+
+// DefaultMaxGroupAge is how long a group is kept before it is dropped when it doesn't complete,
+// so that the groups of the Aggregators without timeout don't accumulate.
+const DefaultMaxGroupAge = 24 * time.Hour
+
+// DefaultMaxGroups is the maximum number of incomplete groups of all the Aggregators.
+const DefaultMaxGroups = 10000
+
+var (
+	// ErrTooManyGroups is returned when an event would start a group while the maximum number
+	// of groups is reached.
+	ErrTooManyGroups = errors.New("too many incomplete groups")
+	// ErrGroupFull is returned when an event which doesn't complete its group would exceed the
+	// maximum number of events of a group.
+	ErrGroupFull = errors.New("the group is full")
+)
+
+// Group is the events received by an Aggregator with the same correlation value.
+type Group struct {
+	Ref         types.NamespacedName
+	Correlation string
+	Events      []cloudevents.Event
+	// Deadline is when the group completes by timeout, zero when the group has no timeout.
+	Deadline time.Time
+	// Created is when the first event of the group was received.
+	Created time.Time
+}
+
+type groupKey struct {
+	ref         types.NamespacedName
+	correlation string
+}
+
+// Groups holds the incomplete groups of events of the Aggregators, in memory. They are lost when
+// the aggregator restarts.
+type Groups struct {
+	// maxAge is how long a group is kept before it is dropped.
+	maxAge time.Duration
+	// maxGroups is the maximum number of groups.
+	maxGroups int
+	// maxEvents is the maximum number of events of a group.
+	maxEvents int
+
+	mu     sync.Mutex
+	groups map[groupKey]*Group
+}
+
+func NewGroups(maxAge time.Duration, maxGroups int) *Groups {
+	return &Groups{
+		maxAge:    maxAge,
+		maxGroups: maxGroups,
+		maxEvents: sinksv1alpha1.AggregatorMaxGroupEvents,
+		groups:    make(map[groupKey]*Group),
+	}
+}
+
+// Add appends the event to its group, which starts with the given timeout when this is its first
+// event. complete is called with the group once the event is added and returns the reason the
+// group is complete, or "" when it isn't. A complete group is removed and returned with its reason,
+// it must be restored when it fails to be sent. The event is rejected with ErrTooManyGroups when it
+// would start a group beyond the maximum number of groups, and with ErrGroupFull when it doesn't
+// complete its group and exceeds the maximum number of events of a group.
+func (g *Groups) Add(ref types.NamespacedName, correlation string, e cloudevents.Event, now time.Time, timeout time.Duration, complete func(*Group) string) (*Group, string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := groupKey{ref: ref, correlation: correlation}
+	group, ok := g.groups[key]
+	if !ok {
+		if g.maxGroups > 0 && len(g.groups) >= g.maxGroups {
+			return nil, "", ErrTooManyGroups
+		}
+		group = &Group{
+			Ref:         ref,
+			Correlation: correlation,
+			Created:     now,
+		}
+		if timeout > 0 {
+			group.Deadline = now.Add(timeout)
+		}
+	}
+	group.Events = append(group.Events, e)
+
+	reason := complete(group)
+	if reason == "" {
+		if g.maxEvents > 0 && len(group.Events) > g.maxEvents {
+			group.Events = group.Events[:len(group.Events)-1]
+			return nil, "", ErrGroupFull
+		}
+		g.groups[key] = group
+		return nil, "", nil
+	}
+	delete(g.groups, key)
+	return group, reason, nil
+}
+
+// Restore puts back a complete group which failed to be sent, before the events of the same
+// correlation added since it was removed.
+func (g *Groups) Restore(group *Group) {
+	if len(group.Events) == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := groupKey{ref: group.Ref, correlation: group.Correlation}
+	current, ok := g.groups[key]
+	if !ok {
+		g.groups[key] = group
+		return
+	}
+	current.Events = append(append([]cloudevents.Event(nil), group.Events...), current.Events...)
+	current.Created = group.Created
+	if !group.Deadline.IsZero() && (current.Deadline.IsZero() || group.Deadline.Before(current.Deadline)) {
+		current.Deadline = group.Deadline
+	}
+}
+
+// Expire removes and returns the groups whose deadline is before now, oldest first, along with
+// the groups older than the maximum age which are dropped, including those which timed out and
+// keep failing to be sent.
+func (g *Groups) Expire(now time.Time) (expired []*Group, dropped []*Group) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for key, group := range g.groups {
+		switch {
+		case g.maxAge > 0 && now.Sub(group.Created) > g.maxAge:
+			dropped = append(dropped, group)
+			delete(g.groups, key)
+		case !group.Deadline.IsZero() && group.Deadline.Before(now):
+			expired = append(expired, group)
+			delete(g.groups, key)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].Deadline.Before(expired[j].Deadline)
+	})
+	return expired, dropped
+}
+
+// Refs returns the Aggregators having incomplete groups.
+func (g *Groups) Refs() []types.NamespacedName {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	seen := make(map[types.NamespacedName]struct{})
+	var refs []types.NamespacedName
+	for key := range g.groups {
+		if _, ok := seen[key.ref]; !ok {
+			seen[key.ref] = struct{}{}
+			refs = append(refs, key.ref)
+		}
+	}
+	return refs
+}
+
+// Delete drops the incomplete groups of an Aggregator.
+func (g *Groups) Delete(ref types.NamespacedName) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for key := range g.groups {
+		if key.ref == ref {
+			delete(g.groups, key)
+		}
+	}
+}
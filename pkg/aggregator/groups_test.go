@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGroups(t *testing.T) {
+	groups := NewGroups(DefaultMaxGroupAge, DefaultMaxGroups)
+	ref := types.NamespacedName{Namespace: "ns", Name: "aggregator"}
+	other := types.NamespacedName{Namespace: "ns", Name: "other"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	countOf := func(n int) func(*Group) string {
+		return func(g *Group) string {
+			if len(g.Events) >= n {
+				return CompletionCount
+			}
+			return ""
+		}
+	}
+	event := func(id string) cloudevents.Event {
+		e := cetest.MinEvent()
+		e.SetID(id)
+		return e
+	}
+
+	if g, _, _ := groups.Add(ref, "a", event("1"), now, time.Minute, countOf(2)); g != nil {
+		t.Fatal("expected the group to be incomplete")
+	}
+	if g, _, _ := groups.Add(ref, "b", event("2"), now.Add(time.Second), time.Minute, countOf(2)); g != nil {
+		t.Fatal("expected the group to be incomplete")
+	}
+	if g, _, _ := groups.Add(other, "a", event("3"), now, 0, countOf(2)); g != nil {
+		t.Fatal("expected the group to be incomplete")
+	}
+
+	g, reason, _ := groups.Add(ref, "a", event("4"), now.Add(2*time.Minute), time.Minute, countOf(2))
+	if g == nil || reason != CompletionCount {
+		t.Fatalf("expected the group to be complete by count, got %v %q", g, reason)
+	}
+	if g.Correlation != "a" || len(g.Events) != 2 || g.Events[0].ID() != "1" || g.Events[1].ID() != "4" {
+		t.Errorf("unexpected group %+v", g)
+	}
+	// The deadline is set by the first event of the group.
+	if want := now.Add(time.Minute); !g.Deadline.Equal(want) {
+		t.Errorf("expected the deadline %v, got %v", want, g.Deadline)
+	}
+
+	if got, _ := groups.Expire(now.Add(time.Minute)); len(got) != 0 {
+		t.Errorf("expected no expired group, got %d", len(got))
+	}
+	expired, dropped := groups.Expire(now.Add(time.Hour))
+	if len(expired) != 1 || expired[0].Ref != ref || expired[0].Correlation != "b" {
+		t.Errorf("expected the group b to expire, got %+v", expired)
+	}
+	if len(dropped) != 0 {
+		t.Errorf("expected no dropped group, got %+v", dropped)
+	}
+
+	// The groups without timeout don't expire.
+	if got := groups.Refs(); len(got) != 1 || got[0] != other {
+		t.Errorf("expected the groups of %v only, got %v", other, got)
+	}
+	groups.Delete(other)
+	if got := groups.Refs(); len(got) != 0 {
+		t.Errorf("expected no group, got %v", got)
+	}
+}
+
+func TestGroupsRestore(t *testing.T) {
+	groups := NewGroups(time.Hour, DefaultMaxGroups)
+	ref := types.NamespacedName{Namespace: "ns", Name: "aggregator"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	complete := func(*Group) string { return CompletionCount }
+	incomplete := func(*Group) string { return "" }
+	event := func(id string) cloudevents.Event {
+		e := cetest.MinEvent()
+		e.SetID(id)
+		return e
+	}
+
+	g, _, _ := groups.Add(ref, "a", event("1"), now, 0, complete)
+	if g == nil {
+		t.Fatal("expected the group to be complete")
+	}
+	groups.Add(ref, "a", event("2"), now.Add(time.Minute), 0, incomplete)
+
+	// The group failed to be sent, its events come before the events added since.
+	groups.Restore(g)
+	g, _, _ = groups.Add(ref, "a", event("3"), now.Add(2*time.Minute), 0, complete)
+	if g == nil || len(g.Events) != 3 || g.Events[0].ID() != "1" || g.Events[1].ID() != "2" || g.Events[2].ID() != "3" {
+		t.Fatalf("unexpected group %+v", g)
+	}
+	if !g.Created.Equal(now) {
+		t.Errorf("expected the group to be created at %v, got %v", now, g.Created)
+	}
+
+	// The groups without timeout are dropped after the maximum age.
+	groups.Restore(g)
+	if expired, dropped := groups.Expire(now.Add(time.Hour)); len(expired) != 0 || len(dropped) != 0 {
+		t.Errorf("expected no expired or dropped group, got %+v %+v", expired, dropped)
+	}
+	expired, dropped := groups.Expire(now.Add(2 * time.Hour))
+	if len(expired) != 0 || len(dropped) != 1 || dropped[0].Correlation != "a" {
+		t.Errorf("expected the group a to be dropped, got %+v %+v", expired, dropped)
+	}
+	if got := groups.Refs(); len(got) != 0 {
+		t.Errorf("expected no group, got %v", got)
+	}
+}
+
+func TestGroupsLimits(t *testing.T) {
+	groups := NewGroups(DefaultMaxGroupAge, 2)
+	groups.maxEvents = 2
+	ref := types.NamespacedName{Namespace: "ns", Name: "aggregator"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	incomplete := func(*Group) string { return "" }
+	completeWith := func(id string) func(*Group) string {
+		return func(g *Group) string {
+			if g.Events[len(g.Events)-1].ID() == id {
+				return CompletionCESQL
+			}
+			return ""
+		}
+	}
+	event := func(id string) cloudevents.Event {
+		e := cetest.MinEvent()
+		e.SetID(id)
+		return e
+	}
+
+	for _, correlation := range []string{"a", "b"} {
+		if _, _, err := groups.Add(ref, correlation, event("1"), now, 0, incomplete); err != nil {
+			t.Fatalf("unexpected error adding to the group %s: %v", correlation, err)
+		}
+	}
+	if _, _, err := groups.Add(ref, "c", event("1"), now, 0, incomplete); !errors.Is(err, ErrTooManyGroups) {
+		t.Errorf("expected ErrTooManyGroups, got %v", err)
+	}
+
+	if _, _, err := groups.Add(ref, "a", event("2"), now, 0, incomplete); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if _, _, err := groups.Add(ref, "a", event("3"), now, 0, incomplete); !errors.Is(err, ErrGroupFull) {
+		t.Errorf("expected ErrGroupFull, got %v", err)
+	}
+	// The event completing a full group is accepted.
+	g, reason, err := groups.Add(ref, "a", event("4"), now, 0, completeWith("4"))
+	if err != nil || reason != CompletionCESQL {
+		t.Fatalf("expected the group to be complete, got %q %v", reason, err)
+	}
+	if len(g.Events) != 3 || g.Events[1].ID() != "2" || g.Events[2].ID() != "4" {
+		t.Errorf("unexpected group %+v", g)
+	}
+
+	// The complete group made room for a new one.
+	if _, _, err := groups.Add(ref, "c", event("1"), now, 0, incomplete); err != nil {
+		t.Error("unexpected error:", err)
+	}
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/google/uuid"
+	"github.com/rickb777/date/period"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+	sinkslister "knative.dev/eventing/pkg/client/listers/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// CompletionExtension is the extension of the aggregate events holding the reason their group
+// completed: count, timeout or cesql.
+const CompletionExtension = "aggregatecompletion"
+
+// cesqlFilter is the parsed completion expression of an Aggregator.
+type cesqlFilter struct {
+	expression string
+	filter     eventfilter.Filter
+}
+
+// Handler receives the events of the Aggregators on POST /<namespace>/<name>, groups them by
+// the value of their correlation attribute, and sends an aggregate event with the events of a
+// group to the sink of its Aggregator once the group is complete.
+type Handler struct {
+	groups            *Groups
+	lister            sinkslister.AggregatorLister
+	dispatcher        *kncloudevents.Dispatcher
+	withContext       func(ctx context.Context) context.Context
+	oidcTokenVerifier *auth.OIDCTokenVerifier
+	now               func() time.Time
+
+	filtersMu sync.Mutex
+	filters   map[types.NamespacedName]cesqlFilter
+}
+
+func NewHandler(groups *Groups, lister sinkslister.AggregatorLister, dispatcher *kncloudevents.Dispatcher, oidcTokenVerifier *auth.OIDCTokenVerifier, withContext func(ctx context.Context) context.Context) *Handler {
+	return &Handler{
+		groups:            groups,
+		lister:            lister,
+		dispatcher:        dispatcher,
+		withContext:       withContext,
+		oidcTokenVerifier: oidcTokenVerifier,
+		now:               time.Now,
+		filters:           make(map[types.NamespacedName]cesqlFilter),
+	}
+}
+
+// healthzPath is the path of the endpoint probed by the kubelet.
+const healthzPath = "/healthz"
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := h.withContext(r.Context())
+	logger := logging.FromContext(ctx).Desugar()
+
+	if r.URL.Path == healthzPath {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 2 {
+		logger.Info("Malformed uri", zap.String("URI", r.RequestURI))
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ref := types.NamespacedName{
+		Namespace: parts[0],
+		Name:      parts[1],
+	}
+
+	a, err := h.lister.Aggregators(ref.Namespace).Get(ref.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		logger.Warn("Failed to retrieve the aggregator", zap.String("ref", ref.String()), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	features := feature.FromContext(ctx)
+	if features.IsOIDCAuthentication() {
+		audience := auth.GetAudienceDirect(sinksv1alpha1.SchemeGroupVersion.WithKind("Aggregator"), ref.Namespace, ref.Name)
+		if err := h.oidcTokenVerifier.VerifyRequest(ctx, features, &audience, ref.Namespace, a.Status.Policies, r, w); err != nil {
+			logger.Warn("Failed to verify AuthN and AuthZ", zap.Error(err))
+			return
+		}
+	}
+
+	message := cehttp.NewMessageFromHttpRequest(r)
+	defer message.Finish(nil)
+
+	e, err := binding.ToEvent(ctx, message)
+	if err != nil {
+		logger.Warn("failed to extract event from request", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := e.Validate(); err != nil {
+		logger.Info("failed to validate event from request", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	correlation, ok := correlationValue(*e, a.Spec.CorrelationAttribute)
+	if !ok {
+		http.Error(w, fmt.Sprintf("the event has no %s attribute", a.Spec.CorrelationAttribute), http.StatusBadRequest)
+		return
+	}
+
+	var timeout time.Duration
+	if a.Spec.Completion.Timeout != nil {
+		p, err := period.Parse(*a.Spec.Completion.Timeout)
+		if err != nil {
+			logger.Warn("Invalid timeout of the aggregator", zap.String("ref", ref.String()), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		timeout = p.DurationApprox()
+	}
+
+	filter, err := h.cesqlFilter(ref, a.Spec.Completion.CESQL)
+	if err != nil {
+		logger.Warn("Invalid CESQL expression of the aggregator", zap.String("ref", ref.String()), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	group, reason, err := h.groups.Add(ref, correlation, *e, h.now(), timeout, func(g *Group) string {
+		if count := a.Spec.Completion.Count; count != nil && int32(len(g.Events)) >= *count {
+			return CompletionCount
+		}
+		if filter != nil && filter.Filter(ctx, *e) == eventfilter.PassFilter {
+			return CompletionCESQL
+		}
+		return ""
+	})
+	if err != nil {
+		// The event is sent again by the sender, once groups complete.
+		logger.Info("Rejected the event", zap.String("ref", ref.String()), zap.String("correlation", correlation), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if group != nil {
+		if err := h.send(ctx, a, group, reason); err != nil {
+			logger.Warn("Failed to send the aggregate event", zap.String("ref", ref.String()), zap.String("correlation", correlation), zap.Error(err))
+			// The event is rejected and sent again by the sender, the others are kept in the
+			// group until it completes again.
+			group.Events = group.Events[:len(group.Events)-1]
+			h.groups.Restore(group)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Expire sends the aggregate events of the groups which timed out, and drops the groups of the
+// Aggregators which were deleted and the groups which didn't complete within the maximum age. The
+// groups which fail to be sent are kept and sent again the next time.
+func (h *Handler) Expire(ctx context.Context) {
+	logger := logging.FromContext(ctx).Desugar()
+
+	for _, ref := range h.groups.Refs() {
+		if _, err := h.lister.Aggregators(ref.Namespace).Get(ref.Name); apierrors.IsNotFound(err) {
+			h.groups.Delete(ref)
+			h.filtersMu.Lock()
+			delete(h.filters, ref)
+			h.filtersMu.Unlock()
+		}
+	}
+
+	expired, dropped := h.groups.Expire(h.now())
+	for _, group := range dropped {
+		logger.Warn("Dropping the group which didn't complete", zap.String("ref", group.Ref.String()), zap.String("correlation", group.Correlation), zap.Int("events", len(group.Events)))
+	}
+	for _, group := range expired {
+		a, err := h.lister.Aggregators(group.Ref.Namespace).Get(group.Ref.Name)
+		if err != nil {
+			logger.Warn("Failed to retrieve the aggregator", zap.String("ref", group.Ref.String()), zap.Error(err))
+			h.groups.Restore(group)
+			continue
+		}
+		if err := h.send(ctx, a, group, CompletionTimeout); err != nil {
+			logger.Warn("Failed to send the aggregate event", zap.String("ref", group.Ref.String()), zap.String("correlation", group.Correlation), zap.Error(err))
+			h.groups.Restore(group)
+		}
+	}
+}
+
+// cesqlFilter returns the parsed completion expression of an Aggregator, nil when it has none.
+func (h *Handler) cesqlFilter(ref types.NamespacedName, expression string) (eventfilter.Filter, error) {
+	if expression == "" {
+		return nil, nil
+	}
+
+	h.filtersMu.Lock()
+	defer h.filtersMu.Unlock()
+
+	if f, ok := h.filters[ref]; ok && f.expression == expression {
+		return f.filter, nil
+	}
+	filter, err := subscriptionsapi.NewCESQLFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	h.filters[ref] = cesqlFilter{expression: expression, filter: filter}
+	return filter, nil
+}
+
+// send sends the aggregate event of a complete group to the sink of its Aggregator.
+func (h *Handler) send(ctx context.Context, a *sinksv1alpha1.Aggregator, group *Group, reason string) error {
+	if a.Status.SinkURI == nil {
+		return fmt.Errorf("the sink of the aggregator isn't resolved")
+	}
+
+	e := cloudevents.NewEvent()
+	e.SetID(uuid.NewString())
+	e.SetType(a.Spec.Type)
+	e.SetSource(fmt.Sprintf("/apis/v1alpha1/namespaces/%s/aggregators/%s", a.Namespace, a.Name))
+	e.SetTime(h.now())
+	setCorrelationValue(&e, a.Spec.CorrelationAttribute, group.Correlation)
+	e.SetExtension(CompletionExtension, reason)
+	if err := e.SetData(cloudevents.ApplicationJSON, group.Events); err != nil {
+		return fmt.Errorf("failed to encode the events of the group: %w", err)
+	}
+
+	var opts []kncloudevents.SendOption
+	if a.Status.Auth != nil && a.Status.Auth.ServiceAccountName != nil {
+		opts = append(opts, kncloudevents.WithOIDCAuthentication(&types.NamespacedName{
+			Namespace: a.Namespace,
+			Name:      *a.Status.Auth.ServiceAccountName,
+		}))
+	}
+
+	_, err := h.dispatcher.SendEvent(ctx, e, duckv1.Addressable{
+		URL:      a.Status.SinkURI,
+		CACerts:  a.Status.SinkCACerts,
+		Audience: a.Status.SinkAudience,
+	}, opts...)
+	return err
+}
+
+// correlationValue returns the value of the correlation attribute of the event, which is an
+// extension or a context attribute.
+func correlationValue(e cloudevents.Event, attribute string) (string, bool) {
+	v, ok := attributes.LookupAttribute(e, attribute)
+	if !ok || v == nil {
+		return "", false
+	}
+	s, err := cetypes.Format(v)
+	if err != nil || s == "" {
+		return "", false
+	}
+	return s, true
+}
+
+// setCorrelationValue sets the correlation attribute on the aggregate event. The context
+// attributes other than subject describe the aggregate event itself and are left as they are.
+func setCorrelationValue(e *cloudevents.Event, attribute, value string) {
+	switch attribute {
+	case "subject":
+		e.SetSubject(value)
+	case "specversion", "type", "source", "id", "time", "dataschema", "schemaurl",
+		"datacontenttype", "datamediatype", "datacontentencoding":
+	default:
+		e.SetExtension(attribute, value)
+	}
+}
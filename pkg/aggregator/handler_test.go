@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/injection"
+
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+	sinkslister "knative.dev/eventing/pkg/client/listers/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/eventingtls"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	received := make(chan event.Event, 10)
+	var fail atomic.Bool
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Error("failed to read the aggregate event:", err)
+		} else {
+			received <- *e
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+	sinkURL, _ := apis.ParseURL(sink.URL)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	aggregator := &sinksv1alpha1.Aggregator{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "aggregator"},
+		Spec: sinksv1alpha1.AggregatorSpec{
+			CorrelationAttribute: "orderid",
+			Completion: sinksv1alpha1.AggregatorCompletion{
+				Count:   ptr.To[int32](3),
+				Timeout: ptr.To("PT1M"),
+				CESQL:   "type = 'order.closed'",
+			},
+			Type: "order.aggregate",
+		},
+		Status: sinksv1alpha1.AggregatorStatus{SinkURI: sinkURL},
+	}
+	_ = indexer.Add(aggregator)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewHandler(NewGroups(DefaultMaxGroupAge, DefaultMaxGroups), sinkslister.NewAggregatorLister(indexer),
+		kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx)),
+		nil, func(ctx context.Context) context.Context { return ctx })
+	h.now = func() time.Time { return now }
+
+	do := func(method, target string, body []byte) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		req.Header.Set("Content-Type", event.ApplicationCloudEventsJSON)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+	post := func(id, typ, orderID string) int {
+		t.Helper()
+		e := cetest.MinEvent()
+		e.SetID(id)
+		e.SetType(typ)
+		if orderID != "" {
+			e.SetExtension("orderid", orderID)
+		}
+		b, _ := json.Marshal(e)
+		return do(http.MethodPost, "/ns/aggregator", b).Code
+	}
+	expectAggregate := func(orderID, reason string, ids ...string) {
+		t.Helper()
+		var e event.Event
+		select {
+		case e = <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected an aggregate event")
+		}
+		if e.Type() != "order.aggregate" || e.Source() != "/apis/v1alpha1/namespaces/ns/aggregators/aggregator" {
+			t.Errorf("unexpected aggregate event attributes %s", e.Context)
+		}
+		if got := e.Extensions()["orderid"]; got != orderID {
+			t.Errorf("expected the correlation %s, got %v", orderID, got)
+		}
+		if got := e.Extensions()[CompletionExtension]; got != reason {
+			t.Errorf("expected the completion %s, got %v", reason, got)
+		}
+		var events []event.Event
+		if err := json.Unmarshal(e.Data(), &events); err != nil {
+			t.Fatal("failed to read the aggregated events:", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.ID())
+		}
+		if len(got) != len(ids) {
+			t.Fatalf("expected the events %v, got %v", ids, got)
+		}
+		for i := range ids {
+			if got[i] != ids[i] {
+				t.Fatalf("expected the events %v, got %v", ids, got)
+			}
+		}
+	}
+
+	if w := do(http.MethodGet, "/healthz", nil); w.Code != http.StatusOK {
+		t.Errorf("expected the health endpoint to be ok, got %d", w.Code)
+	}
+	if code := post("0", "order.created", ""); code != http.StatusBadRequest {
+		t.Errorf("expected the event without correlation to be rejected, got %d", code)
+	}
+	if w := do(http.MethodPost, "/ns/unknown", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected the unknown aggregator to be not found, got %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/ns/aggregator", nil); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET to be not allowed, got %d", w.Code)
+	}
+
+	// A group completes by count.
+	for _, id := range []string{"1", "2", "3"} {
+		if code := post(id, "order.created", "a"); code != http.StatusAccepted {
+			t.Fatalf("expected the event to be accepted, got %d", code)
+		}
+	}
+	expectAggregate("a", CompletionCount, "1", "2", "3")
+
+	// A group completes by CESQL expression.
+	post("4", "order.created", "b")
+	post("5", "order.closed", "b")
+	expectAggregate("b", CompletionCESQL, "4", "5")
+
+	// A group completes by timeout.
+	post("6", "order.created", "c")
+	h.Expire(ctx)
+	select {
+	case e := <-received:
+		t.Fatal("unexpected aggregate event", e)
+	default:
+	}
+	now = now.Add(2 * time.Minute)
+	h.Expire(ctx)
+	expectAggregate("c", CompletionTimeout, "6")
+
+	// A group which fails to be sent is kept, without the event rejected to the sender.
+	fail.Store(true)
+	post("7", "order.created", "d")
+	post("8", "order.created", "d")
+	if code := post("9", "order.created", "d"); code != http.StatusBadGateway {
+		t.Fatalf("expected the event to be rejected, got %d", code)
+	}
+	fail.Store(false)
+	if code := post("9", "order.created", "d"); code != http.StatusAccepted {
+		t.Fatalf("expected the event to be accepted, got %d", code)
+	}
+	expectAggregate("d", CompletionCount, "7", "8", "9")
+
+	// A group which timed out and fails to be sent is sent again.
+	fail.Store(true)
+	post("10", "order.created", "e")
+	now = now.Add(2 * time.Minute)
+	h.Expire(ctx)
+	fail.Store(false)
+	h.Expire(ctx)
+	expectAggregate("e", CompletionTimeout, "10")
+
+	// The events starting a group beyond the maximum number of groups are rejected.
+	h.groups.maxGroups = 1
+	post("11", "order.created", "f")
+	if code := post("12", "order.created", "g"); code != http.StatusTooManyRequests {
+		t.Errorf("expected the event to be rejected, got %d", code)
+	}
+	post("13", "order.closed", "f")
+	expectAggregate("f", CompletionCESQL, "11", "13")
+	h.groups.maxGroups = DefaultMaxGroups
+
+	// The groups of a deleted aggregator are dropped.
+	post("14", "order.created", "h")
+	_ = indexer.Delete(aggregator)
+	h.Expire(ctx)
+	if refs := h.groups.Refs(); len(refs) != 0 {
+		t.Errorf("expected the groups to be dropped, got %v", refs)
+	}
+}
@@ -25,4 +25,8 @@ func (ds *DeliverySpec) SetDefaults(ctx context.Context) {
 	if ds.DeadLetterSink != nil {
 		ds.DeadLetterSink.SetDefaults(ctx)
 	}
+	if ds.BackoffPolicy != nil && *ds.BackoffPolicy == BackoffPolicyExponentialJitter && ds.BackoffMax == nil {
+		backoffMax := DefaultBackoffMax
+		ds.BackoffMax = &backoffMax
+	}
 }
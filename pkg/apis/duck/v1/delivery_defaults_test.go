@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -51,6 +52,33 @@ func TestDeliverySpecSetDefaults(t *testing.T) {
 			given: &DeliverySpec{DeadLetterSink: &duckv1.Destination{}},
 			want:  &DeliverySpec{DeadLetterSink: &duckv1.Destination{}},
 		},
+		{
+			name:  "exponentialJitter backoffMax",
+			ctx:   context.Background(),
+			given: &DeliverySpec{BackoffPolicy: ptr.To(BackoffPolicyExponentialJitter)},
+			want: &DeliverySpec{
+				BackoffPolicy: ptr.To(BackoffPolicyExponentialJitter),
+				BackoffMax:    ptr.To(DefaultBackoffMax),
+			},
+		},
+		{
+			name: "exponentialJitter with backoffMax",
+			ctx:  context.Background(),
+			given: &DeliverySpec{
+				BackoffPolicy: ptr.To(BackoffPolicyExponentialJitter),
+				BackoffMax:    ptr.To("PT1H"),
+			},
+			want: &DeliverySpec{
+				BackoffPolicy: ptr.To(BackoffPolicyExponentialJitter),
+				BackoffMax:    ptr.To("PT1H"),
+			},
+		},
+		{
+			name:  "exponential without backoffMax",
+			ctx:   context.Background(),
+			given: &DeliverySpec{BackoffPolicy: ptr.To(BackoffPolicyExponential)},
+			want:  &DeliverySpec{BackoffPolicy: ptr.To(BackoffPolicyExponential)},
+		},
 		{
 			name: "deadLetterSink.ref.namespace empty string",
 			ctx:  apis.WithinParent(context.Background(), metav1.ObjectMeta{Name: "b", Namespace: "custom"}),
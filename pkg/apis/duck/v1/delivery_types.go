@@ -18,6 +18,7 @@ package v1
 
 import (
 	"context"
+	"regexp"
 
 	"github.com/rickb777/date/period"
 	"knative.dev/pkg/apis"
@@ -48,7 +49,7 @@ type DeliverySpec struct {
 	// +optional
 	Timeout *string `json:"timeout,omitempty"`
 
-	// BackoffPolicy is the retry backoff policy (linear, exponential).
+	// BackoffPolicy is the retry backoff policy (linear, exponential, exponentialJitter).
 	// +optional
 	BackoffPolicy *BackoffPolicyType `json:"backoffPolicy,omitempty"`
 
@@ -59,9 +60,21 @@ type DeliverySpec struct {
 	//
 	// For linear policy, backoff delay is backoffDelay*<numberOfRetries>.
 	// For exponential policy, backoff delay is backoffDelay*2^<numberOfRetries>.
+	// For exponentialJitter policy, backoff delay is a random delay between 0 and
+	// backoffDelay*2^<numberOfRetries>, so that the retries of the failed events are spread
+	// over time rather than sent all at once. Only full jitter is implemented, the delay is not
+	// decorrelated from the previous one.
 	// +optional
 	BackoffDelay *string `json:"backoffDelay,omitempty"`
 
+	// BackoffMax is the maximum delay before retrying, which caps the backoff delay of every
+	// backoff policy. Defaults to PT5M for the exponentialJitter policy.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	BackoffMax *string `json:"backoffMax,omitempty"`
+
 	// RetryAfterMax provides an optional upper bound on the duration specified in a "Retry-After" header
 	// when calculating backoff times for retrying 429 and 503 response codes.  Setting the value to
 	// zero ("PT0S") can be used to opt-out of respecting "Retry-After" header values altogether. This
@@ -82,6 +95,19 @@ type DeliverySpec struct {
 	// +optional
 	RetryAfterMax *string `json:"retryAfterMax,omitempty"`
 
+	// RetryOn is the list of the response status codes to retry, on top of the ones retried by
+	// default (5xx, 404, 408, 409 and 429). Each entry is either a status code, e.g. 400, or a
+	// class of status codes, e.g. 4xx.
+	// +optional
+	RetryOn []string `json:"retryOn,omitempty"`
+
+	// NoRetryOn is the list of the response status codes not to retry, which are sent to the
+	// dead letter sink right away. Each entry is either a status code, e.g. 503, or a class of
+	// status codes, e.g. 5xx. It takes precedence over RetryOn and the status codes retried by
+	// default.
+	// +optional
+	NoRetryOn []string `json:"noRetryOn,omitempty"`
+
 	// format specifies the desired event format for the cloud event.
 	// It can be one of the following values:
 	// - nil: default value, no specific format required.
@@ -89,8 +115,182 @@ type DeliverySpec struct {
 	// - "binary": indicates the event should be in binary mode.
 	//+optional
 	Format *FormatType `json:"format,omitempty"`
+
+	// DeadLetterFormat is the format of the events sent to the dead letter sink (lastAttempt,
+	// history). With lastAttempt, the knativeerrordest, knativeerrorcode and knativeerrordata
+	// extensions describe the last attempt to send the event. With history, the
+	// knativeerrorattempts, knativeerrorfirst, knativeerrorlast, knativeerrorhistory and
+	// knativeerrorresource extensions also describe every attempt to send the event and the
+	// Trigger or Subscription which failed to deliver it. Defaults to lastAttempt.
+	// +optional
+	DeadLetterFormat *DeadLetterFormatType `json:"deadLetterFormat,omitempty"`
+
+	// Ordering is the order in which the events are delivered (unordered, partitionKey).
+	// With partitionKey, the events sharing the value of the partition key attribute are
+	// delivered one after the other, in the order they are received, while the events with
+	// different values are delivered concurrently. The retries of an event are sent by the
+	// broker filter before the next event of the partition, only the delivery to the dead
+	// letter sink isn't ordered. The events without the partition key attribute aren't
+	// ordered. Defaults to unordered.
+	//
+	// Note: This API is EXPERIMENTAL and might be changed at anytime. It is only supported by
+	//       the Triggers of the MTChannelBasedBroker, whose filter replicas order the events
+	//       they receive independently. Brokers, Channels and Subscriptions reject it.
+	// +optional
+	Ordering *DeliveryOrderingType `json:"ordering,omitempty"`
+
+	// PartitionKeyAttribute is the name of the CloudEvent attribute holding the partition key
+	// of the events when the ordering is partitionKey. Defaults to the partitionkey extension.
+	// +optional
+	PartitionKeyAttribute *string `json:"partitionKeyAttribute,omitempty"`
+
+	// CircuitBreaker stops sending the events to the destination while too many of the
+	// requests to it fail, instead of retrying each of them.
+	//
+	// Note: This API is EXPERIMENTAL and might be changed at anytime. It is only supported by
+	//       the Triggers of the MTChannelBasedBroker, whose filter replicas track the failures
+	//       of the subscribers independently. The Triggers report it in their
+	//       SubscriberCircuitClosed condition. Brokers, Channels and Subscriptions reject it.
+	// +optional
+	CircuitBreaker *CircuitBreakerSpec `json:"circuitBreaker,omitempty"`
+
+	// MaxConcurrency is the maximum number of requests sent concurrently to the destination,
+	// including the retries. The limit applies to each replica of the dispatcher, and is shared by
+	// the requests to the same destination with the same limits.
+	// +optional
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
+
+	// RateLimit is the maximum rate of the requests sent to the destination, including the
+	// retries. The limit applies to each replica of the dispatcher, and is shared by the requests
+	// to the same destination with the same limits.
+	// +optional
+	RateLimit *RateLimitSpec `json:"rateLimit,omitempty"`
+}
+
+// RateLimitSpec is the token bucket rate limit of the requests sent to a destination.
+type RateLimitSpec struct {
+	// RequestsPerSecond is the number of requests sent to the destination per second.
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+
+	// Burst is the number of requests which can be sent to the destination at once, above the
+	// rate. Defaults to RequestsPerSecond.
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+func (rl *RateLimitSpec) Validate(ctx context.Context) *apis.FieldError {
+	if rl == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+
+	if rl.RequestsPerSecond < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(rl.RequestsPerSecond, "requestsPerSecond"))
+	}
+	if rl.Burst != nil && *rl.Burst < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*rl.Burst, "burst"))
+	}
+	return errs
 }
 
+// CircuitBreakerSpec configures when the circuit breaker of a destination opens, and what
+// happens to the events while it is open.
+type CircuitBreakerSpec struct {
+	// FailurePercentage is the percentage of the requests to the destination failing within
+	// the window which opens the circuit breaker. The failed requests are the ones without a
+	// response, or with a 429 or 5xx response. Defaults to 50.
+	// +optional
+	FailurePercentage *int32 `json:"failurePercentage,omitempty"`
+
+	// MinimumRequests is the minimum number of requests to the destination within the window
+	// for the circuit breaker to open. Defaults to 10.
+	// +optional
+	MinimumRequests *int32 `json:"minimumRequests,omitempty"`
+
+	// Window is the duration over which the failed requests are counted. Defaults to PT1M.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	Window *string `json:"window,omitempty"`
+
+	// OpenDuration is how long the circuit breaker stays open before a single request probes
+	// the destination. The circuit breaker closes when the probe succeeds, and opens again
+	// otherwise. Defaults to PT30S.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	OpenDuration *string `json:"openDuration,omitempty"`
+
+	// OpenAction is what happens to the events while the circuit breaker is open (deadLetter,
+	// fail). With deadLetter, the events are sent to the dead letter sink, or fail when there
+	// is none. With fail, the events fail without being sent anywhere. Defaults to deadLetter.
+	// +optional
+	OpenAction *CircuitBreakerOpenAction `json:"openAction,omitempty"`
+}
+
+func (cb *CircuitBreakerSpec) Validate(ctx context.Context) *apis.FieldError {
+	if cb == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+
+	if cb.FailurePercentage != nil && (*cb.FailurePercentage < 1 || *cb.FailurePercentage > 100) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*cb.FailurePercentage, 1, 100, "failurePercentage"))
+	}
+
+	if cb.MinimumRequests != nil && *cb.MinimumRequests < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*cb.MinimumRequests, "minimumRequests"))
+	}
+
+	if cb.Window != nil {
+		p, pe := period.Parse(*cb.Window)
+		if pe != nil || p.IsZero() || p.IsNegative() {
+			errs = errs.Also(apis.ErrInvalidValue(*cb.Window, "window"))
+		}
+	}
+
+	if cb.OpenDuration != nil {
+		p, pe := period.Parse(*cb.OpenDuration)
+		if pe != nil || p.IsZero() || p.IsNegative() {
+			errs = errs.Also(apis.ErrInvalidValue(*cb.OpenDuration, "openDuration"))
+		}
+	}
+
+	if cb.OpenAction != nil {
+		switch *cb.OpenAction {
+		case CircuitBreakerOpenActionDeadLetter, CircuitBreakerOpenActionFail:
+			// nothing
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(*cb.OpenAction, "openAction"))
+		}
+	}
+
+	return errs
+}
+
+// CircuitBreakerOpenAction is the type for the handling of the events while a circuit breaker is open
+type CircuitBreakerOpenAction string
+
+const (
+	// CircuitBreakerOpenActionDeadLetter sends the events to the dead letter sink.
+	CircuitBreakerOpenActionDeadLetter CircuitBreakerOpenAction = "deadLetter"
+
+	// CircuitBreakerOpenActionFail fails the events.
+	CircuitBreakerOpenActionFail CircuitBreakerOpenAction = "fail"
+)
+
+// DefaultPartitionKeyAttribute is the CloudEvent extension holding the partition key of the
+// events, as defined by the Partitioning extension of the CloudEvents specification.
+const DefaultPartitionKeyAttribute = "partitionkey"
+
+var validAttributeName = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// validStatusCode matches the status codes, e.g. 503, and the classes of status codes, e.g. 5xx,
+// of RetryOn and NoRetryOn.
+var validStatusCode = regexp.MustCompile(`^[1-5]([0-9]{2}|xx)$`)
+
 func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
 	if ds == nil {
 		return nil
@@ -117,7 +317,7 @@ func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
 
 	if ds.BackoffPolicy != nil {
 		switch *ds.BackoffPolicy {
-		case BackoffPolicyExponential, BackoffPolicyLinear:
+		case BackoffPolicyExponential, BackoffPolicyExponentialJitter, BackoffPolicyLinear:
 			// nothing
 		default:
 			errs = errs.Also(apis.ErrInvalidValue(*ds.BackoffPolicy, "backoffPolicy"))
@@ -131,6 +331,29 @@ func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
 		}
 	}
 
+	if ds.BackoffMax != nil {
+		m, me := period.Parse(*ds.BackoffMax)
+		if me != nil || m.IsZero() || m.IsNegative() {
+			errs = errs.Also(apis.ErrInvalidValue(*ds.BackoffMax, "backoffMax"))
+		} else if ds.BackoffDelay != nil {
+			if d, de := period.Parse(*ds.BackoffDelay); de == nil && m.DurationApprox() < d.DurationApprox() {
+				errs = errs.Also(apis.ErrGeneric("backoffMax must not be shorter than backoffDelay", "backoffMax"))
+			}
+		}
+	}
+
+	for i, code := range ds.RetryOn {
+		if !validStatusCode.MatchString(code) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(code, "retryOn", i))
+		}
+	}
+
+	for i, code := range ds.NoRetryOn {
+		if !validStatusCode.MatchString(code) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(code, "noRetryOn", i))
+		}
+	}
+
 	if ds.Format != nil {
 		switch *ds.Format {
 		case DeliveryFormatBinary, DeliveryFormatJson:
@@ -140,6 +363,44 @@ func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
 		}
 	}
 
+	if ds.DeadLetterFormat != nil {
+		switch *ds.DeadLetterFormat {
+		case DeadLetterFormatLastAttempt, DeadLetterFormatHistory:
+			// nothing
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(*ds.DeadLetterFormat, "deadLetterFormat"))
+		}
+	}
+
+	if ds.Ordering != nil {
+		switch *ds.Ordering {
+		case DeliveryOrderingUnordered, DeliveryOrderingPartitionKey:
+			// nothing
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(*ds.Ordering, "ordering"))
+		}
+	}
+
+	if ds.PartitionKeyAttribute != nil {
+		if ds.Ordering == nil || *ds.Ordering != DeliveryOrderingPartitionKey {
+			errs = errs.Also(apis.ErrGeneric("partitionKeyAttribute is only allowed with the partitionKey ordering", "partitionKeyAttribute"))
+		} else if !validAttributeName.MatchString(*ds.PartitionKeyAttribute) {
+			errs = errs.Also(apis.ErrInvalidValue(*ds.PartitionKeyAttribute, "partitionKeyAttribute"))
+		}
+	}
+
+	if cbe := ds.CircuitBreaker.Validate(ctx); cbe != nil {
+		errs = errs.Also(cbe).ViaField("circuitBreaker")
+	}
+
+	if ds.MaxConcurrency != nil && *ds.MaxConcurrency < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*ds.MaxConcurrency, "maxConcurrency"))
+	}
+
+	if rle := ds.RateLimit.Validate(ctx); rle != nil {
+		errs = errs.Also(rle.ViaField("rateLimit"))
+	}
+
 	if ds.RetryAfterMax != nil {
 		if feature.FromContext(ctx).IsEnabled(feature.DeliveryRetryAfter) {
 			p, me := period.Parse(*ds.RetryAfterMax)
@@ -163,6 +424,13 @@ const (
 
 	// Exponential backoff policy
 	BackoffPolicyExponential BackoffPolicyType = "exponential"
+
+	// Exponential backoff policy with full jitter. Decorrelated jitter isn't implemented.
+	BackoffPolicyExponentialJitter BackoffPolicyType = "exponentialJitter"
+
+	// DefaultBackoffMax is the maximum backoff delay of the exponentialJitter backoff policy
+	// when none is set.
+	DefaultBackoffMax = "PT5M"
 )
 
 // FormatType is the type for delivery format
@@ -173,6 +441,71 @@ const (
 	DeliveryFormatBinary FormatType = "binary"
 )
 
+// DeadLetterFormatType is the type for the formats of the events sent to the dead letter sink
+type DeadLetterFormatType string
+
+const (
+	// DeadLetterFormatLastAttempt describes the last attempt to send the event.
+	DeadLetterFormatLastAttempt DeadLetterFormatType = "lastAttempt"
+
+	// DeadLetterFormatHistory describes every attempt to send the event.
+	DeadLetterFormatHistory DeadLetterFormatType = "history"
+)
+
+// FailureHistory returns whether the events sent to the dead letter sink describe every attempt
+// to send them.
+func (ds *DeliverySpec) FailureHistory() bool {
+	return ds != nil && ds.DeadLetterFormat != nil && *ds.DeadLetterFormat == DeadLetterFormatHistory
+}
+
+// DeliveryOrderingType is the type for delivery orderings
+type DeliveryOrderingType string
+
+const (
+	// DeliveryOrderingUnordered delivers the events concurrently.
+	DeliveryOrderingUnordered DeliveryOrderingType = "unordered"
+
+	// DeliveryOrderingPartitionKey delivers the events sharing a partition key one after the other.
+	DeliveryOrderingPartitionKey DeliveryOrderingType = "partitionKey"
+)
+
+// PartitionKey returns the name of the attribute holding the partition key of the events when
+// the ordering of the delivery is partitionKey, or an empty string when the events aren't ordered.
+func (ds *DeliverySpec) PartitionKey() string {
+	if ds == nil || ds.Ordering == nil || *ds.Ordering != DeliveryOrderingPartitionKey {
+		return ""
+	}
+	if ds.PartitionKeyAttribute != nil {
+		return *ds.PartitionKeyAttribute
+	}
+	return DefaultPartitionKeyAttribute
+}
+
+// ValidateUnordered rejects the ordering of the delivery, for the resources which deliver their
+// events concurrently.
+func (ds *DeliverySpec) ValidateUnordered() *apis.FieldError {
+	if ds == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if ds.Ordering != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("ordering"))
+	}
+	if ds.PartitionKeyAttribute != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("partitionKeyAttribute"))
+	}
+	return errs
+}
+
+// ValidateWithoutCircuitBreaker rejects the circuit breaker of the delivery, for the resources
+// whose dispatchers don't support it. Only the Triggers of the MTChannelBasedBroker do.
+func (ds *DeliverySpec) ValidateWithoutCircuitBreaker() *apis.FieldError {
+	if ds == nil || ds.CircuitBreaker == nil {
+		return nil
+	}
+	return apis.ErrDisallowedFields("circuitBreaker")
+}
+
 // DeliveryStatus contains the Status of an object supporting delivery options. This type is intended to be embedded into a status struct.
 type DeliveryStatus struct {
 	// DeadLetterSink is a KReference that is the reference to the native, platform specific channel
@@ -98,6 +98,50 @@ func TestDeliverySpecValidation(t *testing.T) {
 		want: func() *apis.FieldError {
 			return apis.ErrInvalidValue(invalidDuration, "backoffDelay")
 		}(),
+	}, {
+		name: "valid exponentialJitter backoffPolicy",
+		spec: &DeliverySpec{BackoffPolicy: ptr.To(BackoffPolicyExponentialJitter)},
+		want: nil,
+	}, {
+		name: "invalid backoffPolicy",
+		spec: &DeliverySpec{BackoffPolicy: ptr.To(BackoffPolicyType("jitter"))},
+		want: apis.ErrInvalidValue("jitter", "backoffPolicy"),
+	}, {
+		name: "valid backoffMax",
+		spec: &DeliverySpec{BackoffDelay: &validDuration, BackoffMax: pointer.String("PT1M")},
+		want: nil,
+	}, {
+		name: "invalid backoffMax",
+		spec: &DeliverySpec{BackoffMax: &invalidDuration},
+		want: apis.ErrInvalidValue(invalidDuration, "backoffMax"),
+	}, {
+		name: "zero backoffMax",
+		spec: &DeliverySpec{BackoffMax: pointer.String("PT0S")},
+		want: apis.ErrInvalidValue("PT0S", "backoffMax"),
+	}, {
+		name: "backoffMax shorter than backoffDelay",
+		spec: &DeliverySpec{BackoffDelay: pointer.String("PT1M"), BackoffMax: pointer.String("PT10S")},
+		want: apis.ErrGeneric("backoffMax must not be shorter than backoffDelay", "backoffMax"),
+	}, {
+		name: "valid retryOn and noRetryOn",
+		spec: &DeliverySpec{RetryOn: []string{"400", "4xx"}, NoRetryOn: []string{"503"}},
+		want: nil,
+	}, {
+		name: "invalid retryOn",
+		spec: &DeliverySpec{RetryOn: []string{"400", "40x", "600"}},
+		want: apis.ErrInvalidArrayValue("40x", "retryOn", 1).Also(apis.ErrInvalidArrayValue("600", "retryOn", 2)),
+	}, {
+		name: "invalid noRetryOn",
+		spec: &DeliverySpec{NoRetryOn: []string{"5XX"}},
+		want: apis.ErrInvalidArrayValue("5XX", "noRetryOn", 0),
+	}, {
+		name: "valid deadLetterFormat",
+		spec: &DeliverySpec{DeadLetterFormat: ptr.To(DeadLetterFormatHistory)},
+		want: nil,
+	}, {
+		name: "invalid deadLetterFormat",
+		spec: &DeliverySpec{DeadLetterFormat: ptr.To(DeadLetterFormatType("envelope"))},
+		want: apis.ErrInvalidValue("envelope", "deadLetterFormat"),
 	}, {
 		name: "negative retry",
 		spec: &DeliverySpec{Retry: pointer.Int32(-1)},
@@ -158,6 +202,80 @@ func TestDeliverySpecValidation(t *testing.T) {
 			want: func() *apis.FieldError {
 				return apis.ErrInvalidValue("invalid", "format")
 			}(),
+		}, {
+			name: "valid ordering",
+			spec: &DeliverySpec{Ordering: ptr.To(DeliveryOrderingPartitionKey)},
+		}, {
+			name: "invalid ordering",
+			spec: &DeliverySpec{Ordering: ptr.To(DeliveryOrderingType("strict"))},
+			want: apis.ErrInvalidValue("strict", "ordering"),
+		}, {
+			name: "valid partition key attribute",
+			spec: &DeliverySpec{
+				Ordering:              ptr.To(DeliveryOrderingPartitionKey),
+				PartitionKeyAttribute: ptr.To("subject"),
+			},
+		}, {
+			name: "invalid partition key attribute",
+			spec: &DeliverySpec{
+				Ordering:              ptr.To(DeliveryOrderingPartitionKey),
+				PartitionKeyAttribute: ptr.To("Subject"),
+			},
+			want: apis.ErrInvalidValue("Subject", "partitionKeyAttribute"),
+		}, {
+			name: "partition key attribute without ordering",
+			spec: &DeliverySpec{
+				Ordering:              ptr.To(DeliveryOrderingUnordered),
+				PartitionKeyAttribute: ptr.To("subject"),
+			},
+			want: apis.ErrGeneric("partitionKeyAttribute is only allowed with the partitionKey ordering", "partitionKeyAttribute"),
+		}, {
+			name: "valid circuit breaker",
+			spec: &DeliverySpec{CircuitBreaker: &CircuitBreakerSpec{
+				FailurePercentage: ptr.To[int32](20),
+				MinimumRequests:   ptr.To[int32](5),
+				Window:            ptr.To("PT10S"),
+				OpenDuration:      ptr.To("PT1M"),
+				OpenAction:        ptr.To(CircuitBreakerOpenActionFail),
+			}},
+		}, {
+			name: "empty circuit breaker",
+			spec: &DeliverySpec{CircuitBreaker: &CircuitBreakerSpec{}},
+		}, {
+			name: "invalid circuit breaker",
+			spec: &DeliverySpec{CircuitBreaker: &CircuitBreakerSpec{
+				FailurePercentage: ptr.To[int32](101),
+				MinimumRequests:   ptr.To[int32](0),
+				Window:            ptr.To("PT0S"),
+				OpenDuration:      ptr.To("soon"),
+				OpenAction:        ptr.To(CircuitBreakerOpenAction("drop")),
+			}},
+			want: apis.ErrOutOfBoundsValue(101, 1, 100, "circuitBreaker.failurePercentage").
+				Also(apis.ErrInvalidValue(0, "circuitBreaker.minimumRequests")).
+				Also(apis.ErrInvalidValue("PT0S", "circuitBreaker.window")).
+				Also(apis.ErrInvalidValue("soon", "circuitBreaker.openDuration")).
+				Also(apis.ErrInvalidValue("drop", "circuitBreaker.openAction")),
+		}, {
+			name: "valid throttling",
+			spec: &DeliverySpec{
+				MaxConcurrency: ptr.To[int32](5),
+				RateLimit: &RateLimitSpec{
+					RequestsPerSecond: 10,
+					Burst:             ptr.To[int32](20),
+				},
+			},
+		}, {
+			name: "invalid throttling",
+			spec: &DeliverySpec{
+				MaxConcurrency: ptr.To[int32](0),
+				RateLimit: &RateLimitSpec{
+					RequestsPerSecond: 0,
+					Burst:             ptr.To[int32](-1),
+				},
+			},
+			want: apis.ErrInvalidValue(0, "maxConcurrency").
+				Also(apis.ErrInvalidValue(0, "rateLimit.requestsPerSecond")).
+				Also(apis.ErrInvalidValue(-1, "rateLimit.burst")),
 		}}
 
 	for _, test := range tests {
@@ -173,3 +291,63 @@ func TestDeliverySpecValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestDeliverySpecPartitionKey(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *DeliverySpec
+		want string
+	}{{
+		name: "nil",
+	}, {
+		name: "unordered",
+		spec: &DeliverySpec{Ordering: ptr.To(DeliveryOrderingUnordered)},
+	}, {
+		name: "default attribute",
+		spec: &DeliverySpec{Ordering: ptr.To(DeliveryOrderingPartitionKey)},
+		want: DefaultPartitionKeyAttribute,
+	}, {
+		name: "custom attribute",
+		spec: &DeliverySpec{
+			Ordering:              ptr.To(DeliveryOrderingPartitionKey),
+			PartitionKeyAttribute: ptr.To("subject"),
+		},
+		want: "subject",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.spec.PartitionKey(); got != test.want {
+				t.Errorf("PartitionKey() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDeliverySpecFailureHistory(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *DeliverySpec
+		want bool
+	}{{
+		name: "nil",
+	}, {
+		name: "default format",
+		spec: &DeliverySpec{},
+	}, {
+		name: "lastAttempt",
+		spec: &DeliverySpec{DeadLetterFormat: ptr.To(DeadLetterFormatLastAttempt)},
+	}, {
+		name: "history",
+		spec: &DeliverySpec{DeadLetterFormat: ptr.To(DeadLetterFormatHistory)},
+		want: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.spec.FailureHistory(); got != test.want {
+				t.Errorf("FailureHistory() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -66,6 +66,10 @@ type SubscriberSpec struct {
 	// ReplyAudience is the OIDC audience for the replyUri.
 	// +optional
 	ReplyAudience *string `json:"replyAudience,omitempty"`
+	// ReplyRoutes are evaluated in order against the events returned by the
+	// subscriber, and the first matching route overrides the replyUri.
+	// +optional
+	ReplyRoutes []SubscriberReplyRoute `json:"replyRoutes,omitempty"`
 	// +optional
 	// DeliverySpec contains options controlling the event delivery
 	// +optional
@@ -75,6 +79,25 @@ type SubscriberSpec struct {
 	Auth *duckv1.AuthStatus `json:"auth,omitempty"`
 }
 
+// SubscriberReplyRoute is a resolved conditional reply destination of a
+// subscriber.
+type SubscriberReplyRoute struct {
+	// Filters are the SubscriptionsAPI filters a reply event must match to be
+	// sent to this route. They are kept raw as the filter dialects are
+	// defined by the eventing API group.
+	// +optional
+	Filters []runtime.RawExtension `json:"filters,omitempty"`
+	// URI is the endpoint matching replies are sent to.
+	URI *apis.URL `json:"uri"`
+	// CACerts is the Certification Authority (CA) certificates in PEM
+	// format according to https://www.rfc-editor.org/rfc/rfc7468 for the uri.
+	// +optional
+	CACerts *string `json:"CACerts,omitempty"`
+	// Audience is the OIDC audience for the uri.
+	// +optional
+	Audience *string `json:"audience,omitempty"`
+}
+
 // SubscriberStatus defines the status of a single subscriber to a Channel.
 type SubscriberStatus struct {
 	// UID is used to understand the origin of the subscriber.
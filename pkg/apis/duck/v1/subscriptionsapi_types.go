@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// SubscriptionsAPIFilter allows defining a filter expression using CloudEvents
+// Subscriptions API. If multiple filters are specified, then the same semantics
+// of SubscriptionsAPIFilter.All is applied. If no filter dialect or empty
+// object is specified, then the filter always accept the events.
+type SubscriptionsAPIFilter struct {
+	// All evaluates to true if all the nested expressions evaluate to true.
+	// It must contain at least one filter expression.
+	//
+	// +optional
+	All []SubscriptionsAPIFilter `json:"all,omitempty"`
+
+	// Any evaluates to true if at least one of the nested expressions evaluates
+	// to true. It must contain at least one filter expression.
+	//
+	// +optional
+	Any []SubscriptionsAPIFilter `json:"any,omitempty"`
+
+	// Not evaluates to true if the nested expression evaluates to false.
+	//
+	// +optional
+	Not *SubscriptionsAPIFilter `json:"not,omitempty"`
+
+	// Exact evaluates to true if the values of the matching CloudEvents attributes MUST
+	// all exactly match with the associated value String specified (case-sensitive).
+	// The keys are the names of the CloudEvents attributes to be matched,
+	// and their values are the String values to use in the comparison.
+	// The attribute name and value specified in the filter express MUST NOT be
+	// empty strings.
+	//
+	// +optional
+	Exact map[string]string `json:"exact,omitempty"`
+
+	// Prefix evaluates to true if the values of the matching CloudEvents attributes MUST
+	// all start with the associated value String specified (case sensitive).
+	// The keys are the names of the CloudEvents attributes to be matched,
+	// and their values are the String values to use in the comparison.
+	// The attribute name and value specified in the filter express MUST NOT be
+	// empty strings.
+	//
+	// +optional
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Suffix evaluates to true if the values of the matching CloudEvents attributes MUST
+	// all end with the associated value String specified (case sensitive).
+	// The keys are the names of the CloudEvents attributes to be matched,
+	// and their values are the String values to use in the comparison.
+	// The attribute name and value specified in the filter express MUST NOT be
+	// empty strings.
+	//
+	// +optional
+	Suffix map[string]string `json:"suffix,omitempty"`
+
+	// Regex evaluates to true if the values of the matching CloudEvents attributes MUST
+	// all match the associated regular expression (RE2 syntax, not anchored).
+	// The keys are the names of the CloudEvents attributes to be matched,
+	// and their values are the regular expressions to use in the comparison.
+	// The attribute name and expression specified in the filter express MUST NOT be
+	// empty strings.
+	//
+	// +optional
+	Regex map[string]string `json:"regex,omitempty"`
+
+	// Range evaluates to true if the values of the matching CloudEvents attributes MUST
+	// all be numbers within the associated bounds (inclusive).
+	// The keys are the names of the CloudEvents attributes to be matched,
+	// and their values are the bounds to use in the comparison.
+	// The attribute name specified in the filter express MUST NOT be an empty string
+	// and at least one of the bounds MUST be set.
+	//
+	// +optional
+	Range map[string]SubscriptionsAPIFilterRange `json:"range,omitempty"`
+
+	// Data evaluates to true if the value selected by the JSONPath expression in the
+	// event payload satisfies the associated operator. The payload MUST be JSON
+	// (a datacontenttype of application/json or with a +json suffix), otherwise
+	// the expression evaluates to false regardless of the operator.
+	//
+	// +optional
+	Data *SubscriptionsAPIDataFilter `json:"data,omitempty"`
+
+	// CESQL is a CloudEvents SQL expression that will be evaluated to true or false against each CloudEvent.
+	//
+	// +optional
+	CESQL string `json:"cesql,omitempty"`
+}
+
+// SubscriptionsAPIDataFilter selects a value of the event payload and compares it with
+// exactly one of the operators.
+type SubscriptionsAPIDataFilter struct {
+	// Path is a JSONPath expression selecting a single value of the payload, made
+	// of member names and array indexes, e.g. `$.order.region` or `$.items[0]['sku']`.
+	Path string `json:"path"`
+
+	// Exact evaluates to true if the selected value is a string, number or boolean
+	// whose representation exactly matches the String specified (case-sensitive).
+	//
+	// +optional
+	Exact *string `json:"exact,omitempty"`
+
+	// Prefix evaluates to true if the selected value is a string, number or boolean
+	// whose representation starts with the String specified (case-sensitive).
+	// It MUST NOT be an empty string.
+	//
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+
+	// Exists evaluates to true if the presence of the selected value in the payload,
+	// even when it is null, is the one specified.
+	//
+	// +optional
+	Exists *bool `json:"exists,omitempty"`
+}
+
+// SubscriptionsAPIFilterRange is an inclusive numeric range used by the Range dialect.
+type SubscriptionsAPIFilterRange struct {
+	// Min is the lowest value matching the range. If not set, the range has no lower bound.
+	//
+	// +optional
+	Min *int64 `json:"min,omitempty"`
+
+	// Max is the highest value matching the range. If not set, the range has no upper bound.
+	//
+	// +optional
+	Max *int64 `json:"max,omitempty"`
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	cesqlparser "github.com/cloudevents/sdk-go/sql/v2/parser"
+	"go.uber.org/zap"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	"knative.dev/eventing/pkg/eventfilter/jsonpath"
+)
+
+// Only allow lowercase alphanumeric, starting with letters.
+var validFilterAttributeName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+func ValidateAttributesNames(attrs map[string]string) (errs *apis.FieldError) {
+	for attr := range attrs {
+		if !validFilterAttributeName.MatchString(attr) {
+			errs = errs.Also(apis.ErrInvalidKeyName(attr, apis.CurrentField, "Attribute name must start with a letter and can only contain lowercase alphanumeric").ViaKey(attr))
+		}
+	}
+	return errs
+}
+
+func ValidateSubscriptionAPIFiltersList(ctx context.Context, filters []SubscriptionsAPIFilter) (errs *apis.FieldError) {
+	if filters == nil || !feature.FromContext(ctx).IsEnabled(feature.NewTriggerFilters) {
+		return nil
+	}
+
+	for i, f := range filters {
+		f := f
+		errs = errs.Also(ValidateSubscriptionAPIFilter(ctx, &f)).ViaIndex(i)
+	}
+	return errs
+}
+
+func ValidateCESQLExpression(ctx context.Context, expression string) (errs *apis.FieldError) {
+	if expression == "" {
+		return nil
+	}
+	// Need to recover in case Parse panics
+	defer func() {
+		if r := recover(); r != nil {
+			logging.FromContext(ctx).Debug("Warning! Calling CESQL Parser panicked. Treating expression as invalid.", zap.Any("recovered value", r), zap.String("CESQL", expression))
+			errs = apis.ErrInvalidValue(expression, apis.CurrentField)
+		}
+	}()
+
+	if _, err := cesqlparser.Parse(expression); err != nil {
+		return apis.ErrInvalidValue(expression, apis.CurrentField, err.Error())
+	}
+	return nil
+}
+
+func ValidateRegexExpressions(expressions map[string]string) (errs *apis.FieldError) {
+	errs = ValidateAttributesNames(expressions)
+	for attr, expression := range expressions {
+		if expression == "" {
+			errs = errs.Also(apis.ErrInvalidValue(expression, apis.CurrentField, "regular expression must not be empty").ViaKey(attr))
+			continue
+		}
+		if _, err := regexp.Compile(expression); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(expression, apis.CurrentField, err.Error()).ViaKey(attr))
+		}
+	}
+	return errs
+}
+
+func ValidateRanges(ranges map[string]SubscriptionsAPIFilterRange) (errs *apis.FieldError) {
+	for attr, r := range ranges {
+		if !validFilterAttributeName.MatchString(attr) {
+			errs = errs.Also(apis.ErrInvalidKeyName(attr, apis.CurrentField, "Attribute name must start with a letter and can only contain lowercase alphanumeric").ViaKey(attr))
+		}
+		if r.Min == nil && r.Max == nil {
+			errs = errs.Also(apis.ErrMissingOneOf("min", "max").ViaKey(attr))
+		} else if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			errs = errs.Also(apis.ErrInvalidValue(*r.Max, "max", fmt.Sprintf("max must be greater than or equal to min (%d)", *r.Min)).ViaKey(attr))
+		}
+	}
+	return errs
+}
+
+func ValidateDataFilter(filter *SubscriptionsAPIDataFilter) (errs *apis.FieldError) {
+	if filter == nil {
+		return nil
+	}
+	if filter.Path == "" {
+		errs = errs.Also(apis.ErrMissingField("path"))
+	} else if _, err := jsonpath.Compile(filter.Path); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(filter.Path, "path", err.Error()))
+	}
+
+	var operators []string
+	if filter.Exact != nil {
+		operators = append(operators, "exact")
+	}
+	if filter.Prefix != nil {
+		operators = append(operators, "prefix")
+		if *filter.Prefix == "" {
+			errs = errs.Also(apis.ErrInvalidValue(*filter.Prefix, "prefix", "prefix must not be empty"))
+		}
+	}
+	if filter.Exists != nil {
+		operators = append(operators, "exists")
+	}
+	switch len(operators) {
+	case 0:
+		errs = errs.Also(apis.ErrMissingOneOf("exact", "prefix", "exists"))
+	case 1:
+	default:
+		errs = errs.Also(apis.ErrMultipleOneOf(operators...))
+	}
+	return errs
+}
+
+func ValidateSubscriptionAPIFilter(ctx context.Context, filter *SubscriptionsAPIFilter) (errs *apis.FieldError) {
+	if filter == nil {
+		return nil
+	}
+	errs = errs.Also(
+		ValidateOneOf(filter),
+	).Also(
+		ValidateAttributesNames(filter.Exact).ViaField("exact"),
+	).Also(
+		ValidateAttributesNames(filter.Prefix).ViaField("prefix"),
+	).Also(
+		ValidateAttributesNames(filter.Suffix).ViaField("suffix"),
+	).Also(
+		ValidateRegexExpressions(filter.Regex).ViaField("regex"),
+	).Also(
+		ValidateRanges(filter.Range).ViaField("range"),
+	).Also(
+		ValidateDataFilter(filter.Data).ViaField("data"),
+	).Also(
+		ValidateSubscriptionAPIFiltersList(ctx, filter.All).ViaField("all"),
+	).Also(
+		ValidateSubscriptionAPIFiltersList(ctx, filter.Any).ViaField("any"),
+	).Also(
+		ValidateSubscriptionAPIFilter(ctx, filter.Not).ViaField("not"),
+	).Also(
+		ValidateCESQLExpression(ctx, filter.CESQL).ViaField("cesql"),
+	)
+	return errs
+}
+
+func ValidateOneOf(filter *SubscriptionsAPIFilter) (err *apis.FieldError) {
+	if filter != nil && hasMultipleDialects(filter) {
+		return apis.ErrGeneric("multiple dialects found, filters can have only one dialect set")
+	}
+	return nil
+}
+
+func hasMultipleDialects(filter *SubscriptionsAPIFilter) bool {
+	dialectFound := false
+	if len(filter.Exact) > 0 {
+		dialectFound = true
+	}
+	if len(filter.Prefix) > 0 {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if len(filter.Suffix) > 0 {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if len(filter.Regex) > 0 {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if len(filter.Range) > 0 {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if filter.Data != nil {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if len(filter.All) > 0 {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if len(filter.Any) > 0 {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if filter.Not != nil {
+		if dialectFound {
+			return true
+		} else {
+			dialectFound = true
+		}
+	}
+	if filter.CESQL != "" && dialectFound {
+		return true
+	}
+	return false
+}
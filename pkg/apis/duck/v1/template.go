@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/template"
+	"text/template/parse"
+)
+
+// ErrTemplateOutputTooLarge is returned when the expansion of a template exceeds its maximum size.
+var ErrTemplateOutputTooLarge = errors.New("the expanded template exceeds its maximum size")
+
+// ParseSubstitutionTemplate parses the text into tmpl and checks that it only substitutes values:
+// its actions are a field, key or method chain on the data, or on $, with constant arguments.
+// Functions, pipelines, variables declarations and control structures like range, with or
+// template are rejected, so that the expansion takes a time bounded by the size of the text.
+func ParseSubstitutionTemplate(tmpl *template.Template, text string) (*template.Template, error) {
+	tmpl, err := tmpl.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	// The templates defined in the text can't be executed without a template action, they are
+	// checked all the same.
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if err := checkSubstitutionNode(t.Tree.Root); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+func checkSubstitutionNode(node parse.Node) error {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		for _, n := range node.Nodes {
+			if err := checkSubstitutionNode(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.TextNode, *parse.CommentNode:
+		return nil
+	case *parse.ActionNode:
+		if err := checkSubstitutionPipe(node.Pipe); err != nil {
+			return fmt.Errorf("%s: %w", node, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: only the substitution of values is supported", node)
+	}
+}
+
+func checkSubstitutionPipe(pipe *parse.PipeNode) error {
+	if len(pipe.Decl) > 0 {
+		return errors.New("variables can't be declared")
+	}
+	if len(pipe.Cmds) != 1 {
+		return errors.New("pipelines aren't supported")
+	}
+	for i, arg := range pipe.Cmds[0].Args {
+		switch arg := arg.(type) {
+		case *parse.FieldNode, *parse.DotNode:
+		case *parse.VariableNode:
+			if arg.Ident[0] != "$" {
+				return fmt.Errorf("unknown variable %s", arg.Ident[0])
+			}
+		case *parse.StringNode, *parse.NumberNode, *parse.BoolNode, *parse.NilNode:
+			continue
+		default:
+			return fmt.Errorf("%s isn't a value or a constant", arg)
+		}
+		if i > 0 {
+			return fmt.Errorf("%s: only constants can be passed as arguments", arg)
+		}
+	}
+	return nil
+}
+
+// ExecuteTemplate expands the template with the data. It fails without expanding the whole
+// template once the expansion exceeds maxSize bytes.
+func ExecuteTemplate(tmpl *template.Template, data interface{}, maxSize int64) ([]byte, error) {
+	w := &cappedWriter{max: maxSize}
+	if err := tmpl.Execute(w, data); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// cappedWriter buffers up to max bytes.
+// +k8s:deepcopy-gen=false
+type cappedWriter struct {
+	buf bytes.Buffer
+	max int64
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if int64(w.buf.Len()+len(p)) > w.max {
+		return 0, fmt.Errorf("%w of %d bytes", ErrTemplateOutputTooLarge, w.max)
+	}
+	return w.buf.Write(p)
+}
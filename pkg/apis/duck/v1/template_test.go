@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestParseSubstitutionTemplate(t *testing.T) {
+	data := map[string]interface{}{
+		"Name": "name",
+		"Time": time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	tests := map[string]struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		"text":                     {text: "text", want: "text"},
+		"keys":                     {text: `{{ .Name }}-{{ $.Name }}`, want: "name-name"},
+		"method with constants":    {text: `{{ .Time.Format "15:04" }}`, want: "12:00"},
+		"comment":                  {text: `{{ .Name }}{{/* comment */}}`, want: "name"},
+		"range":                    {text: `{{ range 9223372036854775807 }}{{ end }}`, wantErr: true},
+		"if":                       {text: `{{ if .Name }}x{{ end }}`, wantErr: true},
+		"with":                     {text: `{{ with .Name }}{{ . }}{{ end }}`, wantErr: true},
+		"template":                 {text: `{{ define "t" }}x{{ end }}{{ template "t" }}`, wantErr: true},
+		"define":                   {text: `{{ define "t" }}{{ range 1 }}{{ end }}{{ end }}`, wantErr: true},
+		"function":                 {text: `{{ printf "%099999999d" 1 }}`, wantErr: true},
+		"pipeline":                 {text: `{{ .Name | print }}`, wantErr: true},
+		"declaration":              {text: `{{ $x := .Name }}`, wantErr: true},
+		"value passed as argument": {text: `{{ .Time.Format .Name }}`, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := ParseSubstitutionTemplate(template.New(name), tc.text)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("ParseSubstitutionTemplate(%q) succeeded, want an error", tc.text)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSubstitutionTemplate(%q) = %v", tc.text, err)
+			}
+			got, err := ExecuteTemplate(tmpl, data, 64)
+			if err != nil {
+				t.Fatal("ExecuteTemplate() =", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("ExecuteTemplate() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExecuteTemplateMaxSize(t *testing.T) {
+	tmpl, err := ParseSubstitutionTemplate(template.New("t"), "{{ . }}{{ . }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExecuteTemplate(tmpl, "12345", 9); !errors.Is(err, ErrTemplateOutputTooLarge) {
+		t.Errorf("ExecuteTemplate() = %v, want %v", err, ErrTemplateOutputTooLarge)
+	}
+	if got, err := ExecuteTemplate(tmpl, "12345", 10); err != nil || string(got) != "1234512345" {
+		t.Errorf("ExecuteTemplate() = %q, %v", got, err)
+	}
+}
@@ -89,6 +89,21 @@ const (
 	// annotation key used to specify the namespace of the channel for
 	// the triggers to subscribe to.
 	BrokerChannelNamespaceStatusAnnotationKey = "knative.dev/channelNamespace"
+
+	// BrokerIngressRateLimitStatusAnnotationKey is the broker status
+	// annotation key used to specify the JSON encoded rate limit the
+	// ingress applies to the events sent to the broker.
+	BrokerIngressRateLimitStatusAnnotationKey = "knative.dev/ingressRateLimit"
+
+	// BrokerIngressDeduplicationStatusAnnotationKey is the broker status
+	// annotation key used to specify the JSON encoded deduplication the
+	// ingress applies to the events sent to the broker.
+	BrokerIngressDeduplicationStatusAnnotationKey = "knative.dev/ingressDeduplication"
+
+	// BrokerIngressSchedulingStatusAnnotationKey is the broker status
+	// annotation key used to specify the JSON encoded scheduling the
+	// ingress applies to the events sent to the broker.
+	BrokerIngressSchedulingStatusAnnotationKey = "knative.dev/ingressScheduling"
 )
 
 var (
@@ -29,6 +29,22 @@ import (
 
 const (
 	BrokerClassAnnotationKey = "eventing.knative.dev/broker.class"
+
+	// BrokerEventValidationAnnotationKey is the annotation key on Brokers enabling the validation
+	// of the incoming events against the attribute definitions of their EventTypes.
+	BrokerEventValidationAnnotationKey = "eventing.knative.dev/event-validation"
+	// BrokerEventValidationReject rejects the events which don't conform to their EventType.
+	BrokerEventValidationReject = "reject"
+	// BrokerEventValidationDeadLetter sends the events which don't conform to their EventType to
+	// the dead letter sink of the Broker.
+	BrokerEventValidationDeadLetter = "dead-letter"
+
+	// BrokerDispatchModeAnnotationKey is the annotation key on Brokers selecting how the events
+	// are dispatched to their Triggers.
+	BrokerDispatchModeAnnotationKey = "eventing.knative.dev/dispatch-mode"
+	// BrokerDispatchModeIndexed dispatches each event once to the broker filter, which matches it
+	// against every Trigger of the Broker at once, rather than once per Trigger.
+	BrokerDispatchModeIndexed = "indexed"
 )
 
 func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
@@ -55,6 +71,15 @@ func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
 		errs = errs.Also(apis.ErrMissingField(BrokerClassAnnotationKey))
 	}
 
+	if mode, ok := b.GetAnnotations()[BrokerEventValidationAnnotationKey]; ok &&
+		mode != BrokerEventValidationReject && mode != BrokerEventValidationDeadLetter {
+		errs = errs.Also(apis.ErrInvalidValue(mode, BrokerEventValidationAnnotationKey))
+	}
+
+	if mode, ok := b.GetAnnotations()[BrokerDispatchModeAnnotationKey]; ok && mode != BrokerDispatchModeIndexed {
+		errs = errs.Also(apis.ErrInvalidValue(mode, BrokerDispatchModeAnnotationKey))
+	}
+
 	errs = errs.Also(b.Spec.Validate(withNS).ViaField("spec"))
 	if apis.IsInUpdate(ctx) {
 		original := apis.GetBaseline(ctx).(*Broker)
@@ -77,6 +102,8 @@ func (bs *BrokerSpec) Validate(ctx context.Context) *apis.FieldError {
 		if de := bs.Delivery.Validate(ctx); de != nil {
 			errs = errs.Also(de.ViaField("delivery"))
 		}
+		errs = errs.Also(bs.Delivery.ValidateUnordered().ViaField("delivery"))
+		errs = errs.Also(bs.Delivery.ValidateWithoutCircuitBreaker().ViaField("delivery"))
 	}
 	return errs
 }
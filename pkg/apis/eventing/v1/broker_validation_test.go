@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	"k8s.io/utils/ptr"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
@@ -105,6 +106,48 @@ func TestValidate(t *testing.T) {
 				Annotations: map[string]string{"eventing.knative.dev/broker.class": "MTChannelBasedBroker"},
 			},
 		},
+	}, {
+		name: "valid event validation",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":     "MTChannelBasedBroker",
+					"eventing.knative.dev/event-validation": "dead-letter",
+				},
+			},
+		},
+	}, {
+		name: "invalid event validation",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":     "MTChannelBasedBroker",
+					"eventing.knative.dev/event-validation": "drop",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("drop", "eventing.knative.dev/event-validation"),
+	}, {
+		name: "valid dispatch mode",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":  "MTChannelBasedBroker",
+					"eventing.knative.dev/dispatch-mode": "indexed",
+				},
+			},
+		},
+	}, {
+		name: "invalid dispatch mode",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":  "MTChannelBasedBroker",
+					"eventing.knative.dev/dispatch-mode": "fanout",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("fanout", "eventing.knative.dev/dispatch-mode"),
 	}, {
 		name: "valid config",
 		b: Broker{
@@ -405,6 +448,18 @@ func TestValidSpec(t *testing.T) {
 			},
 			Delivery: &eventingduckv1.DeliverySpec{BackoffPolicy: &bop},
 		},
+	}, {
+		name: "ordered delivery",
+		spec: BrokerSpec{
+			Delivery: &eventingduckv1.DeliverySpec{Ordering: ptr.To(eventingduckv1.DeliveryOrderingPartitionKey)},
+		},
+		want: apis.ErrDisallowedFields("delivery.ordering"),
+	}, {
+		name: "circuit breaker",
+		spec: BrokerSpec{
+			Delivery: &eventingduckv1.DeliverySpec{CircuitBreaker: &eventingduckv1.CircuitBreakerSpec{}},
+		},
+		want: apis.ErrDisallowedFields("delivery.circuitBreaker"),
 	}, {}}
 
 	for _, test := range tests {
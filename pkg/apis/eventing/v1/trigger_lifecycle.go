@@ -44,6 +44,11 @@ const (
 
 	TriggerConditionOIDCIdentityCreated apis.ConditionType = "OIDCIdentityCreated"
 
+	// TriggerConditionSubscriberCircuitClosed has status False while the circuit breaker of the
+	// subscriber is open. It is only set when the delivery of the Trigger has a circuit breaker,
+	// and doesn't affect the readiness of the Trigger.
+	TriggerConditionSubscriberCircuitClosed apis.ConditionType = "SubscriberCircuitClosed"
+
 	// TriggerAnyFilter Constant to represent that we should allow anything.
 	TriggerAnyFilter = ""
 )
@@ -226,3 +231,30 @@ func (ts *TriggerStatus) MarkOIDCIdentityCreatedNotSupported() {
 	// in case the OIDC feature is not supported, we mark the condition as true, to not mark the Trigger unready.
 	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionOIDCIdentityCreated, fmt.Sprintf("%s feature not yet supported for this Broker class", feature.OIDCAuthentication), "")
 }
+
+// MarkSubscriberCircuitClosed sets the informational SubscriberCircuitClosed condition to True.
+func (ts *TriggerStatus) MarkSubscriberCircuitClosed() {
+	triggerCondSet.Manage(ts).SetCondition(apis.Condition{
+		Type:     TriggerConditionSubscriberCircuitClosed,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityInfo,
+	})
+}
+
+// MarkSubscriberCircuitOpen sets the informational SubscriberCircuitClosed condition to False,
+// without affecting the readiness of the Trigger.
+func (ts *TriggerStatus) MarkSubscriberCircuitOpen(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).SetCondition(apis.Condition{
+		Type:     TriggerConditionSubscriberCircuitClosed,
+		Status:   corev1.ConditionFalse,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+		Severity: apis.ConditionSeverityInfo,
+	})
+}
+
+// ClearSubscriberCircuitCondition removes the SubscriberCircuitClosed condition, when the
+// delivery of the Trigger has no circuit breaker.
+func (ts *TriggerStatus) ClearSubscriberCircuitCondition() {
+	_ = triggerCondSet.Manage(ts).ClearCondition(TriggerConditionSubscriberCircuitClosed)
+}
@@ -421,3 +421,35 @@ func TestTriggerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestTriggerSubscriberCircuitCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	ts.PropagateBrokerCondition(TestHelper.ReadyBrokerStatus().GetTopLevelCondition())
+	ts.PropagateSubscriptionCondition(TestHelper.ReadySubscriptionCondition())
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDeadLetterSinkResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+	ts.MarkOIDCIdentityCreatedSucceeded()
+
+	ts.MarkSubscriberCircuitOpen("CircuitBreakerOpen", "too many failures")
+	if c := ts.GetCondition(TriggerConditionSubscriberCircuitClosed); c == nil || c.Status != corev1.ConditionFalse || c.Severity != apis.ConditionSeverityInfo {
+		t.Errorf("unexpected SubscriberCircuitClosed condition: %+v", c)
+	}
+	if !ts.IsReady() {
+		t.Error("expected the Trigger to stay ready while the circuit breaker is open")
+	}
+
+	ts.MarkSubscriberCircuitClosed()
+	if c := ts.GetCondition(TriggerConditionSubscriberCircuitClosed); c == nil || c.Status != corev1.ConditionTrue {
+		t.Errorf("unexpected SubscriberCircuitClosed condition: %+v", c)
+	}
+
+	ts.ClearSubscriberCircuitCondition()
+	if c := ts.GetCondition(TriggerConditionSubscriberCircuitClosed); c != nil {
+		t.Errorf("expected the SubscriberCircuitClosed condition to be removed, got %+v", c)
+	}
+	if !ts.IsReady() {
+		t.Error("expected the Trigger to stay ready")
+	}
+}
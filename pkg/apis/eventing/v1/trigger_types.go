@@ -107,6 +107,12 @@ type TriggerSpec struct {
 	// Delivery contains the delivery spec for this specific trigger.
 	// +optional
 	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// Transform modifies the context attributes and extensions of the events
+	// that pass the filters before they are sent to the Subscriber.
+	//
+	// +optional
+	Transform *TriggerTransform `json:"transform,omitempty"`
 }
 
 type TriggerFilter struct {
@@ -120,64 +126,52 @@ type TriggerFilter struct {
 	Attributes TriggerFilterAttributes `json:"attributes,omitempty"`
 }
 
-// SubscriptionsAPIFilter allows defining a filter expression using CloudEvents
-// Subscriptions API. If multiple filters are specified, then the same semantics
-// of SubscriptionsAPIFilter.All is applied. If no filter dialect or empty
-// object is specified, then the filter always accept the events.
-type SubscriptionsAPIFilter struct {
-	// All evaluates to true if all the nested expressions evaluate to true.
-	// It must contain at least one filter expression.
+// TriggerTransformValueMaxSize is the maximum size in bytes of the values set by
+// the transform of a Trigger, before and after the expansion of their templates.
+const TriggerTransformValueMaxSize = 4096
+
+// TriggerTransform modifies the context attributes and extensions of an event.
+// The renames are applied first, then the values are set and finally the
+// attributes are removed.
+type TriggerTransform struct {
+	// Set sets the value of context attributes and extensions, adding the ones
+	// which are not present. The keys are the names of the attributes and the
+	// values are Go templates evaluated against the incoming event, e.g.
+	// `{{ .Type }}.v2` or `{{ .Extensions.tenant }}`. The templates can only
+	// substitute values of the event, without functions or control structures,
+	// and the values can't exceed 4KiB once expanded. The specversion attribute
+	// can't be set.
 	//
 	// +optional
-	All []SubscriptionsAPIFilter `json:"all,omitempty"`
+	Set map[string]string `json:"set,omitempty"`
 
-	// Any evaluates to true if at least one of the nested expressions evaluates
-	// to true. It must contain at least one filter expression.
+	// Remove is the list of the context attributes and extensions to remove.
+	// The required attributes (id, source, specversion and type) can't be removed.
 	//
 	// +optional
-	Any []SubscriptionsAPIFilter `json:"any,omitempty"`
+	Remove []string `json:"remove,omitempty"`
 
-	// Not evaluates to true if the nested expression evaluates to false.
+	// Rename moves the value of context attributes and extensions to another
+	// attribute. The keys are the current names and the values the new ones.
+	// The required attributes can't be renamed and the specversion attribute
+	// can't be the target of a rename. The renames are applied at once, each
+	// moves the value the attribute had in the received event.
 	//
 	// +optional
-	Not *SubscriptionsAPIFilter `json:"not,omitempty"`
-
-	// Exact evaluates to true if the values of the matching CloudEvents attributes MUST
-	// all exactly match with the associated value String specified (case-sensitive).
-	// The keys are the names of the CloudEvents attributes to be matched,
-	// and their values are the String values to use in the comparison.
-	// The attribute name and value specified in the filter express MUST NOT be
-	// empty strings.
-	//
-	// +optional
-	Exact map[string]string `json:"exact,omitempty"`
-
-	// Prefix evaluates to true if the values of the matching CloudEvents attributes MUST
-	// all start with the associated value String specified (case sensitive).
-	// The keys are the names of the CloudEvents attributes to be matched,
-	// and their values are the String values to use in the comparison.
-	// The attribute name and value specified in the filter express MUST NOT be
-	// empty strings.
-	//
-	// +optional
-	Prefix map[string]string `json:"prefix,omitempty"`
-
-	// Suffix evaluates to true if the values of the matching CloudEvents attributes MUST
-	// all end with the associated value String specified (case sensitive).
-	// The keys are the names of the CloudEvents attributes to be matched,
-	// and their values are the String values to use in the comparison.
-	// The attribute name and value specified in the filter express MUST NOT be
-	// empty strings.
-	//
-	// +optional
-	Suffix map[string]string `json:"suffix,omitempty"`
-
-	// CESQL is a CloudEvents SQL expression that will be evaluated to true or false against each CloudEvent.
-	//
-	// +optional
-	CESQL string `json:"cesql,omitempty"`
+	Rename map[string]string `json:"rename,omitempty"`
 }
 
+// SubscriptionsAPIFilter allows defining a filter expression using CloudEvents
+// Subscriptions API, it's shared by the API groups filtering events.
+type SubscriptionsAPIFilter = eventingduckv1.SubscriptionsAPIFilter
+
+// SubscriptionsAPIDataFilter selects a value of the event payload and compares it with
+// exactly one of the operators.
+type SubscriptionsAPIDataFilter = eventingduckv1.SubscriptionsAPIDataFilter
+
+// SubscriptionsAPIFilterRange is an inclusive numeric range used by the Range dialect.
+type SubscriptionsAPIFilterRange = eventingduckv1.SubscriptionsAPIFilterRange
+
 // TriggerFilterAttributes is a map of context attribute names to values for
 // filtering by equality. Only exact matches will pass the filter. You can use
 // the value ” to indicate all strings match.
@@ -21,21 +21,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"text/template"
 
-	cesqlparser "github.com/cloudevents/sdk-go/sql/v2/parser"
-	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	cn "knative.dev/eventing/pkg/crossnamespace"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmp"
-	"knative.dev/pkg/logging"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/apis/feature"
 )
 
 var (
 	// Only allow lowercase alphanumeric, starting with letters.
 	validAttributeName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+	// The attributes every CloudEvent must have.
+	requiredAttributes = sets.New("id", "source", "specversion", "type")
 )
 
 // Validate the Trigger.
@@ -80,6 +83,8 @@ func (ts *TriggerSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 		ts.Subscriber.Validate(ctx).ViaField("subscriber"),
 	).Also(
 		ts.Delivery.Validate(ctx).ViaField("delivery"),
+	).Also(
+		ValidateTransform(ts.Transform).ViaField("transform"),
 	)
 }
 
@@ -176,118 +181,83 @@ func ValidateAttributeFilters(filter *TriggerFilter) (errs *apis.FieldError) {
 	return errs.Also(ValidateAttributesNames(filter.Attributes).ViaField("attributes"))
 }
 
-func ValidateAttributesNames(attrs map[string]string) (errs *apis.FieldError) {
-	for attr := range attrs {
+func ValidateAttributesNames(attrs map[string]string) *apis.FieldError {
+	return eventingduckv1.ValidateAttributesNames(attrs)
+}
+
+func ValidateTransform(transform *TriggerTransform) (errs *apis.FieldError) {
+	if transform == nil {
+		return nil
+	}
+	if len(transform.Set) == 0 && len(transform.Remove) == 0 && len(transform.Rename) == 0 {
+		return apis.ErrMissingOneOf("set", "remove", "rename")
+	}
+
+	for attr, value := range transform.Set {
 		if !validAttributeName.MatchString(attr) {
-			errs = errs.Also(apis.ErrInvalidKeyName(attr, apis.CurrentField, "Attribute name must start with a letter and can only contain lowercase alphanumeric").ViaKey(attr))
+			errs = errs.Also(apis.ErrInvalidKeyName(attr, "set", "Attribute name must start with a letter and can only contain lowercase alphanumeric").ViaKey(attr))
+		} else if attr == "specversion" {
+			errs = errs.Also(apis.ErrInvalidKeyName(attr, "set", "the specversion attribute can't be set").ViaKey(attr))
+		}
+		if value == "" {
+			errs = errs.Also(apis.ErrInvalidValue(value, "set", "value must not be empty").ViaKey(attr))
+		} else if len(value) > TriggerTransformValueMaxSize {
+			errs = errs.Also(apis.ErrInvalidValue(value, "set", fmt.Sprintf("value must not exceed %d bytes", TriggerTransformValueMaxSize)).ViaKey(attr))
+		} else if _, err := eventingduckv1.ParseSubstitutionTemplate(template.New(attr), value); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(value, "set", err.Error()).ViaKey(attr))
 		}
 	}
-	return errs
-}
 
-func ValidateSubscriptionAPIFiltersList(ctx context.Context, filters []SubscriptionsAPIFilter) (errs *apis.FieldError) {
-	if filters == nil || !feature.FromContext(ctx).IsEnabled(feature.NewTriggerFilters) {
-		return nil
+	for i, attr := range transform.Remove {
+		if !validAttributeName.MatchString(attr) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(attr, "remove", i))
+		} else if requiredAttributes.Has(attr) {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("the required attribute %q can't be removed", attr), apis.CurrentField).ViaFieldIndex("remove", i))
+		}
 	}
 
-	for i, f := range filters {
-		f := f
-		errs = errs.Also(ValidateSubscriptionAPIFilter(ctx, &f)).ViaIndex(i)
+	for from, to := range transform.Rename {
+		if !validAttributeName.MatchString(from) {
+			errs = errs.Also(apis.ErrInvalidKeyName(from, "rename", "Attribute name must start with a letter and can only contain lowercase alphanumeric").ViaKey(from))
+		} else if requiredAttributes.Has(from) {
+			errs = errs.Also(apis.ErrInvalidKeyName(from, "rename", "required attributes can't be renamed").ViaKey(from))
+		}
+		if !validAttributeName.MatchString(to) {
+			errs = errs.Also(apis.ErrInvalidValue(to, "rename", "Attribute name must start with a letter and can only contain lowercase alphanumeric").ViaKey(from))
+		} else if to == "specversion" || to == from {
+			errs = errs.Also(apis.ErrInvalidValue(to, "rename", "the target attribute must differ from the renamed one and can't be specversion").ViaKey(from))
+		}
 	}
 	return errs
 }
 
-func ValidateCESQLExpression(ctx context.Context, expression string) (errs *apis.FieldError) {
-	if expression == "" {
-		return nil
-	}
-	// Need to recover in case Parse panics
-	defer func() {
-		if r := recover(); r != nil {
-			logging.FromContext(ctx).Debug("Warning! Calling CESQL Parser panicked. Treating expression as invalid.", zap.Any("recovered value", r), zap.String("CESQL", expression))
-			errs = apis.ErrInvalidValue(expression, apis.CurrentField)
-		}
-	}()
+// The SubscriptionsAPI filters are validated by the duck package, so that the API groups
+// filtering events share them.
 
-	if _, err := cesqlparser.Parse(expression); err != nil {
-		return apis.ErrInvalidValue(expression, apis.CurrentField, err.Error())
-	}
-	return nil
+func ValidateSubscriptionAPIFiltersList(ctx context.Context, filters []SubscriptionsAPIFilter) *apis.FieldError {
+	return eventingduckv1.ValidateSubscriptionAPIFiltersList(ctx, filters)
 }
 
-func ValidateSubscriptionAPIFilter(ctx context.Context, filter *SubscriptionsAPIFilter) (errs *apis.FieldError) {
-	if filter == nil {
-		return nil
-	}
-	errs = errs.Also(
-		ValidateOneOf(filter),
-	).Also(
-		ValidateAttributesNames(filter.Exact).ViaField("exact"),
-	).Also(
-		ValidateAttributesNames(filter.Prefix).ViaField("prefix"),
-	).Also(
-		ValidateAttributesNames(filter.Suffix).ViaField("suffix"),
-	).Also(
-		ValidateSubscriptionAPIFiltersList(ctx, filter.All).ViaField("all"),
-	).Also(
-		ValidateSubscriptionAPIFiltersList(ctx, filter.Any).ViaField("any"),
-	).Also(
-		ValidateSubscriptionAPIFilter(ctx, filter.Not).ViaField("not"),
-	).Also(
-		ValidateCESQLExpression(ctx, filter.CESQL).ViaField("cesql"),
-	)
-	return errs
+func ValidateCESQLExpression(ctx context.Context, expression string) *apis.FieldError {
+	return eventingduckv1.ValidateCESQLExpression(ctx, expression)
 }
 
-func ValidateOneOf(filter *SubscriptionsAPIFilter) (err *apis.FieldError) {
-	if filter != nil && hasMultipleDialects(filter) {
-		return apis.ErrGeneric("multiple dialects found, filters can have only one dialect set")
-	}
-	return nil
+func ValidateRegexExpressions(expressions map[string]string) *apis.FieldError {
+	return eventingduckv1.ValidateRegexExpressions(expressions)
 }
 
-func hasMultipleDialects(filter *SubscriptionsAPIFilter) bool {
-	dialectFound := false
-	if len(filter.Exact) > 0 {
-		dialectFound = true
-	}
-	if len(filter.Prefix) > 0 {
-		if dialectFound {
-			return true
-		} else {
-			dialectFound = true
-		}
-	}
-	if len(filter.Suffix) > 0 {
-		if dialectFound {
-			return true
-		} else {
-			dialectFound = true
-		}
-	}
-	if len(filter.All) > 0 {
-		if dialectFound {
-			return true
-		} else {
-			dialectFound = true
-		}
-	}
-	if len(filter.Any) > 0 {
-		if dialectFound {
-			return true
-		} else {
-			dialectFound = true
-		}
-	}
-	if filter.Not != nil {
-		if dialectFound {
-			return true
-		} else {
-			dialectFound = true
-		}
-	}
-	if filter.CESQL != "" && dialectFound {
-		return true
-	}
-	return false
+func ValidateRanges(ranges map[string]SubscriptionsAPIFilterRange) *apis.FieldError {
+	return eventingduckv1.ValidateRanges(ranges)
+}
+
+func ValidateDataFilter(filter *SubscriptionsAPIDataFilter) *apis.FieldError {
+	return eventingduckv1.ValidateDataFilter(filter)
+}
+
+func ValidateSubscriptionAPIFilter(ctx context.Context, filter *SubscriptionsAPIFilter) *apis.FieldError {
+	return eventingduckv1.ValidateSubscriptionAPIFilter(ctx, filter)
+}
+
+func ValidateOneOf(filter *SubscriptionsAPIFilter) *apis.FieldError {
+	return eventingduckv1.ValidateOneOf(filter)
 }
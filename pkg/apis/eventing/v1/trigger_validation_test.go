@@ -19,12 +19,14 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
 
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/apis/feature"
@@ -499,6 +501,70 @@ func TestTriggerSpecValidation(t *testing.T) {
 			fe.Details = "only name, apiVersion and kind are supported fields when feature.CrossNamespaceEventLinks is disabled"
 			return fe
 		}(),
+	}, {
+		name: "valid transform",
+		ts: &TriggerSpec{
+			Broker:     "test_broker",
+			Filter:     validTriggerFilter,
+			Subscriber: validSubscriber,
+			Transform: &TriggerTransform{
+				Set:    map[string]string{"type": "{{ .Type }}.v2", "tenant": "acme"},
+				Remove: []string{"subject"},
+				Rename: map[string]string{"oldname": "newname"},
+			},
+		},
+		want: &apis.FieldError{},
+	}, {
+		name: "empty transform",
+		ts: &TriggerSpec{
+			Broker:     "test_broker",
+			Filter:     validTriggerFilter,
+			Subscriber: validSubscriber,
+			Transform:  &TriggerTransform{},
+		},
+		want: apis.ErrMissingOneOf("set", "remove", "rename").ViaField("transform"),
+	}, {
+		name: "invalid transform set",
+		ts: &TriggerSpec{
+			Broker:     "test_broker",
+			Filter:     validTriggerFilter,
+			Subscriber: validSubscriber,
+			Transform: &TriggerTransform{
+				Set: map[string]string{"specversion": "0.3", "type": "{{ .Type"},
+			},
+		},
+		want: apis.ErrInvalidKeyName("specversion", "set", "the specversion attribute can't be set").ViaKey("specversion").
+			Also(apis.ErrInvalidValue("{{ .Type", "set", "template: type:1: unclosed action").ViaKey("type")).
+			ViaField("transform"),
+	}, {
+		name: "unbounded transform set",
+		ts: &TriggerSpec{
+			Broker:     "test_broker",
+			Filter:     validTriggerFilter,
+			Subscriber: validSubscriber,
+			Transform: &TriggerTransform{
+				Set: map[string]string{"type": "{{ range 100000000 }}x{{ end }}", "tenant": strings.Repeat("x", TriggerTransformValueMaxSize+1)},
+			},
+		},
+		want: apis.ErrInvalidValue(strings.Repeat("x", TriggerTransformValueMaxSize+1), "set", "value must not exceed 4096 bytes").ViaKey("tenant").
+			Also(apis.ErrInvalidValue("{{ range 100000000 }}x{{ end }}", "set", "{{range 100000000}}x{{end}}: only the substitution of values is supported").ViaKey("type")).
+			ViaField("transform"),
+	}, {
+		name: "invalid transform remove and rename",
+		ts: &TriggerSpec{
+			Broker:     "test_broker",
+			Filter:     validTriggerFilter,
+			Subscriber: validSubscriber,
+			Transform: &TriggerTransform{
+				Remove: []string{"type", "inVALID"},
+				Rename: map[string]string{"source": "origin", "tenant": "specversion"},
+			},
+		},
+		want: apis.ErrGeneric(`the required attribute "type" can't be removed`, apis.CurrentField).ViaFieldIndex("remove", 0).
+			Also(apis.ErrInvalidArrayValue("inVALID", "remove", 1)).
+			Also(apis.ErrInvalidKeyName("source", "rename", "required attributes can't be renamed").ViaKey("source")).
+			Also(apis.ErrInvalidValue("specversion", "rename", "the target attribute must differ from the renamed one and can't be specversion").ViaKey("tenant")).
+			ViaField("transform"),
 	}}
 
 	for _, test := range tests {
@@ -829,6 +895,124 @@ func TestFilterSpecValidation(t *testing.T) {
 				},
 			}},
 		want: &apis.FieldError{},
+	}, {
+		name: "valid regex filter",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Regex: map[string]string{
+					"source": "^/sources/[a-z]+$",
+				},
+			}},
+		want: &apis.FieldError{},
+	}, {
+		name: "regex filter contains invalid expression",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Regex: map[string]string{
+					"source": "[a-z",
+				},
+			}},
+		want: apis.ErrInvalidValue("[a-z", apis.CurrentField, "error parsing regexp: missing closing ]: `[a-z`").ViaFieldKey("regex", "source").ViaFieldIndex("filters", 0),
+	}, {
+		name: "regex filter contains empty expression",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Regex: map[string]string{
+					"source": "",
+				},
+			}},
+		want: apis.ErrInvalidValue("", apis.CurrentField, "regular expression must not be empty").ViaFieldKey("regex", "source").ViaFieldIndex("filters", 0),
+	}, {
+		name: "regex filter contains invalid attribute name",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Regex: map[string]string{
+					"invALID": "abc",
+				},
+			}},
+		want: apis.ErrInvalidKeyName("invALID", apis.CurrentField,
+			"Attribute name must start with a letter and can only contain "+
+				"lowercase alphanumeric").ViaFieldKey("regex", "invALID").ViaFieldIndex("filters", 0),
+	}, {
+		name: "valid range filter",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Range: map[string]SubscriptionsAPIFilterRange{
+					"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)},
+					"severity": {Min: ptr.Int64(3)},
+				},
+			}},
+		want: &apis.FieldError{},
+	}, {
+		name: "range filter without bounds",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Range: map[string]SubscriptionsAPIFilterRange{
+					"priority": {},
+				},
+			}},
+		want: apis.ErrMissingOneOf("min", "max").ViaFieldKey("range", "priority").ViaFieldIndex("filters", 0),
+	}, {
+		name: "range filter with unordered bounds",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Range: map[string]SubscriptionsAPIFilterRange{
+					"priority": {Min: ptr.Int64(7), Max: ptr.Int64(3)},
+				},
+			}},
+		want: apis.ErrInvalidValue(int64(3), "max", "max must be greater than or equal to min (7)").ViaFieldKey("range", "priority").ViaFieldIndex("filters", 0),
+	}, {
+		name: "invalid multiple dialects with regex and range",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Regex: map[string]string{
+					"source": "abc",
+				},
+				Range: map[string]SubscriptionsAPIFilterRange{
+					"priority": {Min: ptr.Int64(3)},
+				},
+			}},
+		want: apis.ErrGeneric("multiple dialects found, filters can have only one dialect set"),
+	}, {
+		name: "valid data filter",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Data: &SubscriptionsAPIDataFilter{Path: "$.order['region']", Exact: ptr.String("eu-west")},
+			}},
+		want: &apis.FieldError{},
+	}, {
+		name: "data filter with invalid path",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Data: &SubscriptionsAPIDataFilter{Path: "$..region", Exists: ptr.Bool(true)},
+			}},
+		want: apis.ErrInvalidValue("$..region", "path",
+			`invalid JSONPath expression "$..region": wildcards and recursive descent are not supported`).ViaField("data").ViaFieldIndex("filters", 0),
+	}, {
+		name: "data filter without path and operator",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Data: &SubscriptionsAPIDataFilter{},
+			}},
+		want: apis.ErrMissingField("path").Also(apis.ErrMissingOneOf("exact", "prefix", "exists")).ViaField("data").ViaFieldIndex("filters", 0),
+	}, {
+		name: "data filter with multiple operators",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Data: &SubscriptionsAPIDataFilter{Path: "$.region", Exact: ptr.String("eu-west"), Prefix: ptr.String("")},
+			}},
+		want: apis.ErrInvalidValue("", "prefix", "prefix must not be empty").
+			Also(apis.ErrMultipleOneOf("exact", "prefix")).ViaField("data").ViaFieldIndex("filters", 0),
+	}, {
+		name: "invalid multiple dialects with exact and data",
+		filters: []SubscriptionsAPIFilter{
+			{
+				Exact: map[string]string{
+					"source": "abc",
+				},
+				Data: &SubscriptionsAPIDataFilter{Path: "$.region", Exists: ptr.Bool(true)},
+			}},
+		want: apis.ErrGeneric("multiple dialects found, filters can have only one dialect set"),
 	}, {
 		name: "not nested expression is valid",
 		filters: []SubscriptionsAPIFilter{
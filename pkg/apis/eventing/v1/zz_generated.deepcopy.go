@@ -135,62 +135,6 @@ func (in *BrokerStatus) DeepCopy() *BrokerStatus {
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubscriptionsAPIFilter) DeepCopyInto(out *SubscriptionsAPIFilter) {
-	*out = *in
-	if in.All != nil {
-		in, out := &in.All, &out.All
-		*out = make([]SubscriptionsAPIFilter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Any != nil {
-		in, out := &in.Any, &out.Any
-		*out = make([]SubscriptionsAPIFilter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Not != nil {
-		in, out := &in.Not, &out.Not
-		*out = new(SubscriptionsAPIFilter)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Exact != nil {
-		in, out := &in.Exact, &out.Exact
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Prefix != nil {
-		in, out := &in.Prefix, &out.Prefix
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Suffix != nil {
-		in, out := &in.Suffix, &out.Suffix
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionsAPIFilter.
-func (in *SubscriptionsAPIFilter) DeepCopy() *SubscriptionsAPIFilter {
-	if in == nil {
-		return nil
-	}
-	out := new(SubscriptionsAPIFilter)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Trigger) DeepCopyInto(out *Trigger) {
 	*out = *in
@@ -323,6 +267,11 @@ func (in *TriggerSpec) DeepCopyInto(out *TriggerSpec) {
 		*out = new(apisduckv1.DeliverySpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = new(TriggerTransform)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -373,3 +322,38 @@ func (in *TriggerStatus) DeepCopy() *TriggerStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerTransform) DeepCopyInto(out *TriggerTransform) {
+	*out = *in
+	if in.Set != nil {
+		in, out := &in.Set, &out.Set
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rename != nil {
+		in, out := &in.Rename, &out.Rename
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerTransform.
+func (in *TriggerTransform) DeepCopy() *TriggerTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerTransform)
+	in.DeepCopyInto(out)
+	return out
+}
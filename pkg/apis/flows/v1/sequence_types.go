@@ -90,6 +90,13 @@ type SequenceStep struct {
 	// This includes things like retries, DLS, etc.
 	// +optional
 	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// ReplyRoutes are conditional destinations for the events returned by
+	// the subscriber. A reply matching a route leaves the Sequence and is
+	// sent to the route destination, any other reply continues to the next
+	// step.
+	// +optional
+	ReplyRoutes []messagingv1.ReplyRoute `json:"replyRoutes,omitempty"`
 }
 
 type SequenceChannelStatus struct {
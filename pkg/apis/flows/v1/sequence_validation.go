@@ -64,5 +64,9 @@ func (ss *SequenceStep) Validate(ctx context.Context) *apis.FieldError {
 		}
 	}
 
+	for i, route := range ss.ReplyRoutes {
+		errs = errs.Also(route.Validate(ctx).ViaFieldIndex("replyRoutes", i))
+	}
+
 	return errs
 }
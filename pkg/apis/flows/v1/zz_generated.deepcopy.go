@@ -400,6 +400,13 @@ func (in *SequenceStep) DeepCopyInto(out *SequenceStep) {
 		*out = new(apisduckv1.DeliverySpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplyRoutes != nil {
+		in, out := &in.ReplyRoutes, &out.ReplyRoutes
+		*out = make([]messagingv1.ReplyRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -54,6 +54,8 @@ func (cs *ChannelSpec) Validate(ctx context.Context) *apis.FieldError {
 		if fe := cs.Delivery.Validate(ctx); fe != nil {
 			errs = errs.Also(fe.ViaField("delivery"))
 		}
+		errs = errs.Also(cs.Delivery.ValidateUnordered().ViaField("delivery"))
+		errs = errs.Also(cs.Delivery.ValidateWithoutCircuitBreaker().ViaField("delivery"))
 	}
 
 	return errs
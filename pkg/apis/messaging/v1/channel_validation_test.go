@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"knative.dev/pkg/apis"
 
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
@@ -111,6 +112,24 @@ func TestChannelValidation(t *testing.T) {
 			},
 		},
 		want: apis.ErrInvalidValue(backoffDelayInvalid, "spec.delivery.backoffDelay"),
+	}, {
+		name: "ordered Delivery",
+		cr: &Channel{
+			Spec: ChannelSpec{
+				ChannelTemplate: &ChannelTemplateSpec{
+					TypeMeta: v1.TypeMeta{
+						Kind:       "Channel",
+						APIVersion: SchemeGroupVersion.String(),
+					},
+				},
+				ChannelableSpec: eventingduck.ChannelableSpec{
+					Delivery: &eventingduck.DeliverySpec{
+						Ordering: ptr.To(eventingduck.DeliveryOrderingPartitionKey),
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("spec.delivery.ordering"),
 	}, {
 		name: "valid Delivery",
 		cr: &Channel{
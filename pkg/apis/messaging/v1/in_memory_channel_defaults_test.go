@@ -54,7 +54,7 @@ func TestInMemoryChannelSetDefaults(t *testing.T) {
 					Namespace:   "custom",
 					Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1"},
 				},
-				Spec: InMemoryChannelSpec{eventingduckv1.ChannelableSpec{
+				Spec: InMemoryChannelSpec{ChannelableSpec: eventingduckv1.ChannelableSpec{
 					Delivery: &eventingduckv1.DeliverySpec{
 						DeadLetterSink: &duckv1.Destination{
 							Ref: &duckv1.KReference{
@@ -70,7 +70,7 @@ func TestInMemoryChannelSetDefaults(t *testing.T) {
 					Namespace:   "custom",
 					Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1"},
 				},
-				Spec: InMemoryChannelSpec{eventingduckv1.ChannelableSpec{
+				Spec: InMemoryChannelSpec{ChannelableSpec: eventingduckv1.ChannelableSpec{
 					Delivery: &eventingduckv1.DeliverySpec{
 						DeadLetterSink: &duckv1.Destination{
 							Ref: &duckv1.KReference{
@@ -67,6 +67,31 @@ var (
 type InMemoryChannelSpec struct {
 	// Channel conforms to Duck type Channelable.
 	eventingduckv1.ChannelableSpec `json:",inline"`
+
+	// Persistence is how the dispatcher keeps the events until they are delivered to all the
+	// subscribers. Defaults to None, the events are lost when the dispatcher restarts.
+	// +optional
+	Persistence *InMemoryChannelPersistence `json:"persistence,omitempty"`
+}
+
+// InMemoryChannelPersistence is the persistence mode of an InMemoryChannel.
+type InMemoryChannelPersistence string
+
+const (
+	// InMemoryChannelPersistenceNone keeps the events in memory only.
+	InMemoryChannelPersistenceNone InMemoryChannelPersistence = "None"
+
+	// InMemoryChannelPersistenceWriteAheadLog appends the events to a log on the volume of the
+	// dispatcher before acknowledging them. The events whose delivery to the subscribers didn't
+	// complete are delivered again after the dispatcher restarts. The log keeps up to 1GiB of
+	// events, the oldest ones are dropped beyond.
+	InMemoryChannelPersistenceWriteAheadLog InMemoryChannelPersistence = "WriteAheadLog"
+)
+
+// IsPersistent returns whether the events of the InMemoryChannel are appended to a write-ahead
+// log.
+func (imcs *InMemoryChannelSpec) IsPersistent() bool {
+	return imcs.Persistence != nil && *imcs.Persistence == InMemoryChannelPersistenceWriteAheadLog
 }
 
 // ChannelStatus represents the current state of a Channel.
@@ -61,6 +61,17 @@ func (imcs *InMemoryChannelSpec) Validate(ctx context.Context) *apis.FieldError
 		}
 	}
 
+	if imcs.Persistence != nil {
+		switch *imcs.Persistence {
+		case InMemoryChannelPersistenceNone, InMemoryChannelPersistenceWriteAheadLog:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(*imcs.Persistence, "persistence"))
+		}
+	}
+
+	errs = errs.Also(imcs.Delivery.ValidateUnordered().ViaField("delivery"))
+	errs = errs.Also(imcs.Delivery.ValidateWithoutCircuitBreaker().ViaField("delivery"))
+
 	return errs
 }
 
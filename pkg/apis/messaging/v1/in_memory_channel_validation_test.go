@@ -22,6 +22,7 @@ import (
 	"golang.org/x/net/context"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmp"
 
@@ -118,6 +119,47 @@ func TestInMemoryChannelValidation(t *testing.T) {
 			errs = errs.Also(fe)
 			return errs
 		}(),
+	}, {
+		name: "write-ahead log persistence",
+		cr: &InMemoryChannel{
+			Spec: InMemoryChannelSpec{
+				Persistence: ptr.To(InMemoryChannelPersistenceWriteAheadLog),
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid persistence",
+		cr: &InMemoryChannel{
+			Spec: InMemoryChannelSpec{
+				Persistence: ptr.To(InMemoryChannelPersistence("Disk")),
+			},
+		},
+		want: apis.ErrInvalidValue("Disk", "spec.persistence"),
+	}, {
+		name: "ordered delivery",
+		cr: &InMemoryChannel{
+			Spec: InMemoryChannelSpec{
+				ChannelableSpec: eventingduck.ChannelableSpec{
+					Delivery: &eventingduck.DeliverySpec{
+						Ordering:              ptr.To(eventingduck.DeliveryOrderingPartitionKey),
+						PartitionKeyAttribute: ptr.To("subject"),
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("spec.delivery.ordering", "spec.delivery.partitionKeyAttribute"),
+	}, {
+		name: "circuit breaker",
+		cr: &InMemoryChannel{
+			Spec: InMemoryChannelSpec{
+				ChannelableSpec: eventingduck.ChannelableSpec{
+					Delivery: &eventingduck.DeliverySpec{
+						CircuitBreaker: &eventingduck.CircuitBreakerSpec{},
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("spec.delivery.circuitBreaker"),
 	}, {
 		name: "invalid scope annotation",
 		cr: &InMemoryChannel{
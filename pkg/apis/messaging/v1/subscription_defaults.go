@@ -37,5 +37,8 @@ func (ss *SubscriptionSpec) SetDefaults(ctx context.Context) {
 
 	ss.Subscriber.SetDefaults(ctx)
 	ss.Reply.SetDefaults(ctx)
+	for i := range ss.ReplyRoutes {
+		ss.ReplyRoutes[i].Destination.SetDefaults(ctx)
+	}
 	ss.Delivery.SetDefaults(ctx)
 }
@@ -101,11 +101,30 @@ type SubscriptionSpec struct {
 	// +optional
 	Reply *duckv1.Destination `json:"reply,omitempty"`
 
+	// ReplyRoutes specifies (optionally) conditional destinations for the
+	// events returned from the Subscriber target. Routes are evaluated in
+	// order and a reply is sent to the first route whose filters match it.
+	// Replies not matching any route are handled as specified by the Reply.
+	// +optional
+	ReplyRoutes []ReplyRoute `json:"replyRoutes,omitempty"`
+
 	// Delivery configuration
 	// +optional
 	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
 }
 
+// ReplyRoute sends the events returned from a Subscriber target which match
+// its filters to a Destination.
+type ReplyRoute struct {
+	// Filters is an array of SubscriptionsAPIFilter that a reply must all
+	// match to be sent to this route. An empty list matches every reply.
+	// +optional
+	Filters []eventingduckv1.SubscriptionsAPIFilter `json:"filters,omitempty"`
+
+	// Destination is where the matching replies are sent.
+	Destination duckv1.Destination `json:"destination"`
+}
+
 // SubscriptionStatus (computed) for a subscription
 type SubscriptionStatus struct {
 	// inherits duck/v1 Status, which currently provides:
@@ -154,6 +173,11 @@ type SubscriptionStatusPhysicalSubscription struct {
 	// +optional
 	ReplyAudience *string `json:"replyAudience,omitempty"`
 
+	// ReplyRoutes are the fully resolved addresses of spec.replyRoutes, in
+	// the same order.
+	// +optional
+	ReplyRoutes []duckv1.Addressable `json:"replyRoutes,omitempty"`
+
 	// DeliveryStatus contains a resolved URL to the dead letter sink address, and any other
 	// resolved delivery options.
 	eventingduckv1.DeliveryStatus `json:",inline"`
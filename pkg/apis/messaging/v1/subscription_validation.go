@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/apimachinery/pkg/api/equality"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/apis/feature"
 	cn "knative.dev/eventing/pkg/crossnamespace"
 	"knative.dev/pkg/apis"
@@ -74,15 +75,34 @@ func (ss *SubscriptionSpec) Validate(ctx context.Context) *apis.FieldError {
 		}
 	}
 
+	for i, route := range ss.ReplyRoutes {
+		errs = errs.Also(route.Validate(ctx).ViaFieldIndex("replyRoutes", i))
+	}
+
 	if ss.Delivery != nil {
 		if fe := ss.Delivery.Validate(ctx); fe != nil {
 			errs = errs.Also(fe.ViaField("delivery"))
 		}
+		errs = errs.Also(ss.Delivery.ValidateUnordered().ViaField("delivery"))
+		errs = errs.Also(ss.Delivery.ValidateWithoutCircuitBreaker().ViaField("delivery"))
 	}
 
 	return errs
 }
 
+func (r *ReplyRoute) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if isDestinationNilOrEmpty(&r.Destination) {
+		errs = errs.Also(apis.ErrMissingField("destination"))
+	} else if fe := r.Destination.Validate(ctx); fe != nil {
+		errs = errs.Also(fe.ViaField("destination"))
+	}
+	if fe := eventingduckv1.ValidateSubscriptionAPIFiltersList(ctx, r.Filters); fe != nil {
+		errs = errs.Also(fe.ViaField("filters"))
+	}
+	return errs
+}
+
 func isDestinationNilOrEmpty(d *duckv1.Destination) bool {
 	return d == nil || equality.Semantic.DeepEqual(d, &duckv1.Destination{})
 }
@@ -92,8 +112,8 @@ func (s *Subscription) CheckImmutableFields(ctx context.Context, original *Subsc
 		return nil
 	}
 
-	// Only Subscriber and Reply are mutable.
-	ignoreArguments := cmpopts.IgnoreFields(SubscriptionSpec{}, "Subscriber", "Reply", "Delivery")
+	// Only Subscriber, Reply, ReplyRoutes and Delivery are mutable.
+	ignoreArguments := cmpopts.IgnoreFields(SubscriptionSpec{}, "Subscriber", "Reply", "ReplyRoutes", "Delivery")
 	if diff, err := kmp.ShortDiff(original.Spec, s.Spec, ignoreArguments); err != nil {
 		return &apis.FieldError{
 			Message: "Failed to diff Subscription",
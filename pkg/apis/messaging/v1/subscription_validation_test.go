@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"k8s.io/utils/pointer"
+	"k8s.io/utils/ptr"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
@@ -267,6 +268,26 @@ func TestSubscriptionSpecValidation(t *testing.T) {
 			Delivery:   getDelivery(backoffDelayInvalid),
 		},
 		want: apis.ErrInvalidValue(backoffDelayInvalid, "delivery.backoffDelay"),
+	}, {
+		name: "ordered Delivery",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidDestination(),
+			Delivery: &eventingduckv1.DeliverySpec{
+				Ordering: ptr.To(eventingduckv1.DeliveryOrderingUnordered),
+			},
+		},
+		want: apis.ErrDisallowedFields("delivery.ordering"),
+	}, {
+		name: "circuit breaker",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidDestination(),
+			Delivery: &eventingduckv1.DeliverySpec{
+				CircuitBreaker: &eventingduckv1.CircuitBreakerSpec{},
+			},
+		},
+		want: apis.ErrDisallowedFields("delivery.circuitBreaker"),
 	}, {
 		name: "non-empty Channel namespace",
 		c: &SubscriptionSpec{
@@ -295,6 +316,72 @@ func TestSubscriptionSpecValidation(t *testing.T) {
 	}
 }
 
+func TestSubscriptionSpecValidationReplyRoutes(t *testing.T) {
+	ctx := feature.ToContext(context.TODO(), feature.Flags{
+		feature.NewTriggerFilters: feature.Enabled,
+	})
+
+	tests := []struct {
+		name string
+		c    *SubscriptionSpec
+		want *apis.FieldError
+	}{{
+		name: "valid with reply routes",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidDestination(),
+			Reply:      getValidReply(),
+			ReplyRoutes: []ReplyRoute{{
+				Filters: []eventingduckv1.SubscriptionsAPIFilter{{
+					Exact: map[string]string{"type": "order.rejected"},
+				}},
+				Destination: *getValidReply(),
+			}, {
+				Destination: *getValidDestination(),
+			}},
+		},
+		want: nil,
+	}, {
+		name: "missing reply route destination",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidDestination(),
+			ReplyRoutes: []ReplyRoute{{
+				Filters: []eventingduckv1.SubscriptionsAPIFilter{{
+					Exact: map[string]string{"type": "order.rejected"},
+				}},
+			}},
+		},
+		want: apis.ErrMissingField("replyRoutes[0].destination"),
+	}, {
+		name: "invalid reply route filter",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidDestination(),
+			ReplyRoutes: []ReplyRoute{{
+				Destination: *getValidReply(),
+			}, {
+				Filters: []eventingduckv1.SubscriptionsAPIFilter{{
+					Range: map[string]eventingduckv1.SubscriptionsAPIFilterRange{
+						"priority": {},
+					},
+				}},
+				Destination: *getValidReply(),
+			}},
+		},
+		want: apis.ErrMissingOneOf("min", "max").ViaFieldKey("range", "priority").ViaIndex(0).ViaField("filters").ViaFieldIndex("replyRoutes", 1),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.c.Validate(ctx)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
 func TestSubscriptionSpecValidationWithKRefGroupFeatureEnabled(t *testing.T) {
 	tests := []struct {
 		name string
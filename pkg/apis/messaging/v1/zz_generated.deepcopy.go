@@ -228,6 +228,11 @@ func (in *InMemoryChannelList) DeepCopyObject() runtime.Object {
 func (in *InMemoryChannelSpec) DeepCopyInto(out *InMemoryChannelSpec) {
 	*out = *in
 	in.ChannelableSpec.DeepCopyInto(&out.ChannelableSpec)
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(InMemoryChannelPersistence)
+		**out = **in
+	}
 	return
 }
 
@@ -258,6 +263,30 @@ func (in *InMemoryChannelStatus) DeepCopy() *InMemoryChannelStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplyRoute) DeepCopyInto(out *ReplyRoute) {
+	*out = *in
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]apisduckv1.SubscriptionsAPIFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplyRoute.
+func (in *ReplyRoute) DeepCopy() *ReplyRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplyRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
@@ -333,6 +362,13 @@ func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 		*out = new(duckv1.Destination)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplyRoutes != nil {
+		in, out := &in.ReplyRoutes, &out.ReplyRoutes
+		*out = make([]ReplyRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Delivery != nil {
 		in, out := &in.Delivery, &out.Delivery
 		*out = new(apisduckv1.DeliverySpec)
@@ -407,6 +443,13 @@ func (in *SubscriptionStatusPhysicalSubscription) DeepCopyInto(out *Subscription
 		*out = new(string)
 		**out = **in
 	}
+	if in.ReplyRoutes != nil {
+		in, out := &in.ReplyRoutes, &out.ReplyRoutes
+		*out = make([]duckv1.Addressable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.DeliveryStatus.DeepCopyInto(&out.DeliveryStatus)
 	return
 }
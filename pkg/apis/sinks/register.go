@@ -33,6 +33,18 @@ var (
 		Group:    GroupName,
 		Resource: "jobsinks",
 	}
+
+	// ReplaySinkResource respresents a Knative Eventing sink ReplaySink
+	ReplaySinkResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "replaysinks",
+	}
+
+	// AggregatorResource respresents a Knative Eventing sink Aggregator
+	AggregatorResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "aggregators",
+	}
 )
 
 type Config struct {
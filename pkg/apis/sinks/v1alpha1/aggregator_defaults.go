@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// DefaultAggregatorCorrelationAttribute is the default attribute the events received by an
+	// Aggregator are grouped by.
+	DefaultAggregatorCorrelationAttribute = "correlationid"
+
+	// DefaultAggregatorType is the default type of the aggregate events.
+	DefaultAggregatorType = "dev.knative.sinks.aggregate"
+)
+
+func (a *Aggregator) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, a.ObjectMeta)
+	a.Spec.SetDefaults(ctx)
+}
+
+func (as *AggregatorSpec) SetDefaults(ctx context.Context) {
+	if as.CorrelationAttribute == "" {
+		as.CorrelationAttribute = DefaultAggregatorCorrelationAttribute
+	}
+	if as.Type == "" {
+		as.Type = DefaultAggregatorType
+	}
+	as.Sink.SetDefaults(ctx)
+}
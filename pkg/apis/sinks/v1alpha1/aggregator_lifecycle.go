@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// AggregatorConditionReady has status True when the Aggregator is ready to receive events.
+	AggregatorConditionReady = apis.ConditionReady
+
+	AggregatorConditionAddressable apis.ConditionType = "Addressable"
+
+	// AggregatorConditionSinkProvided has status True when the Aggregator has been configured
+	// with a sink target.
+	AggregatorConditionSinkProvided apis.ConditionType = "SinkProvided"
+
+	// AggregatorConditionEventPoliciesReady has status True when all the applying EventPolicies
+	// for this Aggregator are ready.
+	AggregatorConditionEventPoliciesReady apis.ConditionType = "EventPoliciesReady"
+
+	// AggregatorConditionOIDCIdentityCreated has status True when the OIDCIdentity has been created.
+	// This condition is only relevant if the OIDC feature is enabled.
+	AggregatorConditionOIDCIdentityCreated apis.ConditionType = "OIDCIdentityCreated"
+)
+
+var AggregatorCondSet = apis.NewLivingConditionSet(
+	AggregatorConditionAddressable,
+	AggregatorConditionSinkProvided,
+	AggregatorConditionEventPoliciesReady,
+	AggregatorConditionOIDCIdentityCreated,
+)
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*Aggregator) GetConditionSet() apis.ConditionSet {
+	return AggregatorCondSet
+}
+
+// GetUntypedSpec returns the spec of the Aggregator.
+func (a *Aggregator) GetUntypedSpec() interface{} {
+	return a.Spec
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (a *Aggregator) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Aggregator")
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *AggregatorStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return AggregatorCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level Condition.
+func (s *AggregatorStatus) GetTopLevelCondition() *apis.Condition {
+	return AggregatorCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *AggregatorStatus) IsReady() bool {
+	return AggregatorCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *AggregatorStatus) InitializeConditions() {
+	AggregatorCondSet.Manage(s).InitializeConditions()
+}
+
+// SetAddress sets the address of the Aggregator and marks the Addressable condition accordingly.
+func (s *AggregatorStatus) SetAddress(address *duckv1.Addressable) {
+	s.Address = address
+	if address == nil || address.URL.IsEmpty() {
+		AggregatorCondSet.Manage(s).MarkFalse(AggregatorConditionAddressable, "EmptyHostname", "hostname is the empty string")
+	} else {
+		AggregatorCondSet.Manage(s).MarkTrue(AggregatorConditionAddressable)
+	}
+}
+
+// MarkSink sets the condition that the Aggregator has a sink configured.
+func (s *AggregatorStatus) MarkSink(addr *duckv1.Addressable) {
+	if addr != nil {
+		s.SinkURI = addr.URL
+		s.SinkCACerts = addr.CACerts
+		s.SinkAudience = addr.Audience
+		AggregatorCondSet.Manage(s).MarkTrue(AggregatorConditionSinkProvided)
+	} else {
+		AggregatorCondSet.Manage(s).MarkFalse(AggregatorConditionSinkProvided, "SinkEmpty", "Sink has resolved to empty.")
+	}
+}
+
+// MarkNoSink sets the condition that the Aggregator does not have a sink configured.
+func (s *AggregatorStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	s.SinkURI = nil
+	s.SinkCACerts = nil
+	s.SinkAudience = nil
+	AggregatorCondSet.Manage(s).MarkFalse(AggregatorConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkEventPoliciesFailed marks the EventPoliciesReady condition to False with the given reason and message.
+func (s *AggregatorStatus) MarkEventPoliciesFailed(reason, messageFormat string, messageA ...interface{}) {
+	AggregatorCondSet.Manage(s).MarkFalse(AggregatorConditionEventPoliciesReady, reason, messageFormat, messageA...)
+}
+
+// MarkEventPoliciesUnknown marks the EventPoliciesReady condition to Unknown with the given reason and message.
+func (s *AggregatorStatus) MarkEventPoliciesUnknown(reason, messageFormat string, messageA ...interface{}) {
+	AggregatorCondSet.Manage(s).MarkUnknown(AggregatorConditionEventPoliciesReady, reason, messageFormat, messageA...)
+}
+
+// MarkEventPoliciesTrue marks the EventPoliciesReady condition to True.
+func (s *AggregatorStatus) MarkEventPoliciesTrue() {
+	AggregatorCondSet.Manage(s).MarkTrue(AggregatorConditionEventPoliciesReady)
+}
+
+// MarkEventPoliciesTrueWithReason marks the EventPoliciesReady condition to True with the given reason and message.
+func (s *AggregatorStatus) MarkEventPoliciesTrueWithReason(reason, messageFormat string, messageA ...interface{}) {
+	AggregatorCondSet.Manage(s).MarkTrueWithReason(AggregatorConditionEventPoliciesReady, reason, messageFormat, messageA...)
+}
+
+func (s *AggregatorStatus) MarkOIDCIdentityCreatedSucceeded() {
+	AggregatorCondSet.Manage(s).MarkTrue(AggregatorConditionOIDCIdentityCreated)
+}
+
+func (s *AggregatorStatus) MarkOIDCIdentityCreatedSucceededWithReason(reason, messageFormat string, messageA ...interface{}) {
+	AggregatorCondSet.Manage(s).MarkTrueWithReason(AggregatorConditionOIDCIdentityCreated, reason, messageFormat, messageA...)
+}
+
+func (s *AggregatorStatus) MarkOIDCIdentityCreatedFailed(reason, messageFormat string, messageA ...interface{}) {
+	AggregatorCondSet.Manage(s).MarkFalse(AggregatorConditionOIDCIdentityCreated, reason, messageFormat, messageA...)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestAggregatorGetConditionSet(t *testing.T) {
+	a := &Aggregator{}
+
+	if got, want := a.GetConditionSet().GetTopLevelConditionType(), apis.ConditionReady; got != want {
+		t.Errorf("GetTopLevelCondition=%v, want=%v", got, want)
+	}
+}
+
+func TestAggregatorInitializeConditions(t *testing.T) {
+	as := &AggregatorStatus{}
+	as.InitializeConditions()
+
+	want := &AggregatorStatus{
+		Status: duckv1.Status{
+			Conditions: []apis.Condition{{
+				Type:   AggregatorConditionAddressable,
+				Status: corev1.ConditionUnknown,
+			}, {
+				Type:   AggregatorConditionEventPoliciesReady,
+				Status: corev1.ConditionUnknown,
+			}, {
+				Type:   AggregatorConditionOIDCIdentityCreated,
+				Status: corev1.ConditionUnknown,
+			}, {
+				Type:   AggregatorConditionReady,
+				Status: corev1.ConditionUnknown,
+			}, {
+				Type:   AggregatorConditionSinkProvided,
+				Status: corev1.ConditionUnknown,
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, as, ignoreAllButTypeAndStatus); diff != "" {
+		t.Error("unexpected conditions (-want, +got) =", diff)
+	}
+}
+
+func TestAggregatorIsReady(t *testing.T) {
+	sink := &duckv1.Addressable{URL: apis.HTTP("sink.example.com")}
+	address := &duckv1.Addressable{URL: apis.HTTP("aggregator.knative-eventing.svc.cluster.local")}
+
+	tests := map[string]struct {
+		mark func(*AggregatorStatus)
+		want bool
+	}{
+		"nothing marked": {
+			mark: func(*AggregatorStatus) {},
+		},
+		"all marked": {
+			mark: func(as *AggregatorStatus) {
+				as.SetAddress(address)
+				as.MarkSink(sink)
+				as.MarkEventPoliciesTrue()
+				as.MarkOIDCIdentityCreatedSucceeded()
+			},
+			want: true,
+		},
+		"no sink": {
+			mark: func(as *AggregatorStatus) {
+				as.SetAddress(address)
+				as.MarkNoSink("NotFound", "")
+				as.MarkEventPoliciesTrue()
+				as.MarkOIDCIdentityCreatedSucceeded()
+			},
+		},
+		"empty sink": {
+			mark: func(as *AggregatorStatus) {
+				as.SetAddress(address)
+				as.MarkSink(nil)
+				as.MarkEventPoliciesTrue()
+				as.MarkOIDCIdentityCreatedSucceeded()
+			},
+		},
+		"no address": {
+			mark: func(as *AggregatorStatus) {
+				as.SetAddress(nil)
+				as.MarkSink(sink)
+				as.MarkEventPoliciesTrue()
+				as.MarkOIDCIdentityCreatedSucceeded()
+			},
+		},
+		"event policies not ready": {
+			mark: func(as *AggregatorStatus) {
+				as.SetAddress(address)
+				as.MarkSink(sink)
+				as.MarkEventPoliciesFailed("EventPoliciesNotReady", "")
+				as.MarkOIDCIdentityCreatedSucceeded()
+			},
+		},
+		"OIDC identity failed": {
+			mark: func(as *AggregatorStatus) {
+				as.SetAddress(address)
+				as.MarkSink(sink)
+				as.MarkEventPoliciesTrue()
+				as.MarkOIDCIdentityCreatedFailed("Failed", "")
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			as := &AggregatorStatus{}
+			as.InitializeConditions()
+			tc.mark(as)
+			if got := as.IsReady(); got != tc.want {
+				t.Errorf("IsReady() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:defaulter-gen=true
+
+// Aggregator is a sink grouping the events it receives by a correlation attribute, and sending
+// one aggregate event containing the events of a group to its sink once the group is complete.
+type Aggregator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AggregatorSpec   `json:"spec,omitempty"`
+	Status AggregatorStatus `json:"status,omitempty"`
+}
+
+// Check the interfaces that Aggregator should be implementing.
+var (
+	_ runtime.Object     = (*Aggregator)(nil)
+	_ kmeta.OwnerRefable = (*Aggregator)(nil)
+	_ apis.Validatable   = (*Aggregator)(nil)
+	_ apis.Defaultable   = (*Aggregator)(nil)
+	_ apis.HasSpec       = (*Aggregator)(nil)
+	_ duckv1.KRShaped    = (*Aggregator)(nil)
+)
+
+// AggregatorSpec defines the desired state of the Aggregator.
+type AggregatorSpec struct {
+	// CorrelationAttribute is the name of the CloudEvents attribute or extension the events
+	// are grouped by. The events without this attribute are rejected.
+	// Defaults to correlationid.
+	// +optional
+	CorrelationAttribute string `json:"correlationAttribute,omitempty"`
+
+	// Completion defines when a group of events is complete. A group is complete as soon as
+	// any of the conditions is met. A group which isn't complete after a day is dropped. The
+	// events exceeding 1000 events in a group are rejected, unless they complete it. The groups
+	// are held in memory, the incomplete groups are lost when the aggregator restarts.
+	Completion AggregatorCompletion `json:"completion"`
+
+	// Type is the type of the aggregate events.
+	// Defaults to dev.knative.sinks.aggregate.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Sink is where the aggregate events are sent.
+	Sink duckv1.Destination `json:"sink"`
+}
+
+// AggregatorMaxGroupEvents is the maximum number of events of a group.
+const AggregatorMaxGroupEvents = 1000
+
+// AggregatorCompletion defines when a group of events is complete. At least one condition
+// must be set.
+type AggregatorCompletion struct {
+	// Count completes a group once it contains this number of events, up to 1000.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// Timeout completes a group once this time has passed since its first event, as an
+	// ISO-8601 duration.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+
+	// CESQL completes a group once it receives an event matching this CESQL expression. The
+	// matching event is part of the group.
+	// +optional
+	CESQL string `json:"cesql,omitempty"`
+}
+
+// AggregatorStatus defines the observed state of Aggregator.
+type AggregatorStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// AddressStatus is the part where the Aggregator fulfills the Addressable contract.
+	// It exposes the endpoint as an URI to get events delivered.
+	// +optional
+	duckv1.AddressStatus `json:",inline"`
+
+	// SinkURI is the current active sink URI that has been configured for the Aggregator.
+	// +optional
+	SinkURI *apis.URL `json:"sinkUri,omitempty"`
+
+	// SinkCACerts are Certification Authority (CA) certificates in PEM format
+	// according to https://www.rfc-editor.org/rfc/rfc7468.
+	// +optional
+	SinkCACerts *string `json:"sinkCACerts,omitempty"`
+
+	// SinkAudience is the OIDC audience of the sink.
+	// +optional
+	SinkAudience *string `json:"sinkAudience,omitempty"`
+
+	// Auth defines the attributes that provide the generated service account
+	// name in the resource status.
+	// +optional
+	Auth *duckv1.AuthStatus `json:"auth,omitempty"`
+
+	// AppliedEventPoliciesStatus contains the list of EventPolicies which apply to this Aggregator
+	// +optional
+	eventingduckv1.AppliedEventPoliciesStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AggregatorList contains a list of Aggregator.
+type AggregatorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Aggregator `json:"items"`
+}
+
+// GetStatus retrieves the status of the Aggregator. Implements the KRShaped interface.
+func (a *Aggregator) GetStatus() *duckv1.Status {
+	return &a.Status.Status
+}
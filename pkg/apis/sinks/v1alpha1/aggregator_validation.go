@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/rickb777/date/period"
+	"knative.dev/pkg/apis"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+// validAttributeName matches the names of the CloudEvents attributes.
+var validAttributeName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+func (a *Aggregator) Validate(ctx context.Context) *apis.FieldError {
+	ctx = apis.WithinParent(ctx, a.ObjectMeta)
+	return a.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (as *AggregatorSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if !validAttributeName.MatchString(as.CorrelationAttribute) {
+		errs = errs.Also(apis.ErrInvalidValue(as.CorrelationAttribute, "correlationAttribute", "Attribute name must start with a letter and can only contain lowercase alphanumeric"))
+	}
+
+	errs = errs.Also(as.Completion.Validate(ctx).ViaField("completion"))
+
+	if fe := as.Sink.Validate(ctx); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+
+	return errs
+}
+
+func (ac *AggregatorCompletion) Validate(ctx context.Context) *apis.FieldError {
+	if ac.Count == nil && ac.Timeout == nil && ac.CESQL == "" {
+		return apis.ErrMissingOneOf("count", "timeout", "cesql")
+	}
+
+	var errs *apis.FieldError
+	if ac.Count != nil && *ac.Count < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*ac.Count, "count", "count must be at least 1"))
+	} else if ac.Count != nil && *ac.Count > AggregatorMaxGroupEvents {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*ac.Count, 1, AggregatorMaxGroupEvents, "count"))
+	}
+	if ac.Timeout != nil {
+		p, err := period.Parse(*ac.Timeout)
+		if err != nil || p.IsZero() || p.IsNegative() {
+			errs = errs.Also(apis.ErrInvalidValue(*ac.Timeout, "timeout"))
+		}
+	}
+	errs = errs.Also(eventingv1.ValidateCESQLExpression(ctx, ac.CESQL).ViaField("cesql"))
+	return errs
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestAggregatorValidation(t *testing.T) {
+	sink := duckv1.Destination{URI: apis.HTTP("sink.example.com")}
+
+	tests := map[string]struct {
+		spec    AggregatorSpec
+		wantErr string
+	}{
+		"count": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{Count: ptr.To[int32](3)},
+				Sink:       sink,
+			},
+		},
+		"all the conditions": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{
+					Count:   ptr.To[int32](3),
+					Timeout: ptr.To("PT30S"),
+					CESQL:   "type = 'order.closed'",
+				},
+				Sink: sink,
+			},
+		},
+		"no condition": {
+			spec: AggregatorSpec{
+				Sink: sink,
+			},
+			wantErr: "expected exactly one, got neither: spec.completion.cesql, spec.completion.count, spec.completion.timeout",
+		},
+		"zero count": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{Count: ptr.To[int32](0)},
+				Sink:       sink,
+			},
+			wantErr: "invalid value: 0: spec.completion.count\ncount must be at least 1",
+		},
+		"count too large": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{Count: ptr.To[int32](1001)},
+				Sink:       sink,
+			},
+			wantErr: "expected 1 <= 1001 <= 1000: spec.completion.count",
+		},
+		"invalid timeout": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{Timeout: ptr.To("30s")},
+				Sink:       sink,
+			},
+			wantErr: "invalid value: 30s: spec.completion.timeout",
+		},
+		"zero timeout": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{Timeout: ptr.To("PT0S")},
+				Sink:       sink,
+			},
+			wantErr: "invalid value: PT0S: spec.completion.timeout",
+		},
+		"invalid cesql": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{CESQL: "type = "},
+				Sink:       sink,
+			},
+			wantErr: "invalid value: type = : spec.completion.cesql",
+		},
+		"invalid correlation attribute": {
+			spec: AggregatorSpec{
+				CorrelationAttribute: "order-id",
+				Completion:           AggregatorCompletion{Count: ptr.To[int32](3)},
+				Sink:                 sink,
+			},
+			wantErr: "invalid value: order-id: spec.correlationAttribute\nAttribute name must start with a letter and can only contain lowercase alphanumeric",
+		},
+		"no sink": {
+			spec: AggregatorSpec{
+				Completion: AggregatorCompletion{Count: ptr.To[int32](3)},
+			},
+			wantErr: "expected at least one, got none: spec.sink.ref, spec.sink.uri",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := &Aggregator{Spec: tc.spec}
+			a.SetDefaults(context.Background())
+			err := a.Validate(context.Background())
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Error("unexpected error:", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("expected error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAggregatorDefaults(t *testing.T) {
+	a := &Aggregator{}
+	a.SetDefaults(context.Background())
+	if a.Spec.CorrelationAttribute != DefaultAggregatorCorrelationAttribute {
+		t.Errorf("expected the default correlation attribute %s, got %s", DefaultAggregatorCorrelationAttribute, a.Spec.CorrelationAttribute)
+	}
+	if a.Spec.Type != DefaultAggregatorType {
+		t.Errorf("expected the default type %s, got %s", DefaultAggregatorType, a.Spec.Type)
+	}
+
+	a = &Aggregator{Spec: AggregatorSpec{CorrelationAttribute: "orderid", Type: "order.aggregate"}}
+	a.SetDefaults(context.Background())
+	if a.Spec.CorrelationAttribute != "orderid" || a.Spec.Type != "order.aggregate" {
+		t.Errorf("expected the attributes to be kept, got %s and %s", a.Spec.CorrelationAttribute, a.Spec.Type)
+	}
+}
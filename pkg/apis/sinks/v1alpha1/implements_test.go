@@ -30,6 +30,10 @@ func TestTypesImplements(t *testing.T) {
 	}{
 		{instance: &JobSink{}, iface: &duckv1.Conditions{}},
 		{instance: &JobSink{}, iface: &duckv1.Addressable{}},
+		{instance: &ReplaySink{}, iface: &duckv1.Conditions{}},
+		{instance: &ReplaySink{}, iface: &duckv1.Addressable{}},
+		{instance: &Aggregator{}, iface: &duckv1.Conditions{}},
+		{instance: &Aggregator{}, iface: &duckv1.Addressable{}},
 	}
 	for _, tc := range testCases {
 		if err := duck.VerifyType(tc.instance, tc.iface); err != nil {
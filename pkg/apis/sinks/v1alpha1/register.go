@@ -47,6 +47,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&JobSink{},
 		&JobSinkList{},
+		&ReplaySink{},
+		&ReplaySinkList{},
+		&Aggregator{},
+		&AggregatorList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -62,6 +62,10 @@ func TestKnownTypes(t *testing.T) {
 	for _, name := range []string{
 		"JobSink",
 		"JobSinkList",
+		"ReplaySink",
+		"ReplaySinkList",
+		"Aggregator",
+		"AggregatorList",
 	} {
 		if _, ok := types[name]; !ok {
 			t.Errorf("Did not find %q as registered type", name)
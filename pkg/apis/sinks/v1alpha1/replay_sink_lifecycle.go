@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// ReplaySinkConditionReady has status True when the ReplaySink is ready to receive events.
+	ReplaySinkConditionReady = apis.ConditionReady
+
+	ReplaySinkConditionAddressable apis.ConditionType = "Addressable"
+)
+
+var ReplaySinkCondSet = apis.NewLivingConditionSet(
+	ReplaySinkConditionAddressable,
+)
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*ReplaySink) GetConditionSet() apis.ConditionSet {
+	return ReplaySinkCondSet
+}
+
+// GetUntypedSpec returns the spec of the ReplaySink.
+func (sink *ReplaySink) GetUntypedSpec() interface{} {
+	return sink.Spec
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (sink *ReplaySink) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("ReplaySink")
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *ReplaySinkStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return ReplaySinkCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level Condition.
+func (s *ReplaySinkStatus) GetTopLevelCondition() *apis.Condition {
+	return ReplaySinkCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *ReplaySinkStatus) IsReady() bool {
+	return ReplaySinkCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *ReplaySinkStatus) InitializeConditions() {
+	ReplaySinkCondSet.Manage(s).InitializeConditions()
+}
+
+// SetAddress sets the address of the ReplaySink and marks the Addressable condition accordingly.
+func (s *ReplaySinkStatus) SetAddress(address *duckv1.Addressable) {
+	s.Address = address
+	if address == nil || address.URL.IsEmpty() {
+		ReplaySinkCondSet.Manage(s).MarkFalse(ReplaySinkConditionAddressable, "EmptyHostname", "hostname is the empty string")
+	} else {
+		ReplaySinkCondSet.Manage(s).MarkTrue(ReplaySinkConditionAddressable)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestReplaySinkGetConditionSet(t *testing.T) {
+	r := &ReplaySink{}
+
+	if got, want := r.GetConditionSet().GetTopLevelConditionType(), apis.ConditionReady; got != want {
+		t.Errorf("GetTopLevelCondition=%v, want=%v", got, want)
+	}
+}
+
+func TestReplaySinkInitializeConditions(t *testing.T) {
+	rs := &ReplaySinkStatus{}
+	rs.InitializeConditions()
+
+	want := &ReplaySinkStatus{
+		Status: duckv1.Status{
+			Conditions: []apis.Condition{{
+				Type:   ReplaySinkConditionAddressable,
+				Status: corev1.ConditionUnknown,
+			}, {
+				Type:   ReplaySinkConditionReady,
+				Status: corev1.ConditionUnknown,
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, rs, ignoreAllButTypeAndStatus); diff != "" {
+		t.Error("unexpected conditions (-want, +got) =", diff)
+	}
+}
+
+func TestReplaySinkSetAddress(t *testing.T) {
+	tests := map[string]struct {
+		address *duckv1.Addressable
+		want    bool
+	}{
+		"nil address": {},
+		"empty address": {
+			address: &duckv1.Addressable{},
+		},
+		"address": {
+			address: &duckv1.Addressable{URL: apis.HTTP("replay.knative-eventing.svc.cluster.local")},
+			want:    true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rs := &ReplaySinkStatus{}
+			rs.InitializeConditions()
+			rs.SetAddress(tc.address)
+			if got := rs.IsReady(); got != tc.want {
+				t.Errorf("IsReady() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:defaulter-gen=true
+
+// ReplaySink is a dead letter sink storing the events it receives, so that they can be listed,
+// inspected and sent again to their destination once it has been fixed.
+type ReplaySink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReplaySinkSpec   `json:"spec,omitempty"`
+	Status ReplaySinkStatus `json:"status,omitempty"`
+}
+
+// Check the interfaces that ReplaySink should be implementing.
+var (
+	_ runtime.Object     = (*ReplaySink)(nil)
+	_ kmeta.OwnerRefable = (*ReplaySink)(nil)
+	_ apis.Validatable   = (*ReplaySink)(nil)
+	_ apis.Defaultable   = (*ReplaySink)(nil)
+	_ apis.HasSpec       = (*ReplaySink)(nil)
+	_ duckv1.KRShaped    = (*ReplaySink)(nil)
+)
+
+// ReplaySinkSpec defines the desired state of the ReplaySink.
+type ReplaySinkSpec struct {
+	// Retention is how long the received events are kept, as an ISO-8601 duration.
+	// Defaults to P7D.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	Retention *string `json:"retention,omitempty"`
+}
+
+// ReplaySinkStatus defines the observed state of ReplaySink.
+type ReplaySinkStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// AddressStatus is the part where the ReplaySink fulfills the Addressable contract.
+	// It exposes the endpoint as an URI to get events delivered.
+	// +optional
+	duckv1.AddressStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ReplaySinkList contains a list of ReplaySink.
+type ReplaySinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReplaySink `json:"items"`
+}
+
+// GetStatus retrieves the status of the ReplaySink. Implements the KRShaped interface.
+func (sink *ReplaySink) GetStatus() *duckv1.Status {
+	return &sink.Status.Status
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/utils/ptr"
+)
+
+func TestReplaySinkValidation(t *testing.T) {
+	tests := map[string]struct {
+		spec    ReplaySinkSpec
+		wantErr string
+	}{
+		"no retention": {},
+		"retention": {
+			spec: ReplaySinkSpec{Retention: ptr.To("P1D")},
+		},
+		"invalid retention": {
+			spec:    ReplaySinkSpec{Retention: ptr.To("1d")},
+			wantErr: "invalid value: 1d: spec.retention",
+		},
+		"zero retention": {
+			spec:    ReplaySinkSpec{Retention: ptr.To("PT0S")},
+			wantErr: "invalid value: PT0S: spec.retention",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sink := &ReplaySink{Spec: tc.spec}
+			err := sink.Validate(context.Background())
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Error("unexpected error:", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("expected error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReplaySinkDefaults(t *testing.T) {
+	sink := &ReplaySink{}
+	sink.SetDefaults(context.Background())
+	if sink.Spec.Retention == nil || *sink.Spec.Retention != DefaultReplaySinkRetention {
+		t.Errorf("expected the default retention %s, got %v", DefaultReplaySinkRetention, sink.Spec.Retention)
+	}
+
+	sink = &ReplaySink{Spec: ReplaySinkSpec{Retention: ptr.To("PT1H")}}
+	sink.SetDefaults(context.Background())
+	if *sink.Spec.Retention != "PT1H" {
+		t.Errorf("expected the retention to be kept, got %s", *sink.Spec.Retention)
+	}
+}
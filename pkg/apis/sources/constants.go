@@ -30,6 +30,9 @@ const (
 	ApiServerSourceUpdateRefEventType = "dev.knative.apiserver.ref.update"
 	// ApiServerSourceDeleteRefEventType is the ApiServerSource CloudEvent type for ref deletions.
 	ApiServerSourceDeleteRefEventType = "dev.knative.apiserver.ref.delete"
+
+	// ApiServerSourceUpdateDiffEventType is the ApiServerSource CloudEvent type for diff updates.
+	ApiServerSourceUpdateDiffEventType = "dev.knative.apiserver.diff.update"
 )
 
 // ApiServerSourceEventReferenceModeTypes is the list of CloudEvent types the ApiServerSource with EventMode of ReferenceMode emits.
@@ -45,3 +48,10 @@ var ApiServerSourceEventResourceModeTypes = []string{
 	ApiServerSourceDeleteEventType,
 	ApiServerSourceUpdateEventType,
 }
+
+// ApiServerSourceEventDiffModeTypes is the list of CloudEvent types the ApiServerSource with EventMode of DiffMode emits.
+var ApiServerSourceEventDiffModeTypes = []string{
+	ApiServerSourceAddEventType,
+	ApiServerSourceDeleteEventType,
+	ApiServerSourceUpdateDiffEventType,
+}
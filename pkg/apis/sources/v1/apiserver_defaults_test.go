@@ -77,6 +77,57 @@ func TestApiServerSourceDefaults(t *testing.T) {
 				},
 			},
 		},
+		"Diff EventMode": {
+			initial: ApiServerSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name",
+					Namespace: "test-namespace",
+				},
+				Spec: ApiServerSourceSpec{
+					EventMode: DiffMode,
+					Resources: []APIVersionKindSelector{{
+						APIVersion: "v1",
+						Kind:       "Foo",
+					}},
+					ServiceAccountName: "default",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			expected: ApiServerSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name",
+					Namespace: "test-namespace",
+				},
+				Spec: ApiServerSourceSpec{
+					EventMode: DiffMode,
+					Diff: &ApiServerSourceDiff{
+						Format: MergePatchDiffFormat,
+					},
+					Resources: []APIVersionKindSelector{{
+						APIVersion: "v1",
+						Kind:       "Foo",
+					}},
+					ServiceAccountName: "default",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+		},
 		"no ServiceAccountName": {
 			initial: ApiServerSource{
 				ObjectMeta: metav1.ObjectMeta{
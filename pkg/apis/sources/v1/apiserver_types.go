@@ -73,10 +73,16 @@ type ApiServerSourceSpec struct {
 	// EventMode controls the format of the event.
 	// `Reference` sends a dataref event type for the resource under watch.
 	// `Resource` send the full resource lifecycle event.
+	// `Diff` sends the full resource for additions and deletions, and the patch
+	// from the old to the new resource for updates.
 	// Defaults to `Reference`
 	// +optional
 	EventMode string `json:"mode,omitempty"`
 
+	// Diff configures the patches sent for updates when EventMode is `Diff`.
+	// +optional
+	Diff *ApiServerSourceDiff `json:"diff,omitempty"`
+
 	// ServiceAccountName is the name of the ServiceAccount to use to run this
 	// source. Defaults to default if not set.
 	// +optional
@@ -96,6 +102,29 @@ type ApiServerSourceSpec struct {
 	//
 	// +optional
 	Filters []eventingv1.SubscriptionsAPIFilter `json:"filters,omitempty"`
+
+	// ResumeWatches persists the last resource version seen for each watched
+	// resource, so that the watches resume from it after a restart of the adapter
+	// and only the changes are sent. The resources are listed again, and sent as
+	// events with the `relist` extension set to "true", only when that version
+	// expired. The ServiceAccount needs to get and update ConfigMaps.
+	// +optional
+	ResumeWatches bool `json:"resumeWatches,omitempty"`
+}
+
+// ApiServerSourceDiff configures the patches sent for updates in `Diff` mode.
+type ApiServerSourceDiff struct {
+	// Format of the patches.
+	// `MergePatch` sends a JSON merge patch (RFC 7386).
+	// `JSONPatch` sends a JSON patch (RFC 6902).
+	// Defaults to `MergePatch`
+	// +optional
+	Format string `json:"format,omitempty"`
+
+	// IgnoreStatusChanges drops the updates that only change the status or
+	// the managed fields of the resource.
+	// +optional
+	IgnoreStatusChanges bool `json:"ignoreStatusChanges,omitempty"`
 }
 
 // ApiServerSourceStatus defines the observed state of ApiServerSource
@@ -32,6 +32,14 @@ const (
 	ReferenceMode = "Reference"
 	// ResourceMode produces payloads of ResourceEvent
 	ResourceMode = "Resource"
+	// DiffMode produces payloads of ResourceEvent for additions and deletions,
+	// and of patches for updates
+	DiffMode = "Diff"
+
+	// MergePatchDiffFormat produces JSON merge patches (RFC 7386)
+	MergePatchDiffFormat = "MergePatch"
+	// JSONPatchDiffFormat produces JSON patches (RFC 6902)
+	JSONPatchDiffFormat = "JSONPatch"
 )
 
 func (c *ApiServerSource) Validate(ctx context.Context) *apis.FieldError {
@@ -43,12 +51,24 @@ func (cs *ApiServerSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 
 	// Validate mode, if can be empty or set as certain value
 	switch cs.EventMode {
-	case ReferenceMode, ResourceMode:
+	case ReferenceMode, ResourceMode, DiffMode:
 	// EventMode is valid.
 	default:
 		errs = errs.Also(apis.ErrInvalidValue(cs.EventMode, "mode"))
 	}
 
+	if cs.Diff != nil {
+		if cs.EventMode != DiffMode {
+			errs = errs.Also(apis.ErrDisallowedFields("diff"))
+		}
+		switch cs.Diff.Format {
+		case "", MergePatchDiffFormat, JSONPatchDiffFormat:
+		// Format is valid.
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(cs.Diff.Format, "format").ViaField("diff"))
+		}
+	}
+
 	// Validate sink
 	errs = errs.Also(cs.Sink.Validate(ctx).ViaField("sink"))
 
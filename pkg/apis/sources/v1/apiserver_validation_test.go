@@ -92,6 +92,71 @@ func TestAPIServerValidation(t *testing.T) {
 			errs = errs.Also(apis.ErrInvalidValue("Test", "mode"))
 			return errs
 		}(),
+	}, {
+		name: "valid diff mode",
+		spec: ApiServerSourceSpec{
+			EventMode: "Diff",
+			Diff: &ApiServerSourceDiff{
+				Format:              "JSONPatch",
+				IgnoreStatusChanges: true,
+			},
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Foo",
+			}},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid diff format",
+		spec: ApiServerSourceSpec{
+			EventMode: "Diff",
+			Diff: &ApiServerSourceDiff{
+				Format: "StrategicMergePatch",
+			},
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Foo",
+			}},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("StrategicMergePatch", "diff.format"),
+	}, {
+		name: "diff without diff mode",
+		spec: ApiServerSourceSpec{
+			EventMode: "Resource",
+			Diff:      &ApiServerSourceDiff{},
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Foo",
+			}},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("diff"),
 	}, {
 		name: "invalid apiVersion",
 		spec: ApiServerSourceSpec{
@@ -38,6 +38,10 @@ const (
 
 	// PingSourceConditionOIDCIdentityCreated has status True when the PingSource has had it's OIDC identity created.
 	PingSourceConditionOIDCIdentityCreated apis.ConditionType = "OIDCIdentityCreated"
+
+	// PingSourceConditionCompleted has status True when the PingSource firing once has sent its event.
+	// It doesn't affect the readiness of the PingSource.
+	PingSourceConditionCompleted apis.ConditionType = "Completed"
 )
 
 var PingSourceCondSet = apis.NewLivingConditionSet(
@@ -48,6 +52,16 @@ var PingSourceCondSet = apis.NewLivingConditionSet(
 const (
 	// PingSourceEventType is the default PingSource CloudEvent type.
 	PingSourceEventType = "dev.knative.sources.ping"
+
+	// PingSourceExcludeDatesLabelKey is the label key of the ConfigMaps listing the excluded
+	// dates of PingSources.
+	PingSourceExcludeDatesLabelKey = "sources.knative.dev/ping-exclude-dates"
+	// PingSourceExcludeDatesLabelValue is the label value of the ConfigMaps listing the excluded
+	// dates of PingSources.
+	PingSourceExcludeDatesLabelValue = "true"
+	// PingSourceExcludeDatesLabelSelector is the label selector of the ConfigMaps listing the
+	// excluded dates of PingSources, which the adapter watches.
+	PingSourceExcludeDatesLabelSelector = PingSourceExcludeDatesLabelKey + "=" + PingSourceExcludeDatesLabelValue
 )
 
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
@@ -143,3 +157,18 @@ func (s *PingSourceStatus) MarkOIDCIdentityCreatedFailed(reason, messageFormat s
 func (s *PingSourceStatus) MarkOIDCIdentityCreatedUnknown(reason, messageFormat string, messageA ...interface{}) {
 	PingSourceCondSet.Manage(s).MarkUnknown(PingSourceConditionOIDCIdentityCreated, reason, messageFormat, messageA...)
 }
+
+// MarkCompleted sets the condition that the PingSource firing once has sent its event.
+func (s *PingSourceStatus) MarkCompleted() {
+	PingSourceCondSet.Manage(s).MarkTrue(PingSourceConditionCompleted)
+}
+
+// MarkNotCompleted sets the condition that the PingSource firing once hasn't sent its event yet.
+func (s *PingSourceStatus) MarkNotCompleted(reason, messageFormat string, messageA ...interface{}) {
+	PingSourceCondSet.Manage(s).MarkFalse(PingSourceConditionCompleted, reason, messageFormat, messageA...)
+}
+
+// ClearCompleted removes the condition of the PingSources firing on a schedule.
+func (s *PingSourceStatus) ClearCompleted() {
+	_ = PingSourceCondSet.Manage(s).ClearCondition(PingSourceConditionCompleted)
+}
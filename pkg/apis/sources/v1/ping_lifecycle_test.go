@@ -328,3 +328,34 @@ func TestPingSourceStatusGetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestPingSourceStatusCompleted(t *testing.T) {
+	exampleUri, _ := apis.ParseURL("uri://example")
+
+	s := &PingSourceStatus{}
+	s.InitializeConditions()
+	s.MarkOIDCIdentityCreatedSucceeded()
+	s.MarkSink(&duckv1.Addressable{URL: exampleUri})
+	s.PropagateDeploymentAvailability(availableDeployment)
+
+	s.MarkNotCompleted("Scheduled", "")
+	if got := s.GetCondition(PingSourceConditionCompleted); got == nil || got.Status != corev1.ConditionFalse {
+		t.Errorf("Expected the Completed condition to be False, got %v", got)
+	}
+	if !s.IsReady() {
+		t.Error("Expected a PingSource not completed yet to be ready")
+	}
+
+	s.MarkCompleted()
+	if got := s.GetCondition(PingSourceConditionCompleted); got == nil || got.Status != corev1.ConditionTrue {
+		t.Errorf("Expected the Completed condition to be True, got %v", got)
+	}
+	if !s.IsReady() {
+		t.Error("Expected a completed PingSource to be ready")
+	}
+
+	s.ClearCompleted()
+	if got := s.GetCondition(PingSourceConditionCompleted); got != nil {
+		t.Errorf("Expected no Completed condition, got %v", got)
+	}
+}
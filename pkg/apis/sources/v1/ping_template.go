@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"text/template"
+	"time"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// PingSourceTemplatedDataMaxSize is the maximum size in bytes of the expanded templated data of
+// the PingSources.
+const PingSourceTemplatedDataMaxSize = 1 << 20
+
+// PingSourceTemplateData are the variables of the templated data of the PingSources.
+// +k8s:deepcopy-gen=false
+type PingSourceTemplateData struct {
+	ScheduledTime   time.Time
+	Sequence        int64
+	SourceName      string
+	SourceNamespace string
+}
+
+// PingSourceSampleTemplateData are the variables the templated data is expanded with to validate
+// the PingSources, so that the validation doesn't depend on when it runs.
+var PingSourceSampleTemplateData = PingSourceTemplateData{
+	ScheduledTime:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	Sequence:        1,
+	SourceName:      "name",
+	SourceNamespace: "namespace",
+}
+
+// ExpandTemplatedData expands the templated data of a PingSource with the given variables. The
+// template can only substitute the variables, see eventingduckv1.ParseSubstitutionTemplate, and
+// the expansion fails without expanding the whole data once it exceeds maxSize bytes.
+func ExpandTemplatedData(data string, vars PingSourceTemplateData, maxSize int64) ([]byte, error) {
+	tmpl, err := eventingduckv1.ParseSubstitutionTemplate(template.New("data"), data)
+	if err != nil {
+		return nil, err
+	}
+	return eventingduckv1.ExecuteTemplate(tmpl, vars, maxSize)
+}
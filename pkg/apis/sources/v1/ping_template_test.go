@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+func TestExpandTemplatedData(t *testing.T) {
+	vars := PingSourceTemplateData{
+		ScheduledTime:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Sequence:        42,
+		SourceName:      "name",
+		SourceNamespace: "namespace",
+	}
+
+	got, err := ExpandTemplatedData(`{{.SourceNamespace}}/{{.SourceName}} #{{.Sequence}} at {{.ScheduledTime.Format "15:04"}}`, vars, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "namespace/name #42 at 12:00"; string(got) != want {
+		t.Errorf("ExpandTemplatedData() = %q, want %q", got, want)
+	}
+
+	if _, err := ExpandTemplatedData(`{{.SourceName}}{{.SourceName}}{{.SourceName}}`, vars, 10); !errors.Is(err, eventingduckv1.ErrTemplateOutputTooLarge) {
+		t.Errorf("ExpandTemplatedData() error = %v, want %v", err, eventingduckv1.ErrTemplateOutputTooLarge)
+	}
+
+	for _, data := range []string{
+		`{{range 9223372036854775807}}{{end}}`,
+		`{{with .SourceName}}{{.}}{{end}}`,
+		`{{define "t"}}x{{end}}{{template "t"}}`,
+		`{{printf "%099999999d" 1}}`,
+		`{{.SourceName | len}}`,
+	} {
+		if _, err := ExpandTemplatedData(data, vars, 64); err == nil {
+			t.Errorf("ExpandTemplatedData(%q) succeeded, want an error", data)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package v1
 import (
 	"knative.dev/pkg/apis"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -68,6 +69,31 @@ type PingSourceSpec struct {
 	// List of valid timezone values: https://en.wikipedia.org/wiki/List_of_tz_database_time_zones
 	Timezone string `json:"timezone,omitempty"`
 
+	// StartTime is the time before which the schedule doesn't fire.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is the time after which the schedule doesn't fire.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// ExcludeDates are the dates, formatted as YYYY-MM-DD in the timezone of the schedule,
+	// on which the schedule doesn't fire.
+	// +optional
+	ExcludeDates []string `json:"excludeDates,omitempty"`
+
+	// ExcludeDatesConfigMap selects the key of a ConfigMap in the namespace of the PingSource
+	// listing more dates on which the schedule doesn't fire, one per line, e.g. a holiday
+	// calendar. The ConfigMap must be labeled sources.knative.dev/ping-exclude-dates: "true",
+	// and the schedule doesn't fire while it can't be read.
+	// +optional
+	ExcludeDatesConfigMap *corev1.ConfigMapKeySelector `json:"excludeDatesConfigMap,omitempty"`
+
+	// At is the time at which the PingSource fires exactly once, instead of on a schedule.
+	// Mutually exclusive with Schedule.
+	// +optional
+	At *metav1.Time `json:"at,omitempty"`
+
 	// ContentType is the media type of Data or DataBase64. Default is empty.
 	// +optional
 	ContentType string `json:"contentType,omitempty"`
@@ -81,8 +107,58 @@ type PingSourceSpec struct {
 	// Mutually exclusive with Data.
 	// +optional
 	DataBase64 string `json:"dataBase64,omitempty"`
+
+	// TemplatedData expands Data as a Go template on every schedule, with the variables
+	// .ScheduledTime, .Sequence (the number of schedules since the creation of the source),
+	// .SourceName and .SourceNamespace. The template can only substitute the variables, with
+	// constant arguments for their methods, e.g. {{ .ScheduledTime.Format "15:04" }}. The
+	// expanded data can't exceed 1MiB.
+	// +optional
+	TemplatedData bool `json:"templatedData,omitempty"`
+
+	// MissedSchedulePolicy defines what happens to the schedules missed while the adapter
+	// was unavailable, e.g. during a restart or a leader election: skip drops them, fireOnce
+	// sends one event for the latest one and fireAll sends one event for each of them.
+	// Defaults to skip.
+	// +optional
+	MissedSchedulePolicy MissedSchedulePolicy `json:"missedSchedulePolicy,omitempty"`
+
+	// MissedScheduleMaxLookback is how far back the missed schedules are caught up, as an
+	// ISO-8601 duration up to P1D. Only used with the fireOnce and fireAll policies. Defaults
+	// to PT1H. At most the latest 100 missed schedules are caught up.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	MissedScheduleMaxLookback *string `json:"missedScheduleMaxLookback,omitempty"`
 }
 
+// MissedSchedulePolicy defines what happens to the schedules of a PingSource missed while
+// the adapter was unavailable.
+type MissedSchedulePolicy string
+
+const (
+	// MissedScheduleSkip drops the missed schedules.
+	MissedScheduleSkip MissedSchedulePolicy = "skip"
+
+	// MissedScheduleFireOnce sends one event for the latest missed schedule.
+	MissedScheduleFireOnce MissedSchedulePolicy = "fireOnce"
+
+	// MissedScheduleFireAll sends one event for each missed schedule.
+	MissedScheduleFireAll MissedSchedulePolicy = "fireAll"
+
+	// DefaultMissedScheduleMaxLookback is how far back the missed schedules are caught up
+	// when MissedScheduleMaxLookback isn't set.
+	DefaultMissedScheduleMaxLookback = "PT1H"
+
+	// MissedScheduleMaxLookbackLimit is the longest MissedScheduleMaxLookback.
+	MissedScheduleMaxLookbackLimit = "P1D"
+
+	// MissedSchedulesMaxCatchUp is the maximum number of missed schedules caught up, the
+	// earlier ones are skipped.
+	MissedSchedulesMaxCatchUp = 100
+)
+
 // PingSourceStatus defines the observed state of PingSource.
 type PingSourceStatus struct {
 	// inherits duck/v1 SourceStatus, which currently provides:
@@ -23,9 +23,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
+	"github.com/rickb777/date/period"
 	"github.com/robfig/cron/v3"
 	"knative.dev/pkg/apis"
 
@@ -38,26 +40,10 @@ func (c *PingSource) Validate(ctx context.Context) *apis.FieldError {
 
 func (cs *PingSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 	var errs *apis.FieldError
-	schedule := cs.Schedule
-
-	errs = validateDescriptor(schedule)
-
-	if cs.Timezone != "" {
-		schedule = "CRON_TZ=" + cs.Timezone + " " + schedule
-	}
-
-	parser := cron.NewParser(
-		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
-	)
-
-	if _, err := parser.Parse(schedule); err != nil {
-		if strings.HasPrefix(err.Error(), "provided bad location") {
-			fe := apis.ErrInvalidValue(err, "timezone")
-			errs = errs.Also(fe)
-		} else {
-			fe := apis.ErrInvalidValue(err, "schedule")
-			errs = errs.Also(fe)
-		}
+	if cs.At != nil {
+		errs = cs.validateOneShot()
+	} else {
+		errs = cs.validateSchedule()
 	}
 
 	pingConfig := config.FromContextOrDefaults(ctx)
@@ -91,17 +77,128 @@ func (cs *PingSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 			fe := apis.ErrInvalidValue(fmt.Sprintf("the data length of %d bytes exceeds limit set at %d.", bsize, pingDefaults.DataMaxSize), "data")
 			errs = errs.Also(fe)
 		}
-		if cs.ContentType == cloudevents.ApplicationJSON {
+		if cs.TemplatedData {
+			errs = errs.Also(cs.validateTemplatedData(pingDefaults.DataMaxSize))
+		} else if cs.ContentType == cloudevents.ApplicationJSON {
 			// validate if data is valid JSON
 			if err := validateJSON(cs.Data); err != nil {
 				errs = errs.Also(apis.ErrInvalidValue(err, "data"))
 			}
 		}
 	}
+	if cs.TemplatedData && cs.Data == "" {
+		errs = errs.Also(apis.ErrMissingField("data"))
+	}
+	errs = errs.Also(cs.validateMissedSchedules())
+	errs = errs.Also(cs.validateCalendar())
 	errs = errs.Also(cs.SourceSpec.Validate(ctx))
 	return errs
 }
 
+// validateTemplatedData validates the templated data expanded with sample variables, as it is
+// expanded on every schedule.
+func (cs *PingSourceSpec) validateTemplatedData(dataMaxSize int64) *apis.FieldError {
+	maxSize := int64(PingSourceTemplatedDataMaxSize)
+	if dataMaxSize > -1 && dataMaxSize < maxSize {
+		maxSize = dataMaxSize
+	}
+	data, err := ExpandTemplatedData(cs.Data, PingSourceSampleTemplateData, maxSize)
+	if err != nil {
+		return apis.ErrInvalidValue(err, "data")
+	}
+	if cs.ContentType == cloudevents.ApplicationJSON {
+		if err := validateJSON(string(data)); err != nil {
+			return apis.ErrInvalidValue(err, "data")
+		}
+	}
+	return nil
+}
+
+func (cs *PingSourceSpec) validateSchedule() *apis.FieldError {
+	schedule := cs.Schedule
+
+	errs := validateDescriptor(schedule)
+
+	if cs.Timezone != "" {
+		schedule = "CRON_TZ=" + cs.Timezone + " " + schedule
+	}
+
+	parser := cron.NewParser(
+		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	)
+
+	if _, err := parser.Parse(schedule); err != nil {
+		if strings.HasPrefix(err.Error(), "provided bad location") {
+			fe := apis.ErrInvalidValue(err, "timezone")
+			errs = errs.Also(fe)
+		} else {
+			fe := apis.ErrInvalidValue(err, "schedule")
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
+
+// validateOneShot validates that a PingSource firing once has no schedule nor calendar.
+func (cs *PingSourceSpec) validateOneShot() *apis.FieldError {
+	var errs *apis.FieldError
+	if cs.Schedule != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("at", "schedule"))
+	}
+	if cs.StartTime != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("startTime"))
+	}
+	if cs.EndTime != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("endTime"))
+	}
+	if len(cs.ExcludeDates) > 0 {
+		errs = errs.Also(apis.ErrDisallowedFields("excludeDates"))
+	}
+	if cs.ExcludeDatesConfigMap != nil {
+		errs = errs.Also(apis.ErrDisallowedFields("excludeDatesConfigMap"))
+	}
+	return errs
+}
+
+func (cs *PingSourceSpec) validateCalendar() *apis.FieldError {
+	var errs *apis.FieldError
+	if cs.StartTime != nil && cs.EndTime != nil && !cs.EndTime.After(cs.StartTime.Time) {
+		errs = errs.Also(apis.ErrInvalidValue(cs.EndTime.Format(time.RFC3339), "endTime", "endTime must be after startTime"))
+	}
+	for i, date := range cs.ExcludeDates {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(date, "excludeDates", i))
+		}
+	}
+	if cm := cs.ExcludeDatesConfigMap; cm != nil {
+		if cm.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("excludeDatesConfigMap.name"))
+		}
+		if cm.Key == "" {
+			errs = errs.Also(apis.ErrMissingField("excludeDatesConfigMap.key"))
+		}
+	}
+	return errs
+}
+
+func (cs *PingSourceSpec) validateMissedSchedules() *apis.FieldError {
+	var errs *apis.FieldError
+	switch cs.MissedSchedulePolicy {
+	case "", MissedScheduleSkip, MissedScheduleFireOnce, MissedScheduleFireAll:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(cs.MissedSchedulePolicy, "missedSchedulePolicy"))
+	}
+	if cs.MissedScheduleMaxLookback != nil {
+		p, err := period.Parse(*cs.MissedScheduleMaxLookback)
+		if err != nil || p.IsZero() || p.IsNegative() {
+			errs = errs.Also(apis.ErrInvalidValue(*cs.MissedScheduleMaxLookback, "missedScheduleMaxLookback"))
+		} else if p.DurationApprox() > period.MustParse(MissedScheduleMaxLookbackLimit).DurationApprox() {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*cs.MissedScheduleMaxLookback, "PT1S", MissedScheduleMaxLookbackLimit, "missedScheduleMaxLookback"))
+		}
+	}
+	return errs
+}
+
 func validateJSON(str string) error {
 	var objmap map[string]interface{}
 	return json.Unmarshal([]byte(str), &objmap)
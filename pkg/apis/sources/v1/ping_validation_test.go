@@ -21,8 +21,12 @@ import (
 	"encoding/base64"
 	"strings"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
@@ -475,6 +479,319 @@ func TestPingSourceValidation(t *testing.T) {
 				errs = errs.Also(fe)
 				return errs
 			}(),
+		}, {
+			name: "valid spec with missed schedule policy",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:                  "*/2 * * * *",
+					MissedSchedulePolicy:      MissedScheduleFireAll,
+					MissedScheduleMaxLookback: ptr.To("PT6H"),
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "invalid missed schedule policy",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:             "*/2 * * * *",
+					MissedSchedulePolicy: "fireTwice",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("fireTwice", "spec.missedSchedulePolicy"),
+		}, {
+			name: "invalid missed schedule max lookback",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:                  "*/2 * * * *",
+					MissedSchedulePolicy:      MissedScheduleFireOnce,
+					MissedScheduleMaxLookback: ptr.To("PT0S"),
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("PT0S", "spec.missedScheduleMaxLookback"),
+		}, {
+			name: "missed schedule max lookback too long",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:                  "*/2 * * * *",
+					MissedSchedulePolicy:      MissedScheduleFireOnce,
+					MissedScheduleMaxLookback: ptr.To("P2D"),
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrOutOfBoundsValue("P2D", "PT1S", "P1D", "spec.missedScheduleMaxLookback"),
+		}, {
+			name: "valid templated data",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:      "*/2 * * * *",
+					ContentType:   "application/json",
+					Data:          `{"sequence": {{.Sequence}}, "source": "{{.SourceName}}"}`,
+					TemplatedData: true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "invalid templated data",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:      "*/2 * * * *",
+					Data:          "{{.Sequence",
+					TemplatedData: true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("template: data:1: unclosed action", "spec.data"),
+		}, {
+			name: "templated data with an undefined variable",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:      "*/2 * * * *",
+					Data:          "{{.Foo}}",
+					TemplatedData: true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue(`template: data:1:2: executing "data" at <.Foo>: can't evaluate field Foo in type v1.PingSourceTemplateData`, "spec.data"),
+		}, {
+			name: "templated data with a control structure",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:      "*/2 * * * *",
+					Data:          "{{range 9223372036854775807}}{{end}}",
+					TemplatedData: true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("{{range 9223372036854775807}}{{end}}: only the substitution of values is supported", "spec.data"),
+		}, {
+			name: "templated data expanding to invalid JSON",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:      "*/2 * * * *",
+					ContentType:   "application/json",
+					Data:          `{"source": {{.SourceName}}}`,
+					TemplatedData: true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("invalid character 'a' in literal null (expecting 'u')", "spec.data"),
+		}, {
+			name: "templated data expanding beyond the limit",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:      "*/2 * * * *",
+					Data:          "{{.ScheduledTime}}",
+					TemplatedData: true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			ctx: func(ctx context.Context) context.Context {
+				return config.ToContext(ctx, &config.Config{PingDefaults: &config.PingDefaults{DataMaxSize: 20}})
+			},
+			want: apis.ErrInvalidValue("the expanded template exceeds its maximum size of 20 bytes", "spec.data"),
+		}, {
+			name: "templated data without data",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:      "*/2 * * * *",
+					DataBase64:    "c29tZSBkYXRh",
+					TemplatedData: true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrMissingField("spec.data"),
+		}, {
+			name: "valid calendar",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "*/2 * * * *",
+					StartTime:    ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+					EndTime:      ptr.To(metav1.NewTime(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))),
+					ExcludeDates: []string{"2024-12-25", "2024-12-26"},
+					ExcludeDatesConfigMap: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "holidays"},
+						Key:                  "dates",
+					},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "end time before start time",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:  "*/2 * * * *",
+					StartTime: ptr.To(metav1.NewTime(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))),
+					EndTime:   ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("2024-01-01T00:00:00Z", "spec.endTime", "endTime must be after startTime"),
+		}, {
+			name: "invalid exclude dates",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "*/2 * * * *",
+					ExcludeDates: []string{"2024-12-25", "2024-13-01"},
+					ExcludeDatesConfigMap: &corev1.ConfigMapKeySelector{
+						Key: "dates",
+					},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidArrayValue("2024-13-01", "spec.excludeDates", 1).Also(apis.ErrMissingField("spec.excludeDatesConfigMap.name")),
+		}, {
+			name: "valid one-shot",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					At: ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "one-shot with a schedule and a calendar",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "*/2 * * * *",
+					At:           ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+					ExcludeDates: []string{"2024-12-25"},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrMultipleOneOf("spec.at", "spec.schedule").Also(apis.ErrDisallowedFields("spec.excludeDates")),
 		},
 	}
 
@@ -22,6 +22,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
@@ -92,6 +93,22 @@ func (in *ApiServerSource) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApiServerSourceDiff) DeepCopyInto(out *ApiServerSourceDiff) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiServerSourceDiff.
+func (in *ApiServerSourceDiff) DeepCopy() *ApiServerSourceDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(ApiServerSourceDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApiServerSourceList) DeepCopyInto(out *ApiServerSourceList) {
 	*out = *in
@@ -141,6 +158,11 @@ func (in *ApiServerSourceSpec) DeepCopyInto(out *ApiServerSourceSpec) {
 		*out = new(APIVersionKind)
 		**out = **in
 	}
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = new(ApiServerSourceDiff)
+		**out = **in
+	}
 	if in.NamespaceSelector != nil {
 		in, out := &in.NamespaceSelector, &out.NamespaceSelector
 		*out = new(metav1.LabelSelector)
@@ -349,6 +371,33 @@ func (in *PingSourceList) DeepCopyObject() runtime.Object {
 func (in *PingSourceSpec) DeepCopyInto(out *PingSourceSpec) {
 	*out = *in
 	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExcludeDates != nil {
+		in, out := &in.ExcludeDates, &out.ExcludeDates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeDatesConfigMap != nil {
+		in, out := &in.ExcludeDatesConfigMap, &out.ExcludeDatesConfigMap
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.At != nil {
+		in, out := &in.At, &out.At
+		*out = (*in).DeepCopy()
+	}
+	if in.MissedScheduleMaxLookback != nil {
+		in, out := &in.MissedScheduleMaxLookback, &out.MissedScheduleMaxLookback
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -34,12 +34,20 @@ func (source *PingSource) ConvertTo(ctx context.Context, obj apis.Convertible) e
 			SourceStatus: source.Status.SourceStatus,
 		}
 		sink.Spec = v1.PingSourceSpec{
-			SourceSpec:  source.Spec.SourceSpec,
-			Schedule:    source.Spec.Schedule,
-			Timezone:    source.Spec.Timezone,
-			ContentType: source.Spec.ContentType,
-			Data:        source.Spec.Data,
-			DataBase64:  source.Spec.DataBase64,
+			SourceSpec:                source.Spec.SourceSpec,
+			Schedule:                  source.Spec.Schedule,
+			Timezone:                  source.Spec.Timezone,
+			ContentType:               source.Spec.ContentType,
+			Data:                      source.Spec.Data,
+			DataBase64:                source.Spec.DataBase64,
+			StartTime:                 source.Spec.StartTime,
+			EndTime:                   source.Spec.EndTime,
+			ExcludeDates:              source.Spec.ExcludeDates,
+			ExcludeDatesConfigMap:     source.Spec.ExcludeDatesConfigMap,
+			At:                        source.Spec.At,
+			TemplatedData:             source.Spec.TemplatedData,
+			MissedSchedulePolicy:      v1.MissedSchedulePolicy(source.Spec.MissedSchedulePolicy),
+			MissedScheduleMaxLookback: source.Spec.MissedScheduleMaxLookback,
 		}
 
 		return nil
@@ -59,12 +67,20 @@ func (sink *PingSource) ConvertFrom(ctx context.Context, obj apis.Convertible) e
 		}
 
 		sink.Spec = PingSourceSpec{
-			SourceSpec:  source.Spec.SourceSpec,
-			Schedule:    source.Spec.Schedule,
-			Timezone:    source.Spec.Timezone,
-			ContentType: source.Spec.ContentType,
-			Data:        source.Spec.Data,
-			DataBase64:  source.Spec.DataBase64,
+			SourceSpec:                source.Spec.SourceSpec,
+			Schedule:                  source.Spec.Schedule,
+			Timezone:                  source.Spec.Timezone,
+			ContentType:               source.Spec.ContentType,
+			Data:                      source.Spec.Data,
+			DataBase64:                source.Spec.DataBase64,
+			StartTime:                 source.Spec.StartTime,
+			EndTime:                   source.Spec.EndTime,
+			ExcludeDates:              source.Spec.ExcludeDates,
+			ExcludeDatesConfigMap:     source.Spec.ExcludeDatesConfigMap,
+			At:                        source.Spec.At,
+			TemplatedData:             source.Spec.TemplatedData,
+			MissedSchedulePolicy:      MissedSchedulePolicy(source.Spec.MissedSchedulePolicy),
+			MissedScheduleMaxLookback: source.Spec.MissedScheduleMaxLookback,
 		}
 
 		return nil
@@ -20,8 +20,15 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"knative.dev/pkg/apis"
+
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
 )
 
 // implement apis.Convertible
@@ -46,3 +53,36 @@ func TestPingSourceConversionBadType(t *testing.T) {
 		t.Errorf("ConvertFrom() = %#v, wanted error", good)
 	}
 }
+
+func TestPingSourceConversionRoundTrip(t *testing.T) {
+	in := &PingSource{
+		Spec: PingSourceSpec{
+			Schedule:     "*/2 * * * *",
+			Timezone:     "Europe/Paris",
+			Data:         "data",
+			StartTime:    ptr.To(metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+			EndTime:      ptr.To(metav1.NewTime(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))),
+			ExcludeDates: []string{"2024-12-25"},
+			ExcludeDatesConfigMap: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "holidays"},
+				Key:                  "dates",
+			},
+			At:                        ptr.To(metav1.NewTime(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))),
+			TemplatedData:             true,
+			MissedSchedulePolicy:      MissedScheduleFireAll,
+			MissedScheduleMaxLookback: ptr.To("PT6H"),
+		},
+	}
+
+	mid := &v1.PingSource{}
+	if err := in.ConvertTo(context.Background(), mid); err != nil {
+		t.Fatal("ConvertTo() =", err)
+	}
+	got := &PingSource{}
+	if err := got.ConvertFrom(context.Background(), mid); err != nil {
+		t.Fatal("ConvertFrom() =", err)
+	}
+	if diff := cmp.Diff(in, got); diff != "" {
+		t.Error("roundtrip (-want, +got) =", diff)
+	}
+}
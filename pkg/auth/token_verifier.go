@@ -80,6 +80,12 @@ func (v *OIDCTokenVerifier) VerifyJWTFromRequest(ctx context.Context, r *http.Re
 	return err
 }
 
+// VerifyJWTFromRequestForIDToken verifies if the incoming request contains a correct JWT token
+// and returns the parsed ID token, for the callers which need its subject.
+func (v *OIDCTokenVerifier) VerifyJWTFromRequestForIDToken(ctx context.Context, r *http.Request, audience *string, response http.ResponseWriter) (*IDToken, error) {
+	return v.verifyAuthN(ctx, audience, r, response)
+}
+
 // VerifyRequest verifies AuthN and AuthZ in the request. On verification errors, it sets the
 // responses HTTP status and returns an error
 func (v *OIDCTokenVerifier) VerifyRequest(ctx context.Context, features feature.Flags, requiredOIDCAudience *string, resourceNamespace string, policyRefs []duckv1.AppliedEventPolicyRef, req *http.Request, resp http.ResponseWriter) error {
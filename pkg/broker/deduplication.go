@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cm "knative.dev/pkg/configmap"
+)
+
+const (
+	// IngressDeduplicationWindowKey is the key of the Broker config ConfigMap setting for how long
+	// the ingress drops the events with the source and id of an event it already accepted. The
+	// events with the source and id of an event still being sent are refused with 429 Too Many
+	// Requests. The ingress doesn't deduplicate the events when it is missing.
+	IngressDeduplicationWindowKey = "ingress-deduplication-window"
+	// IngressDeduplicationCacheSizeKey is the key of the Broker config ConfigMap setting the
+	// maximum number of events the ingress remembers. When it is reached, the least recently seen
+	// events are forgotten before the end of the window.
+	IngressDeduplicationCacheSizeKey = "ingress-deduplication-cache-size"
+
+	// DefaultIngressDeduplicationCacheSize is the default number of events the ingress remembers
+	// for each Broker.
+	DefaultIngressDeduplicationCacheSize = 10000
+)
+
+// Deduplication is the configuration of the deduplication of the events sent to a Broker, based
+// on their source and id attributes.
+type Deduplication struct {
+	Window    metav1.Duration `json:"window"`
+	CacheSize int             `json:"cacheSize"`
+}
+
+// NewDeduplicationFromConfigMap returns the ingress deduplication set in the data of the config
+// ConfigMap of a Broker, or nil if there is none.
+func NewDeduplicationFromConfigMap(data map[string]string) (*Deduplication, error) {
+	if _, ok := data[IngressDeduplicationWindowKey]; !ok {
+		return nil, nil
+	}
+
+	var window time.Duration
+	dedup := &Deduplication{CacheSize: DefaultIngressDeduplicationCacheSize}
+	if err := cm.Parse(data,
+		cm.AsDuration(IngressDeduplicationWindowKey, &window),
+		cm.AsInt(IngressDeduplicationCacheSizeKey, &dedup.CacheSize),
+	); err != nil {
+		return nil, fmt.Errorf("failed to parse the ingress deduplication: %w", err)
+	}
+	dedup.Window = metav1.Duration{Duration: window}
+
+	if window <= 0 {
+		return nil, fmt.Errorf("%s must be a positive duration, was: %v", IngressDeduplicationWindowKey, window)
+	}
+	if dedup.CacheSize <= 0 {
+		return nil, fmt.Errorf("%s must be a positive integer, was: %d", IngressDeduplicationCacheSizeKey, dedup.CacheSize)
+	}
+	return dedup, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewDeduplicationFromConfigMap(t *testing.T) {
+	tests := map[string]struct {
+		data    map[string]string
+		want    *Deduplication
+		wantErr bool
+	}{
+		"no deduplication": {
+			data: map[string]string{IngressRateLimitEventsPerSecondKey: "10"},
+		},
+		"defaults": {
+			data: map[string]string{IngressDeduplicationWindowKey: "5m"},
+			want: &Deduplication{
+				Window:    metav1.Duration{Duration: 5 * time.Minute},
+				CacheSize: DefaultIngressDeduplicationCacheSize,
+			},
+		},
+		"all set": {
+			data: map[string]string{
+				IngressDeduplicationWindowKey:    "30s",
+				IngressDeduplicationCacheSizeKey: "500",
+			},
+			want: &Deduplication{
+				Window:    metav1.Duration{Duration: 30 * time.Second},
+				CacheSize: 500,
+			},
+		},
+		"invalid window": {
+			data:    map[string]string{IngressDeduplicationWindowKey: "forever"},
+			wantErr: true,
+		},
+		"zero window": {
+			data:    map[string]string{IngressDeduplicationWindowKey: "0s"},
+			wantErr: true,
+		},
+		"zero cache size": {
+			data: map[string]string{
+				IngressDeduplicationWindowKey:    "1m",
+				IngressDeduplicationCacheSizeKey: "0",
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewDeduplicationFromConfigMap(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewDeduplicationFromConfigMap() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected deduplication (-want +got):", diff)
+			}
+		})
+	}
+}
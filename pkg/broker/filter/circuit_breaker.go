@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"go.uber.org/zap"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// circuitBreakerOptions returns the options enabling the circuit breaker of the Trigger's
+// subscriber, when its delivery spec has one. The state of the circuit breaker is local to the
+// filter replica, its changes are logged, reported as metrics and recorded for the Trigger
+// reconciler by the CircuitBreakerRecorder.
+func (h *Handler) circuitBreakerOptions(t *eventingv1.Trigger, reportArgs *ReportArgs) ([]kncloudevents.SendOption, error) {
+	if t.Spec.Delivery == nil {
+		return nil, nil
+	}
+	config, err := kncloudevents.CircuitBreakerConfigFromDeliverySpec(*t.Spec.Delivery)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	namespace, name, uid := t.Namespace, t.Name, t.UID
+	config.OnStateChange = func(state kncloudevents.CircuitBreakerState) {
+		h.logger.Info("Circuit breaker of the trigger subscriber changed state",
+			zap.String("trigger", namespace+"/"+name), zap.String("state", string(state)))
+		_ = h.reporter.ReportCircuitBreakerStateChange(reportArgs, string(state))
+		if h.CircuitBreakerRecorder != nil {
+			h.CircuitBreakerRecorder.record(uid, state != kncloudevents.CircuitBreakerClosed)
+		}
+	}
+	return []kncloudevents.SendOption{kncloudevents.WithCircuitBreaker(config)}, nil
+}
+
+// circuitOpenDeadLetterSinkOptions returns the options sending the events rejected by the open
+// circuit breaker of the Trigger's subscriber to the dead letter sink of the Trigger (or else of
+// the Broker), when the channel sends the other failed events there.
+func circuitOpenDeadLetterSinkOptions(t *eventingv1.Trigger) []kncloudevents.SendOption {
+	if t.Spec.Delivery == nil || t.Spec.Delivery.CircuitBreaker == nil || t.Status.DeadLetterSinkURI == nil {
+		return nil
+	}
+	return []kncloudevents.SendOption{kncloudevents.WithCircuitOpenDeadLetterSink(&duckv1.Addressable{
+		URL:      t.Status.DeadLetterSinkURI,
+		CACerts:  t.Status.DeadLetterSinkCACerts,
+		Audience: t.Status.DeadLetterSinkAudience,
+	})}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CircuitBreakersConfigMapName is the name of the ConfigMap in the system namespace where the
+	// filter replicas record the Triggers whose subscriber has an open circuit breaker, by replica,
+	// so that the Trigger reconciler can report it in the status of the Triggers.
+	CircuitBreakersConfigMapName = "mt-broker-filter-circuit-breakers"
+
+	// CircuitBreakerStatesTTL is how long the circuit breakers recorded by a replica are
+	// considered. The replicas refresh them while some are open, so that the records of the
+	// replicas which are gone expire.
+	CircuitBreakerStatesTTL = 3 * time.Minute
+
+	// circuitBreakerStatesRefreshInterval is how often a replica refreshes its open circuit
+	// breakers.
+	circuitBreakerStatesRefreshInterval = time.Minute
+
+	// circuitBreakerStatesWriteInterval is how often the changes of the circuit breakers are
+	// written, so that the circuit breakers changing state often don't flood the API server.
+	circuitBreakerStatesWriteInterval = 5 * time.Second
+)
+
+// CircuitBreakerStates are the circuit breakers open in a filter replica, recorded as JSON under
+// the name of the replica in the circuit breakers ConfigMap.
+type CircuitBreakerStates struct {
+	// Updated is the time the states were recorded.
+	Updated time.Time `json:"updated"`
+	// Open are the UIDs of the Triggers whose subscriber has an open circuit breaker.
+	Open []types.UID `json:"open,omitempty"`
+}
+
+// CircuitBreakerOpenReplicas returns the number of filter replicas which recorded an open circuit
+// breaker for the subscriber of the Trigger in the circuit breakers ConfigMap, ignoring the
+// records older than CircuitBreakerStatesTTL.
+func CircuitBreakerOpenReplicas(cm *corev1.ConfigMap, uid types.UID, now time.Time) int {
+	if cm == nil {
+		return 0
+	}
+	replicas := 0
+	for _, recorded := range cm.Data {
+		states, ok := parseCircuitBreakerStates(recorded, now)
+		if !ok {
+			continue
+		}
+		for _, open := range states.Open {
+			if open == uid {
+				replicas++
+				break
+			}
+		}
+	}
+	return replicas
+}
+
+// parseCircuitBreakerStates parses the states recorded by a replica, and returns whether they are
+// valid and fresh.
+func parseCircuitBreakerStates(recorded string, now time.Time) (CircuitBreakerStates, bool) {
+	var states CircuitBreakerStates
+	if err := json.Unmarshal([]byte(recorded), &states); err != nil {
+		return states, false
+	}
+	return states, now.Sub(states.Updated) <= CircuitBreakerStatesTTL
+}
+
+// CircuitBreakerRecorder records the Triggers whose subscriber has an open circuit breaker in the
+// filter replica in the circuit breakers ConfigMap. The circuit breakers themselves stay local to
+// the replica, only the Trigger reconciler writes the status of the Triggers.
+type CircuitBreakerRecorder struct {
+	logger     *zap.Logger
+	kubeClient kubernetes.Interface
+	namespace  string
+	replica    string
+
+	mu      sync.Mutex
+	open    map[types.UID]struct{}
+	changed bool
+	written time.Time
+}
+
+// NewCircuitBreakerRecorder creates a CircuitBreakerRecorder writing the states of the replica
+// under its name in the circuit breakers ConfigMap of the namespace.
+func NewCircuitBreakerRecorder(logger *zap.Logger, kubeClient kubernetes.Interface, namespace, replica string) *CircuitBreakerRecorder {
+	return &CircuitBreakerRecorder{
+		logger:     logger,
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		replica:    replica,
+		open:       make(map[types.UID]struct{}),
+	}
+}
+
+// record records whether the circuit breaker of the subscriber of the Trigger is open.
+func (r *CircuitBreakerRecorder) record(uid types.UID, open bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.open[uid]; ok == open {
+		return
+	}
+	if open {
+		r.open[uid] = struct{}{}
+	} else {
+		delete(r.open, uid)
+	}
+	r.changed = true
+}
+
+// Run writes the changes of the circuit breakers, and refreshes the open ones, until stopCh is
+// closed. The record of the replica is then removed, when it was written.
+func (r *CircuitBreakerRecorder) Run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(circuitBreakerStatesWriteInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.write(time.Now())
+		case <-stopCh:
+			r.mu.Lock()
+			written := !r.written.IsZero()
+			r.mu.Unlock()
+			if !written {
+				return
+			}
+			if err := r.patch(map[string]interface{}{r.replica: nil}); err != nil {
+				r.logger.Warn("Failed to remove the circuit breaker states of the replica", zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+// write writes the states of the circuit breakers when they changed, or when some are open and
+// they weren't refreshed for circuitBreakerStatesRefreshInterval.
+func (r *CircuitBreakerRecorder) write(now time.Time) {
+	r.mu.Lock()
+	if !r.changed && (len(r.open) == 0 || now.Sub(r.written) < circuitBreakerStatesRefreshInterval) {
+		r.mu.Unlock()
+		return
+	}
+	states := CircuitBreakerStates{Updated: now.UTC()}
+	for uid := range r.open {
+		states.Open = append(states.Open, uid)
+	}
+	r.changed = false
+	r.mu.Unlock()
+
+	recorded, err := json.Marshal(states)
+	if err != nil {
+		return
+	}
+	data := map[string]interface{}{r.replica: string(recorded)}
+	// Remove the expired records of the replicas which are gone.
+	if cm, err := r.kubeClient.CoreV1().ConfigMaps(r.namespace).Get(context.Background(), CircuitBreakersConfigMapName, metav1.GetOptions{}); err == nil {
+		for replica, recorded := range cm.Data {
+			if _, ok := parseCircuitBreakerStates(recorded, now); !ok && replica != r.replica {
+				data[replica] = nil
+			}
+		}
+	}
+
+	if err := r.patch(data); err != nil {
+		r.logger.Warn("Failed to record the circuit breaker states", zap.Error(err))
+		// The states are written again on the next tick.
+		r.mu.Lock()
+		r.changed = true
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Lock()
+	r.written = now
+	r.mu.Unlock()
+}
+
+// patch merges the data into the circuit breakers ConfigMap, creating it when it doesn't exist.
+func (r *CircuitBreakerRecorder) patch(data map[string]interface{}) error {
+	ctx := context.Background()
+	configMaps := r.kubeClient.CoreV1().ConfigMaps(r.namespace)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = configMaps.Patch(ctx, CircuitBreakersConfigMapName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CircuitBreakersConfigMapName,
+			Namespace: r.namespace,
+		},
+		Data: make(map[string]string, len(data)),
+	}
+	for key, value := range data {
+		if recorded, ok := value.(string); ok {
+			cm.Data[key] = recorded
+		}
+	}
+	_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// Created concurrently by another replica.
+		_, err = configMaps.Patch(ctx, CircuitBreakersConfigMapName, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCircuitBreakerOpenReplicas(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+	record := func(updated time.Time, open ...types.UID) string {
+		recorded, _ := json.Marshal(CircuitBreakerStates{Updated: updated, Open: open})
+		return string(recorded)
+	}
+	cm := &corev1.ConfigMap{
+		Data: map[string]string{
+			"filter-1": record(now.Add(-time.Minute), "a", "b"),
+			"filter-2": record(now.Add(-2*time.Minute), "a"),
+			"filter-3": record(now.Add(-time.Hour), "a", "c"),
+			"filter-4": "invalid",
+		},
+	}
+
+	for uid, want := range map[types.UID]int{"a": 2, "b": 1, "c": 0, "d": 0} {
+		if got := CircuitBreakerOpenReplicas(cm, uid, now); got != want {
+			t.Errorf("unexpected open replicas for %q, want %d got %d", uid, want, got)
+		}
+	}
+	if got := CircuitBreakerOpenReplicas(nil, "a", now); got != 0 {
+		t.Errorf("unexpected open replicas without the ConfigMap: %d", got)
+	}
+}
+
+func TestCircuitBreakerRecorder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+	stale, _ := json.Marshal(CircuitBreakerStates{Updated: now.Add(-time.Hour), Open: []types.UID{"a"}})
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: CircuitBreakersConfigMapName, Namespace: "knative-eventing"},
+		Data:       map[string]string{"filter-gone": string(stale)},
+	})
+	r := NewCircuitBreakerRecorder(zap.NewNop(), kubeClient, "knative-eventing", "filter-1")
+	get := func() *corev1.ConfigMap {
+		cm, err := kubeClient.CoreV1().ConfigMaps("knative-eventing").Get(ctx, CircuitBreakersConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal("failed to get the ConfigMap:", err)
+		}
+		return cm
+	}
+
+	r.record("a", true)
+	r.record("b", true)
+	r.record("b", false)
+	r.write(now)
+	cm := get()
+	if _, ok := cm.Data["filter-gone"]; ok {
+		t.Error("expected the stale record of the replica which is gone to be removed")
+	}
+	if got := CircuitBreakerOpenReplicas(cm, "a", now); got != 1 {
+		t.Errorf("expected the circuit breaker to be recorded as open, got %d replicas", got)
+	}
+	if got := CircuitBreakerOpenReplicas(cm, "b", now); got != 0 {
+		t.Errorf("expected the circuit breaker to be recorded as closed, got %d replicas", got)
+	}
+
+	// The open circuit breakers are refreshed.
+	later := now.Add(circuitBreakerStatesRefreshInterval)
+	r.write(later)
+	if got := CircuitBreakerOpenReplicas(get(), "a", later.Add(CircuitBreakerStatesTTL)); got != 1 {
+		t.Errorf("expected the open circuit breaker to be refreshed, got %d replicas", got)
+	}
+
+	r.record("a", false)
+	r.write(later)
+	if got := CircuitBreakerOpenReplicas(get(), "a", later); got != 0 {
+		t.Errorf("expected the circuit breaker to be recorded as closed, got %d replicas", got)
+	}
+
+	stopCh := make(chan struct{})
+	close(stopCh)
+	r.Run(stopCh)
+	if _, ok := get().Data["filter-1"]; ok {
+		t.Error("expected the record of the replica to be removed when it stops")
+	}
+}
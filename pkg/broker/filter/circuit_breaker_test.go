@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/fake"
+	reconcilertesting "knative.dev/pkg/reconciler/testing"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/auth"
+	brokerinformerfake "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker/fake"
+	triggerinformerfake "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/trigger/fake"
+	"knative.dev/eventing/pkg/kncloudevents/attributes"
+	"knative.dev/eventing/pkg/reconciler/sugar/trigger/path"
+)
+
+func TestReceiver_CircuitBreaker(t *testing.T) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+	var received atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	reporter := &mockReporter{}
+	r, err := NewHandler(
+		zap.NewNop(),
+		auth.NewOIDCTokenVerifier(ctx),
+		auth.NewOIDCTokenProvider(ctx),
+		triggerinformerfake.Get(ctx),
+		brokerinformerfake.Get(ctx),
+		reporter,
+		configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+		func(ctx context.Context) context.Context {
+			return ctx
+		},
+	)
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(&eventingv1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: testNS,
+		},
+	})
+	trigger := makeTrigger(withName("breaker"), withUID("breaker"))
+	trigger.Spec.Broker = "default"
+	trigger.Spec.Delivery = &eventingduckv1.DeliverySpec{
+		CircuitBreaker: &eventingduckv1.CircuitBreakerSpec{
+			FailurePercentage: ptr.To[int32](100),
+			MinimumRequests:   ptr.To[int32](2),
+			OpenDuration:      ptr.To("PT1H"),
+		},
+		DeadLetterFormat: ptr.To(eventingduckv1.DeadLetterFormatHistory),
+	}
+	trigger.Status.SubscriberURI, _ = apis.ParseURL(s.URL)
+	r.triggerIndex.Set(trigger)
+
+	for i := 0; i < 4; i++ {
+		e := makeEvent()
+		b, err := e.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/brokers/%s/%s", testNS, "default"), bytes.NewBuffer(b))
+		request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		r.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	if got := received.Load(); got != 2 {
+		t.Errorf("expected the subscriber to receive 2 events, got %d", got)
+	}
+	reporter.mu.Lock()
+	states := reporter.circuitBreakerStates
+	reporter.mu.Unlock()
+	if diff := cmp.Diff([]string{"open"}, states); diff != "" {
+		t.Error("unexpected circuit breaker state changes (-want +got):", diff)
+	}
+
+}
+
+func TestReceiver_CircuitBreakerDeadLetterSink(t *testing.T) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+	var received, deadLettered atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dls" {
+			deadLettered.Add(1)
+			if got := r.Header.Get("ce-" + attributes.KnativeErrorResourceExtensionKey); got != "Trigger/"+testNS+"/breaker" {
+				t.Errorf("unexpected failure history resource %q", got)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		received.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	r, err := NewHandler(
+		zap.NewNop(),
+		auth.NewOIDCTokenVerifier(ctx),
+		auth.NewOIDCTokenProvider(ctx),
+		triggerinformerfake.Get(ctx),
+		brokerinformerfake.Get(ctx),
+		&mockReporter{},
+		configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+		func(ctx context.Context) context.Context {
+			return ctx
+		},
+	)
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+
+	trigger := makeTrigger(withName("breaker"), withUID("breaker"))
+	trigger.Spec.Broker = "default"
+	trigger.Spec.Delivery = &eventingduckv1.DeliverySpec{
+		CircuitBreaker: &eventingduckv1.CircuitBreakerSpec{
+			FailurePercentage: ptr.To[int32](100),
+			MinimumRequests:   ptr.To[int32](2),
+			OpenDuration:      ptr.To("PT1H"),
+		},
+		DeadLetterFormat: ptr.To(eventingduckv1.DeadLetterFormatHistory),
+	}
+	trigger.Status.SubscriberURI, _ = apis.ParseURL(s.URL)
+	trigger.Status.DeadLetterSinkURI, _ = apis.ParseURL(s.URL + "/dls")
+	triggerinformerfake.Get(ctx).Informer().GetStore().Add(trigger)
+
+	// The channel retries the events failed by the subscriber and sends them to the dead letter
+	// sink, while the filter sends the events rejected by the open circuit breaker there.
+	var statusCodes []int
+	for i := 0; i < 4; i++ {
+		b, err := makeEvent().MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		request := httptest.NewRequest(http.MethodPost, path.Generate(trigger), bytes.NewBuffer(b))
+		request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		responseWriter := httptest.NewRecorder()
+		r.ServeHTTP(responseWriter, request)
+		statusCodes = append(statusCodes, responseWriter.Code)
+	}
+
+	want := []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusAccepted, http.StatusAccepted}
+	if diff := cmp.Diff(want, statusCodes); diff != "" {
+		t.Error("unexpected status codes (-want +got):", diff)
+	}
+	if got := received.Load(); got != 2 {
+		t.Errorf("expected the subscriber to receive 2 events, got %d", got)
+	}
+	if got := deadLettered.Load(); got != 2 {
+		t.Errorf("expected the dead letter sink to receive 2 events, got %d", got)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// maxTrackedDispatches bounds the number of events whose dispatch to the Triggers of a Broker is
+// remembered while waiting for their redelivery.
+const maxTrackedDispatches = 10000
+
+// brokerEvent identifies an event dispatched to the Triggers of a Broker.
+type brokerEvent struct {
+	broker k8stypes.NamespacedName
+	source string
+	id     string
+}
+
+// dispatchCompletions remembers the Triggers an event was delivered to when the delivery to other
+// Triggers of the Broker failed, so that the redelivery of the event only reaches the Triggers it
+// wasn't delivered to yet.
+type dispatchCompletions struct {
+	mu sync.Mutex
+	// completed holds the UIDs of the Triggers the event was delivered to, by brokerEvent. It is
+	// bounded, the least recently dispatched events are evicted first.
+	completed *simplelru.LRU
+}
+
+func newDispatchCompletions() *dispatchCompletions {
+	completed, _ := simplelru.NewLRU(maxTrackedDispatches, nil)
+	return &dispatchCompletions{completed: completed}
+}
+
+// get returns the UIDs of the Triggers the event was already delivered to.
+func (d *dispatchCompletions) get(key brokerEvent) map[k8stypes.UID]struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if completed, ok := d.completed.Get(key); ok {
+		return completed.(map[k8stypes.UID]struct{})
+	}
+	return nil
+}
+
+// set records the UIDs of the Triggers the event was delivered to, it is expected to be redelivered.
+func (d *dispatchCompletions) set(key brokerEvent, completed map[k8stypes.UID]struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.completed.Add(key, completed)
+}
+
+// forget removes the event once it was delivered to every Trigger.
+func (d *dispatchCompletions) forget(key brokerEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.completed.Remove(key)
+}
@@ -24,11 +24,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	opencensusclient "github.com/cloudevents/sdk-go/observability/opencensus/v2/client"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
 	"github.com/cloudevents/sdk-go/v2/event"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"go.opencensus.io/trace"
@@ -44,6 +46,7 @@ import (
 	"knative.dev/eventing/pkg/eventingtls"
 	"knative.dev/eventing/pkg/utils"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	"knative.dev/eventing/pkg/apis/feature"
 	eventingbroker "knative.dev/eventing/pkg/broker"
@@ -51,6 +54,7 @@ import (
 	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
 	"knative.dev/eventing/pkg/eventfilter"
 	"knative.dev/eventing/pkg/eventfilter/attributes"
+	"knative.dev/eventing/pkg/eventfilter/index"
 	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
 	"knative.dev/eventing/pkg/eventtype"
 	"knative.dev/eventing/pkg/kncloudevents"
@@ -84,8 +88,16 @@ type Handler struct {
 	logger           *zap.Logger
 	withContext      func(ctx context.Context) context.Context
 	filtersMap       *subscriptionsapi.FiltersMap
+	triggerIndex     *index.Index
 	tokenVerifier    *auth.OIDCTokenVerifier
 	EventTypeCreator *eventtype.EventTypeAutoHandler
+	partitionLocks   *partitionLocks
+	completions      *dispatchCompletions
+	transforms       *transformCache
+
+	// CircuitBreakerRecorder, when set, records the open circuit breakers of the Trigger
+	// subscribers for the Trigger reconciler.
+	CircuitBreakerRecorder *CircuitBreakerRecorder
 }
 
 // NewHandler creates a new Handler and its associated EventReceiver.
@@ -96,11 +108,28 @@ func NewHandler(logger *zap.Logger, tokenVerifier *auth.OIDCTokenVerifier, oidcT
 	})
 
 	fm := subscriptionsapi.NewFiltersMap()
+	idx := index.NewIndex()
+	tc := newTransformCache()
 
 	clientConfig := eventingtls.ClientConfig{
 		TrustBundleConfigMapLister: trustBundleConfigMapLister,
 	}
 
+	h := &Handler{
+		reporter:        reporter,
+		eventDispatcher: kncloudevents.NewDispatcher(clientConfig, oidcTokenProvider),
+		triggerLister:   triggerInformer.Lister(),
+		brokerLister:    brokerInformer.Lister(),
+		logger:          logger,
+		tokenVerifier:   tokenVerifier,
+		withContext:     wc,
+		filtersMap:      fm,
+		triggerIndex:    idx,
+		partitionLocks:  newPartitionLocks(),
+		completions:     newDispatchCompletions(),
+		transforms:      tc,
+	}
+
 	triggerInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			trigger, ok := obj.(*eventingv1.Trigger)
@@ -109,18 +138,23 @@ func NewHandler(logger *zap.Logger, tokenVerifier *auth.OIDCTokenVerifier, oidcT
 			}
 			logger.Debug("Adding filter to filtersMap")
 			fm.Set(trigger, createSubscriptionsAPIFilters(logger, trigger))
+			idx.Set(trigger)
 			kncloudevents.AddOrUpdateAddressableHandler(clientConfig, duckv1.Addressable{
 				URL:     trigger.Status.SubscriberURI,
 				CACerts: trigger.Status.SubscriberCACerts,
 			})
 		},
-		UpdateFunc: func(_, obj interface{}) {
+		UpdateFunc: func(oldObj, obj interface{}) {
 			trigger, ok := obj.(*eventingv1.Trigger)
 			if !ok {
 				return
 			}
+			if old, ok := oldObj.(*eventingv1.Trigger); ok && old.Status.SubscriberURI.String() != trigger.Status.SubscriberURI.String() {
+				h.eventDispatcher.DeleteCircuitBreaker(old.Status.SubscriberURI.String())
+			}
 			logger.Debug("Updating filter in filtersMap")
 			fm.Set(trigger, createSubscriptionsAPIFilters(logger, trigger))
+			idx.Set(trigger)
 			kncloudevents.AddOrUpdateAddressableHandler(clientConfig, duckv1.Addressable{
 				URL:     trigger.Status.SubscriberURI,
 				CACerts: trigger.Status.SubscriberCACerts,
@@ -133,6 +167,12 @@ func NewHandler(logger *zap.Logger, tokenVerifier *auth.OIDCTokenVerifier, oidcT
 			}
 			logger.Debug("Deleting filter in filtersMap")
 			fm.Delete(trigger)
+			idx.Delete(trigger)
+			tc.delete(trigger)
+			h.eventDispatcher.DeleteCircuitBreaker(trigger.Status.SubscriberURI.String())
+			if h.CircuitBreakerRecorder != nil {
+				h.CircuitBreakerRecorder.record(trigger.UID, false)
+			}
 			kncloudevents.DeleteAddressableHandler(duckv1.Addressable{
 				URL:     trigger.Status.SubscriberURI,
 				CACerts: trigger.Status.SubscriberCACerts,
@@ -140,16 +180,7 @@ func NewHandler(logger *zap.Logger, tokenVerifier *auth.OIDCTokenVerifier, oidcT
 		},
 	})
 
-	return &Handler{
-		reporter:        reporter,
-		eventDispatcher: kncloudevents.NewDispatcher(clientConfig, oidcTokenProvider),
-		triggerLister:   triggerInformer.Lister(),
-		brokerLister:    brokerInformer.Lister(),
-		logger:          logger,
-		tokenVerifier:   tokenVerifier,
-		withContext:     wc,
-		filtersMap:      fm,
-	}, nil
+	return h, nil
 }
 
 func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
@@ -162,6 +193,11 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	if path.IsBroker(request.RequestURI) {
+		h.serveBroker(ctx, writer, request)
+		return
+	}
+
 	triggerRef, err := path.Parse(request.RequestURI)
 	if err != nil {
 		h.logger.Info("Unable to parse path as trigger", zap.Error(err), zap.String("path", request.RequestURI))
@@ -198,19 +234,8 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		span.AddAttributes(opencensusclient.EventTraceAttributes(event)...)
 	}
 
-	features := feature.FromContext(ctx)
-	if features.IsOIDCAuthentication() {
-		h.logger.Debug("OIDC authentication is enabled")
-
-		audience := FilterAudience
-
-		err = h.tokenVerifier.VerifyJWTFromRequest(ctx, request, &audience, writer)
-		if err != nil {
-			h.logger.Warn("Error when validating the JWT token in the request", zap.Error(err))
-			return
-		}
-
-		h.logger.Debug("Request contained a valid JWT. Continuing...")
+	if !h.verifyRequest(ctx, writer, request) {
+		return
 	}
 
 	if triggerRef.IsReply {
@@ -226,6 +251,73 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	h.handleDispatchToSubscriberRequest(ctx, trigger, writer, request, event)
 }
 
+// serveBroker handles a request sent to every Trigger of a Broker at once: the event is matched
+// against the broker-wide Trigger index and dispatched to the subscribers of the matching Triggers
+// only, instead of requiring a request per Trigger.
+func (h *Handler) serveBroker(ctx context.Context, writer http.ResponseWriter, request *http.Request) {
+	brokerRef, err := path.ParseBroker(request.RequestURI)
+	if err != nil {
+		h.logger.Info("Unable to parse path as broker", zap.Error(err), zap.String("path", request.RequestURI))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	broker, err := h.brokerLister.Brokers(brokerRef.Namespace).Get(brokerRef.Name)
+	if err != nil {
+		h.logger.Info("Unable to get the Broker", zap.Error(err), zap.Any("brokerRef", brokerRef))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	event, err := cehttp.NewEventFromHTTPRequest(request)
+	if err != nil {
+		h.logger.Warn("failed to extract event from request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Received message", zap.Any("broker", brokerRef), zap.Stringer("event", event))
+
+	ctx, span := trace.StartSpan(ctx, tracing.BrokerMessagingDestination(brokerRef))
+	defer span.End()
+
+	if span.IsRecordingEvents() {
+		span.AddAttributes(
+			tracing.MessagingSystemAttribute,
+			tracing.MessagingProtocolHTTP,
+			tracing.BrokerMessagingDestinationAttribute(brokerRef),
+			tracing.MessagingMessageIDAttribute(event.ID()),
+		)
+		span.AddAttributes(opencensusclient.EventTraceAttributes(event)...)
+	}
+
+	if !h.verifyRequest(ctx, writer, request) {
+		return
+	}
+
+	h.handleDispatchToBrokerRequest(ctx, broker, writer, request, event)
+}
+
+// verifyRequest verifies the OIDC token of the request when OIDC authentication is enabled.
+// It returns false when the request must not be processed any further, in that case the
+// response has already been written.
+func (h *Handler) verifyRequest(ctx context.Context, writer http.ResponseWriter, request *http.Request) bool {
+	if !feature.FromContext(ctx).IsOIDCAuthentication() {
+		return true
+	}
+	h.logger.Debug("OIDC authentication is enabled")
+
+	audience := FilterAudience
+
+	if err := h.tokenVerifier.VerifyJWTFromRequest(ctx, request, &audience, writer); err != nil {
+		h.logger.Warn("Error when validating the JWT token in the request", zap.Error(err))
+		return false
+	}
+
+	h.logger.Debug("Request contained a valid JWT. Continuing...")
+	return true
+}
+
 func (h *Handler) handleDispatchToReplyRequest(ctx context.Context, trigger *eventingv1.Trigger, writer http.ResponseWriter, request *http.Request, event *event.Event) {
 	var brokerName, brokerNamespace string
 	if feature.FromContext(ctx).IsEnabled(feature.CrossNamespaceEventLinks) && trigger.Spec.BrokerRef != nil {
@@ -324,6 +416,15 @@ func (h *Handler) handleDispatchToDLSRequest(ctx context.Context, trigger *event
 		reportArgs.requestScheme = "http"
 	}
 
+	// The dead letter sink receives the event as it was sent to the subscriber.
+	event, err = h.transforms.transformEvent(ctx, trigger, event)
+	if err != nil {
+		h.logger.Error("failed to transform event", zap.Error(err))
+		writer.WriteHeader(http.StatusInternalServerError)
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusInternalServerError)
+		return
+	}
+
 	h.logger.Info("sending to dls", zap.Any("target", target))
 
 	// since the broker-filter acts here like a proxy, we don't filter headers
@@ -386,7 +487,7 @@ func (h *Handler) handleDispatchToSubscriberRequest(ctx context.Context, trigger
 
 	// Check if the event should be sent.
 	ctx = logging.WithLogger(ctx, h.logger.Sugar().With(zap.String("trigger", fmt.Sprintf("%s/%s", trigger.GetNamespace(), trigger.GetName()))))
-	filterResult := h.filterEvent(ctx, trigger, *event)
+	filterResult := h.filterEvent(subscriptionsapi.WithParsedPayload(ctx, *event), trigger, *event)
 
 	if filterResult == eventfilter.FailFilter {
 		// We do not count the event. The event will be counted in the broker ingress.
@@ -402,10 +503,250 @@ func (h *Handler) handleDispatchToSubscriberRequest(ctx context.Context, trigger
 		Audience: trigger.Status.SubscriberAudience,
 	}
 
-	h.send(ctx, writer, utils.PassThroughHeaders(request.Header), target, reportArgs, event, trigger, ttl)
+	unlock, err := h.lockPartition(ctx, trigger, *event)
+	if err != nil {
+		h.logger.Warn("failed to wait for the previous events of the partition", zap.Error(err))
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusServiceUnavailable)
+		return
+	}
+	defer unlock()
+
+	retryOpts, err := orderedRetryOptions(trigger)
+	if err != nil {
+		h.logger.Error("failed to create retry config", zap.Error(err))
+		writer.WriteHeader(http.StatusInternalServerError)
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusInternalServerError)
+		return
+	}
+
+	opts := append(throttleOptions(trigger), retryOpts...)
+	opts = append(opts, circuitOpenDeadLetterSinkOptions(trigger)...)
+	opts = append(opts, failureHistoryOptions(trigger, trigger.Spec.Delivery)...)
+
+	event, err = h.transforms.transformEvent(ctx, trigger, event)
+	if err != nil {
+		h.logger.Error("failed to transform event", zap.Error(err))
+		writer.WriteHeader(http.StatusInternalServerError)
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusInternalServerError)
+		return
+	}
+	h.send(ctx, writer, utils.PassThroughHeaders(request.Header), target, reportArgs, event, trigger, ttl, opts...)
+}
+
+// handleDispatchToBrokerRequest dispatches the event to the subscribers of every Trigger of the
+// Broker that matches it. Since no single subscriber response can be proxied back, each Trigger's
+// retries, dead letter sink and reply forwarding are handled here rather than by the channel.
+func (h *Handler) handleDispatchToBrokerRequest(ctx context.Context, broker *eventingv1.Broker, writer http.ResponseWriter, request *http.Request, event *event.Event) {
+	brokerRef := types.NamespacedName{
+		Name:      broker.Name,
+		Namespace: broker.Namespace,
+	}
+
+	// Remove the TTL attribute that is used by the Broker.
+	ttl, err := eventingbroker.GetTTL(event.Context)
+	if err != nil {
+		// Only messages sent by the Broker should be here. If the attribute isn't here, then the
+		// event wasn't sent by the Broker, so we can drop it.
+		h.logger.Warn("No TTL seen, dropping", zap.Any("brokerRef", brokerRef), zap.Any("event", event))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := eventingbroker.DeleteTTL(event.Context); err != nil {
+		h.logger.Warn("Failed to delete TTL.", zap.Error(err))
+	}
+
+	mode := index.AttributesMode
+	if feature.FromContext(ctx).IsEnabled(feature.NewTriggerFilters) {
+		mode = index.SubscriptionsAPIMode
+	}
+
+	requestScheme := "http"
+	if request.TLS != nil {
+		requestScheme = "https"
+	}
+	headers := utils.PassThroughHeaders(request.Header)
+
+	// The payload is parsed at most once for all the Triggers of the Broker.
+	filterCtx := subscriptionsapi.WithParsedPayload(ctx, *event)
+
+	// When the event is redelivered, it is only dispatched to the Triggers it wasn't delivered to.
+	key := brokerEvent{broker: brokerRef, source: event.Source(), id: event.ID()}
+	delivered := h.completions.get(key)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	completed := make(map[types.UID]struct{}, len(delivered))
+	failed := false
+	for _, trigger := range h.triggerIndex.Candidates(brokerRef, *event, mode) {
+		if trigger.Status.SubscriberURI == nil {
+			continue
+		}
+		if _, ok := delivered[trigger.UID]; ok {
+			completed[trigger.UID] = struct{}{}
+			continue
+		}
+
+		triggerCtx := logging.WithLogger(filterCtx, h.logger.Sugar().With(zap.String("trigger", fmt.Sprintf("%s/%s", trigger.GetNamespace(), trigger.GetName()))))
+		if h.filterEvent(triggerCtx, trigger, *event) == eventfilter.FailFilter {
+			continue
+		}
+
+		reportArgs := &ReportArgs{
+			ns:            trigger.Namespace,
+			trigger:       trigger.Name,
+			broker:        broker.Name,
+			filterType:    triggerFilterAttribute(trigger.Spec.Filter, "type"),
+			requestType:   "filter",
+			requestScheme: requestScheme,
+		}
+		h.reportArrivalTime(event, reportArgs)
+
+		wg.Add(1)
+		go func(trigger *eventingv1.Trigger) {
+			defer wg.Done()
+			// The event is delivered once it is accepted by the subscriber or, when the retries
+			// are exhausted, by the dead letter sink of the Trigger.
+			err := h.dispatchToTrigger(triggerCtx, broker, trigger, headers, reportArgs, event, ttl)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				h.logger.Error("failed to dispatch event to trigger", zap.Error(err), zap.String("trigger", fmt.Sprintf("%s/%s", trigger.GetNamespace(), trigger.GetName())))
+				failed = true
+				return
+			}
+			completed[trigger.UID] = struct{}{}
+		}(trigger)
+	}
+	wg.Wait()
+
+	if failed {
+		// The upstream redelivers the event, the Triggers it was delivered to are skipped then.
+		h.completions.set(key, completed)
+		writer.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	h.completions.forget(key)
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// dispatchToTrigger sends the event to the Trigger's subscriber applying the Trigger's (or else
+// the Broker's) delivery spec, and forwards the eventual reply to the Broker.
+func (h *Handler) dispatchToTrigger(ctx context.Context, broker *eventingv1.Broker, t *eventingv1.Trigger, headers http.Header, reportArgs *ReportArgs, event *cloudevents.Event, ttl int32) error {
+	target := duckv1.Addressable{
+		URL:      t.Status.SubscriberURI,
+		CACerts:  t.Status.SubscriberCACerts,
+		Audience: t.Status.SubscriberAudience,
+	}
+
+	opts := h.sendOptions(headers, t)
+
+	delivery := t.Spec.Delivery
+	if delivery == nil {
+		delivery = broker.Spec.Delivery
+	}
+	if delivery != nil {
+		retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(*delivery)
+		if err != nil {
+			return fmt.Errorf("failed to create retry config: %w", err)
+		}
+		opts = append(opts, kncloudevents.WithRetryConfig(&retryConfig))
+	}
+
+	circuitBreakerOpts, err := h.circuitBreakerOptions(t, reportArgs)
+	if err != nil {
+		return fmt.Errorf("failed to create circuit breaker config: %w", err)
+	}
+	opts = append(opts, circuitBreakerOpts...)
+	opts = append(opts, throttleOptions(t)...)
+
+	if dls := deadLetterSink(t, broker); dls != nil {
+		opts = append(opts, kncloudevents.WithDeadLetterSink(dls))
+	}
+
+	opts = append(opts, failureHistoryOptions(t, delivery)...)
+
+	if broker.Status.Address != nil && broker.Status.Address.URL != nil {
+		reply := *broker.Status.Address
+		opts = append(opts,
+			kncloudevents.WithReply(&reply),
+			// Reattach the TTL (with the same value) to the reply event before sending it to the Broker.
+			kncloudevents.WithReplyTransformers(transformer.AddExtension(eventingbroker.TTLAttribute, ttl)),
+		)
+	}
+
+	// The events of a partition are dispatched one after the other, including their retries.
+	unlock, err := h.lockPartition(ctx, t, *event)
+	if err != nil {
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusServiceUnavailable)
+		return fmt.Errorf("failed to wait for the previous events of the partition: %w", err)
+	}
+	defer unlock()
+
+	event, err = h.transforms.transformEvent(ctx, t, event)
+	if err != nil {
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusInternalServerError)
+		return fmt.Errorf("failed to transform event: %w", err)
+	}
+
+	dispatchInfo, err := h.eventDispatcher.SendEvent(ctx, *event, target, opts...)
+	if dispatchInfo == nil || dispatchInfo.ResponseCode <= 0 {
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusInternalServerError)
+		return err
+	}
+
+	h.reporter.ReportEventDispatchTime(reportArgs, dispatchInfo.ResponseCode, dispatchInfo.Duration)
+	_ = h.reporter.ReportEventCount(reportArgs, dispatchInfo.ResponseCode)
+	return err
+}
+
+// deadLetterSink returns the dead letter sink of the Trigger, or else the one of the Broker.
+func deadLetterSink(t *eventingv1.Trigger, b *eventingv1.Broker) *duckv1.Addressable {
+	if t.Status.DeadLetterSinkURI != nil {
+		return &duckv1.Addressable{
+			URL:      t.Status.DeadLetterSinkURI,
+			CACerts:  t.Status.DeadLetterSinkCACerts,
+			Audience: t.Status.DeadLetterSinkAudience,
+		}
+	}
+	if b.Status.DeadLetterSinkURI != nil {
+		return &duckv1.Addressable{
+			URL:      b.Status.DeadLetterSinkURI,
+			CACerts:  b.Status.DeadLetterSinkCACerts,
+			Audience: b.Status.DeadLetterSinkAudience,
+		}
+	}
+	return nil
+}
+
+// throttleOptions returns the options throttling the requests to the Trigger's subscriber, when its
+// delivery spec limits them.
+func throttleOptions(t *eventingv1.Trigger) []kncloudevents.SendOption {
+	if t.Spec.Delivery == nil {
+		return nil
+	}
+	config := kncloudevents.ThrottleConfigFromDeliverySpec(*t.Spec.Delivery)
+	if config == nil {
+		return nil
+	}
+	return []kncloudevents.SendOption{kncloudevents.WithThrottle(config)}
+}
+
+// failureHistoryOptions returns the options adding the history of the attempts to send the event
+// to the events sent to the dead letter sink, when the delivery spec asks for it.
+func failureHistoryOptions(t *eventingv1.Trigger, delivery *eventingduckv1.DeliverySpec) []kncloudevents.SendOption {
+	if !delivery.FailureHistory() {
+		return nil
+	}
+	return []kncloudevents.SendOption{kncloudevents.WithFailureHistory(&duckv1.KReference{
+		Name:       t.Name,
+		Namespace:  t.Namespace,
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Kind:       "Trigger",
+	})}
 }
 
-func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers http.Header, target duckv1.Addressable, reportArgs *ReportArgs, event *cloudevents.Event, t *eventingv1.Trigger, ttl int32) {
+func (h *Handler) sendOptions(headers http.Header, t *eventingv1.Trigger) []kncloudevents.SendOption {
 	additionalHeaders := headers.Clone()
 	additionalHeaders.Set(apis.KnNamespaceHeader, t.GetNamespace())
 
@@ -433,6 +774,21 @@ func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers
 		}))
 	}
 
+	return opts
+}
+
+func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers http.Header, target duckv1.Addressable, reportArgs *ReportArgs, event *cloudevents.Event, t *eventingv1.Trigger, ttl int32, extraOpts ...kncloudevents.SendOption) {
+	opts := append(h.sendOptions(headers, t), extraOpts...)
+
+	circuitBreakerOpts, err := h.circuitBreakerOptions(t, reportArgs)
+	if err != nil {
+		h.logger.Error("failed to create circuit breaker config", zap.Error(err))
+		writer.WriteHeader(http.StatusInternalServerError)
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusInternalServerError)
+		return
+	}
+	opts = append(opts, circuitBreakerOpts...)
+
 	dispatchInfo, err := h.eventDispatcher.SendEvent(ctx, *event, target, opts...)
 	if err != nil {
 		h.logger.Error("failed to send event", zap.Error(err))
@@ -589,62 +945,7 @@ func createSubscriptionsAPIFilters(logger *zap.Logger, trigger *eventingv1.Trigg
 		logger.Debug("Found no filters for trigger", zap.Any("trigger.Spec", trigger.Spec))
 		return subscriptionsapi.NewNoFilter()
 	}
-	return subscriptionsapi.NewAllFilter(MaterializeFiltersList(logger, trigger.Spec.Filters)...)
-}
-
-func materializeSubscriptionsAPIFilter(logger *zap.Logger, filter eventingv1.SubscriptionsAPIFilter) eventfilter.Filter {
-	var materializedFilter eventfilter.Filter
-	var err error
-	switch {
-	case len(filter.Exact) > 0:
-		// The webhook validates that this map has only a single key:value pair.
-		materializedFilter, err = subscriptionsapi.NewExactFilter(filter.Exact)
-		if err != nil {
-			logger.Debug("Invalid exact expression", zap.Any("filters", filter.Exact), zap.Error(err))
-			return nil
-		}
-	case len(filter.Prefix) > 0:
-		// The webhook validates that this map has only a single key:value pair.
-		materializedFilter, err = subscriptionsapi.NewPrefixFilter(filter.Prefix)
-		if err != nil {
-			logger.Debug("Invalid prefix expression", zap.Any("filters", filter.Exact), zap.Error(err))
-			return nil
-		}
-	case len(filter.Suffix) > 0:
-		// The webhook validates that this map has only a single key:value pair.
-		materializedFilter, err = subscriptionsapi.NewSuffixFilter(filter.Suffix)
-		if err != nil {
-			logger.Debug("Invalid suffix expression", zap.Any("filters", filter.Exact), zap.Error(err))
-			return nil
-		}
-	case len(filter.All) > 0:
-		materializedFilter = subscriptionsapi.NewAllFilter(MaterializeFiltersList(logger, filter.All)...)
-	case len(filter.Any) > 0:
-		materializedFilter = subscriptionsapi.NewAnyFilter(MaterializeFiltersList(logger, filter.Any)...)
-	case filter.Not != nil:
-		materializedFilter = subscriptionsapi.NewNotFilter(materializeSubscriptionsAPIFilter(logger, *filter.Not))
-	case filter.CESQL != "":
-		if materializedFilter, err = subscriptionsapi.NewCESQLFilter(filter.CESQL); err != nil {
-			// This is weird, CESQL expression should be validated when Trigger's are created.
-			logger.Debug("Found an Invalid CE SQL expression", zap.String("expression", filter.CESQL))
-			return nil
-		}
-	}
-	return materializedFilter
-}
-
-// MaterialzieFilterList allows any component that supports `SubscriptionsAPIFilter` to process them
-func MaterializeFiltersList(logger *zap.Logger, filters []eventingv1.SubscriptionsAPIFilter) []eventfilter.Filter {
-	materializedFilters := make([]eventfilter.Filter, 0, len(filters))
-	for _, f := range filters {
-		f := materializeSubscriptionsAPIFilter(logger, f)
-		if f == nil {
-			logger.Warn("Failed to parse filter. Skipping filter.", zap.Any("filter", f))
-			continue
-		}
-		materializedFilters = append(materializedFilters, f)
-	}
-	return materializedFilters
+	return subscriptionsapi.NewAllFilter(subscriptionsapi.MaterializeFiltersList(logger, trigger.Spec.Filters)...)
 }
 
 func applyAttributesFilter(ctx context.Context, filter *eventingv1.TriggerFilter, event cloudevents.Event) eventfilter.FilterResult {
@@ -23,7 +23,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -40,6 +42,7 @@ import (
 	"knative.dev/pkg/apis"
 	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/fake"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
 	reconcilertesting "knative.dev/pkg/reconciler/testing"
 
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
@@ -134,6 +137,39 @@ func TestReceiver(t *testing.T) {
 			expectedEventCount:        true,
 			expectedEventDispatchTime: true,
 		},
+		"Dispatch succeeded - Transform": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withTransform(&eventingv1.TriggerTransform{
+					Set:    map[string]string{"type": "{{ .Type }}.v2", "origin": "{{ .Source }}"},
+					Rename: map[string]string{extensionName: "renamed"},
+					Remove: []string{"subject"},
+				})),
+			},
+			event: func() *cloudevents.Event {
+				e := makeEventWithExtension(extensionName, extensionValue)
+				e.SetSubject("some-subject")
+				return e
+			}(),
+			expectedHeaders: http.Header{
+				"Ce-Type":        []string{eventType + ".v2"},
+				"Ce-Origin":      []string{eventSource},
+				"Ce-Renamed":     []string{extensionValue},
+				"Ce-Myextension": nil,
+				"Ce-Subject":     nil,
+			},
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Transform fails": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withTransform(&eventingv1.TriggerTransform{
+					Set: map[string]string{"tenant": "{{ .Extensions.tenant }}"},
+				})),
+			},
+			expectedStatus:     http.StatusInternalServerError,
+			expectedEventCount: true,
+		},
 		"No TTL": {
 			triggers: []*eventingv1.Trigger{
 				makeTrigger(withAttributesFilter(&eventingv1.TriggerFilter{
@@ -590,6 +626,73 @@ func TestReceiver_WithSubscriptionsAPI(t *testing.T) {
 			},
 			expectedEventCount: false,
 		},
+		"Wrong source regex": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
+					Regex: map[string]string{"source": "^/other"},
+				})),
+			},
+			expectedEventCount: false,
+		},
+		"Dispatch succeeded - Source regex": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
+					Regex: map[string]string{"source": "^/my[a-z]+$"},
+				})),
+			},
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Extension out of range": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
+					Range: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)}},
+				})),
+			},
+			event:              makeEventWithExtension("priority", "8"),
+			expectedEventCount: false,
+		},
+		"Dispatch succeeded - Extension within range": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
+					Range: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)}},
+				})),
+			},
+			event:                     makeEventWithExtension("priority", "5"),
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Wrong data value": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
+					Data: &eventingv1.SubscriptionsAPIDataFilter{Path: "$.region", Exact: ptr.String("us-east")},
+				})),
+			},
+			event:              makeEventWithData(cloudevents.ApplicationJSON, `{"region": "eu-west"}`),
+			expectedEventCount: false,
+		},
+		"Non JSON data": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
+					Data: &eventingv1.SubscriptionsAPIDataFilter{Path: "$.region", Exists: ptr.Bool(false)},
+				})),
+			},
+			event:              makeEventWithData("text/plain", "region=eu-west"),
+			expectedEventCount: false,
+		},
+		"Dispatch succeeded - Data prefix": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
+					Data: &eventingv1.SubscriptionsAPIDataFilter{Path: "$.region", Prefix: ptr.String("eu-")},
+				})),
+			},
+			event:                     makeEventWithData(cloudevents.ApplicationJSON, `{"region": "eu-west"}`),
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
 		"Dispatch succeeded - Source with type": {
 			triggers: []*eventingv1.Trigger{
 				makeTrigger(withSubscriptionAPIFilter(&eventingv1.SubscriptionsAPIFilter{
@@ -721,6 +824,253 @@ func TestReceiver_WithSubscriptionsAPI(t *testing.T) {
 	}
 }
 
+func TestReceiver_BrokerDispatch(t *testing.T) {
+	testCases := map[string]struct {
+		triggers           []*eventingv1.Trigger
+		path               string
+		event              *cloudevents.Event
+		subscriberStatus   int
+		expectedStatus     int
+		expectedDispatched []string
+		expectedTypes      map[string]string
+	}{
+		"Unknown broker": {
+			path:           fmt.Sprintf("/brokers/%s/%s", testNS, "unknown"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"No TTL": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withName("a")),
+			},
+			event:          makeEventWithoutTTL(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Dispatch to matching triggers only": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withName("a"), withUID("a"), withAttributesFilter(&eventingv1.TriggerFilter{
+					Attributes: map[string]string{"type": eventType},
+				})),
+				makeTrigger(withName("b"), withUID("b"), withAttributesFilter(&eventingv1.TriggerFilter{
+					Attributes: map[string]string{"type": "some-other-type"},
+				})),
+				makeTrigger(withName("c"), withUID("c"), withAttributesFilter(&eventingv1.TriggerFilter{
+					Attributes: map[string]string{"type": eventType, "source": "some-other-source"},
+				})),
+				makeTrigger(withName("d"), withUID("d")),
+				makeTrigger(withName("e"), withUID("e"), withoutSubscriberURI()),
+			},
+			expectedStatus:     http.StatusAccepted,
+			expectedDispatched: []string{"a", "d"},
+		},
+		"Subscriber fails": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withName("a"), withUID("a")),
+			},
+			subscriberStatus:   http.StatusServiceUnavailable,
+			expectedStatus:     http.StatusBadGateway,
+			expectedDispatched: []string{"a"},
+		},
+		"Transform applies to its trigger only": {
+			triggers: []*eventingv1.Trigger{
+				makeTrigger(withName("a"), withUID("a"), withTransform(&eventingv1.TriggerTransform{
+					Set: map[string]string{"type": "{{ .Type }}.v2"},
+				})),
+				makeTrigger(withName("b"), withUID("b")),
+			},
+			expectedStatus:     http.StatusAccepted,
+			expectedDispatched: []string{"a", "b"},
+			expectedTypes:      map[string]string{"a": eventType + ".v2", "b": eventType},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+			var mu sync.Mutex
+			var dispatched []string
+			receivedTypes := make(map[string]string)
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				dispatched = append(dispatched, strings.TrimPrefix(r.URL.Path, "/"))
+				receivedTypes[strings.TrimPrefix(r.URL.Path, "/")] = r.Header.Get("Ce-Type")
+				mu.Unlock()
+				if tc.subscriberStatus != 0 {
+					w.WriteHeader(tc.subscriberStatus)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer s.Close()
+
+			logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller()))
+
+			reporter := &mockReporter{}
+			r, err := NewHandler(
+				logger,
+				auth.NewOIDCTokenVerifier(ctx),
+				auth.NewOIDCTokenProvider(ctx),
+				triggerinformerfake.Get(ctx),
+				brokerinformerfake.Get(ctx),
+				reporter,
+				configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+				func(ctx context.Context) context.Context {
+					return ctx
+				},
+			)
+			if err != nil {
+				t.Fatal("Unable to create receiver:", err)
+			}
+
+			brokerinformerfake.Get(ctx).Informer().GetStore().Add(&v1.Broker{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: testNS,
+				},
+			})
+			for _, trig := range tc.triggers {
+				trig.Spec.Broker = "default"
+				if trig.Status.SubscriberURI != nil {
+					url, err := apis.ParseURL(s.URL + "/" + trig.Name)
+					if err != nil {
+						t.Fatalf("Failed to parse URL %q : %s", s.URL, err)
+					}
+					trig.Status.SubscriberURI = url
+				}
+				r.triggerIndex.Set(trig)
+			}
+
+			e := tc.event
+			if e == nil {
+				e = makeEvent()
+			}
+			b, err := e.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := tc.path
+			if p == "" {
+				p = fmt.Sprintf("/brokers/%s/%s", testNS, "default")
+			}
+			request := httptest.NewRequest(http.MethodPost, p, bytes.NewBuffer(b))
+			request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+			responseWriter := httptest.NewRecorder()
+			r.ServeHTTP(&responseWriterWithInvocationsCheck{
+				ResponseWriter: responseWriter,
+				headersWritten: atomic.NewBool(false),
+				t:              t,
+			}, request)
+
+			if got := responseWriter.Result().StatusCode; got != tc.expectedStatus {
+				t.Errorf("Unexpected status. Expected %v. Actual %v.", tc.expectedStatus, got)
+			}
+			sort.Strings(dispatched)
+			if diff := cmp.Diff(tc.expectedDispatched, dispatched); diff != "" {
+				t.Error("Unexpected dispatched triggers (-want +got):", diff)
+			}
+			if tc.expectedTypes != nil {
+				if diff := cmp.Diff(tc.expectedTypes, receivedTypes); diff != "" {
+					t.Error("Unexpected event types (-want +got):", diff)
+				}
+			}
+			if (len(tc.expectedDispatched) > 0) != reporter.eventCountReported {
+				t.Errorf("Incorrect event count reported metric. Expected %v, Actual %v", len(tc.expectedDispatched) > 0, reporter.eventCountReported)
+			}
+		})
+	}
+}
+
+func TestReceiver_BrokerDispatchRedelivery(t *testing.T) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+	var mu sync.Mutex
+	var dispatched []string
+	failing := map[string]bool{"b": true, "c": true}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		mu.Lock()
+		defer mu.Unlock()
+		dispatched = append(dispatched, name)
+		if failing[name] {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer s.Close()
+
+	logger := zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller()))
+	r, err := NewHandler(
+		logger,
+		auth.NewOIDCTokenVerifier(ctx),
+		auth.NewOIDCTokenProvider(ctx),
+		triggerinformerfake.Get(ctx),
+		brokerinformerfake.Get(ctx),
+		&mockReporter{},
+		configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+		func(ctx context.Context) context.Context {
+			return ctx
+		},
+	)
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(&v1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: testNS,
+		},
+	})
+	// The delivery to "c" fails, but its dead letter sink accepts the event.
+	for _, trig := range []*eventingv1.Trigger{
+		makeTrigger(withName("a"), withUID("a")),
+		makeTrigger(withName("b"), withUID("b")),
+		makeTrigger(withName("c"), withUID("c")),
+	} {
+		trig.Spec.Broker = "default"
+		trig.Status.SubscriberURI, _ = apis.ParseURL(s.URL + "/" + trig.Name)
+		if trig.Name == "c" {
+			trig.Status.DeadLetterSinkURI, _ = apis.ParseURL(s.URL + "/c-dls")
+		}
+		r.triggerIndex.Set(trig)
+	}
+
+	dispatch := func() int {
+		b, err := makeEvent().MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/brokers/%s/%s", testNS, "default"), bytes.NewBuffer(b))
+		request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		responseWriter := httptest.NewRecorder()
+		r.ServeHTTP(responseWriter, request)
+
+		mu.Lock()
+		defer mu.Unlock()
+		sort.Strings(dispatched)
+		return responseWriter.Result().StatusCode
+	}
+
+	if got := dispatch(); got != http.StatusBadGateway {
+		t.Errorf("Unexpected status. Expected %v. Actual %v.", http.StatusBadGateway, got)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c", "c-dls"}, dispatched); diff != "" {
+		t.Error("Unexpected dispatched triggers (-want +got):", diff)
+	}
+
+	// The redelivery only reaches the Trigger the event wasn't delivered to.
+	mu.Lock()
+	dispatched = nil
+	failing["b"] = false
+	mu.Unlock()
+	if got := dispatch(); got != http.StatusAccepted {
+		t.Errorf("Unexpected status. Expected %v. Actual %v.", http.StatusAccepted, got)
+	}
+	if diff := cmp.Diff([]string{"b"}, dispatched); diff != "" {
+		t.Error("Unexpected dispatched triggers (-want +got):", diff)
+	}
+}
+
 func withSubscriptionAPIFilter(filter *eventingv1.SubscriptionsAPIFilter) TriggerOption {
 	return func(trigger *eventingv1.Trigger) {
 		trigger.Spec.Filters = []eventingv1.SubscriptionsAPIFilter{
@@ -743,26 +1093,42 @@ func (r *responseWriterWithInvocationsCheck) WriteHeader(statusCode int) {
 }
 
 type mockReporter struct {
+	// mu guards the fields, as the events are dispatched to the triggers concurrently.
+	mu                          sync.Mutex
 	eventCountReported          bool
 	eventDispatchTimeReported   bool
 	eventProcessingTimeReported bool
+	circuitBreakerStates        []string
 }
 
 func (r *mockReporter) ReportEventCount(args *ReportArgs, responseCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.eventCountReported = true
 	return nil
 }
 
 func (r *mockReporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.eventDispatchTimeReported = true
 	return nil
 }
 
 func (r *mockReporter) ReportEventProcessingTime(args *ReportArgs, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.eventProcessingTimeReported = true
 	return nil
 }
 
+func (r *mockReporter) ReportCircuitBreakerStateChange(args *ReportArgs, state string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.circuitBreakerStates = append(r.circuitBreakerStates, state)
+	return nil
+}
+
 type fakeHandler struct {
 	t *testing.T
 
@@ -860,6 +1226,12 @@ func makeTrigger(options ...TriggerOption) *eventingv1.Trigger {
 	return t
 }
 
+func withName(name string) TriggerOption {
+	return func(t *eventingv1.Trigger) {
+		t.ObjectMeta.Name = name
+	}
+}
+
 func withUID(uid string) TriggerOption {
 	return func(t *eventingv1.Trigger) {
 		t.ObjectMeta.UID = types.UID(uid)
@@ -872,6 +1244,12 @@ func withAttributesFilter(filter *eventingv1.TriggerFilter) TriggerOption {
 	}
 }
 
+func withTransform(transform *eventingv1.TriggerTransform) TriggerOption {
+	return func(t *eventingv1.Trigger) {
+		t.Spec.Transform = transform
+	}
+}
+
 func withoutSubscriberURI() TriggerOption {
 	return func(t *eventingv1.Trigger) {
 		t.Status.SubscriberURI = nil
@@ -911,6 +1289,12 @@ func makeEventWithExtension(extName, extValue string) *cloudevents.Event {
 	return &e
 }
 
+func makeEventWithData(contentType, data string) *cloudevents.Event {
+	e := makeEvent()
+	_ = e.SetData(contentType, []byte(data))
+	return e
+}
+
 func makeNonEmptyResponse() *http.Response {
 	r := &http.Response{
 		Status:     "200 OK",
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// partition identifies the events of a Trigger sharing a partition key.
+type partition struct {
+	trigger k8stypes.UID
+	key     string
+}
+
+// partitionLocks serializes the dispatch of the events of a partition, in the order the locks
+// are requested. The dispatch of the events of different partitions isn't serialized.
+type partitionLocks struct {
+	mu sync.Mutex
+	// waiters holds, for each locked partition, the channels closed to hand the lock over to
+	// the next dispatch of the partition.
+	waiters map[partition][]chan struct{}
+}
+
+func newPartitionLocks() *partitionLocks {
+	return &partitionLocks{waiters: make(map[partition][]chan struct{})}
+}
+
+// lock waits until the dispatches of the partition requested before are done, and returns the
+// function to call once the event is dispatched. An error is returned when the context is done
+// before, in which case the partition isn't locked.
+func (p *partitionLocks) lock(ctx context.Context, key partition) (func(), error) {
+	p.mu.Lock()
+	waiters, locked := p.waiters[key]
+	if !locked {
+		p.waiters[key] = nil
+		p.mu.Unlock()
+		return func() { p.unlock(key) }, nil
+	}
+	ready := make(chan struct{})
+	p.waiters[key] = append(waiters, ready)
+	p.mu.Unlock()
+
+	select {
+	case <-ready:
+		return func() { p.unlock(key) }, nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, w := range p.waiters[key] {
+		if w == ready {
+			p.waiters[key] = append(p.waiters[key][:i:i], p.waiters[key][i+1:]...)
+			return nil, ctx.Err()
+		}
+	}
+	// The lock was handed over concurrently, pass it on.
+	p.handOver(key)
+	return nil, ctx.Err()
+}
+
+func (p *partitionLocks) unlock(key partition) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handOver(key)
+}
+
+// handOver passes the lock of the partition to the next waiting dispatch, or releases it when
+// there is none. It must be called with mu held.
+func (p *partitionLocks) handOver(key partition) {
+	waiters := p.waiters[key]
+	if len(waiters) == 0 {
+		delete(p.waiters, key)
+		return
+	}
+	p.waiters[key] = waiters[1:]
+	close(waiters[0])
+}
+
+// lockPartition locks the partition of the event when the Trigger orders the delivery of its
+// events by partition key. The returned function must be called once the event is dispatched.
+func (h *Handler) lockPartition(ctx context.Context, t *eventingv1.Trigger, event cloudevents.Event) (func(), error) {
+	key, ok := partitionKey(t, event)
+	if !ok {
+		return func() {}, nil
+	}
+	return h.partitionLocks.lock(ctx, partition{trigger: t.UID, key: key})
+}
+
+// orderedRetryOptions returns the options retrying the delivery of the events to the Triggers
+// ordering them. The filter retries these events itself while their partition is locked, rather
+// than the channel, which would let the next events of the partition through between the attempts.
+func orderedRetryOptions(t *eventingv1.Trigger) ([]kncloudevents.SendOption, error) {
+	if t.Spec.Delivery.PartitionKey() == "" {
+		return nil, nil
+	}
+	retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(*t.Spec.Delivery)
+	if err != nil {
+		return nil, err
+	}
+	return []kncloudevents.SendOption{kncloudevents.WithRetryConfig(&retryConfig)}, nil
+}
+
+// partitionKey returns the partition key of the event, and whether the delivery of the event to
+// the Trigger is ordered.
+func partitionKey(t *eventingv1.Trigger, event cloudevents.Event) (string, bool) {
+	attribute := t.Spec.Delivery.PartitionKey()
+	if attribute == "" {
+		return "", false
+	}
+	value, ok := attributes.LookupAttribute(event, attribute)
+	if !ok {
+		return "", false
+	}
+	key, err := types.Format(value)
+	if err != nil || key == "" {
+		return "", false
+	}
+	return key, true
+}
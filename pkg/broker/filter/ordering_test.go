@@ -0,0 +1,295 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/fake"
+	reconcilertesting "knative.dev/pkg/reconciler/testing"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/auth"
+	brokerinformerfake "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker/fake"
+	triggerinformerfake "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/trigger/fake"
+	"knative.dev/eventing/pkg/reconciler/sugar/trigger/path"
+)
+
+func TestPartitionLocks(t *testing.T) {
+	locks := newPartitionLocks()
+	a := partition{trigger: "trigger", key: "a"}
+	b := partition{trigger: "trigger", key: "b"}
+
+	unlockA, err := locks.lock(context.Background(), a)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// Another partition isn't blocked.
+	unlockB, err := locks.lock(context.Background(), b)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	unlockB()
+
+	// The waiting dispatches get the lock in the order they requested it.
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			unlock, err := locks.lock(context.Background(), a)
+			if err != nil {
+				t.Error("unexpected error:", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			unlock()
+		}(i)
+		waitForWaiters(t, locks, a, i)
+	}
+
+	// A dispatch giving up leaves the queue.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := locks.lock(ctx, a)
+		done <- err
+	}()
+	waitForWaiters(t, locks, a, 4)
+	cancel()
+	if err := <-done; err == nil {
+		t.Error("expected an error when the context is done")
+	}
+	waitForWaiters(t, locks, a, 3)
+
+	unlockA()
+	wg.Wait()
+
+	if diff := cmp.Diff([]int{1, 2, 3}, order); diff != "" {
+		t.Error("unexpected dispatch order (-want +got):", diff)
+	}
+	if len(locks.waiters) != 0 {
+		t.Errorf("expected all the partitions to be unlocked, got %v", locks.waiters)
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	ordered := &eventingduckv1.DeliverySpec{Ordering: ptr.To(eventingduckv1.DeliveryOrderingPartitionKey)}
+	bySubject := &eventingduckv1.DeliverySpec{
+		Ordering:              ptr.To(eventingduckv1.DeliveryOrderingPartitionKey),
+		PartitionKeyAttribute: ptr.To("subject"),
+	}
+
+	tests := map[string]struct {
+		delivery *eventingduckv1.DeliverySpec
+		event    func(e *event.Event)
+		wantKey  string
+		wantOk   bool
+	}{
+		"unordered": {
+			event: func(e *event.Event) { e.SetExtension("partitionkey", "a") },
+		},
+		"partitionkey extension": {
+			delivery: ordered,
+			event:    func(e *event.Event) { e.SetExtension("partitionkey", "a") },
+			wantKey:  "a",
+			wantOk:   true,
+		},
+		"missing partitionkey extension": {
+			delivery: ordered,
+		},
+		"subject": {
+			delivery: bySubject,
+			event:    func(e *event.Event) { e.SetSubject("orders/1") },
+			wantKey:  "orders/1",
+			wantOk:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			trigger := makeTrigger()
+			trigger.Spec.Delivery = tc.delivery
+			e := makeEvent()
+			if tc.event != nil {
+				tc.event(e)
+			}
+			key, ok := partitionKey(trigger, *e)
+			if key != tc.wantKey || ok != tc.wantOk {
+				t.Errorf("partitionKey() = %q, %v, want %q, %v", key, ok, tc.wantKey, tc.wantOk)
+			}
+		})
+	}
+}
+
+func TestReceiver_OrderedDelivery(t *testing.T) {
+	for name, requestPath := range map[string]func(*eventingv1.Trigger) string{
+		"Broker": func(*eventingv1.Trigger) string {
+			return fmt.Sprintf("/brokers/%s/%s", testNS, "default")
+		},
+		// The retries of the ordered Triggers are sent by the filter rather than the channel.
+		"Trigger": path.Generate,
+	} {
+		t.Run(name, func(t *testing.T) {
+			testOrderedDelivery(t, requestPath)
+		})
+	}
+}
+
+func testOrderedDelivery(t *testing.T, requestPath func(*eventingv1.Trigger) string) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+	// The first attempt to deliver the first event blocks until released, and fails.
+	release := make(chan struct{})
+	firstReceived := make(chan struct{})
+	var mu sync.Mutex
+	var received []string
+	attempts := make(map[string]int)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("Ce-Id")
+		mu.Lock()
+		received = append(received, id)
+		attempts[id]++
+		attempt := attempts[id]
+		mu.Unlock()
+		if id == "a-1" && attempt == 1 {
+			close(firstReceived)
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer s.Close()
+
+	r, err := NewHandler(
+		zap.NewNop(),
+		auth.NewOIDCTokenVerifier(ctx),
+		auth.NewOIDCTokenProvider(ctx),
+		triggerinformerfake.Get(ctx),
+		brokerinformerfake.Get(ctx),
+		&mockReporter{},
+		configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+		func(ctx context.Context) context.Context {
+			return ctx
+		},
+	)
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(&eventingv1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: testNS,
+		},
+	})
+	trigger := makeTrigger(withName("ordered"), withUID("ordered"))
+	trigger.Spec.Broker = "default"
+	trigger.Spec.Delivery = &eventingduckv1.DeliverySpec{
+		Retry:         ptr.To[int32](1),
+		BackoffPolicy: ptr.To(eventingduckv1.BackoffPolicyLinear),
+		BackoffDelay:  ptr.To("PT0.01S"),
+		Ordering:      ptr.To(eventingduckv1.DeliveryOrderingPartitionKey),
+	}
+	trigger.Status.SubscriberURI, _ = apis.ParseURL(s.URL)
+	r.triggerIndex.Set(trigger)
+	triggerinformerfake.Get(ctx).Informer().GetStore().Add(trigger)
+
+	send := func(id, key string) <-chan int {
+		statusCode := make(chan int, 1)
+		e := makeEventWithExtension("partitionkey", key)
+		e.SetID(id)
+		b, err := e.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			request := httptest.NewRequest(http.MethodPost, requestPath(trigger), bytes.NewBuffer(b))
+			request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+			responseWriter := httptest.NewRecorder()
+			r.ServeHTTP(responseWriter, request)
+			statusCode <- responseWriter.Code
+		}()
+		return statusCode
+	}
+
+	first := send("a-1", "a")
+	<-firstReceived
+
+	// The events of another partition are delivered meanwhile.
+	select {
+	case statusCode := <-send("b-1", "b"):
+		if statusCode != http.StatusAccepted {
+			t.Errorf("expected status code %d got %d", http.StatusAccepted, statusCode)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the event of another partition wasn't delivered")
+	}
+
+	// The next event of the partition waits for the retry of the first one.
+	second := send("a-2", "a")
+	waitForWaiters(t, r.partitionLocks, partition{trigger: "ordered", key: "a"}, 1)
+	close(release)
+
+	for _, statusCode := range []int{<-first, <-second} {
+		if statusCode != http.StatusAccepted {
+			t.Errorf("expected status code %d got %d", http.StatusAccepted, statusCode)
+		}
+	}
+	if diff := cmp.Diff("a-1,b-1,a-1,a-2", strings.Join(received, ",")); diff != "" {
+		t.Error("unexpected delivery order (-want +got):", diff)
+	}
+}
+
+// waitForWaiters waits until the given number of dispatches wait for the lock of the partition.
+func waitForWaiters(t *testing.T, locks *partitionLocks, key partition, n int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		locks.mu.Lock()
+		waiting := len(locks.waiters[key])
+		locks.mu.Unlock()
+		if waiting == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d dispatches waiting for the partition, got %d", n, waiting)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -62,6 +62,14 @@ var (
 		stats.UnitMilliseconds,
 	)
 
+	// circuitBreakerStateChangeCountM is a counter which records the number of times the circuit
+	// breaker of a Trigger subscriber changed state.
+	circuitBreakerStateChangeCountM = stats.Int64(
+		"circuit_breaker_state_change_count",
+		"Number of times the circuit breaker of a Trigger subscriber changed state",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -72,6 +80,7 @@ var (
 	triggerFilterRequestSchemeKey = tag.MustNewKey(eventingmetrics.LabelEventScheme)
 	responseCodeKey               = tag.MustNewKey(eventingmetrics.LabelResponseCode)
 	responseCodeClassKey          = tag.MustNewKey(eventingmetrics.LabelResponseCodeClass)
+	circuitBreakerStateKey        = tag.MustNewKey(eventingmetrics.LabelCircuitBreakerState)
 )
 
 type ReportArgs struct {
@@ -92,6 +101,7 @@ type StatsReporter interface {
 	ReportEventCount(args *ReportArgs, responseCode int) error
 	ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error
 	ReportEventProcessingTime(args *ReportArgs, d time.Duration) error
+	ReportCircuitBreakerStateChange(args *ReportArgs, state string) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -132,6 +142,12 @@ func register() {
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000
 			TagKeys:     []tag.Key{triggerFilterTypeKey, triggerFilterRequestTypeKey, triggerFilterRequestSchemeKey, broker.UniqueTagKey, broker.ContainerTagKey},
 		},
+		&view.View{
+			Description: circuitBreakerStateChangeCountM.Description(),
+			Measure:     circuitBreakerStateChangeCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{triggerFilterTypeKey, triggerFilterRequestTypeKey, triggerFilterRequestSchemeKey, circuitBreakerStateKey, broker.UniqueTagKey, broker.ContainerTagKey},
+		},
 	)
 	if err != nil {
 		log.Printf("failed to register opencensus views, %s", err)
@@ -175,6 +191,17 @@ func (r *reporter) ReportEventProcessingTime(args *ReportArgs, d time.Duration)
 	return nil
 }
 
+// ReportCircuitBreakerStateChange captures the state changes of the circuit breaker of the
+// Trigger subscriber.
+func (r *reporter) ReportCircuitBreakerStateChange(args *ReportArgs, state string) error {
+	ctx, err := r.generateTag(args, tag.Insert(circuitBreakerStateKey, state))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, circuitBreakerStateChangeCountM.M(1))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, tags ...tag.Mutator) (context.Context, error) {
 	ctx := metricskey.WithResource(emptyContext, resource.Resource{
 		Type: eventingmetrics.ResourceTypeKnativeTrigger,
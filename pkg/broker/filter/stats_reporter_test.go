@@ -90,6 +90,18 @@ func TestStatsReporter(t *testing.T) {
 	})
 	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("event_processing_latencies", 2, wantTags))
 	metricstest.CheckDistributionData(t, "event_processing_latencies", wantTags, 2, 1000.0, 8000.0)
+
+	// test ReportCircuitBreakerStateChange
+	expectSuccess(t, func() error {
+		return r.ReportCircuitBreakerStateChange(args, "open")
+	})
+	wantStateTags := map[string]string{
+		metrics.LabelCircuitBreakerState: "open",
+	}
+	for k, v := range wantTags {
+		wantStateTags[k] = v
+	}
+	metricstest.AssertMetric(t, metricstest.IntMetric("circuit_breaker_state_change_count", 1, wantStateTags).WithResource(&resource))
 }
 
 func TestReporterEmptySourceAndTypeFilter(t *testing.T) {
@@ -155,6 +167,7 @@ func resetMetrics() {
 	metricstest.Unregister(
 		"event_count",
 		"event_dispatch_latencies",
-		"event_processing_latencies")
+		"event_processing_latencies",
+		"circuit_breaker_state_change_count")
 	register()
 }
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+)
+
+// transformCache holds the parsed templates of the Triggers' transforms, so that they are parsed
+// once per generation of the Trigger rather than for every event.
+type transformCache struct {
+	mu        sync.RWMutex
+	templates map[types.UID]*triggerTemplates
+}
+
+// triggerTemplates are the parsed templates of the values set by a generation of a Trigger.
+type triggerTemplates struct {
+	generation int64
+	set        map[string]*template.Template
+	err        error
+}
+
+func newTransformCache() *transformCache {
+	return &transformCache{templates: make(map[types.UID]*triggerTemplates)}
+}
+
+// get returns the parsed templates of the values set by the Trigger, by attribute name. The values
+// which aren't templated have no entry.
+func (c *transformCache) get(t *eventingv1.Trigger) (map[string]*template.Template, error) {
+	c.mu.RLock()
+	tt, ok := c.templates[t.UID]
+	c.mu.RUnlock()
+	if ok && tt.generation == t.Generation {
+		return tt.set, tt.err
+	}
+
+	tt = &triggerTemplates{generation: t.Generation, set: make(map[string]*template.Template)}
+	for name, text := range t.Spec.Transform.Set {
+		if !strings.Contains(text, "{{") {
+			continue
+		}
+		tmpl, err := eventingduckv1.ParseSubstitutionTemplate(template.New(name).Option("missingkey=error"), text)
+		if err != nil {
+			tt.err = fmt.Errorf("failed to parse the template of %q: %w", name, err)
+			break
+		}
+		tt.set[name] = tmpl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.templates[t.UID] = tt
+	return tt.set, tt.err
+}
+
+// delete removes the templates of the deleted Trigger.
+func (c *transformCache) delete(t *eventingv1.Trigger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.templates, t.UID)
+}
+
+// transformers returns the transformers applying the transform of the Trigger to the event. The
+// renamed and templated values are read from the event as it was received, before any of the
+// modifications.
+func (c *transformCache) transformers(t *eventingv1.Trigger, event cloudevents.Event) (binding.Transformers, error) {
+	transform := t.Spec.Transform
+	if transform == nil {
+		return nil, nil
+	}
+	templates, err := c.get(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every renamed attribute is removed before any is set, so that chained renames (a to b and b
+	// to c) don't remove the attributes they set.
+	// Iterate over the maps in a stable order, so that the result doesn't change between events.
+	var removals, transformers binding.Transformers
+	for _, from := range sortedKeys(transform.Rename) {
+		value, ok := attributes.LookupAttribute(event, from)
+		if !ok {
+			continue
+		}
+		removals = append(removals, deleteMetadata(from))
+		transformers = append(transformers, setMetadata(transform.Rename[from], value))
+	}
+	transformers = append(removals, transformers...)
+
+	// The templates are evaluated against a copy of the event, as the methods they call could
+	// modify it.
+	var data cloudevents.Event
+	if len(templates) > 0 {
+		data = event.Clone()
+	}
+	for _, name := range sortedKeys(transform.Set) {
+		value := transform.Set[name]
+		if tmpl, ok := templates[name]; ok {
+			b, err := eventingduckv1.ExecuteTemplate(tmpl, data, eventingv1.TriggerTransformValueMaxSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate the template of %q: %w", name, err)
+			}
+			value = string(b)
+		}
+		transformers = append(transformers, setMetadata(name, value))
+	}
+
+	for _, name := range transform.Remove {
+		transformers = append(transformers, deleteMetadata(name))
+	}
+	return transformers, nil
+}
+
+// transformEvent returns the event transformed by the transform of the Trigger, or the event
+// itself when it has none. The transform is applied to the event rather than with
+// kncloudevents.WithTransformers, since those are applied to the reply of the subscriber too.
+func (c *transformCache) transformEvent(ctx context.Context, t *eventingv1.Trigger, event *cloudevents.Event) (*cloudevents.Event, error) {
+	transformers, err := c.transformers(t, *event)
+	if err != nil {
+		return nil, err
+	}
+	if len(transformers) == 0 {
+		return event, nil
+	}
+	// The event is shared by the triggers dispatched concurrently, transform a copy of it.
+	transformed := event.Clone()
+	return binding.ToEvent(ctx, binding.ToMessage(&transformed), transformers...)
+}
+
+func setMetadata(name string, value interface{}) binding.Transformer {
+	updater := func(interface{}) (interface{}, error) { return value, nil }
+	if attr := spec.V1.Attribute(name); attr != nil {
+		return transformer.SetAttribute(attr.Kind(), updater)
+	}
+	return transformer.SetExtension(name, updater)
+}
+
+func deleteMetadata(name string) binding.Transformer {
+	if attr := spec.V1.Attribute(name); attr != nil {
+		return transformer.DeleteAttribute(attr.Kind())
+	}
+	return transformer.DeleteExtension(name)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
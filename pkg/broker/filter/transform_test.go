@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+func TestTriggerTransformers(t *testing.T) {
+	tests := map[string]struct {
+		transform *eventingv1.TriggerTransform
+		want      func(e *cloudevents.Event)
+		wantErr   bool
+	}{
+		"No transform": {
+			want: func(e *cloudevents.Event) {},
+		},
+		"Set attributes and extensions": {
+			transform: &eventingv1.TriggerTransform{
+				Set: map[string]string{
+					"type":    "{{ .Type }}.v2",
+					"subject": "{{ .Extensions.myextension }}",
+					"static":  "value",
+				},
+			},
+			want: func(e *cloudevents.Event) {
+				e.SetType(eventType + ".v2")
+				e.SetSubject(extensionValue)
+				e.SetExtension("static", "value")
+			},
+		},
+		"Rename, set and remove": {
+			transform: &eventingv1.TriggerTransform{
+				Rename: map[string]string{extensionName: "renamed", "missing": "other"},
+				// Templates see the event before the rename.
+				Set:    map[string]string{"copy": "{{ .Extensions.myextension }}"},
+				Remove: []string{"subject"},
+			},
+			want: func(e *cloudevents.Event) {
+				e.SetExtension(extensionName, nil)
+				e.SetExtension("renamed", extensionValue)
+				e.SetExtension("copy", extensionValue)
+				e.SetSubject("")
+			},
+		},
+		"Chained renames": {
+			transform: &eventingv1.TriggerTransform{
+				Rename: map[string]string{extensionName: "subject", "subject": "previoussubject"},
+			},
+			want: func(e *cloudevents.Event) {
+				e.SetExtension(extensionName, nil)
+				e.SetSubject(extensionValue)
+				e.SetExtension("previoussubject", "some-subject")
+			},
+		},
+		"Swapped renames": {
+			transform: &eventingv1.TriggerTransform{
+				Rename: map[string]string{extensionName: "subject", "subject": extensionName},
+			},
+			want: func(e *cloudevents.Event) {
+				e.SetSubject(extensionValue)
+				e.SetExtension(extensionName, "some-subject")
+			},
+		},
+		"Invalid template": {
+			transform: &eventingv1.TriggerTransform{
+				Set: map[string]string{"tenant": "{{ .Extensions.tenant"},
+			},
+			wantErr: true,
+		},
+		"Missing key in a template": {
+			transform: &eventingv1.TriggerTransform{
+				Set: map[string]string{"tenant": "{{ .Extensions.tenant }}"},
+			},
+			wantErr: true,
+		},
+		"Control structure in a template": {
+			transform: &eventingv1.TriggerTransform{
+				Set: map[string]string{"tenant": "{{ range 100000000 }}xxxxxxxx{{ end }}"},
+			},
+			wantErr: true,
+		},
+		"Expanded value too large": {
+			transform: &eventingv1.TriggerTransform{
+				Set: map[string]string{"tenant": strings.Repeat("x", eventingv1.TriggerTransformValueMaxSize) + "{{ .Type }}"},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := makeEventWithExtension(extensionName, extensionValue)
+			e.SetSubject("some-subject")
+
+			trigger := &eventingv1.Trigger{Spec: eventingv1.TriggerSpec{Transform: tc.transform}}
+			transformers, err := newTransformCache().transformers(trigger, *e)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("transformers() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			got, err := binding.ToEvent(context.Background(), binding.ToMessage(e), transformers...)
+			if err != nil {
+				t.Fatal("failed to apply the transformers:", err)
+			}
+
+			want := e.Clone()
+			tc.want(&want)
+			if diff := cmp.Diff(want.String(), got.String()); diff != "" {
+				t.Error("unexpected event (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestTransformCache(t *testing.T) {
+	c := newTransformCache()
+	trigger := &eventingv1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{UID: "uid", Generation: 1},
+		Spec: eventingv1.TriggerSpec{
+			Transform: &eventingv1.TriggerTransform{Set: map[string]string{"type": "{{ .Type }}.v2", "static": "value"}},
+		},
+	}
+
+	first, err := c.get(trigger)
+	if err != nil {
+		t.Fatal("get() =", err)
+	}
+	if _, ok := first["static"]; ok {
+		t.Error("the values which aren't templated must not be parsed")
+	}
+	if second, _ := c.get(trigger); second["type"] != first["type"] {
+		t.Error("the templates must be parsed once per generation")
+	}
+
+	trigger = trigger.DeepCopy()
+	trigger.Generation = 2
+	trigger.Spec.Transform.Set["type"] = "{{ .Source }}"
+	updated, err := c.get(trigger)
+	if err != nil {
+		t.Fatal("get() =", err)
+	}
+	if updated["type"] == first["type"] {
+		t.Error("the templates must be parsed again for a new generation")
+	}
+
+	c.delete(trigger)
+	if len(c.templates) != 0 {
+		t.Error("the templates of the deleted trigger must be removed")
+	}
+}
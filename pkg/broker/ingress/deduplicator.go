@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/broker"
+)
+
+const (
+	// deduplicationUnique is the result of the deduplication of the first event with a given
+	// source and id within the window.
+	deduplicationUnique = "unique"
+	// deduplicationDuplicate is the result of the deduplication of the events with the source and
+	// id of an event accepted within the window.
+	deduplicationDuplicate = "duplicate"
+	// deduplicationInFlight is the result of the deduplication of the events with the source and
+	// id of an event still being sent, which are refused until it is accepted or fails.
+	deduplicationInFlight = "inflight"
+)
+
+// deduplicator drops the events with the source and id of an event accepted within the window set
+// through the ingress deduplication status annotation of the Brokers.
+type deduplicator struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	brokers map[types.NamespacedName]*brokerDeduplicator
+}
+
+// brokerDeduplicator remembers the events recently accepted by a Broker.
+type brokerDeduplicator struct {
+	// annotation is the value of the status annotation the cache was created from.
+	annotation string
+	window     time.Duration
+	// seen holds the time the events were first accepted, by eventKey. It is bounded, the least
+	// recently seen events are evicted first.
+	seen *simplelru.LRU
+	// inFlight are the events being sent, they are seen once they are accepted.
+	inFlight map[eventKey]struct{}
+}
+
+type eventKey struct {
+	source string
+	id     string
+}
+
+func newDeduplicator() *deduplicator {
+	return &deduplicator{
+		now:     time.Now,
+		brokers: make(map[types.NamespacedName]*brokerDeduplicator),
+	}
+}
+
+// record returns deduplicationDuplicate when an event with the given source and id was accepted by
+// the Broker within the window, and deduplicationInFlight when it is still being sent. Otherwise,
+// it records the event as being sent and returns deduplicationUnique, the caller must then call
+// complete once it is sent. An empty result is returned when the Broker doesn't deduplicate its
+// events.
+func (d *deduplicator) record(b *eventingv1.Broker, source, id string) (string, error) {
+	name := types.NamespacedName{Namespace: b.Namespace, Name: b.Name}
+	annotation, ok := b.Status.Annotations[eventing.BrokerIngressDeduplicationStatusAnnotationKey]
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !ok {
+		delete(d.brokers, name)
+		return "", nil
+	}
+
+	bd, ok := d.brokers[name]
+	if !ok || bd.annotation != annotation {
+		var dedup broker.Deduplication
+		if err := json.Unmarshal([]byte(annotation), &dedup); err != nil {
+			return "", fmt.Errorf("failed to parse the ingress deduplication of the broker: %w", err)
+		}
+		seen, err := simplelru.NewLRU(dedup.CacheSize, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create the deduplication cache of the broker: %w", err)
+		}
+		bd = &brokerDeduplicator{
+			annotation: annotation,
+			window:     dedup.Window.Duration,
+			seen:       seen,
+			inFlight:   make(map[eventKey]struct{}),
+		}
+		d.brokers[name] = bd
+	}
+
+	key := eventKey{source: source, id: id}
+	now := d.now()
+	if accepted, ok := bd.seen.Get(key); ok && now.Sub(accepted.(time.Time)) < bd.window {
+		return deduplicationDuplicate, nil
+	}
+	if _, ok := bd.inFlight[key]; ok {
+		return deduplicationInFlight, nil
+	}
+	bd.inFlight[key] = struct{}{}
+	return deduplicationUnique, nil
+}
+
+// complete records the end of the sending of the event with the given source and id. The event is
+// seen when it was accepted, otherwise it is forgotten so that the sender can retry it.
+func (d *deduplicator) complete(b *eventingv1.Broker, source, id string, accepted bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	bd, ok := d.brokers[types.NamespacedName{Namespace: b.Namespace, Name: b.Name}]
+	if !ok {
+		return
+	}
+	key := eventKey{source: source, id: id}
+	delete(bd.inFlight, key)
+	if accepted {
+		bd.seen.Add(key, d.now())
+	}
+}
+
+// forget removes the events remembered for the deleted Broker.
+func (d *deduplicator) forget(b *eventingv1.Broker) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.brokers, types.NamespacedName{Namespace: b.Namespace, Name: b.Name})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+	"time"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+)
+
+func TestDeduplicator(t *testing.T) {
+	type request struct {
+		source string
+		id     string
+		after  time.Duration
+		want   string
+	}
+	tests := map[string]struct {
+		dedup    string
+		requests []request
+	}{
+		"no deduplication": {
+			requests: []request{
+				{source: "a", id: "1"},
+				{source: "a", id: "1"},
+			},
+		},
+		"within the window": {
+			dedup: `{"window":"1m0s","cacheSize":10}`,
+			requests: []request{
+				{source: "a", id: "1", want: deduplicationUnique},
+				{source: "a", id: "2", want: deduplicationUnique},
+				{source: "b", id: "1", want: deduplicationUnique},
+				{source: "a", id: "1", after: 30 * time.Second, want: deduplicationDuplicate},
+				{source: "b", id: "1", want: deduplicationDuplicate},
+			},
+		},
+		"after the window": {
+			dedup: `{"window":"1m0s","cacheSize":10}`,
+			requests: []request{
+				{source: "a", id: "1", want: deduplicationUnique},
+				{source: "a", id: "1", after: time.Minute, want: deduplicationUnique},
+				{source: "a", id: "1", after: 59 * time.Second, want: deduplicationDuplicate},
+			},
+		},
+		"evicted from the cache": {
+			dedup: `{"window":"1m0s","cacheSize":2}`,
+			requests: []request{
+				{source: "a", id: "1", want: deduplicationUnique},
+				{source: "a", id: "2", want: deduplicationUnique},
+				{source: "a", id: "3", want: deduplicationUnique},
+				{source: "a", id: "1", want: deduplicationUnique},
+				{source: "a", id: "3", want: deduplicationDuplicate},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			dedup := newDeduplicator()
+			dedup.now = func() time.Time { return now }
+
+			b := makeBroker("name", "ns")
+			if tc.dedup != "" {
+				b.Status.Annotations[eventing.BrokerIngressDeduplicationStatusAnnotationKey] = tc.dedup
+			}
+			for i, r := range tc.requests {
+				now = now.Add(r.after)
+				got, err := dedup.record(b, r.source, r.id)
+				if err != nil {
+					t.Fatalf("request %d: unexpected error: %v", i, err)
+				}
+				if got != r.want {
+					t.Errorf("request %d: result = %q, want %q", i, got, r.want)
+				}
+				if got == deduplicationUnique {
+					dedup.complete(b, r.source, r.id, true)
+				}
+			}
+		})
+	}
+}
+
+func TestDeduplicatorInFlight(t *testing.T) {
+	dedup := newDeduplicator()
+	b := makeBroker("name", "ns")
+	b.Status.Annotations[eventing.BrokerIngressDeduplicationStatusAnnotationKey] = `{"window":"1m0s","cacheSize":10}`
+
+	if got, _ := dedup.record(b, "source", "id"); got != deduplicationUnique {
+		t.Fatalf("result = %q, want %q", got, deduplicationUnique)
+	}
+	if got, _ := dedup.record(b, "source", "id"); got != deduplicationInFlight {
+		t.Errorf("expected the event being sent to be in flight, got %q", got)
+	}
+
+	// The event which failed to be sent can be retried.
+	dedup.complete(b, "source", "id", false)
+	if got, _ := dedup.record(b, "source", "id"); got != deduplicationUnique {
+		t.Errorf("expected the failed event to be accepted again, got %q", got)
+	}
+	dedup.complete(b, "source", "id", true)
+	if got, _ := dedup.record(b, "source", "id"); got != deduplicationDuplicate {
+		t.Errorf("expected the accepted event to be a duplicate, got %q", got)
+	}
+
+	dedup.forget(b)
+	if len(dedup.brokers) != 0 {
+		t.Error("expected the events of the broker to be forgotten")
+	}
+}
+
+func TestDeduplicatorInvalidAnnotation(t *testing.T) {
+	dedup := newDeduplicator()
+	b := makeBroker("name", "ns")
+	b.Status.Annotations[eventing.BrokerIngressDeduplicationStatusAnnotationKey] = `{"window":"forever"}`
+
+	if _, err := dedup.record(b, "source", "id"); err == nil {
+		t.Error("expected an error for the invalid annotation")
+	}
+}
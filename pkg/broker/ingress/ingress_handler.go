@@ -18,8 +18,13 @@ package ingress
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -48,6 +53,7 @@ import (
 	"knative.dev/eventing/pkg/eventingtls"
 	"knative.dev/eventing/pkg/eventtype"
 	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/kncloudevents/attributes"
 	"knative.dev/eventing/pkg/tracing"
 	"knative.dev/eventing/pkg/utils"
 )
@@ -67,12 +73,28 @@ type Handler struct {
 
 	EvenTypeHandler *eventtype.EventTypeAutoHandler
 
+	// EventTypeValidator validates the events sent to the Brokers enabling the validation
+	// against the attribute definitions of their EventTypes.
+	EventTypeValidator *eventtype.Validator
+
+	// SchedulerURL, when set, is the ingress the events scheduled for a later delivery are
+	// forwarded to, which stores them until they are due.
+	SchedulerURL *apis.URL
+
 	Logger *zap.Logger
 
 	eventDispatcher *kncloudevents.Dispatcher
 
 	tokenVerifier *auth.OIDCTokenVerifier
 
+	rateLimiter *rateLimiter
+
+	deduplicator *deduplicator
+
+	// scheduler holds the events with a delivery time until they are due, once started with
+	// StartScheduler.
+	scheduler *scheduler
+
 	withContext func(ctx context.Context) context.Context
 }
 
@@ -87,6 +109,9 @@ func NewHandler(logger *zap.Logger, reporter StatsReporter, defaulter client.Eve
 		TrustBundleConfigMapLister: trustBundleConfigMapLister,
 	}
 
+	limiter := newRateLimiter()
+	dedup := newDeduplicator()
+
 	brokerInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			broker, ok := obj.(*eventingv1.Broker)
@@ -110,7 +135,12 @@ func NewHandler(logger *zap.Logger, reporter StatsReporter, defaulter client.Eve
 		},
 		DeleteFunc: func(obj interface{}) {
 			broker, ok := obj.(*eventingv1.Broker)
-			if !ok || broker == nil || broker.Status.Address == nil {
+			if !ok || broker == nil {
+				return
+			}
+			limiter.forget(broker)
+			dedup.forget(broker)
+			if broker.Status.Address == nil {
 				return
 			}
 			kncloudevents.DeleteAddressableHandler(duckv1.Addressable{
@@ -127,6 +157,8 @@ func NewHandler(logger *zap.Logger, reporter StatsReporter, defaulter client.Eve
 		BrokerLister:    brokerInformer.Lister(),
 		eventDispatcher: kncloudevents.NewDispatcher(clientConfig, oidcTokenProvider),
 		tokenVerifier:   tokenVerifier,
+		rateLimiter:     limiter,
+		deduplicator:    dedup,
 		withContext:     withContext,
 	}, nil
 }
@@ -197,6 +229,13 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	ctx := h.withContext(request.Context())
+	brokerNamespace := nsBrokerName[1]
+	brokerName := nsBrokerName[2]
+
+	if isBatch(request.Header) {
+		h.serveBatch(ctx, writer, request, brokerNamespace, brokerName)
+		return
+	}
 
 	message := cehttp.NewMessageFromHttpRequest(request)
 	defer message.Finish(nil)
@@ -216,32 +255,156 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	brokerNamespace := nsBrokerName[1]
-	brokerName := nsBrokerName[2]
-	brokerNamespacedName := types.NamespacedName{
-		Name:      brokerName,
-		Namespace: brokerNamespace,
+	broker, subject, ok := h.getVerifiedBroker(ctx, writer, request, brokerName, brokerNamespace)
+	if !ok {
+		return
+	}
+
+	statusCode, err := h.handleEvent(ctx, utils.PassThroughHeaders(request.Header), event, broker, requestScheme(request), subject)
+
+	var rateLimited *rateLimitedError
+	if errors.As(err, &rateLimited) {
+		writer.Header().Set("Retry-After", rateLimited.retryAfterSeconds())
+	}
+	writer.WriteHeader(statusCode)
+	if err != nil {
+		if _, err := writer.Write([]byte(err.Error())); err != nil {
+			h.Logger.Warn("failed to write response", zap.Error(err))
+		}
+	}
+
+	// EventType auto-create feature handling
+	if h.EvenTypeHandler != nil {
+		h.EvenTypeHandler.AutoCreateEventType(ctx, event, toKReference(broker), broker.GetUID())
+	}
+}
+
+// BatchEventResult is the outcome of the ingestion of one of the events of a batch. The response
+// to a batch contains a result for every event, in the same order as the events of the batch.
+type BatchEventResult struct {
+	ID         string `json:"id,omitempty"`
+	Source     string `json:"source,omitempty"`
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error,omitempty"`
+}
+
+// serveBatch handles a request in the batched content mode. Every event is validated and sent to
+// the channel independently, and the response lists the status of each of them so that the
+// client can retry only the failed ones. The response status is 202 when all the events were
+// accepted and 207 otherwise.
+func (h *Handler) serveBatch(ctx context.Context, writer http.ResponseWriter, request *http.Request, brokerNamespace, brokerName string) {
+	events, err := binding.ToEvents(ctx, cehttp.NewMessageFromHttpRequest(request), request.Body)
+	if err != nil {
+		h.Logger.Warn("failed to extract events from batch request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	broker, subject, ok := h.getVerifiedBroker(ctx, writer, request, brokerName, brokerNamespace)
+	if !ok {
+		return
+	}
+
+	scheme := requestScheme(request)
+	_ = h.Reporter.ReportBatchSize(&ReportArgs{
+		ns:          brokerNamespace,
+		broker:      brokerName,
+		eventScheme: scheme,
+	}, len(events))
+
+	// The events are sent one after the other, so that the channel receives them in the order
+	// of the batch.
+	headers := utils.PassThroughHeaders(request.Header)
+	results := make([]BatchEventResult, len(events))
+	var rateLimited *rateLimitedError
+	for i := range events {
+		event := &events[i]
+		results[i] = BatchEventResult{ID: event.ID(), Source: event.Source()}
+		if err := event.Validate(); err != nil {
+			h.Logger.Warn("failed to validate event of the batch", zap.Error(err))
+			results[i].StatusCode = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].StatusCode, err = h.handleEvent(ctx, headers, event, broker, scheme, subject)
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+		var eventRateLimited *rateLimitedError
+		if errors.As(err, &eventRateLimited) && (rateLimited == nil || eventRateLimited.retryAfter > rateLimited.retryAfter) {
+			rateLimited = eventRateLimited
+		}
+	}
+
+	statusCode := http.StatusAccepted
+	for _, result := range results {
+		if result.StatusCode < 200 || result.StatusCode >= 300 {
+			statusCode = http.StatusMultiStatus
+			break
+		}
+	}
+
+	body, err := json.Marshal(results)
+	if err != nil {
+		h.Logger.Error("failed to marshal batch results", zap.Error(err))
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set(cehttp.ContentType, "application/json")
+	if rateLimited != nil {
+		writer.Header().Set("Retry-After", rateLimited.retryAfterSeconds())
+	}
+	writer.WriteHeader(statusCode)
+	if _, err := writer.Write(body); err != nil {
+		h.Logger.Warn("failed to write batch response", zap.Error(err))
+	}
+
+	// EventType auto-create feature handling, for the events which passed the validation
+	if h.EvenTypeHandler != nil {
+		for i, result := range results {
+			if result.Error == "" {
+				h.EvenTypeHandler.AutoCreateEventType(ctx, &events[i], toKReference(broker), broker.GetUID())
+			}
+		}
 	}
+}
 
+// getVerifiedBroker returns the Broker the request is sent to, after verifying the OIDC token of
+// the request when the authentication is enabled, along with the subject of the token. When false
+// is returned, the response has already been written.
+func (h *Handler) getVerifiedBroker(ctx context.Context, writer http.ResponseWriter, request *http.Request, brokerName, brokerNamespace string) (*eventingv1.Broker, string, bool) {
 	broker, err := h.getBroker(brokerName, brokerNamespace)
 	if err != nil {
 		h.Logger.Warn("Failed to retrieve broker", zap.Error(err))
 		writer.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, "", false
 	}
 
+	subject := ""
 	features := feature.FromContext(ctx)
 	if features.IsOIDCAuthentication() {
 		h.Logger.Debug("OIDC authentication is enabled")
 
-		err = h.tokenVerifier.VerifyJWTFromRequest(ctx, request, broker.Status.Address.Audience, writer)
+		idToken, err := h.tokenVerifier.VerifyJWTFromRequestForIDToken(ctx, request, broker.Status.Address.Audience, writer)
 		if err != nil {
 			h.Logger.Warn("Error when validating the JWT token in the request", zap.Error(err))
-			return
+			return nil, "", false
 		}
+		subject = idToken.Subject
 
 		h.Logger.Debug("Request contained a valid JWT. Continuing...")
 	}
+	return broker, subject, true
+}
+
+// handleEvent sends a single event to the channel of the Broker, records the metrics of the
+// ingestion and returns the resulting status code, along with the reason when the event is
+// rejected.
+func (h *Handler) handleEvent(ctx context.Context, headers http.Header, event *cloudevents.Event, broker *eventingv1.Broker, scheme, subject string) (int, error) {
+	brokerNamespacedName := types.NamespacedName{
+		Name:      broker.Name,
+		Namespace: broker.Namespace,
+	}
 
 	ctx, span := trace.StartSpan(ctx, tracing.BrokerMessagingDestination(brokerNamespacedName))
 	defer span.End()
@@ -257,29 +420,69 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	reporterArgs := &ReportArgs{
-		ns:        brokerNamespace,
-		broker:    brokerName,
-		eventType: event.Type(),
+		ns:          broker.Namespace,
+		broker:      broker.Name,
+		eventType:   event.Type(),
+		eventScheme: scheme,
 	}
 
-	if request.TLS != nil {
-		reporterArgs.eventScheme = "https"
-	} else {
-		reporterArgs.eventScheme = "http"
+	if err := h.rateLimiter.allow(broker, event.Source(), subject); err != nil {
+		var rateLimited *rateLimitedError
+		if errors.As(err, &rateLimited) {
+			h.Logger.Debug("rate limit exceeded", zap.String("event.id", event.ID()), zap.Error(err))
+			_ = h.Reporter.ReportRateLimited(reporterArgs, string(rateLimited.key))
+			_ = h.Reporter.ReportEventCount(reporterArgs, http.StatusTooManyRequests)
+			return http.StatusTooManyRequests, err
+		}
+		h.Logger.Warn("failed to apply the rate limit of the broker", zap.Error(err))
+	}
+
+	dedupResult, err := h.deduplicator.record(broker, event.Source(), event.ID())
+	if err != nil {
+		h.Logger.Warn("failed to apply the deduplication of the broker", zap.Error(err))
+	}
+	if dedupResult != "" {
+		_ = h.Reporter.ReportDeduplication(reporterArgs, dedupResult)
+	}
+	if dedupResult == deduplicationDuplicate {
+		// The event was already accepted, acknowledge it without sending it again.
+		h.Logger.Debug("dropping duplicate event", zap.String("event.id", event.ID()), zap.String("event.source", event.Source()))
+		_ = h.Reporter.ReportEventCount(reporterArgs, http.StatusAccepted)
+		return http.StatusAccepted, nil
+	}
+	if dedupResult == deduplicationInFlight {
+		// The event is being sent, let the sender retry it until it is accepted or fails.
+		h.Logger.Debug("refusing event being sent", zap.String("event.id", event.ID()), zap.String("event.source", event.Source()))
+		_ = h.Reporter.ReportEventCount(reporterArgs, http.StatusTooManyRequests)
+		return http.StatusTooManyRequests, nil
 	}
 
-	statusCode, dispatchTime := h.receive(ctx, utils.PassThroughHeaders(request.Header), event, broker)
+	statusCode, dispatchTime, err := h.receive(ctx, headers, event, broker, reporterArgs)
 	if dispatchTime > kncloudevents.NoDuration {
 		_ = h.Reporter.ReportEventDispatchTime(reporterArgs, statusCode, dispatchTime)
 	}
 	_ = h.Reporter.ReportEventCount(reporterArgs, statusCode)
 
-	writer.WriteHeader(statusCode)
+	if dedupResult == deduplicationUnique {
+		// The event is dropped as a duplicate only once it was accepted, the sender can retry
+		// it otherwise.
+		h.deduplicator.complete(broker, event.Source(), event.ID(), statusCode >= 200 && statusCode < 300)
+	}
 
-	// EventType auto-create feature handling
-	if h.EvenTypeHandler != nil {
-		h.EvenTypeHandler.AutoCreateEventType(ctx, event, toKReference(broker), broker.GetUID())
+	return statusCode, err
+}
+
+func requestScheme(request *http.Request) string {
+	if request.TLS != nil {
+		return "https"
 	}
+	return "http"
+}
+
+// isBatch returns whether the request uses the batched content mode.
+func isBatch(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get(cehttp.ContentType))
+	return err == nil && mediaType == cloudevents.ApplicationCloudEventsBatchJSON
 }
 
 func toKReference(broker *eventingv1.Broker) *duckv1.KReference {
@@ -299,7 +502,7 @@ func toKReference(broker *eventingv1.Broker) *duckv1.KReference {
 	return kref
 }
 
-func (h *Handler) receive(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerObj *eventingv1.Broker) (int, time.Duration) {
+func (h *Handler) receive(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerObj *eventingv1.Broker, args *ReportArgs) (int, time.Duration, error) {
 	// Setting the extension as a string as the CloudEvents sdk does not support non-string extensions.
 	event.SetExtension(broker.EventArrivalTime, cloudevents.Timestamp{Time: time.Now()})
 	if h.Defaulter != nil {
@@ -309,28 +512,95 @@ func (h *Handler) receive(ctx context.Context, headers http.Header, event *cloud
 
 	if ttl, err := broker.GetTTL(event.Context); err != nil || ttl <= 0 {
 		h.Logger.Debug("dropping event based on TTL status.", zap.Int32("TTL", ttl), zap.String("event.id", event.ID()), zap.Error(err))
-		return http.StatusBadRequest, kncloudevents.NoDuration
+		return http.StatusBadRequest, kncloudevents.NoDuration, nil
+	}
+
+	if mode, err := h.validateEvent(event, brokerObj, args); err != nil {
+		h.Logger.Debug("event doesn't conform to its EventType", zap.String("event.id", event.ID()), zap.Error(err))
+		if mode == eventingv1.BrokerEventValidationDeadLetter && brokerObj.Status.DeadLetterSinkURI != nil {
+			return h.deadLetter(ctx, headers, event, brokerObj, err)
+		}
+		return http.StatusBadRequest, kncloudevents.NoDuration, err
 	}
 
 	channelAddress, err := h.getChannelAddress(brokerObj)
 	if err != nil {
 		h.Logger.Warn("could not get channel address from broker", zap.Error(err))
-		return http.StatusBadRequest, kncloudevents.NoDuration
+		return http.StatusBadRequest, kncloudevents.NoDuration, nil
 	}
 
-	opts := []kncloudevents.SendOption{
-		kncloudevents.WithHeader(headers),
-		kncloudevents.WithOIDCAuthentication(&types.NamespacedName{
-			Name:      "mt-broker-ingress-oidc",
-			Namespace: system.Namespace(),
-		}),
+	deliverAt, scheduled, err := h.deliveryTime(event, brokerObj)
+	if err != nil {
+		h.Logger.Debug("invalid event scheduling", zap.String("event.id", event.ID()), zap.Error(err))
+		return http.StatusBadRequest, kncloudevents.NoDuration, err
+	}
+	if scheduled {
+		return h.schedule(ctx, headers, event, brokerObj, deliverAt)
 	}
 
-	dispatchInfo, err := h.eventDispatcher.SendEvent(ctx, *event, *channelAddress, opts...)
+	dispatchInfo, err := h.eventDispatcher.SendEvent(ctx, *event, *channelAddress, sendOptions(headers)...)
 	if err != nil {
 		h.Logger.Error("failed to dispatch event", zap.Error(err))
-		return http.StatusInternalServerError, kncloudevents.NoDuration
+		return http.StatusInternalServerError, kncloudevents.NoDuration, nil
+	}
+
+	return dispatchInfo.ResponseCode, dispatchInfo.Duration, nil
+}
+
+// validateEvent validates the event against its EventType when the Broker enables the validation,
+// and records the result in the report arguments. It returns the validation mode of the Broker,
+// along with the violation when the event doesn't conform to its EventType.
+func (h *Handler) validateEvent(event *cloudevents.Event, brokerObj *eventingv1.Broker, args *ReportArgs) (string, error) {
+	args.validation = validationSkipped
+	mode := brokerObj.GetAnnotations()[eventingv1.BrokerEventValidationAnnotationKey]
+	if mode == "" || h.EventTypeValidator == nil {
+		return mode, nil
+	}
+
+	validated, err := h.EventTypeValidator.Validate(*event, brokerObj.Namespace, brokerObj.Name)
+	if !validated {
+		return mode, nil
+	}
+	if err != nil {
+		args.validation = validationFailed
+		return mode, err
+	}
+	args.validation = validationPassed
+	return mode, nil
+}
+
+// deadLetter sends the event which doesn't conform to its EventType to the dead letter sink of
+// the Broker, with the violation as the error data.
+func (h *Handler) deadLetter(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerObj *eventingv1.Broker, violation error) (int, time.Duration, error) {
+	deadLetterSink := duckv1.Addressable{
+		URL:      brokerObj.Status.DeadLetterSinkURI,
+		CACerts:  brokerObj.Status.DeadLetterSinkCACerts,
+		Audience: brokerObj.Status.DeadLetterSinkAudience,
+	}
+
+	var destination url.URL
+	if brokerObj.Status.Address != nil && brokerObj.Status.Address.URL != nil {
+		destination = *brokerObj.Status.Address.URL.URL()
 	}
+	errorData := base64.StdEncoding.EncodeToString([]byte(violation.Error()))
+	opts := append(sendOptions(headers),
+		kncloudevents.WithTransformers(attributes.KnativeErrorTransformers(destination, http.StatusBadRequest, errorData)...))
 
-	return dispatchInfo.ResponseCode, dispatchInfo.Duration
+	dispatchInfo, err := h.eventDispatcher.SendEvent(ctx, *event, deadLetterSink, opts...)
+	if err != nil {
+		h.Logger.Error("failed to dispatch event to the dead letter sink", zap.Error(err))
+		return http.StatusInternalServerError, kncloudevents.NoDuration, nil
+	}
+
+	return dispatchInfo.ResponseCode, dispatchInfo.Duration, nil
+}
+
+func sendOptions(headers http.Header) []kncloudevents.SendOption {
+	return []kncloudevents.SendOption{
+		kncloudevents.WithHeader(headers),
+		kncloudevents.WithOIDCAuthentication(&types.NamespacedName{
+			Name:      "mt-broker-ingress-oidc",
+			Namespace: system.Namespace(),
+		}),
+	}
 }
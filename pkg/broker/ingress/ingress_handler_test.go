@@ -19,13 +19,17 @@ package ingress
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	nethttp "net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/client"
 	"github.com/cloudevents/sdk-go/v2/event"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
@@ -34,14 +38,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/fake"
 
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	reconcilertesting "knative.dev/pkg/reconciler/testing"
 
 	"knative.dev/eventing/pkg/apis/eventing"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
 	"knative.dev/eventing/pkg/auth"
 	"knative.dev/eventing/pkg/broker"
+	fakeeventingclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	"knative.dev/eventing/pkg/client/informers/externalversions"
+	"knative.dev/eventing/pkg/eventtype"
 
 	brokerinformerfake "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker/fake"
 
@@ -330,6 +339,486 @@ func TestHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestHandler_ServeHTTPBatch(t *testing.T) {
+	t.Parallel()
+
+	logger := zap.NewNop()
+
+	tt := []struct {
+		name            string
+		body            io.Reader
+		contentType     string
+		handler         nethttp.Handler
+		statusCode      int
+		expectedResults []BatchEventResult
+		reporter        *mockReporter
+	}{
+		{
+			name:        "all events accepted",
+			body:        getBatch(makeEvent("1", "source"), makeEvent("2", "source")),
+			contentType: event.ApplicationCloudEventsBatchJSON,
+			handler:     handler(),
+			statusCode:  nethttp.StatusAccepted,
+			expectedResults: []BatchEventResult{
+				{ID: "1", Source: "source", StatusCode: senderResponseStatusCode},
+				{ID: "2", Source: "source", StatusCode: senderResponseStatusCode},
+			},
+			reporter: &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true, BatchSize: 2},
+		},
+		{
+			name: "invalid event",
+			body: bytes.NewBufferString(`[
+				{"specversion": "1.0", "id": "1", "source": "source", "type": "type"},
+				{"specversion": "1.0", "id": "2", "type": "type"}
+			]`),
+			contentType: event.ApplicationCloudEventsBatchJSON + "; charset=utf-8",
+			handler:     handler(),
+			statusCode:  nethttp.StatusMultiStatus,
+			expectedResults: []BatchEventResult{
+				{ID: "1", Source: "source", StatusCode: senderResponseStatusCode},
+				{ID: "2", StatusCode: nethttp.StatusBadRequest, Error: "source: REQUIRED\n"},
+			},
+			reporter: &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true, BatchSize: 2},
+		},
+		{
+			name:        "channel failure",
+			body:        getBatch(makeEvent("1", "source")),
+			contentType: event.ApplicationCloudEventsBatchJSON,
+			handler: nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+				writer.WriteHeader(nethttp.StatusServiceUnavailable)
+			}),
+			statusCode: nethttp.StatusMultiStatus,
+			expectedResults: []BatchEventResult{
+				{ID: "1", Source: "source", StatusCode: nethttp.StatusInternalServerError},
+			},
+			reporter: &mockReporter{StatusCode: nethttp.StatusInternalServerError, BatchSize: 1},
+		},
+		{
+			name:        "malformed batch",
+			body:        getValidEvent(),
+			contentType: event.ApplicationCloudEventsBatchJSON,
+			handler:     handler(),
+			statusCode:  nethttp.StatusBadRequest,
+			reporter:    &mockReporter{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+			s := httptest.NewServer(tc.handler)
+			defer s.Close()
+
+			b := makeBroker("name", "ns")
+			b.Status.Annotations = map[string]string{
+				eventing.BrokerChannelAddressStatusAnnotationKey: s.URL,
+			}
+			brokerinformerfake.Get(ctx).Informer().GetStore().Add(b)
+
+			h, err := NewHandler(logger,
+				&mockReporter{},
+				broker.TTLDefaulter(logger, 100),
+				brokerinformerfake.Get(ctx),
+				auth.NewOIDCTokenVerifier(ctx),
+				auth.NewOIDCTokenProvider(ctx),
+				configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+				func(ctx context.Context) context.Context {
+					return ctx
+				})
+			if err != nil {
+				t.Fatal("Unable to create receiver:", err)
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", tc.body)
+			request.Header.Set(cehttp.ContentType, tc.contentType)
+			h.ServeHTTP(recorder, request)
+
+			result := recorder.Result()
+			if result.StatusCode != tc.statusCode {
+				t.Errorf("expected status code %d got %d", tc.statusCode, result.StatusCode)
+			}
+			if tc.expectedResults != nil {
+				var results []BatchEventResult
+				if err := json.NewDecoder(result.Body).Decode(&results); err != nil {
+					t.Fatal("failed to decode the batch results:", err)
+				}
+				if diff := cmp.Diff(tc.expectedResults, results); diff != "" {
+					t.Error("unexpected batch results (-want +got)", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.reporter, h.Reporter); diff != "" {
+				t.Errorf("expected reporter state %+v got %+v - diff %s", tc.reporter, h.Reporter, diff)
+			}
+		})
+	}
+}
+
+func TestHandler_ServeHTTPEventValidation(t *testing.T) {
+	t.Parallel()
+
+	logger := zap.NewNop()
+
+	tt := []struct {
+		name               string
+		mode               string
+		deadLetterSink     bool
+		event              event.Event
+		statusCode         int
+		expectedBody       string
+		expectedValidation string
+		channelEvents      int
+		deadLetterEvents   int
+	}{
+		{
+			name:               "validation disabled",
+			event:              makeEvent("1", "source"),
+			statusCode:         nethttp.StatusAccepted,
+			expectedValidation: validationSkipped,
+			channelEvents:      1,
+		},
+		{
+			name:               "no EventType",
+			mode:               eventingv1.BrokerEventValidationReject,
+			event:              makeEventOfType("other.type"),
+			statusCode:         nethttp.StatusAccepted,
+			expectedValidation: validationSkipped,
+			channelEvents:      1,
+		},
+		{
+			name:               "conforming event",
+			mode:               eventingv1.BrokerEventValidationReject,
+			event:              makeEvent("1", "http://source.example.com"),
+			statusCode:         nethttp.StatusAccepted,
+			expectedValidation: validationPassed,
+			channelEvents:      1,
+		},
+		{
+			name:               "rejected event",
+			mode:               eventingv1.BrokerEventValidationReject,
+			event:              makeEvent("1", "source"),
+			statusCode:         nethttp.StatusBadRequest,
+			expectedBody:       `event doesn't conform to EventType et: value "source" of attribute "source" doesn't match "http://source.example.com"`,
+			expectedValidation: validationFailed,
+		},
+		{
+			name:               "dead-lettered event",
+			mode:               eventingv1.BrokerEventValidationDeadLetter,
+			deadLetterSink:     true,
+			event:              makeEvent("1", "source"),
+			statusCode:         nethttp.StatusAccepted,
+			expectedValidation: validationFailed,
+			deadLetterEvents:   1,
+		},
+		{
+			name:               "dead-lettered event without dead letter sink",
+			mode:               eventingv1.BrokerEventValidationDeadLetter,
+			event:              makeEvent("1", "source"),
+			statusCode:         nethttp.StatusBadRequest,
+			expectedBody:       `event doesn't conform to EventType et: value "source" of attribute "source" doesn't match "http://source.example.com"`,
+			expectedValidation: validationFailed,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := reconcilertesting.SetupFakeContext(t)
+
+			channel := &eventRecorder{}
+			channelServer := httptest.NewServer(channel)
+			defer channelServer.Close()
+			deadLetterSink := &eventRecorder{}
+			deadLetterServer := httptest.NewServer(deadLetterSink)
+			defer deadLetterServer.Close()
+
+			b := makeBroker("name", "ns")
+			b.Status.Annotations = map[string]string{
+				eventing.BrokerChannelAddressStatusAnnotationKey: channelServer.URL,
+			}
+			if tc.mode != "" {
+				b.Annotations = map[string]string{eventingv1.BrokerEventValidationAnnotationKey: tc.mode}
+			}
+			if tc.deadLetterSink {
+				b.Status.DeadLetterSinkURI, _ = apis.ParseURL(deadLetterServer.URL)
+			}
+			brokerinformerfake.Get(ctx).Informer().GetStore().Add(b)
+
+			informer := externalversions.NewSharedInformerFactory(fakeeventingclientset.NewSimpleClientset(), 0).Eventing().V1beta2().EventTypes()
+			validator, err := eventtype.NewValidator(informer)
+			if err != nil {
+				t.Fatal("Unable to create validator:", err)
+			}
+			informer.Informer().GetIndexer().Add(&eventingv1beta2.EventType{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "et", UID: "et"},
+				Spec: eventingv1beta2.EventTypeSpec{
+					Reference: &duckv1.KReference{APIVersion: "eventing.knative.dev/v1", Kind: "Broker", Name: "name"},
+					Type:      "type",
+					Source:    apis.HTTP("source.example.com"),
+				},
+			})
+
+			reporter := &validationReporter{}
+			h, err := NewHandler(logger,
+				reporter,
+				broker.TTLDefaulter(logger, 100),
+				brokerinformerfake.Get(ctx),
+				auth.NewOIDCTokenVerifier(ctx),
+				auth.NewOIDCTokenProvider(ctx),
+				configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+				func(ctx context.Context) context.Context {
+					return ctx
+				})
+			if err != nil {
+				t.Fatal("Unable to create receiver:", err)
+			}
+			h.EventTypeValidator = validator
+
+			body, _ := tc.event.MarshalJSON()
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", bytes.NewBuffer(body))
+			request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+			h.ServeHTTP(recorder, request)
+
+			result := recorder.Result()
+			if result.StatusCode != tc.statusCode {
+				t.Errorf("expected status code %d got %d", tc.statusCode, result.StatusCode)
+			}
+			if got := recorder.Body.String(); got != tc.expectedBody {
+				t.Errorf("expected body %q got %q", tc.expectedBody, got)
+			}
+			if reporter.Validation != tc.expectedValidation {
+				t.Errorf("expected validation %q got %q", tc.expectedValidation, reporter.Validation)
+			}
+			if len(channel.events) != tc.channelEvents {
+				t.Errorf("expected %d events sent to the channel got %d", tc.channelEvents, len(channel.events))
+			}
+			if len(deadLetterSink.events) != tc.deadLetterEvents {
+				t.Fatalf("expected %d events sent to the dead letter sink got %d", tc.deadLetterEvents, len(deadLetterSink.events))
+			}
+			for _, e := range deadLetterSink.events {
+				if code := e.Extensions()["knativeerrorcode"]; code != "400" {
+					t.Errorf("expected the knativeerrorcode extension 400 got %v", code)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ServeHTTPRateLimit(t *testing.T) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+	logger := zap.NewNop()
+
+	s := httptest.NewServer(handler())
+	defer s.Close()
+
+	b := makeBroker("name", "ns")
+	b.Status.Annotations = map[string]string{
+		eventing.BrokerChannelAddressStatusAnnotationKey:   s.URL,
+		eventing.BrokerIngressRateLimitStatusAnnotationKey: `{"key":"source","eventsPerSecond":0.01,"burst":1}`,
+	}
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(b)
+
+	reporter := &mockReporter{}
+	h, err := NewHandler(logger,
+		reporter,
+		broker.TTLDefaulter(logger, 100),
+		brokerinformerfake.Get(ctx),
+		auth.NewOIDCTokenVerifier(ctx),
+		auth.NewOIDCTokenProvider(ctx),
+		configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+		func(ctx context.Context) context.Context {
+			return ctx
+		})
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+
+	send := func(body io.Reader, contentType string) *nethttp.Response {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", body)
+		request.Header.Set(cehttp.ContentType, contentType)
+		h.ServeHTTP(recorder, request)
+		return recorder.Result()
+	}
+
+	if result := send(getValidEvent(), event.ApplicationCloudEventsJSON); result.StatusCode != nethttp.StatusAccepted {
+		t.Fatalf("expected status code %d got %d", nethttp.StatusAccepted, result.StatusCode)
+	}
+
+	result := send(getValidEvent(), event.ApplicationCloudEventsJSON)
+	if result.StatusCode != nethttp.StatusTooManyRequests {
+		t.Errorf("expected status code %d got %d", nethttp.StatusTooManyRequests, result.StatusCode)
+	}
+	if retryAfter := result.Header.Get("Retry-After"); retryAfter != "100" {
+		t.Errorf("expected Retry-After 100 got %q", retryAfter)
+	}
+
+	// The limit applies to each source independently.
+	result = send(getBatch(makeEvent("1", "other"), makeEvent("2", "other")), event.ApplicationCloudEventsBatchJSON)
+	if result.StatusCode != nethttp.StatusMultiStatus {
+		t.Errorf("expected status code %d got %d", nethttp.StatusMultiStatus, result.StatusCode)
+	}
+	if retryAfter := result.Header.Get("Retry-After"); retryAfter != "100" {
+		t.Errorf("expected Retry-After 100 got %q", retryAfter)
+	}
+	var results []BatchEventResult
+	if err := json.NewDecoder(result.Body).Decode(&results); err != nil {
+		t.Fatal("failed to decode the batch results:", err)
+	}
+	if len(results) != 2 || results[0].StatusCode != nethttp.StatusAccepted || results[1].StatusCode != nethttp.StatusTooManyRequests {
+		t.Errorf("unexpected batch results %+v", results)
+	}
+
+	if reporter.RateLimited != 2 {
+		t.Errorf("expected 2 rate limited events reported got %d", reporter.RateLimited)
+	}
+}
+
+func TestHandler_ServeHTTPDeduplication(t *testing.T) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+	logger := zap.NewNop()
+
+	var received atomic.Int32
+	s := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, _ *nethttp.Request) {
+		received.Add(1)
+		writer.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer s.Close()
+
+	b := makeBroker("name", "ns")
+	b.Status.Annotations = map[string]string{
+		eventing.BrokerChannelAddressStatusAnnotationKey:       s.URL,
+		eventing.BrokerIngressDeduplicationStatusAnnotationKey: `{"window":"1h0m0s","cacheSize":100}`,
+	}
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(b)
+
+	reporter := &lockedReporter{}
+	h, err := NewHandler(logger,
+		reporter,
+		broker.TTLDefaulter(logger, 100),
+		brokerinformerfake.Get(ctx),
+		auth.NewOIDCTokenVerifier(ctx),
+		auth.NewOIDCTokenProvider(ctx),
+		configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+		func(ctx context.Context) context.Context {
+			return ctx
+		})
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+
+	const senders = 20
+	var wg sync.WaitGroup
+	statusCodes := make([]int, senders)
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", getValidEvent())
+			request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+			h.ServeHTTP(recorder, request)
+			statusCodes[i] = recorder.Code
+		}(i)
+	}
+	wg.Wait()
+
+	// The senders are told to retry the event while it is being sent.
+	accepted := 0
+	for i, statusCode := range statusCodes {
+		switch statusCode {
+		case nethttp.StatusAccepted:
+			accepted++
+		case nethttp.StatusTooManyRequests:
+		default:
+			t.Errorf("sender %d: expected status code %d or %d got %d", i, nethttp.StatusAccepted, nethttp.StatusTooManyRequests, statusCode)
+		}
+	}
+	if got := received.Load(); got != 1 {
+		t.Errorf("expected the event to be sent to the channel once, got %d", got)
+	}
+	if reporter.Duplicates+reporter.InFlight != senders-1 {
+		t.Errorf("expected %d duplicates or events in flight reported got %d and %d", senders-1, reporter.Duplicates, reporter.InFlight)
+	}
+	if accepted != senders-reporter.InFlight {
+		t.Errorf("expected %d senders to be accepted got %d", senders-reporter.InFlight, accepted)
+	}
+
+	// The event retried once it was accepted is dropped.
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", getValidEvent())
+	request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+	h.ServeHTTP(recorder, request)
+	if recorder.Code != nethttp.StatusAccepted {
+		t.Errorf("expected status code %d got %d", nethttp.StatusAccepted, recorder.Code)
+	}
+	if got := received.Load(); got != 1 {
+		t.Errorf("expected the retried event not to be sent to the channel, got %d", got)
+	}
+
+	// Another event with the same source is sent to the channel.
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(nethttp.MethodPost, "/ns/name", getBatch(makeEvent("other", "source")))
+	request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsBatchJSON)
+	h.ServeHTTP(recorder, request)
+	if recorder.Code != nethttp.StatusAccepted {
+		t.Errorf("expected status code %d got %d", nethttp.StatusAccepted, recorder.Code)
+	}
+	if got := received.Load(); got != 2 {
+		t.Errorf("expected the other event to be sent to the channel, got %d events", got)
+	}
+}
+
+// lockedReporter is a mockReporter safe for concurrent use.
+type lockedReporter struct {
+	mu sync.Mutex
+	mockReporter
+}
+
+func (r *lockedReporter) ReportEventCount(args *ReportArgs, responseCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mockReporter.ReportEventCount(args, responseCode)
+}
+
+func (r *lockedReporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mockReporter.ReportEventDispatchTime(args, responseCode, d)
+}
+
+func (r *lockedReporter) ReportDeduplication(args *ReportArgs, result string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mockReporter.ReportDeduplication(args, result)
+}
+
+type validationReporter struct {
+	mockReporter
+	Validation string
+}
+
+func (r *validationReporter) ReportEventCount(args *ReportArgs, responseCode int) error {
+	r.Validation = args.validation
+	return r.mockReporter.ReportEventCount(args, responseCode)
+}
+
+// eventRecorder records the events it receives.
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) ServeHTTP(writer nethttp.ResponseWriter, request *nethttp.Request) {
+	e, err := binding.ToEvent(request.Context(), cehttp.NewMessageFromHttpRequest(request))
+	if err != nil {
+		writer.WriteHeader(nethttp.StatusBadRequest)
+		return
+	}
+	r.events = append(r.events, *e)
+	writer.WriteHeader(nethttp.StatusAccepted)
+}
+
 type svc struct {
 	receivedHeaders nethttp.Header
 }
@@ -348,6 +837,10 @@ func handler() nethttp.Handler {
 type mockReporter struct {
 	StatusCode                int
 	EventDispatchTimeReported bool
+	BatchSize                 int
+	RateLimited               int
+	Duplicates                int
+	InFlight                  int
 }
 
 func (r *mockReporter) ReportEventCount(_ *ReportArgs, responseCode int) error {
@@ -360,6 +853,26 @@ func (r *mockReporter) ReportEventDispatchTime(_ *ReportArgs, _ int, _ time.Dura
 	return nil
 }
 
+func (r *mockReporter) ReportBatchSize(_ *ReportArgs, size int) error {
+	r.BatchSize = size
+	return nil
+}
+
+func (r *mockReporter) ReportRateLimited(_ *ReportArgs, _ string) error {
+	r.RateLimited++
+	return nil
+}
+
+func (r *mockReporter) ReportDeduplication(_ *ReportArgs, result string) error {
+	switch result {
+	case deduplicationDuplicate:
+		r.Duplicates++
+	case deduplicationInFlight:
+		r.InFlight++
+	}
+	return nil
+}
+
 func getValidEvent() io.Reader {
 	e := event.New()
 	e.SetType("type")
@@ -369,6 +882,25 @@ func getValidEvent() io.Reader {
 	return bytes.NewBuffer(b)
 }
 
+func makeEvent(id, source string) event.Event {
+	e := event.New()
+	e.SetType("type")
+	e.SetSource(source)
+	e.SetID(id)
+	return e
+}
+
+func makeEventOfType(eventType string) event.Event {
+	e := makeEvent("1", "source")
+	e.SetType(eventType)
+	return e
+}
+
+func getBatch(events ...event.Event) io.Reader {
+	b, _ := json.Marshal(events)
+	return bytes.NewBuffer(b)
+}
+
 func getInvalidEvent() io.Reader {
 	e := event.New()
 	e.SetType("type")
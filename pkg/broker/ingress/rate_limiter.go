@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/broker"
+)
+
+// limiterPruneInterval is the minimum interval between the removal of the idle limiters.
+const limiterPruneInterval = time.Minute
+
+// rateLimitedError is returned for the events rejected by the rate limiter of the Broker.
+type rateLimitedError struct {
+	key        broker.RateLimitKey
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit of the %s exceeded, retry after %s", e.key, e.retryAfter)
+}
+
+// retryAfterSeconds returns the value of the Retry-After header of the response.
+func (e *rateLimitedError) retryAfterSeconds() string {
+	return strconv.Itoa(int(math.Max(1, math.Ceil(e.retryAfter.Seconds()))))
+}
+
+// rateLimiter applies the token bucket rate limits set through the ingress rate limit status
+// annotation of the Brokers.
+type rateLimiter struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	brokers map[types.NamespacedName]*brokerLimiter
+}
+
+// brokerLimiter holds the limiters of a Broker, one for each key its events are limited by.
+type brokerLimiter struct {
+	// annotation is the value of the status annotation the limiters were created from.
+	annotation string
+	rateLimit  broker.RateLimit
+	limiters   map[string]*rate.Limiter
+	lastPrune  time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		now:     time.Now,
+		brokers: make(map[types.NamespacedName]*brokerLimiter),
+	}
+}
+
+// allow returns nil when the event with the given source, sent with a token of the given OIDC
+// subject, is allowed by the rate limit of the Broker, and a rateLimitedError otherwise.
+func (r *rateLimiter) allow(b *eventingv1.Broker, source, subject string) error {
+	name := types.NamespacedName{Namespace: b.Namespace, Name: b.Name}
+	annotation, ok := b.Status.Annotations[eventing.BrokerIngressRateLimitStatusAnnotationKey]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !ok {
+		delete(r.brokers, name)
+		return nil
+	}
+
+	bl, ok := r.brokers[name]
+	if !ok || bl.annotation != annotation {
+		// The limits changed, start over with the new ones.
+		bl = &brokerLimiter{annotation: annotation, limiters: make(map[string]*rate.Limiter)}
+		if err := json.Unmarshal([]byte(annotation), &bl.rateLimit); err != nil {
+			return fmt.Errorf("failed to parse the ingress rate limit of the broker: %w", err)
+		}
+		r.brokers[name] = bl
+	}
+
+	key := ""
+	switch bl.rateLimit.Key {
+	case broker.RateLimitKeySource:
+		key = source
+	case broker.RateLimitKeySubject:
+		key = subject
+	}
+
+	now := r.now()
+	bl.prune(now)
+
+	limiter, ok := bl.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(bl.rateLimit.EventsPerSecond), bl.rateLimit.Burst)
+		bl.limiters[key] = limiter
+	}
+
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return &rateLimitedError{key: bl.rateLimit.Key, retryAfter: time.Second}
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return &rateLimitedError{key: bl.rateLimit.Key, retryAfter: delay}
+	}
+	return nil
+}
+
+// forget removes the limiters of the deleted Broker.
+func (r *rateLimiter) forget(b *eventingv1.Broker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.brokers, types.NamespacedName{Namespace: b.Namespace, Name: b.Name})
+}
+
+// prune removes the limiters whose bucket is full, as they behave like new ones. This bounds the
+// number of limiters to the number of keys seen recently.
+func (bl *brokerLimiter) prune(now time.Time) {
+	if now.Sub(bl.lastPrune) < limiterPruneInterval {
+		return
+	}
+	bl.lastPrune = now
+	for key, limiter := range bl.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(bl.limiters, key)
+		}
+	}
+}
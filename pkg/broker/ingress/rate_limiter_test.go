@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+func TestRateLimiter(t *testing.T) {
+	type request struct {
+		source      string
+		subject     string
+		after       time.Duration
+		wantLimited bool
+		retryAfter  string
+	}
+	tests := map[string]struct {
+		rateLimit string
+		requests  []request
+	}{
+		"no rate limit": {
+			requests: []request{{}, {}, {}},
+		},
+		"broker": {
+			rateLimit: `{"key":"broker","eventsPerSecond":1,"burst":2}`,
+			requests: []request{
+				{source: "a"},
+				{source: "b"},
+				{source: "c", wantLimited: true, retryAfter: "1"},
+				{source: "c", after: time.Second},
+				{source: "c", wantLimited: true, retryAfter: "1"},
+			},
+		},
+		"source": {
+			rateLimit: `{"key":"source","eventsPerSecond":0.1,"burst":1}`,
+			requests: []request{
+				{source: "a"},
+				{source: "b"},
+				{source: "a", wantLimited: true, retryAfter: "10"},
+				{source: "a", after: 5 * time.Second, wantLimited: true, retryAfter: "5"},
+				{source: "a", after: 5 * time.Second},
+			},
+		},
+		"subject": {
+			rateLimit: `{"key":"subject","eventsPerSecond":1,"burst":1}`,
+			requests: []request{
+				{source: "a", subject: "system:serviceaccount:ns:a"},
+				{source: "a", subject: "system:serviceaccount:ns:b"},
+				{source: "b", subject: "system:serviceaccount:ns:a", wantLimited: true, retryAfter: "1"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			limiter := newRateLimiter()
+			limiter.now = func() time.Time { return now }
+
+			b := makeBroker("name", "ns")
+			if tc.rateLimit != "" {
+				b.Status.Annotations[eventing.BrokerIngressRateLimitStatusAnnotationKey] = tc.rateLimit
+			}
+			for i, r := range tc.requests {
+				now = now.Add(r.after)
+				err := limiter.allow(b, r.source, r.subject)
+				var rateLimited *rateLimitedError
+				if limited := errors.As(err, &rateLimited); limited != r.wantLimited {
+					t.Fatalf("request %d: limited = %v, want %v (error %v)", i, limited, r.wantLimited, err)
+				}
+				if rateLimited != nil && rateLimited.retryAfterSeconds() != r.retryAfter {
+					t.Errorf("request %d: Retry-After = %s, want %s", i, rateLimited.retryAfterSeconds(), r.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterChangedLimit(t *testing.T) {
+	limiter := newRateLimiter()
+	b := makeBroker("name", "ns")
+	b.Status.Annotations[eventing.BrokerIngressRateLimitStatusAnnotationKey] = `{"key":"broker","eventsPerSecond":1,"burst":1}`
+
+	if err := limiter.allow(b, "source", ""); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := limiter.allow(b, "source", ""); err == nil {
+		t.Fatal("expected the event to be rate limited")
+	}
+
+	b.Status.Annotations[eventing.BrokerIngressRateLimitStatusAnnotationKey] = `{"key":"broker","eventsPerSecond":1,"burst":2}`
+	if err := limiter.allow(b, "source", ""); err != nil {
+		t.Fatal("expected the new limit to apply, got:", err)
+	}
+
+	limiter.forget(b)
+	if len(limiter.brokers) != 0 {
+		t.Error("expected the limiters of the broker to be removed")
+	}
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter()
+	limiter.now = func() time.Time { return now }
+
+	b := makeBroker("name", "ns")
+	b.Status.Annotations[eventing.BrokerIngressRateLimitStatusAnnotationKey] = `{"key":"source","eventsPerSecond":1,"burst":1}`
+	for _, source := range []string{"a", "b", "c"} {
+		if err := limiter.allow(b, source, ""); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	now = now.Add(limiterPruneInterval)
+	if err := limiter.allow(b, "d", ""); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	bl := limiter.brokers[brokerKey(b)]
+	if len(bl.limiters) != 1 {
+		t.Errorf("expected only the limiter of the last source to be kept, got %d limiters", len(bl.limiters))
+	}
+}
+
+func brokerKey(b *eventingv1.Broker) types.NamespacedName {
+	return types.NamespacedName{Namespace: b.Namespace, Name: b.Name}
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/broker"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/utils"
+)
+
+const (
+	// scheduledEventFileSuffix is the suffix of the files storing the scheduled events.
+	scheduledEventFileSuffix = ".json"
+	// scheduledEventTmpFileSuffix is the suffix of the files being written, renamed once complete.
+	scheduledEventTmpFileSuffix = ".tmp"
+)
+
+// scheduledEvent is an event held by the ingress until it is due.
+type scheduledEvent struct {
+	ID              string             `json:"id"`
+	BrokerNamespace string             `json:"brokerNamespace"`
+	BrokerName      string             `json:"brokerName"`
+	DeliverAt       time.Time          `json:"deliverAt"`
+	Headers         http.Header        `json:"headers,omitempty"`
+	Event           *cloudevents.Event `json:"event"`
+}
+
+// dueEventDelay is how long after they are scheduled the events which are already due are
+// delivered, as the cron only activates the schedules after the time they are added at.
+const dueEventDelay = time.Second
+
+// scheduler holds the events sent to the Brokers with a delivery time until they are due. The
+// events are stored in a directory, one file per event, so that they survive the restarts of the
+// ingress, and are removed once they are sent.
+type scheduler struct {
+	dir    string
+	logger *zap.Logger
+	now    func() time.Time
+	send   func(se *scheduledEvent)
+
+	// mu makes the jobs of the cron wait for the id of their entry, which they remove once they
+	// are activated.
+	mu   sync.Mutex
+	cron *cron.Cron
+}
+
+func newScheduler(dir string, logger *zap.Logger, send func(se *scheduledEvent)) (*scheduler, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the scheduling directory: %w", err)
+	}
+	return &scheduler{
+		dir:    dir,
+		logger: logger,
+		now:    time.Now,
+		send:   send,
+		cron:   cron.New(),
+	}, nil
+}
+
+// start schedules the events stored in the directory, sending immediately the ones which became
+// due while the ingress was stopped, and runs the cron until the context is done.
+func (s *scheduler) start(ctx context.Context) error {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list the scheduled events: %w", err)
+	}
+	var stored []*scheduledEvent
+	for _, f := range files {
+		path := filepath.Join(s.dir, f.Name())
+		if strings.HasSuffix(f.Name(), scheduledEventTmpFileSuffix) {
+			// The ingress stopped before the event was accepted.
+			_ = os.Remove(path)
+			continue
+		}
+		if !strings.HasSuffix(f.Name(), scheduledEventFileSuffix) {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the scheduled event %s: %w", f.Name(), err)
+		}
+		se := &scheduledEvent{}
+		if err := json.Unmarshal(b, se); err != nil {
+			s.logger.Warn("dropping unreadable scheduled event", zap.String("file", f.Name()), zap.Error(err))
+			_ = os.Remove(path)
+			continue
+		}
+		stored = append(stored, se)
+	}
+
+	// The events are added once the cron runs, so that the ones which became due while reading
+	// them are activated.
+	s.cron.Start()
+	for _, se := range stored {
+		s.add(se)
+	}
+	go func() {
+		<-ctx.Done()
+		<-s.cron.Stop().Done()
+	}()
+	return nil
+}
+
+// schedule stores the event sent to the Broker and sends it to the Broker's channel once it is
+// due.
+func (s *scheduler) schedule(b *eventingv1.Broker, deliverAt time.Time, headers http.Header, event *cloudevents.Event) error {
+	se := &scheduledEvent{
+		ID:              uuid.New().String(),
+		BrokerNamespace: b.Namespace,
+		BrokerName:      b.Name,
+		DeliverAt:       deliverAt,
+		Headers:         headers,
+		Event:           event,
+	}
+	data, err := json.Marshal(se)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the scheduled event: %w", err)
+	}
+
+	path := s.path(se)
+	tmp := path + scheduledEventTmpFileSuffix
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create the scheduled event: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write the scheduled event: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync the scheduled event: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close the scheduled event: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to store the scheduled event: %w", err)
+	}
+
+	s.add(se)
+	return nil
+}
+
+// add schedules the stored event.
+func (s *scheduler) add(se *scheduledEvent) {
+	at := se.DeliverAt
+	if earliest := s.now().Add(dueEventDelay); at.Before(earliest) {
+		at = earliest
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var id cron.EntryID
+	id = s.cron.Schedule(utils.OnceSchedule(at), cron.FuncJob(func() {
+		s.send(se)
+		if err := os.Remove(s.path(se)); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("failed to remove the sent scheduled event", zap.String("id", se.ID), zap.Error(err))
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.cron.Remove(id)
+	}))
+}
+
+func (s *scheduler) path(se *scheduledEvent) string {
+	return filepath.Join(s.dir, se.ID+scheduledEventFileSuffix)
+}
+
+// StartScheduler starts sending the events scheduled for a later delivery, stored in the
+// directory, including the events which were pending when the ingress stopped. The events with a
+// delivery time are rejected until it is started, unless they are forwarded to the SchedulerURL.
+func (h *Handler) StartScheduler(ctx context.Context, dir string) error {
+	s, err := newScheduler(dir, h.Logger, func(se *scheduledEvent) {
+		h.sendScheduled(h.withContext(ctx), se)
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.start(ctx); err != nil {
+		return err
+	}
+	h.scheduler = s
+	return nil
+}
+
+// deliveryTime returns the time the event is delivered at when the Broker enables the scheduling
+// and the event asks to be delivered later. The scheduling extensions are then removed from the
+// event, so that they don't apply again to the replies sent to the Broker.
+func (h *Handler) deliveryTime(event *cloudevents.Event, brokerObj *eventingv1.Broker) (time.Time, bool, error) {
+	annotation, ok := brokerObj.Status.Annotations[eventing.BrokerIngressSchedulingStatusAnnotationKey]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	var scheduling broker.Scheduling
+	if err := json.Unmarshal([]byte(annotation), &scheduling); err != nil {
+		h.Logger.Warn("failed to parse the ingress scheduling of the broker", zap.Error(err))
+		return time.Time{}, false, nil
+	}
+
+	now := time.Now()
+	if h.scheduler != nil {
+		now = h.scheduler.now()
+	}
+	deliverAt, ok, err := broker.GetDeliveryTime(event.Context, now)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	if h.scheduler == nil && h.SchedulerURL == nil {
+		return time.Time{}, false, errors.New("the ingress doesn't schedule events")
+	}
+	broker.DeleteDeliveryTime(event.Context)
+
+	if delay := deliverAt.Sub(now); delay > scheduling.MaxDelay.Duration {
+		return time.Time{}, false, fmt.Errorf("the event is scheduled %v ahead, more than the maximum delay of the broker %v", delay.Round(time.Second), scheduling.MaxDelay.Duration)
+	}
+	return deliverAt, deliverAt.After(now), nil
+}
+
+// schedule holds the event until it is due, in the scheduler of the ingress or else in the ingress
+// of the SchedulerURL.
+func (h *Handler) schedule(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerObj *eventingv1.Broker, deliverAt time.Time) (int, time.Duration, error) {
+	if h.scheduler != nil {
+		if err := h.scheduler.schedule(brokerObj, deliverAt, headers, event); err != nil {
+			h.Logger.Error("failed to schedule event", zap.Error(err))
+			return http.StatusInternalServerError, kncloudevents.NoDuration, nil
+		}
+		return http.StatusAccepted, kncloudevents.NoDuration, nil
+	}
+
+	// The delivery time is forwarded as an absolute time, so that the delay isn't applied twice.
+	event.SetExtension(broker.DeliverAtExtension, cloudevents.Timestamp{Time: deliverAt})
+	url := *h.SchedulerURL
+	url.Path = path.Join("/", url.Path, brokerObj.Namespace, brokerObj.Name)
+	target := duckv1.Addressable{URL: &url}
+	if brokerObj.Status.Address != nil {
+		target.Audience = brokerObj.Status.Address.Audience
+	}
+
+	dispatchInfo, err := h.eventDispatcher.SendEvent(ctx, *event, target, sendOptions(headers)...)
+	if err != nil {
+		h.Logger.Error("failed to forward scheduled event", zap.Error(err))
+		if dispatchInfo != nil && dispatchInfo.ResponseCode > 0 {
+			return dispatchInfo.ResponseCode, dispatchInfo.Duration, nil
+		}
+		return http.StatusInternalServerError, kncloudevents.NoDuration, nil
+	}
+	return dispatchInfo.ResponseCode, dispatchInfo.Duration, nil
+}
+
+// sendScheduled sends the scheduled event to the channel of its Broker. As the sender of the event
+// can't retry it anymore, the retries and the dead letter sink of the Broker's delivery apply.
+func (h *Handler) sendScheduled(ctx context.Context, se *scheduledEvent) {
+	logger := h.Logger.With(zap.String("event.id", se.Event.ID()), zap.String("broker", se.BrokerNamespace+"/"+se.BrokerName))
+
+	brokerObj, err := h.getBroker(se.BrokerName, se.BrokerNamespace)
+	if err != nil {
+		logger.Warn("dropping scheduled event of a missing broker", zap.Error(err))
+		return
+	}
+	channelAddress, err := h.getChannelAddress(brokerObj)
+	if err != nil {
+		logger.Warn("dropping scheduled event, could not get channel address from broker", zap.Error(err))
+		return
+	}
+
+	opts := sendOptions(se.Headers)
+	if brokerObj.Spec.Delivery != nil {
+		retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(*brokerObj.Spec.Delivery)
+		if err != nil {
+			logger.Warn("failed to parse the delivery of the broker", zap.Error(err))
+		} else {
+			opts = append(opts, kncloudevents.WithRetryConfig(&retryConfig))
+		}
+	}
+	if brokerObj.Status.DeadLetterSinkURI != nil {
+		opts = append(opts, kncloudevents.WithDeadLetterSink(&duckv1.Addressable{
+			URL:      brokerObj.Status.DeadLetterSinkURI,
+			CACerts:  brokerObj.Status.DeadLetterSinkCACerts,
+			Audience: brokerObj.Status.DeadLetterSinkAudience,
+		}))
+	}
+
+	dispatchInfo, err := h.eventDispatcher.SendEvent(ctx, *se.Event, *channelAddress, opts...)
+	if err != nil {
+		logger.Error("failed to dispatch scheduled event", zap.Error(err))
+		return
+	}
+	if dispatchInfo.ResponseCode < 200 || dispatchInfo.ResponseCode >= 300 {
+		logger.Error("failed to dispatch scheduled event", zap.Int("status", dispatchInfo.ResponseCode))
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"bytes"
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	"knative.dev/pkg/apis"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/fake"
+	reconcilertesting "knative.dev/pkg/reconciler/testing"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/eventing/pkg/auth"
+	"knative.dev/eventing/pkg/broker"
+	brokerinformerfake "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker/fake"
+)
+
+func TestHandler_ServeHTTPScheduling(t *testing.T) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	logger := zap.NewNop()
+
+	received := make(chan event.Event, 10)
+	s := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		e, err := binding.ToEvent(request.Context(), cehttp.NewMessageFromHttpRequest(request))
+		if err != nil {
+			t.Error("failed to read the event sent to the channel:", err)
+		} else {
+			received <- *e
+		}
+		writer.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer s.Close()
+
+	scheduling := makeBroker("scheduling", "ns")
+	scheduling.Status.Annotations = map[string]string{
+		eventing.BrokerChannelAddressStatusAnnotationKey:    s.URL,
+		eventing.BrokerIngressSchedulingStatusAnnotationKey: `{"maxDelay":"1h0m0s"}`,
+	}
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(scheduling)
+	immediate := makeBroker("immediate", "ns")
+	immediate.Status.Annotations = map[string]string{
+		eventing.BrokerChannelAddressStatusAnnotationKey: s.URL,
+	}
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(immediate)
+
+	h, err := NewHandler(logger,
+		&mockReporter{},
+		broker.TTLDefaulter(logger, 100),
+		brokerinformerfake.Get(ctx),
+		auth.NewOIDCTokenVerifier(ctx),
+		auth.NewOIDCTokenProvider(ctx),
+		configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+		func(ctx context.Context) context.Context {
+			return ctx
+		})
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+	dir := t.TempDir()
+	if err := h.StartScheduler(ctx, dir); err != nil {
+		t.Fatal("Unable to start the scheduler:", err)
+	}
+
+	send := func(target string, extensions map[string]interface{}) int {
+		t.Helper()
+		e := makeEvent("1234", "source")
+		for k, v := range extensions {
+			e.SetExtension(k, v)
+		}
+		b, _ := e.MarshalJSON()
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(nethttp.MethodPost, target, bytes.NewBuffer(b))
+		request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		h.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// The event is held until it is due.
+	deliverAt := time.Now().Add(500 * time.Millisecond)
+	if code := send("/ns/scheduling", map[string]interface{}{broker.DeliverAtExtension: deliverAt.Format(time.RFC3339Nano)}); code != nethttp.StatusAccepted {
+		t.Fatalf("expected status code %d got %d", nethttp.StatusAccepted, code)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("expected the scheduled event to be stored, got %d files", len(files))
+	}
+	select {
+	case e := <-received:
+		if time.Now().Before(deliverAt) {
+			t.Error("the event was sent before it was due")
+		}
+		if _, ok := e.Extensions()[broker.DeliverAtExtension]; ok {
+			t.Error("expected the scheduling extension to be removed")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the scheduled event wasn't sent")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for files, _ := os.ReadDir(dir); len(files) != 0; files, _ = os.ReadDir(dir) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the sent event to be removed from the store")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The event can't be scheduled beyond the maximum delay of the Broker.
+	if code := send("/ns/scheduling", map[string]interface{}{broker.DeliverAfterExtension: "PT2H"}); code != nethttp.StatusBadRequest {
+		t.Errorf("expected status code %d got %d", nethttp.StatusBadRequest, code)
+	}
+	if code := send("/ns/scheduling", map[string]interface{}{broker.DeliverAfterExtension: "later"}); code != nethttp.StatusBadRequest {
+		t.Errorf("expected status code %d got %d", nethttp.StatusBadRequest, code)
+	}
+
+	// The extensions are ignored by the Brokers without scheduling.
+	if code := send("/ns/immediate", map[string]interface{}{broker.DeliverAfterExtension: "PT2H"}); code != nethttp.StatusAccepted {
+		t.Errorf("expected status code %d got %d", nethttp.StatusAccepted, code)
+	}
+	select {
+	case e := <-received:
+		if _, ok := e.Extensions()[broker.DeliverAfterExtension]; !ok {
+			t.Error("expected the scheduling extension to be kept")
+		}
+	default:
+		t.Error("expected the event to be sent immediately")
+	}
+}
+
+func TestHandler_ServeHTTPSchedulingForward(t *testing.T) {
+	ctx, _ := reconcilertesting.SetupFakeContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	logger := zap.NewNop()
+
+	received := make(chan event.Event, 10)
+	channel := httptest.NewServer(nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		e, err := binding.ToEvent(request.Context(), cehttp.NewMessageFromHttpRequest(request))
+		if err != nil {
+			t.Error("failed to read the event sent to the channel:", err)
+		} else {
+			received <- *e
+		}
+		writer.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer channel.Close()
+
+	scheduling := makeBroker("scheduling", "ns")
+	scheduling.Status.Annotations = map[string]string{
+		eventing.BrokerChannelAddressStatusAnnotationKey:    channel.URL,
+		eventing.BrokerIngressSchedulingStatusAnnotationKey: `{"maxDelay":"1h0m0s"}`,
+	}
+	brokerinformerfake.Get(ctx).Informer().GetStore().Add(scheduling)
+
+	newHandler := func() *Handler {
+		h, err := NewHandler(logger,
+			&mockReporter{},
+			broker.TTLDefaulter(logger, 100),
+			brokerinformerfake.Get(ctx),
+			auth.NewOIDCTokenVerifier(ctx),
+			auth.NewOIDCTokenProvider(ctx),
+			configmapinformer.Get(ctx).Lister().ConfigMaps("ns"),
+			func(ctx context.Context) context.Context {
+				return ctx
+			})
+		if err != nil {
+			t.Fatal("Unable to create receiver:", err)
+		}
+		return h
+	}
+	send := func(h *Handler, extensions map[string]interface{}) int {
+		t.Helper()
+		e := makeEvent("1234", "source")
+		for k, v := range extensions {
+			e.SetExtension(k, v)
+		}
+		b, _ := e.MarshalJSON()
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(nethttp.MethodPost, "/ns/scheduling", bytes.NewBuffer(b))
+		request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		h.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// The events with a delivery time are rejected by an ingress which can't schedule them.
+	ingress := newHandler()
+	if code := send(ingress, map[string]interface{}{broker.DeliverAfterExtension: "PT1S"}); code != nethttp.StatusBadRequest {
+		t.Errorf("expected status code %d got %d", nethttp.StatusBadRequest, code)
+	}
+
+	// They are otherwise forwarded to the ingress storing them.
+	scheduler := newHandler()
+	dir := t.TempDir()
+	if err := scheduler.StartScheduler(ctx, dir); err != nil {
+		t.Fatal("Unable to start the scheduler:", err)
+	}
+	s := httptest.NewServer(scheduler)
+	defer s.Close()
+	ingress.SchedulerURL, _ = apis.ParseURL(s.URL)
+
+	deliverAt := time.Now().Add(500 * time.Millisecond)
+	if code := send(ingress, map[string]interface{}{broker.DeliverAfterExtension: "PT0.5S"}); code != nethttp.StatusAccepted {
+		t.Fatalf("expected status code %d got %d", nethttp.StatusAccepted, code)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("expected the scheduled event to be stored by the scheduler, got %d files", len(files))
+	}
+	select {
+	case e := <-received:
+		if time.Now().Before(deliverAt) {
+			t.Error("the event was sent before it was due")
+		}
+		if _, ok := e.Extensions()[broker.DeliverAtExtension]; ok {
+			t.Error("expected the scheduling extension to be removed")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the scheduled event wasn't sent")
+	}
+
+	// The events scheduled beyond the maximum delay of the Broker are still rejected.
+	if code := send(ingress, map[string]interface{}{broker.DeliverAfterExtension: "PT2H"}); code != nethttp.StatusBadRequest {
+		t.Errorf("expected status code %d got %d", nethttp.StatusBadRequest, code)
+	}
+}
+
+func TestScheduler_Restart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	stopped, err := newScheduler(dir, zap.NewNop(), func(*scheduledEvent) {
+		t.Error("the stopped scheduler sent an event")
+	})
+	if err != nil {
+		t.Fatal("newScheduler() =", err)
+	}
+	e := makeEvent("1234", "source")
+	if err := stopped.schedule(makeBroker("name", "ns"), time.Now().Add(-time.Minute), nil, &e); err != nil {
+		t.Fatal("schedule() =", err)
+	}
+	// A leftover of an event the ingress didn't accept.
+	if err := os.WriteFile(dir+"/partial.json.tmp", []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make(chan *scheduledEvent, 1)
+	restarted, err := newScheduler(dir, zap.NewNop(), func(se *scheduledEvent) {
+		sent <- se
+	})
+	if err != nil {
+		t.Fatal("newScheduler() =", err)
+	}
+	if err := restarted.start(ctx); err != nil {
+		t.Fatal("start() =", err)
+	}
+
+	select {
+	case se := <-sent:
+		if se.BrokerNamespace != "ns" || se.BrokerName != "name" || se.Event.ID() != "1234" {
+			t.Errorf("unexpected scheduled event %+v", se)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the pending event wasn't sent after the restart")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for files, _ := os.ReadDir(dir); len(files) != 0; files, _ = os.ReadDir(dir) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the store to be empty, got %d files", len(files))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
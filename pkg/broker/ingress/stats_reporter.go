@@ -49,6 +49,31 @@ var (
 		stats.UnitMilliseconds,
 	)
 
+	// batchSizeM records the number of events received in a single request
+	// using the batched content mode.
+	batchSizeM = stats.Int64(
+		"event_batch_size",
+		"The number of events received in a batch by a Broker",
+		stats.UnitDimensionless,
+	)
+
+	// rateLimitedCountM is a counter which records the number of events
+	// rejected by the rate limiter of the Broker.
+	rateLimitedCountM = stats.Int64(
+		"event_rate_limited_count",
+		"Number of events rejected by the rate limit of a Broker",
+		stats.UnitDimensionless,
+	)
+
+	// deduplicationCountM is a counter which records the number of events
+	// checked by the deduplication of the Broker, by result. The hit rate of
+	// the deduplication is the ratio of the duplicate events.
+	deduplicationCountM = stats.Int64(
+		"event_deduplication_count",
+		"Number of events checked by the deduplication of a Broker",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -58,6 +83,17 @@ var (
 	eventSchemeKey       = tag.MustNewKey(eventingmetrics.LabelEventScheme)
 	responseCodeKey      = tag.MustNewKey(eventingmetrics.LabelResponseCode)
 	responseCodeClassKey = tag.MustNewKey(eventingmetrics.LabelResponseCodeClass)
+	eventValidationKey   = tag.MustNewKey(eventingmetrics.LabelEventValidation)
+	rateLimitKey         = tag.MustNewKey(eventingmetrics.LabelRateLimitKey)
+	deduplicationKey     = tag.MustNewKey(eventingmetrics.LabelDeduplicationResult)
+)
+
+const (
+	// validationSkipped is reported when the event isn't validated, either because the Broker
+	// doesn't enable the validation or because no EventType is registered for the event.
+	validationSkipped = "skipped"
+	validationPassed  = "passed"
+	validationFailed  = "failed"
 )
 
 type ReportArgs struct {
@@ -65,6 +101,8 @@ type ReportArgs struct {
 	broker      string
 	eventType   string
 	eventScheme string
+	// validation is the result of the validation of the event against its EventType.
+	validation string
 }
 
 func init() {
@@ -75,6 +113,9 @@ func init() {
 type StatsReporter interface {
 	ReportEventCount(args *ReportArgs, responseCode int) error
 	ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error
+	ReportBatchSize(args *ReportArgs, size int) error
+	ReportRateLimited(args *ReportArgs, key string) error
+	ReportDeduplication(args *ReportArgs, result string) error
 }
 
 var (
@@ -112,7 +153,7 @@ func register() {
 			Description: eventCountM.Description(),
 			Measure:     eventCountM,
 			Aggregation: view.Count(),
-			TagKeys:     tagKeys,
+			TagKeys:     append([]tag.Key{eventValidationKey}, tagKeys...),
 		},
 		&view.View{
 			Description: dispatchTimeInMsecM.Description(),
@@ -120,6 +161,40 @@ func register() {
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 500, 1000, 5000, 10000
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: batchSizeM.Description(),
+			Measure:     batchSizeM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 1000)...), // 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000
+			TagKeys: []tag.Key{
+				eventSchemeKey,
+				broker.ContainerTagKey,
+				broker.UniqueTagKey,
+			},
+		},
+		&view.View{
+			Description: rateLimitedCountM.Description(),
+			Measure:     rateLimitedCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				rateLimitKey,
+				eventTypeKey,
+				eventSchemeKey,
+				broker.ContainerTagKey,
+				broker.UniqueTagKey,
+			},
+		},
+		&view.View{
+			Description: deduplicationCountM.Description(),
+			Measure:     deduplicationCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				deduplicationKey,
+				eventTypeKey,
+				eventSchemeKey,
+				broker.ContainerTagKey,
+				broker.UniqueTagKey,
+			},
+		},
 	)
 	if err != nil {
 		log.Printf("failed to register opencensus views, %s", err)
@@ -132,6 +207,14 @@ func (r *reporter) ReportEventCount(args *ReportArgs, responseCode int) error {
 	if err != nil {
 		return err
 	}
+	validation := args.validation
+	if validation == "" {
+		validation = validationSkipped
+	}
+	ctx, err = tag.New(ctx, tag.Insert(eventValidationKey, validation))
+	if err != nil {
+		return err
+	}
 	metrics.Record(ctx, eventCountM.M(1))
 	return nil
 }
@@ -147,16 +230,55 @@ func (r *reporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d
 	return nil
 }
 
+// ReportBatchSize captures the number of events received in a batch.
+func (r *reporter) ReportBatchSize(args *ReportArgs, size int) error {
+	ctx, err := tag.New(
+		withBrokerResource(args),
+		tag.Insert(broker.ContainerTagKey, r.container),
+		tag.Insert(broker.UniqueTagKey, r.uniqueName),
+		tag.Insert(eventSchemeKey, args.eventScheme))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, batchSizeM.M(int64(size)))
+	return nil
+}
+
+// ReportRateLimited captures the count of events rejected by the rate limit.
+func (r *reporter) ReportRateLimited(args *ReportArgs, key string) error {
+	ctx, err := tag.New(
+		withBrokerResource(args),
+		tag.Insert(broker.ContainerTagKey, r.container),
+		tag.Insert(broker.UniqueTagKey, r.uniqueName),
+		tag.Insert(eventTypeKey, args.eventType),
+		tag.Insert(eventSchemeKey, args.eventScheme),
+		tag.Insert(rateLimitKey, key))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, rateLimitedCountM.M(1))
+	return nil
+}
+
+// ReportDeduplication captures the count of events checked by the deduplication.
+func (r *reporter) ReportDeduplication(args *ReportArgs, result string) error {
+	ctx, err := tag.New(
+		withBrokerResource(args),
+		tag.Insert(broker.ContainerTagKey, r.container),
+		tag.Insert(broker.UniqueTagKey, r.uniqueName),
+		tag.Insert(eventTypeKey, args.eventType),
+		tag.Insert(eventSchemeKey, args.eventScheme),
+		tag.Insert(deduplicationKey, result))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, deduplicationCountM.M(1))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
-	ctx := metricskey.WithResource(emptyContext, resource.Resource{
-		Type: eventingmetrics.ResourceTypeKnativeBroker,
-		Labels: map[string]string{
-			eventingmetrics.LabelNamespaceName: args.ns,
-			eventingmetrics.LabelBrokerName:    args.broker,
-		},
-	})
 	return tag.New(
-		ctx,
+		withBrokerResource(args),
 		tag.Insert(broker.ContainerTagKey, r.container),
 		tag.Insert(broker.UniqueTagKey, r.uniqueName),
 		tag.Insert(eventTypeKey, args.eventType),
@@ -164,3 +286,13 @@ func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Cont
 		tag.Insert(responseCodeKey, strconv.Itoa(responseCode)),
 		tag.Insert(responseCodeClassKey, metrics.ResponseCodeClass(responseCode)))
 }
+
+func withBrokerResource(args *ReportArgs) context.Context {
+	return metricskey.WithResource(emptyContext, resource.Resource{
+		Type: eventingmetrics.ResourceTypeKnativeBroker,
+		Labels: map[string]string{
+			eventingmetrics.LabelNamespaceName: args.ns,
+			eventingmetrics.LabelBrokerName:    args.broker,
+		},
+	})
+}
@@ -64,7 +64,20 @@ func TestStatsReporter(t *testing.T) {
 	expectSuccess(t, func() error {
 		return r.ReportEventCount(args, http.StatusAccepted)
 	})
-	metricstest.AssertMetric(t, metricstest.IntMetric("event_count", 2, wantTags).WithResource(&resource))
+	metricstest.AssertMetric(t, metricstest.IntMetric("event_count", 2, withTag(wantTags, metrics.LabelEventValidation, "skipped")).WithResource(&resource))
+
+	// test ReportEventCount of a rejected event
+	rejectedArgs := *args
+	rejectedArgs.validation = validationFailed
+	expectSuccess(t, func() error {
+		return r.ReportEventCount(&rejectedArgs, http.StatusBadRequest)
+	})
+	wantRejectedTags := withTag(wantTags, metrics.LabelEventValidation, "failed")
+	wantRejectedTags[metrics.LabelResponseCode] = "400"
+	wantRejectedTags[metrics.LabelResponseCodeClass] = "4xx"
+	wantCount := metricstest.IntMetric("event_count", 2, withTag(wantTags, metrics.LabelEventValidation, "skipped")).WithResource(&resource)
+	wantCount.Values = append(wantCount.Values, metricstest.IntMetric("event_count", 1, wantRejectedTags).Values...)
+	metricstest.AssertMetric(t, wantCount)
 
 	// test ReportDispatchTime
 	expectSuccess(t, func() error {
@@ -75,6 +88,56 @@ func TestStatsReporter(t *testing.T) {
 	})
 	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("event_dispatch_latencies", 2, wantTags))
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
+
+	// test ReportBatchSize
+	wantBatchTags := map[string]string{
+		broker.LabelUniqueName:    "testpod",
+		broker.LabelContainerName: "testcontainer",
+		metrics.LabelEventScheme:  "http",
+	}
+	expectSuccess(t, func() error {
+		return r.ReportBatchSize(args, 3)
+	})
+	expectSuccess(t, func() error {
+		return r.ReportBatchSize(args, 40)
+	})
+	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("event_batch_size", 2, wantBatchTags))
+	metricstest.CheckDistributionData(t, "event_batch_size", wantBatchTags, 2, 3.0, 40.0)
+
+	// test ReportRateLimited
+	wantRateLimitedTags := map[string]string{
+		metrics.LabelEventType:    "testeventtype",
+		metrics.LabelRateLimitKey: "source",
+		broker.LabelUniqueName:    "testpod",
+		broker.LabelContainerName: "testcontainer",
+		metrics.LabelEventScheme:  "http",
+	}
+	expectSuccess(t, func() error {
+		return r.ReportRateLimited(args, "source")
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("event_rate_limited_count", 1, wantRateLimitedTags).WithResource(&resource))
+
+	// test ReportDeduplication
+	wantDeduplicationTags := map[string]string{
+		metrics.LabelEventType:           "testeventtype",
+		metrics.LabelDeduplicationResult: "duplicate",
+		broker.LabelUniqueName:           "testpod",
+		broker.LabelContainerName:        "testcontainer",
+		metrics.LabelEventScheme:         "http",
+	}
+	expectSuccess(t, func() error {
+		return r.ReportDeduplication(args, "duplicate")
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("event_deduplication_count", 1, wantDeduplicationTags).WithResource(&resource))
+}
+
+func withTag(tags map[string]string, key, value string) map[string]string {
+	result := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		result[k] = v
+	}
+	result[key] = value
+	return result
 }
 
 func expectSuccess(t *testing.T, f func() error) {
@@ -92,6 +155,9 @@ func resetMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
 	metricstest.Unregister(
 		"event_count",
-		"event_dispatch_latencies")
+		"event_dispatch_latencies",
+		"event_batch_size",
+		"event_rate_limited_count",
+		"event_deduplication_count")
 	register()
 }
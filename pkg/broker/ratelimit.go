@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"fmt"
+	"math"
+
+	cm "knative.dev/pkg/configmap"
+)
+
+const (
+	// IngressRateLimitEventsPerSecondKey is the key of the Broker config ConfigMap setting the
+	// number of events per second accepted by the ingress. The ingress isn't rate limited when
+	// it is missing.
+	IngressRateLimitEventsPerSecondKey = "ingress-rate-limit-events-per-second"
+	// IngressRateLimitBurstKey is the key of the Broker config ConfigMap setting the number of
+	// events the ingress accepts at once, above the rate limit. It defaults to the rate limit.
+	IngressRateLimitBurstKey = "ingress-rate-limit-burst"
+	// IngressRateLimitKeyKey is the key of the Broker config ConfigMap setting what the rate
+	// limit applies to. It defaults to RateLimitKeyBroker.
+	IngressRateLimitKeyKey = "ingress-rate-limit-key"
+)
+
+// RateLimitKey determines the events sharing a rate limit.
+type RateLimitKey string
+
+const (
+	// RateLimitKeyBroker applies the rate limit to all the events sent to the Broker.
+	RateLimitKeyBroker RateLimitKey = "broker"
+	// RateLimitKeySource applies the rate limit to the events of each CloudEvent source.
+	RateLimitKeySource RateLimitKey = "source"
+	// RateLimitKeySubject applies the rate limit to the events of each OIDC subject. The events
+	// sent without a verified token share the same limit.
+	RateLimitKeySubject RateLimitKey = "subject"
+)
+
+// RateLimit is the token bucket rate limit of the events sent to a Broker.
+type RateLimit struct {
+	Key             RateLimitKey `json:"key"`
+	EventsPerSecond float64      `json:"eventsPerSecond"`
+	Burst           int          `json:"burst"`
+}
+
+// NewRateLimitFromConfigMap returns the ingress rate limit set in the data of the config
+// ConfigMap of a Broker, or nil if there is none.
+func NewRateLimitFromConfigMap(data map[string]string) (*RateLimit, error) {
+	if _, ok := data[IngressRateLimitEventsPerSecondKey]; !ok {
+		return nil, nil
+	}
+
+	rateLimit := &RateLimit{Key: RateLimitKeyBroker, Burst: -1}
+	key := string(rateLimit.Key)
+	if err := cm.Parse(data,
+		cm.AsFloat64(IngressRateLimitEventsPerSecondKey, &rateLimit.EventsPerSecond),
+		cm.AsInt(IngressRateLimitBurstKey, &rateLimit.Burst),
+		cm.AsString(IngressRateLimitKeyKey, &key),
+	); err != nil {
+		return nil, fmt.Errorf("failed to parse the ingress rate limit: %w", err)
+	}
+	rateLimit.Key = RateLimitKey(key)
+
+	if rateLimit.EventsPerSecond <= 0 || math.IsInf(rateLimit.EventsPerSecond, 0) || math.IsNaN(rateLimit.EventsPerSecond) {
+		return nil, fmt.Errorf("%s must be a positive number, was: %v", IngressRateLimitEventsPerSecondKey, rateLimit.EventsPerSecond)
+	}
+	if rateLimit.Burst == -1 {
+		rateLimit.Burst = int(math.Ceil(rateLimit.EventsPerSecond))
+	} else if rateLimit.Burst <= 0 {
+		return nil, fmt.Errorf("%s must be a positive integer, was: %d", IngressRateLimitBurstKey, rateLimit.Burst)
+	}
+	switch rateLimit.Key {
+	case RateLimitKeyBroker, RateLimitKeySource, RateLimitKeySubject:
+	default:
+		return nil, fmt.Errorf("%s must be one of %s, %s or %s, was: %q", IngressRateLimitKeyKey,
+			RateLimitKeyBroker, RateLimitKeySource, RateLimitKeySubject, rateLimit.Key)
+	}
+	return rateLimit, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewRateLimitFromConfigMap(t *testing.T) {
+	tests := map[string]struct {
+		data    map[string]string
+		want    *RateLimit
+		wantErr bool
+	}{
+		"no rate limit": {
+			data: map[string]string{"channel-template-spec": "..."},
+		},
+		"defaults": {
+			data: map[string]string{IngressRateLimitEventsPerSecondKey: "2.5"},
+			want: &RateLimit{Key: RateLimitKeyBroker, EventsPerSecond: 2.5, Burst: 3},
+		},
+		"all set": {
+			data: map[string]string{
+				IngressRateLimitEventsPerSecondKey: "100",
+				IngressRateLimitBurstKey:           "500",
+				IngressRateLimitKeyKey:             "subject",
+			},
+			want: &RateLimit{Key: RateLimitKeySubject, EventsPerSecond: 100, Burst: 500},
+		},
+		"invalid rate": {
+			data:    map[string]string{IngressRateLimitEventsPerSecondKey: "many"},
+			wantErr: true,
+		},
+		"zero rate": {
+			data:    map[string]string{IngressRateLimitEventsPerSecondKey: "0"},
+			wantErr: true,
+		},
+		"zero burst": {
+			data: map[string]string{
+				IngressRateLimitEventsPerSecondKey: "10",
+				IngressRateLimitBurstKey:           "0",
+			},
+			wantErr: true,
+		},
+		"invalid key": {
+			data: map[string]string{
+				IngressRateLimitEventsPerSecondKey: "10",
+				IngressRateLimitKeyKey:             "type",
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewRateLimitFromConfigMap(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewRateLimitFromConfigMap() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected rate limit (-want +got):", diff)
+			}
+		})
+	}
+}
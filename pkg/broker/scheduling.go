@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/rickb777/date/period"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cm "knative.dev/pkg/configmap"
+)
+
+const (
+	// DeliverAtExtension is the name of the CloudEvents extension attribute holding the RFC3339
+	// time the ingress sends the event to the Broker's channel.
+	DeliverAtExtension = "deliverat"
+	// DeliverAfterExtension is the name of the CloudEvents extension attribute holding the
+	// ISO-8601 duration, after the ingress accepted the event, the ingress sends the event to the
+	// Broker's channel. It is ignored when the event sets DeliverAtExtension.
+	DeliverAfterExtension = "deliverafter"
+
+	// IngressSchedulingMaxDelayKey is the key of the Broker config ConfigMap setting how far ahead
+	// the events sent to the Broker may be scheduled. The ingress ignores the scheduling extensions
+	// when it is missing.
+	IngressSchedulingMaxDelayKey = "ingress-scheduling-max-delay"
+)
+
+// Scheduling is the configuration of the delayed delivery of the events sent to a Broker.
+type Scheduling struct {
+	MaxDelay metav1.Duration `json:"maxDelay"`
+}
+
+// NewSchedulingFromConfigMap returns the ingress scheduling set in the data of the config
+// ConfigMap of a Broker, or nil if there is none.
+func NewSchedulingFromConfigMap(data map[string]string) (*Scheduling, error) {
+	if _, ok := data[IngressSchedulingMaxDelayKey]; !ok {
+		return nil, nil
+	}
+
+	var maxDelay time.Duration
+	if err := cm.Parse(data,
+		cm.AsDuration(IngressSchedulingMaxDelayKey, &maxDelay),
+	); err != nil {
+		return nil, fmt.Errorf("failed to parse the ingress scheduling: %w", err)
+	}
+
+	if maxDelay <= 0 {
+		return nil, fmt.Errorf("%s must be a positive duration, was: %v", IngressSchedulingMaxDelayKey, maxDelay)
+	}
+	return &Scheduling{MaxDelay: metav1.Duration{Duration: maxDelay}}, nil
+}
+
+// GetDeliveryTime returns the time the event asks to be delivered at, computed from the
+// DeliverAtExtension or the DeliverAfterExtension relative to now, and false when the event sets
+// neither of them.
+func GetDeliveryTime(ctx cloudevents.EventContext, now time.Time) (time.Time, bool, error) {
+	if deliverAt, err := ctx.GetExtension(DeliverAtExtension); err == nil {
+		t, err := cetypes.ToTime(deliverAt)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s extension: %w", DeliverAtExtension, err)
+		}
+		return t, true, nil
+	}
+
+	deliverAfter, err := ctx.GetExtension(DeliverAfterExtension)
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+	s, err := cetypes.ToString(deliverAfter)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s extension: %w", DeliverAfterExtension, err)
+	}
+	p, err := period.Parse(s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s extension: %w", DeliverAfterExtension, err)
+	}
+	d, _ := p.Duration()
+	if d < 0 {
+		return time.Time{}, false, fmt.Errorf("invalid %s extension: must not be negative, was: %s", DeliverAfterExtension, s)
+	}
+	return now.Add(d), true, nil
+}
+
+// DeleteDeliveryTime removes the scheduling CE extension attributes.
+func DeleteDeliveryTime(ctx cloudevents.EventContext) {
+	_ = ctx.SetExtension(DeliverAtExtension, nil)
+	_ = ctx.SetExtension(DeliverAfterExtension, nil)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewSchedulingFromConfigMap(t *testing.T) {
+	tests := map[string]struct {
+		data    map[string]string
+		want    *Scheduling
+		wantErr bool
+	}{
+		"no scheduling": {
+			data: map[string]string{IngressDeduplicationWindowKey: "1m"},
+		},
+		"max delay": {
+			data: map[string]string{IngressSchedulingMaxDelayKey: "24h"},
+			want: &Scheduling{MaxDelay: metav1.Duration{Duration: 24 * time.Hour}},
+		},
+		"invalid max delay": {
+			data:    map[string]string{IngressSchedulingMaxDelayKey: "tomorrow"},
+			wantErr: true,
+		},
+		"zero max delay": {
+			data:    map[string]string{IngressSchedulingMaxDelayKey: "0s"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewSchedulingFromConfigMap(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewSchedulingFromConfigMap() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected scheduling (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestGetDeliveryTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		extensions map[string]interface{}
+		want       time.Time
+		wantOK     bool
+		wantErr    bool
+	}{
+		"not scheduled": {},
+		"deliver at": {
+			extensions: map[string]interface{}{DeliverAtExtension: "2024-01-01T01:00:00Z"},
+			want:       now.Add(time.Hour),
+			wantOK:     true,
+		},
+		"deliver after": {
+			extensions: map[string]interface{}{DeliverAfterExtension: "PT30M"},
+			want:       now.Add(30 * time.Minute),
+			wantOK:     true,
+		},
+		"deliver at takes precedence": {
+			extensions: map[string]interface{}{
+				DeliverAtExtension:    "2024-01-01T01:00:00Z",
+				DeliverAfterExtension: "PT30M",
+			},
+			want:   now.Add(time.Hour),
+			wantOK: true,
+		},
+		"invalid deliver at": {
+			extensions: map[string]interface{}{DeliverAtExtension: "tomorrow"},
+			wantErr:    true,
+		},
+		"invalid deliver after": {
+			extensions: map[string]interface{}{DeliverAfterExtension: "30m"},
+			wantErr:    true,
+		},
+		"negative deliver after": {
+			extensions: map[string]interface{}{DeliverAfterExtension: "-PT30M"},
+			wantErr:    true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := cloudevents.NewEvent()
+			for k, v := range tc.extensions {
+				e.SetExtension(k, v)
+			}
+			got, ok, err := GetDeliveryTime(e.Context, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetDeliveryTime() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if ok != tc.wantOK || !got.Equal(tc.want) {
+				t.Errorf("GetDeliveryTime() = %v, %v, want %v, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
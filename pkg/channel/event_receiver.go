@@ -60,6 +60,14 @@ func (e BadRequestError) Error() string {
 	return "malformed request: " + string(e)
 }
 
+// QueueFullError represents the error when an event is rejected by a channel dispatcher because
+// the queue of a subscriber is full.
+type QueueFullError string
+
+func (e QueueFullError) Error() string {
+	return "queue full: " + string(e)
+}
+
 // EventReceiver starts a server to receive new events for the channel dispatcher. The new
 // event is emitted via the receiver function.
 type EventReceiver struct {
@@ -266,9 +274,13 @@ func (r *EventReceiver) ServeHTTP(response nethttp.ResponseWriter, request *neth
 
 	err = r.receiverFunc(request.Context(), channel, *event, utils.PassThroughHeaders(request.Header))
 	if err != nil {
-		if _, ok := err.(*UnknownChannelError); ok {
+		switch err.(type) {
+		case *UnknownChannelError:
 			response.WriteHeader(nethttp.StatusNotFound)
-		} else {
+		case QueueFullError:
+			r.logger.Debug("Event rejected", zap.Error(err))
+			response.WriteHeader(nethttp.StatusTooManyRequests)
+		default:
 			r.logger.Info("Error in receiver", zap.Error(err))
 			response.WriteHeader(nethttp.StatusInternalServerError)
 		}
@@ -283,6 +295,8 @@ func ReportEventCountMetricsForDispatchError(err error, reporter StatsReporter,
 		_ = reporter.ReportEventCount(args, nethttp.StatusNotFound)
 	case BadRequestError:
 		_ = reporter.ReportEventCount(args, nethttp.StatusBadRequest)
+	case QueueFullError:
+		_ = reporter.ReportEventCount(args, nethttp.StatusTooManyRequests)
 	default:
 		_ = reporter.ReportEventCount(args, nethttp.StatusInternalServerError)
 	}
@@ -23,7 +23,9 @@ package fanout
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	nethttp "net/http"
 	"sync"
 	"time"
@@ -36,8 +38,12 @@ import (
 
 	"knative.dev/eventing/pkg/apis"
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/channel/wal"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
 	"knative.dev/eventing/pkg/eventtype"
 	"knative.dev/eventing/pkg/kncloudevents"
 )
@@ -55,6 +61,27 @@ type Subscription struct {
 	Name           string
 	Namespace      string
 	UID            types.UID
+	// FailureHistory adds the history of the attempts to send the event to the events sent to
+	// the dead letter sink.
+	FailureHistory bool
+	// Throttle bounds the concurrency and the rate of the requests to the subscriber.
+	Throttle *kncloudevents.ThrottleConfig
+	// ReplyRoutes send the replies of the subscriber matching their filters to their
+	// destination instead of Reply. The first matching route is used.
+	ReplyRoutes []ReplyRoute
+}
+
+// ReplyRoute is a conditional destination of the replies of a subscriber.
+type ReplyRoute struct {
+	// Filters the replies must all match to be sent to Destination.
+	Filters     []eventingv1.SubscriptionsAPIFilter
+	Destination duckv1.Addressable
+}
+
+// replyRoute is a ReplyRoute with its filters materialized.
+type replyRoute struct {
+	filter      eventfilter.Filter
+	destination duckv1.Addressable
 }
 
 // Config for a fanout.EventHandler.
@@ -63,6 +90,17 @@ type Config struct {
 	// Deprecated: AsyncHandler controls whether the Subscriptions are called synchronous or asynchronously.
 	// It is expected to be false when used as a sidecar.
 	AsyncHandler bool `json:"asyncHandler,omitempty"`
+	// Log makes the handler append the events to the log before acknowledging them, and then
+	// dispatch them asynchronously. The events not yet dispatched to the Subscriptions when the
+	// handler is created are dispatched again.
+	Log *wal.Log `json:"-"`
+	// QueueDepth bounds the number of events waiting to be dispatched to each Subscription. The
+	// events are rejected while the queue of a Subscription is full. When zero, the events are
+	// dispatched to the Subscriptions without bound.
+	QueueDepth int `json:"queueDepth,omitempty"`
+	// QueueWorkers is the number of events dispatched concurrently to each Subscription when
+	// QueueDepth is set. Defaults to DefaultQueueWorkers.
+	QueueWorkers int `json:"queueWorkers,omitempty"`
 }
 
 // EventHandler is an http.Handler but has methods for managing
@@ -81,8 +119,16 @@ type FanoutEventHandler struct {
 	// It is expected to be false when used as a sidecar.
 	asyncHandler bool
 
+	// log stores the events until they are dispatched to all the Subscriptions, when set.
+	log *wal.Log
+
+	// queues bound the events waiting to be dispatched to each Subscription, when set.
+	queues *queues
+
 	subscriptionsMutex sync.RWMutex
 	subscriptions      []Subscription
+	// replyRoutes holds the materialized reply routes of the Subscriptions, by subscriptionKey.
+	replyRoutes map[string][]replyRoute
 
 	receiver *channel.EventReceiver
 
@@ -117,11 +163,15 @@ func NewFanoutEventHandler(
 		timeout:          defaultTimeout,
 		reporter:         reporter,
 		asyncHandler:     config.AsyncHandler,
+		log:              config.Log,
 		eventTypeHandler: eventTypeHandler,
 		channelRef:       channelRef,
 		channelUID:       channelUID,
 		eventDispatcher:  eventDispatcher,
 	}
+	if config.QueueDepth > 0 {
+		handler.queues = newQueues(config.QueueDepth, config.QueueWorkers, reporter)
+	}
 
 	handler.SetSubscriptions(context.Background(), config.Subscriptions)
 
@@ -171,7 +221,32 @@ func SubscriberSpecToFanoutConfig(sub eventingduckv1.SubscriberSpec) (*Subscript
 		}
 	}
 
-	s := &Subscription{Subscriber: destination, Reply: reply, DeadLetter: deadLetter, RetryConfig: retryConfig, UID: sub.UID}
+	var replyRoutes []ReplyRoute
+	for i, route := range sub.ReplyRoutes {
+		if route.URI == nil {
+			continue
+		}
+		filters := make([]eventingv1.SubscriptionsAPIFilter, len(route.Filters))
+		for j, raw := range route.Filters {
+			if err := json.Unmarshal(raw.Raw, &filters[j]); err != nil {
+				return nil, fmt.Errorf("failed to parse the filter %d of the reply route %d: %w", j, i, err)
+			}
+		}
+		replyRoutes = append(replyRoutes, ReplyRoute{
+			Filters: filters,
+			Destination: duckv1.Addressable{
+				URL:      route.URI,
+				CACerts:  route.CACerts,
+				Audience: route.Audience,
+			},
+		})
+	}
+
+	s := &Subscription{Subscriber: destination, Reply: reply, ReplyRoutes: replyRoutes, DeadLetter: deadLetter, RetryConfig: retryConfig, UID: sub.UID}
+	if sub.Delivery != nil {
+		s.FailureHistory = sub.Delivery.FailureHistory()
+		s.Throttle = kncloudevents.ThrottleConfigFromDeliverySpec(*sub.Delivery)
+	}
 
 	if sub.Name != nil {
 		s.Name = *sub.Name
@@ -186,6 +261,7 @@ func (f *FanoutEventHandler) SetSubscriptions(ctx context.Context, subs []Subscr
 	s := make([]Subscription, len(subs))
 	copy(s, subs)
 	f.subscriptions = s
+	f.setReplyRoutes(s)
 
 	for _, sub := range f.subscriptions {
 		if sub.Subscriber.URL != nil && sub.Subscriber.URL.Scheme == "https" {
@@ -194,6 +270,114 @@ func (f *FanoutEventHandler) SetSubscriptions(ctx context.Context, subs []Subscr
 			f.hasHttpSubs = true
 		}
 	}
+
+	if f.queues != nil {
+		f.queues.set(s)
+	}
+
+	if f.log != nil {
+		f.setLogConsumers(s)
+	}
+}
+
+// setReplyRoutes materializes the reply routes of the Subscriptions, and cleans up the
+// previous ones.
+func (f *FanoutEventHandler) setReplyRoutes(subs []Subscription) {
+	for _, routes := range f.replyRoutes {
+		for _, route := range routes {
+			route.filter.Cleanup()
+		}
+	}
+	f.replyRoutes = make(map[string][]replyRoute)
+	for _, sub := range subs {
+		for _, route := range sub.ReplyRoutes {
+			f.replyRoutes[subscriptionKey(sub)] = append(f.replyRoutes[subscriptionKey(sub)], replyRoute{
+				filter:      subscriptionsapi.NewAllFilter(subscriptionsapi.MaterializeFiltersList(f.logger, route.Filters)...),
+				destination: route.Destination,
+			})
+		}
+	}
+}
+
+// replyRouter returns the kncloudevents.ReplyRouter sending the replies of the Subscription to
+// the first of its reply routes they match, or nil when the Subscription has none.
+func (f *FanoutEventHandler) replyRouter(sub Subscription) kncloudevents.ReplyRouter {
+	f.subscriptionsMutex.RLock()
+	routes := f.replyRoutes[subscriptionKey(sub)]
+	f.subscriptionsMutex.RUnlock()
+	if len(routes) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, reply *event.Event) *duckv1.Addressable {
+		for _, route := range routes {
+			if route.filter.Filter(subscriptionsapi.WithParsedPayload(ctx, *reply), *reply) != eventfilter.FailFilter {
+				destination := route.destination
+				return &destination
+			}
+		}
+		return nil
+	}
+}
+
+// setLogConsumers sets the Subscriptions the events of the log are dispatched to, and dispatches
+// again the events they didn't receive before the log was opened.
+func (f *FanoutEventHandler) setLogConsumers(subs []Subscription) {
+	keys := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		keys = append(keys, subscriptionKey(sub))
+	}
+	pending, err := f.log.SetConsumers(keys)
+	if err != nil {
+		f.logger.Error("Failed to set the consumers of the log", zap.Error(err))
+	}
+	for _, sub := range subs {
+		if r, ok := pending[subscriptionKey(sub)]; ok {
+			go f.replay(sub, r)
+		}
+	}
+}
+
+// replay dispatches the events of the log in the range to the Subscription, in order.
+func (f *FanoutEventHandler) replay(sub Subscription, r wal.Range) {
+	f.logger.Info("Dispatching the events of the log again",
+		zap.String("subscription", subscriptionKey(sub)), zap.Uint64("from", r.From), zap.Uint64("to", r.To))
+
+	ctx := context.Background()
+	err := f.log.Read(r, func(entry wal.Entry) error {
+		h := entry.Headers.Clone()
+		if h == nil {
+			h = make(nethttp.Header)
+		}
+		h.Set(apis.KnNamespaceHeader, sub.Namespace)
+		if _, err := f.makeFanoutRequest(ctx, *entry.Event, h, sub); err != nil {
+			// The retries are exhausted, the event is committed like the dispatched ones.
+			f.logger.Error("Failed to dispatch the event of the log", zap.Uint64("offset", entry.Offset), zap.Error(err))
+		}
+		f.commit(entry.Offset)(sub)
+		return nil
+	})
+	if err != nil {
+		f.logger.Error("Failed to read the log", zap.Error(err))
+	}
+}
+
+// commit returns the function recording that the event at the offset of the log was handled for
+// a Subscription: it was dispatched or sent to the dead letter sink, it failed once the retries
+// were exhausted, or it doesn't need to be dispatched.
+func (f *FanoutEventHandler) commit(offset uint64) func(Subscription) {
+	return func(sub Subscription) {
+		if err := f.log.Commit(subscriptionKey(sub), offset); err != nil {
+			f.logger.Error("Failed to commit the offset of the log", zap.Uint64("offset", offset), zap.Error(err))
+		}
+	}
+}
+
+// subscriptionKey identifies the Subscription in the log.
+func subscriptionKey(sub Subscription) string {
+	if sub.UID != "" {
+		return string(sub.UID)
+	}
+	return sub.Namespace + "/" + sub.Name
 }
 
 func (f *FanoutEventHandler) GetSubscriptions(ctx context.Context) []Subscription {
@@ -218,6 +402,50 @@ func (f *FanoutEventHandler) autoCreateEventType(ctx context.Context, evnt event
 }
 
 func createEventReceiverFunction(f *FanoutEventHandler) func(context.Context, channel.ChannelReference, event.Event, nethttp.Header) error {
+	if f.log != nil {
+		return func(ctx context.Context, ref channel.ChannelReference, evnt event.Event, additionalHeaders nethttp.Header) error {
+			if f.eventTypeHandler != nil {
+				f.autoCreateEventType(ctx, evnt)
+			}
+
+			// The event is appended while holding the Subscriptions, so that the consumers of
+			// the log are the Subscriptions the event is dispatched to.
+			f.subscriptionsMutex.RLock()
+			subs := make([]Subscription, len(f.subscriptions))
+			copy(subs, f.subscriptions)
+			var offset uint64
+			var err error
+			if len(subs) > 0 {
+				offset, err = f.log.Append(&evnt, additionalHeaders)
+			}
+			f.subscriptionsMutex.RUnlock()
+			if err != nil {
+				return fmt.Errorf("failed to append the event to the log: %w", err)
+			}
+			if len(subs) == 0 {
+				// Nothing to do here
+				return nil
+			}
+
+			// Run async dispatch with background context.
+			ctx = trace.NewContext(context.Background(), trace.FromContext(ctx))
+			// The event is committed for each Subscription once it is handled, the Subscriptions
+			// still dispatching it when the dispatcher stops receive it again the next time the
+			// log is opened.
+			results, err := f.send(ctx, subs, evnt, additionalHeaders, f.commit(offset))
+			if err != nil {
+				// The event was refused and won't be dispatched, don't keep it in the log.
+				for _, sub := range subs {
+					f.commit(offset)(sub)
+				}
+				return err
+			}
+
+			// Any returned error is already logged in f.wait().
+			go f.wait(len(subs), results)
+			return nil
+		}
+	}
 	if f.asyncHandler {
 		return func(ctx context.Context, ref channel.ChannelReference, evnt event.Event, additionalHeaders nethttp.Header) error {
 			if f.eventTypeHandler != nil {
@@ -230,15 +458,15 @@ func createEventReceiverFunction(f *FanoutEventHandler) func(context.Context, ch
 				return nil
 			}
 
-			parentSpan := trace.FromContext(ctx)
-
-			go func(e event.Event, h nethttp.Header, s *trace.Span) {
-				// Run async dispatch with background context.
-				ctx = trace.NewContext(context.Background(), s)
-				// Any returned error is already logged in f.dispatch().
-				_ = f.dispatch(ctx, subs, e, h)
+			// Run async dispatch with background context.
+			ctx = trace.NewContext(context.Background(), trace.FromContext(ctx))
+			results, err := f.send(ctx, subs, evnt, additionalHeaders, nil)
+			if err != nil {
+				return err
+			}
 
-			}(evnt, additionalHeaders, parentSpan)
+			// Any returned error is already logged in f.wait().
+			go f.wait(len(subs), results)
 			return nil
 		}
 	}
@@ -254,7 +482,7 @@ func createEventReceiverFunction(f *FanoutEventHandler) func(context.Context, ch
 		}
 
 		// Any returned error is already logged in f.dispatch().
-		dispatchResultForFanout := f.dispatch(ctx, subs, event, additionalHeaders)
+		dispatchResultForFanout := f.dispatch(ctx, subs, event, additionalHeaders, nil)
 		return dispatchResultForFanout.err
 	}
 }
@@ -282,27 +510,54 @@ func ParseDispatchResultAndReportMetrics(result DispatchResult, reporter channel
 }
 
 // dispatch takes the event, fans it out to each subscription in subs. If all the fanned out
-// events return successfully, then return nil. Else, return an error.
-func (f *FanoutEventHandler) dispatch(ctx context.Context, subs []Subscription, event event.Event, additionalHeaders nethttp.Header) DispatchResult {
+// events return successfully, then return nil. Else, return an error. When set, handled is
+// called once the dispatch of the event to a subscription completed, successfully or not.
+func (f *FanoutEventHandler) dispatch(ctx context.Context, subs []Subscription, event event.Event, additionalHeaders nethttp.Header, handled func(Subscription)) DispatchResult {
+	results, err := f.send(ctx, subs, event, additionalHeaders, handled)
+	if err != nil {
+		return DispatchResult{err: err}
+	}
+	return f.wait(len(subs), results)
+}
+
+// send starts fanning out the event to each subscription in subs, and returns the channel the
+// result of each subscription is sent to. When the queue of a subscription is full, the event
+// isn't dispatched and a channel.QueueFullError is returned.
+func (f *FanoutEventHandler) send(ctx context.Context, subs []Subscription, event event.Event, additionalHeaders nethttp.Header, handled func(Subscription)) (<-chan DispatchResult, error) {
 	results := make(chan DispatchResult, len(subs))
+	send := func(s Subscription) {
+		h := additionalHeaders.Clone()
+		h.Set(apis.KnNamespaceHeader, s.Namespace)
+
+		dispatchedResultPerSub, err := f.makeFanoutRequest(ctx, event, h, s)
+		if handled != nil {
+			handled(s)
+		}
+		r := DispatchResult{err: err, info: dispatchedResultPerSub}
+		results <- r
+
+		args := channel.ReportArgs{
+			Ns:          s.Namespace,
+			EventType:   event.Type(),
+			EventScheme: r.info.Scheme,
+		}
+		_ = ParseDispatchResultAndReportMetrics(r, f.reporter, args)
+	}
+
+	if f.queues != nil {
+		if err := f.queues.enqueue(subs, channel.ReportArgs{EventType: event.Type()}, send); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
 	for _, sub := range subs {
-		go func(s Subscription) {
-			h := additionalHeaders.Clone()
-			h.Set(apis.KnNamespaceHeader, s.Namespace)
-
-			dispatchedResultPerSub, err := f.makeFanoutRequest(ctx, event, h, s)
-			r := DispatchResult{err: err, info: dispatchedResultPerSub}
-			results <- r
-
-			args := channel.ReportArgs{
-				Ns:          s.Namespace,
-				EventType:   event.Type(),
-				EventScheme: r.info.Scheme,
-			}
-			_ = ParseDispatchResultAndReportMetrics(r, f.reporter, args)
-		}(sub)
+		go send(sub)
 	}
+	return results, nil
+}
 
+// wait collects the results of fanning out an event to n subscriptions.
+func (f *FanoutEventHandler) wait(n int, results <-chan DispatchResult) DispatchResult {
 	var totalDispatchTimeForFanout time.Duration = kncloudevents.NoDuration
 	dispatchResultForFanout := DispatchResult{
 		info: &kncloudevents.DispatchInfo{
@@ -310,7 +565,7 @@ func (f *FanoutEventHandler) dispatch(ctx context.Context, subs []Subscription,
 			ResponseCode: kncloudevents.NoResponse,
 		},
 	}
-	for range subs {
+	for i := 0; i < n; i++ {
 		select {
 		case dispatchResult := <-results:
 			if dispatchResult.info != nil {
@@ -348,19 +603,33 @@ func (f *FanoutEventHandler) makeFanoutRequest(ctx context.Context, event event.
 		kncloudevents.WithRetryConfig(sub.RetryConfig),
 	}
 
+	subRef := &duckv1.KReference{
+		Name:       sub.Name,
+		Namespace:  sub.Namespace,
+		APIVersion: messagingv1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+	}
+
 	if f.eventTypeHandler != nil && sub.Name != "" && sub.Namespace != "" && sub.UID != types.UID("") {
 		dispatchOptions = append(dispatchOptions, kncloudevents.WithEventTypeAutoHandler(
 			f.eventTypeHandler,
-			&duckv1.KReference{
-				Name:       sub.Name,
-				Namespace:  sub.Namespace,
-				APIVersion: messagingv1.SchemeGroupVersion.String(),
-				Kind:       "Subscription",
-			},
+			subRef,
 			sub.UID,
 		))
 	}
 
+	if sub.FailureHistory {
+		dispatchOptions = append(dispatchOptions, kncloudevents.WithFailureHistory(subRef))
+	}
+
+	if sub.Throttle != nil {
+		dispatchOptions = append(dispatchOptions, kncloudevents.WithThrottle(sub.Throttle))
+	}
+
+	if router := f.replyRouter(sub); router != nil {
+		dispatchOptions = append(dispatchOptions, kncloudevents.WithReplyRouter(router))
+	}
+
 	if sub.ServiceAccount != nil {
 		dispatchOptions = append(dispatchOptions, kncloudevents.WithOIDCAuthentication(sub.ServiceAccount))
 	}
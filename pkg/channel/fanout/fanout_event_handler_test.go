@@ -21,19 +21,24 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/utils/pointer"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/injection"
 
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	"knative.dev/eventing/pkg/auth"
+	"knative.dev/eventing/pkg/channel/wal"
 	"knative.dev/eventing/pkg/eventingtls"
 	"knative.dev/eventing/pkg/kncloudevents"
 
@@ -70,11 +75,16 @@ func TestSubscriberSpecToFanoutConfig(t *testing.T) {
 	dlsCACerts := "dls-certs"
 	linear := eventingduckv1.BackoffPolicyLinear
 	delay := "PT1S"
+	history := eventingduckv1.DeadLetterFormatHistory
 	spec := &eventingduckv1.SubscriberSpec{
 		SubscriberURI:     apis.HTTP("subscriber.example.com"),
 		SubscriberCACerts: &subscriberCACerts,
 		ReplyURI:          apis.HTTP("reply.example.com"),
 		ReplyCACerts:      &replyCACerts,
+		ReplyRoutes: []eventingduckv1.SubscriberReplyRoute{{
+			Filters: []runtime.RawExtension{{Raw: []byte(`{"exact":{"type":"order.rejected"}}`)}},
+			URI:     apis.HTTP("rejected.example.com"),
+		}},
 		Delivery: &eventingduckv1.DeliverySpec{
 			DeadLetterSink: &duckv1.Destination{
 				Ref: &duckv1.KReference{
@@ -86,9 +96,14 @@ func TestSubscriberSpecToFanoutConfig(t *testing.T) {
 				URI:     apis.HTTP("dls.example.com"),
 				CACerts: &dlsCACerts,
 			},
-			Retry:         pointer.Int32(3),
-			BackoffPolicy: &linear,
-			BackoffDelay:  &delay,
+			Retry:            pointer.Int32(3),
+			BackoffPolicy:    &linear,
+			BackoffDelay:     &delay,
+			RetryOn:          []string{"400"},
+			NoRetryOn:        []string{"503"},
+			DeadLetterFormat: &history,
+			MaxConcurrency:   pointer.Int32(5),
+			RateLimit:        &eventingduckv1.RateLimitSpec{RequestsPerSecond: 10},
 		},
 	}
 	want := Subscription{
@@ -100,6 +115,12 @@ func TestSubscriberSpecToFanoutConfig(t *testing.T) {
 			URL:     apis.HTTP("reply.example.com"),
 			CACerts: &replyCACerts,
 		},
+		ReplyRoutes: []ReplyRoute{{
+			Filters: []eventingv1.SubscriptionsAPIFilter{{
+				Exact: map[string]string{"type": "order.rejected"},
+			}},
+			Destination: duckv1.Addressable{URL: apis.HTTP("rejected.example.com")},
+		}},
 		DeadLetter: &duckv1.Addressable{
 			URL:     apis.HTTP("dls.example.com"),
 			CACerts: &dlsCACerts,
@@ -108,6 +129,14 @@ func TestSubscriberSpecToFanoutConfig(t *testing.T) {
 			RetryMax:      3,
 			BackoffPolicy: &linear,
 			BackoffDelay:  &delay,
+			RetryOn:       []string{"400"},
+			NoRetryOn:     []string{"503"},
+		},
+		FailureHistory: true,
+		Throttle: &kncloudevents.ThrottleConfig{
+			MaxConcurrency:    5,
+			RequestsPerSecond: 10,
+			Burst:             10,
 		},
 	}
 	got, err := SubscriberSpecToFanoutConfig(*spec)
@@ -325,6 +354,331 @@ func TestFanoutEventHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestFanoutEventHandler_Log(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	received := make(chan string, 10)
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("ce-id")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriber.Close()
+
+	// An event was accepted before the dispatcher restarted, but not dispatched.
+	dir := t.TempDir()
+	l, err := wal.Open(dir, wal.DefaultSegmentSize, wal.DefaultMaxSize)
+	if err != nil {
+		t.Fatal("wal.Open() =", err)
+	}
+	if _, err := l.SetConsumers([]string{"sub-uid"}); err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	pending := makeCloudEvent()
+	pending.SetID("pending")
+	if _, err := l.Append(&pending, nil); err != nil {
+		t.Fatal("Append() =", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+	l, err = wal.Open(dir, wal.DefaultSegmentSize, wal.DefaultMaxSize)
+	if err != nil {
+		t.Fatal("wal.Open() =", err)
+	}
+	defer l.Close()
+
+	h, err := NewFanoutEventHandler(
+		zap.NewNop(),
+		Config{
+			Subscriptions: []Subscription{{
+				Subscriber: duckv1.Addressable{URL: apis.HTTP(subscriber.URL[7:])},
+				Namespace:  "ns",
+				UID:        "sub-uid",
+			}},
+			Log: l,
+		},
+		channel.NewStatsReporter("testcontainer", "testpod"),
+		nil,
+		nil,
+		nil,
+		kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx)),
+	)
+	if err != nil {
+		t.Fatal("NewHandler failed =", err)
+	}
+
+	if id := receive(t, received); id != "pending" {
+		t.Errorf("expected the pending event to be dispatched again, got %q", id)
+	}
+
+	event := makeCloudEvent()
+	req := httptest.NewRequest(http.MethodPost, "http://channelname.channelnamespace/", nil)
+	if err := bindingshttp.WriteRequest(ctx, binding.ToMessage(&event), req); err != nil {
+		t.Fatal("WriteRequest =", err)
+	}
+	resp := httptest.ResponseRecorder{}
+	h.ServeHTTP(&resp, req)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, resp.Code)
+	}
+	if id := receive(t, received); id != event.ID() {
+		t.Errorf("expected the event to be dispatched, got %q", id)
+	}
+
+	// Both events are committed.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		offsets, _ := os.ReadFile(filepath.Join(dir, "offsets.json"))
+		if string(offsets) == `{"sub-uid":2}` {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the offsets to be committed, got %s", offsets)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFanoutEventHandler_LogDispatchFailure(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	received := make(chan string, 10)
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("ce-id")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer subscriber.Close()
+
+	dir := t.TempDir()
+	l, err := wal.Open(dir, wal.DefaultSegmentSize, wal.DefaultMaxSize)
+	if err != nil {
+		t.Fatal("wal.Open() =", err)
+	}
+
+	h, err := NewFanoutEventHandler(
+		zap.NewNop(),
+		Config{
+			Subscriptions: []Subscription{{
+				Subscriber: duckv1.Addressable{URL: apis.HTTP(subscriber.URL[7:])},
+				Namespace:  "ns",
+				UID:        "sub-uid",
+			}},
+			Log: l,
+		},
+		channel.NewStatsReporter("testcontainer", "testpod"),
+		nil,
+		nil,
+		nil,
+		kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx)),
+	)
+	if err != nil {
+		t.Fatal("NewHandler failed =", err)
+	}
+
+	event := makeCloudEvent()
+	req := httptest.NewRequest(http.MethodPost, "http://channelname.channelnamespace/", nil)
+	if err := bindingshttp.WriteRequest(ctx, binding.ToMessage(&event), req); err != nil {
+		t.Fatal("WriteRequest =", err)
+	}
+	resp := httptest.ResponseRecorder{}
+	h.ServeHTTP(&resp, req)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, resp.Code)
+	}
+	if id := receive(t, received); id != event.ID() {
+		t.Errorf("expected the event to be dispatched, got %q", id)
+	}
+
+	// The event which failed to be dispatched is committed, so that it doesn't hold back the
+	// removal of the segments.
+	time.Sleep(100 * time.Millisecond)
+	if err := l.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+	offsets, err := os.ReadFile(filepath.Join(dir, "offsets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(offsets) != `{"sub-uid":1}` {
+		t.Errorf("expected the offset to be committed, got %s", offsets)
+	}
+}
+
+func TestFanoutEventHandler_Queue(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	received := make(chan string, 10)
+	unblock := make(chan struct{})
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("ce-id")
+		<-unblock
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriber.Close()
+	defer close(unblock)
+
+	h, err := NewFanoutEventHandler(
+		zap.NewNop(),
+		Config{
+			Subscriptions: []Subscription{{
+				Subscriber: duckv1.Addressable{URL: apis.HTTP(subscriber.URL[7:])},
+				Namespace:  "ns",
+				UID:        "sub-uid",
+			}},
+			AsyncHandler: true,
+			QueueDepth:   1,
+			QueueWorkers: 1,
+		},
+		channel.NewStatsReporter("testcontainer", "testpod"),
+		nil,
+		nil,
+		nil,
+		kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx)),
+	)
+	if err != nil {
+		t.Fatal("NewHandler failed =", err)
+	}
+
+	send := func(id string) int {
+		event := makeCloudEvent()
+		event.SetID(id)
+		req := httptest.NewRequest(http.MethodPost, "http://channelname.channelnamespace/", nil)
+		if err := bindingshttp.WriteRequest(ctx, binding.ToMessage(&event), req); err != nil {
+			t.Fatal("WriteRequest =", err)
+		}
+		resp := httptest.ResponseRecorder{}
+		h.ServeHTTP(&resp, req)
+		return resp.Code
+	}
+
+	// The worker is busy with the first event, the second one waits in the queue.
+	if code := send("1"); code != http.StatusAccepted {
+		t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, code)
+	}
+	if id := receive(t, received); id != "1" {
+		t.Errorf("expected the first event to be dispatched, got %q", id)
+	}
+	if code := send("2"); code != http.StatusAccepted {
+		t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, code)
+	}
+
+	// The queue is full.
+	if code := send("3"); code != http.StatusTooManyRequests {
+		t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusTooManyRequests, code)
+	}
+
+	unblock <- struct{}{}
+	if id := receive(t, received); id != "2" {
+		t.Errorf("expected the queued event to be dispatched, got %q", id)
+	}
+	unblock <- struct{}{}
+
+	// There is room in the queue again.
+	if code := send("4"); code != http.StatusAccepted {
+		t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, code)
+	}
+	if id := receive(t, received); id != "4" {
+		t.Errorf("expected the event to be dispatched, got %q", id)
+	}
+}
+
+func TestFanoutEventHandler_ReplyRoutes(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	// The subscriber rejects the odd orders.
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		replyType := "order.approved"
+		if r.Header.Get("ce-id") == "1" {
+			replyType = "order.rejected"
+		}
+		w.Header().Set("ce-specversion", "1.0")
+		w.Header().Set("ce-id", r.Header.Get("ce-id"))
+		w.Header().Set("ce-type", replyType)
+		w.Header().Set("ce-source", "subscriber")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer subscriber.Close()
+
+	record := func(received chan<- string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- r.Header.Get("ce-id")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+	}
+	rejected := make(chan string, 10)
+	rejectedServer := record(rejected)
+	defer rejectedServer.Close()
+	replied := make(chan string, 10)
+	replyServer := record(replied)
+	defer replyServer.Close()
+
+	h, err := NewFanoutEventHandler(
+		zap.NewNop(),
+		Config{
+			Subscriptions: []Subscription{{
+				Subscriber: duckv1.Addressable{URL: apis.HTTP(subscriber.URL[7:])},
+				Reply:      &duckv1.Addressable{URL: apis.HTTP(replyServer.URL[7:])},
+				ReplyRoutes: []ReplyRoute{{
+					Filters: []eventingv1.SubscriptionsAPIFilter{{
+						Exact: map[string]string{"type": "order.rejected"},
+					}},
+					Destination: duckv1.Addressable{URL: apis.HTTP(rejectedServer.URL[7:])},
+				}},
+				Namespace: "ns",
+				UID:       "sub-uid",
+			}},
+		},
+		channel.NewStatsReporter("testcontainer", "testpod"),
+		nil,
+		nil,
+		nil,
+		kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx)),
+	)
+	if err != nil {
+		t.Fatal("NewHandler failed =", err)
+	}
+
+	for _, id := range []string{"1", "2"} {
+		event := makeCloudEvent()
+		event.SetID(id)
+		req := httptest.NewRequest(http.MethodPost, "http://channelname.channelnamespace/", nil)
+		if err := bindingshttp.WriteRequest(ctx, binding.ToMessage(&event), req); err != nil {
+			t.Fatal("WriteRequest =", err)
+		}
+		resp := httptest.ResponseRecorder{}
+		h.ServeHTTP(&resp, req)
+		if resp.Code != http.StatusAccepted {
+			t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, resp.Code)
+		}
+	}
+
+	if id := receive(t, rejected); id != "1" {
+		t.Errorf("expected the rejected reply to be routed, got %q", id)
+	}
+	if id := receive(t, replied); id != "2" {
+		t.Errorf("expected the approved reply to be sent to the reply, got %q", id)
+	}
+}
+
+func receive(t *testing.T, received <-chan string) string {
+	t.Helper()
+	select {
+	case id := <-received:
+		return id
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the subscriber")
+		return ""
+	}
+}
+
 func testFanoutEventHandler(t *testing.T, async bool, receiverFunc channel.EventReceiverFunc, timeout time.Duration, inSubs []Subscription, subscriberHandler func(http.ResponseWriter, *http.Request), subscriberReqs int, replierHandler func(http.ResponseWriter, *http.Request), replierReqs int, expectedStatus int) {
 	ctx := context.Background()
 	ctx, _ = fakekubeclient.With(ctx)
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fanout
+
+import (
+	"sync"
+
+	"knative.dev/eventing/pkg/channel"
+)
+
+// DefaultQueueWorkers is the number of events dispatched concurrently to a Subscription when the
+// queues are enabled but their workers aren't configured.
+const DefaultQueueWorkers = 1
+
+// queue holds the events waiting to be dispatched to a Subscription. The workers dispatching them
+// are started when events are queued, and stop once the queue is empty, so that a queue doesn't
+// need to be closed.
+type queue struct {
+	sub  Subscription
+	jobs chan func()
+
+	// workers is the number of running workers, guarded by queues.mu.
+	workers    int
+	maxWorkers int
+}
+
+// queues holds the queue of each Subscription of a FanoutEventHandler.
+type queues struct {
+	mu       sync.Mutex
+	queues   map[string]*queue
+	depth    int
+	workers  int
+	reporter channel.StatsReporter
+}
+
+func newQueues(depth, workers int, reporter channel.StatsReporter) *queues {
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+	return &queues{
+		queues:   make(map[string]*queue),
+		depth:    depth,
+		workers:  workers,
+		reporter: reporter,
+	}
+}
+
+// set creates the queues of the new Subscriptions and removes the queues of the Subscriptions that
+// were removed. The events already queued for removed Subscriptions are still dispatched.
+func (qs *queues) set(subs []Subscription) {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	queues := make(map[string]*queue, len(subs))
+	for _, sub := range subs {
+		key := subscriptionKey(sub)
+		if q, ok := qs.queues[key]; ok {
+			q.sub = sub
+			queues[key] = q
+			continue
+		}
+		queues[key] = &queue{
+			sub:        sub,
+			jobs:       make(chan func(), qs.depth),
+			maxWorkers: qs.workers,
+		}
+	}
+	qs.queues = queues
+}
+
+// enqueue adds the job dispatching the event to each Subscription to its queue. Either all the jobs
+// are queued, or none of them is and a channel.QueueFullError is returned, so that the sender can
+// send the event again without it being dispatched twice to some Subscriptions. The job of a
+// Subscription without queue, because it was removed, is run right away.
+func (qs *queues) enqueue(subs []Subscription, args channel.ReportArgs, job func(Subscription)) error {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	for _, sub := range subs {
+		if q, ok := qs.queues[subscriptionKey(sub)]; ok && len(q.jobs) == cap(q.jobs) {
+			args.Ns = sub.Namespace
+			if sub.Subscriber.URL != nil {
+				args.EventScheme = sub.Subscriber.URL.Scheme
+			}
+			if qs.reporter != nil {
+				_ = qs.reporter.ReportEventDropped(&args, sub.Name)
+			}
+			return channel.QueueFullError(subscriptionKey(sub))
+		}
+	}
+
+	for _, sub := range subs {
+		sub := sub
+		q, ok := qs.queues[subscriptionKey(sub)]
+		if !ok {
+			go job(sub)
+			continue
+		}
+		// There is room in the queue since the workers only take jobs from it.
+		q.jobs <- func() { job(sub) }
+		qs.reportDepth(q)
+		if q.workers < q.maxWorkers {
+			q.workers++
+			go qs.work(q)
+		}
+	}
+	return nil
+}
+
+// work runs the jobs of the queue until it is empty.
+func (qs *queues) work(q *queue) {
+	for {
+		select {
+		case job := <-q.jobs:
+			qs.mu.Lock()
+			qs.reportDepth(q)
+			qs.mu.Unlock()
+			job()
+		default:
+			qs.mu.Lock()
+			// Jobs are only queued while holding the lock, check again before stopping so that
+			// none is left behind without worker.
+			if len(q.jobs) == 0 {
+				q.workers--
+				qs.mu.Unlock()
+				return
+			}
+			qs.mu.Unlock()
+		}
+	}
+}
+
+func (qs *queues) reportDepth(q *queue) {
+	if qs.reporter != nil {
+		_ = qs.reporter.ReportQueueDepth(&channel.ReportArgs{Ns: q.sub.Namespace}, q.sub.Name, len(q.jobs))
+	}
+}
@@ -47,6 +47,22 @@ var (
 		stats.UnitMilliseconds,
 	)
 
+	// queueDepthM records the number of events waiting to be dispatched to a subscriber
+	// by the channel.
+	queueDepthM = stats.Int64(
+		"event_queue_depth",
+		"Number of events waiting to be dispatched to a subscriber by the channel",
+		stats.UnitDimensionless,
+	)
+
+	// droppedCountM is a counter which records the number of events rejected by the
+	// channel because the queue of a subscriber is full.
+	droppedCountM = stats.Int64(
+		"event_dropped_count",
+		"Number of events rejected by the channel because the queue of a subscriber is full",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -57,6 +73,7 @@ var (
 	eventScheme          = tag.MustNewKey(eventingmetrics.LabelEventScheme)
 	responseCodeKey      = tag.MustNewKey(eventingmetrics.LabelResponseCode)
 	responseCodeClassKey = tag.MustNewKey(eventingmetrics.LabelResponseCodeClass)
+	subscriptionKey      = tag.MustNewKey(eventingmetrics.LabelSubscriptionName)
 )
 
 type ReportArgs struct {
@@ -72,6 +89,8 @@ func init() {
 type StatsReporter interface {
 	ReportEventCount(args *ReportArgs, responseCode int) error
 	ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error
+	ReportQueueDepth(args *ReportArgs, subscription string, depth int) error
+	ReportEventDropped(args *ReportArgs, subscription string) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -116,6 +135,18 @@ func register() {
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 500, 1000, 5000, 10000
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: queueDepthM.Description(),
+			Measure:     queueDepthM,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{namespaceKey, subscriptionKey, UniqueTagKey, ContainerTagKey},
+		},
+		&view.View{
+			Description: droppedCountM.Description(),
+			Measure:     droppedCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{namespaceKey, subscriptionKey, eventTypeKey, eventScheme, UniqueTagKey, ContainerTagKey},
+		},
 	)
 	if err != nil {
 		log.Print("failed to register opencensus views, " + err.Error())
@@ -143,6 +174,38 @@ func (r *reporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d
 	return nil
 }
 
+// ReportQueueDepth captures the number of events waiting to be dispatched to a subscriber.
+func (r *reporter) ReportQueueDepth(args *ReportArgs, subscription string, depth int) error {
+	ctx, err := tag.New(
+		emptyContext,
+		tag.Insert(namespaceKey, args.Ns),
+		tag.Insert(subscriptionKey, subscription),
+		tag.Insert(ContainerTagKey, r.container),
+		tag.Insert(UniqueTagKey, r.uniqueName))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, queueDepthM.M(int64(depth)))
+	return nil
+}
+
+// ReportEventDropped captures the count of events rejected because the queue of a subscriber is full.
+func (r *reporter) ReportEventDropped(args *ReportArgs, subscription string) error {
+	ctx, err := tag.New(
+		emptyContext,
+		tag.Insert(namespaceKey, args.Ns),
+		tag.Insert(subscriptionKey, subscription),
+		tag.Insert(eventTypeKey, args.EventType),
+		tag.Insert(eventScheme, args.EventScheme),
+		tag.Insert(ContainerTagKey, r.container),
+		tag.Insert(UniqueTagKey, r.uniqueName))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, droppedCountM.M(1))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
 	return tag.New(
 		emptyContext,
@@ -63,6 +63,33 @@ func TestStatsReporter(t *testing.T) {
 		return r.ReportEventDispatchTime(args, http.StatusAccepted, 9100*time.Millisecond)
 	})
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
+
+	// test ReportQueueDepth
+	expectSuccess(t, func() error {
+		return r.ReportQueueDepth(args, "testsubscription", 3)
+	})
+	expectSuccess(t, func() error {
+		return r.ReportQueueDepth(args, "testsubscription", 2)
+	})
+	metricstest.CheckLastValueData(t, "event_queue_depth", map[string]string{
+		metrics.LabelNamespaceName:    "testns",
+		metrics.LabelSubscriptionName: "testsubscription",
+		LabelUniqueName:               "testpod",
+		LabelContainerName:            "testcontainer",
+	}, 2)
+
+	// test ReportEventDropped
+	expectSuccess(t, func() error {
+		return r.ReportEventDropped(args, "testsubscription")
+	})
+	metricstest.CheckCountData(t, "event_dropped_count", map[string]string{
+		metrics.LabelNamespaceName:    "testns",
+		metrics.LabelSubscriptionName: "testsubscription",
+		metrics.LabelEventType:        "testeventtype",
+		metrics.LabelEventScheme:      "http",
+		LabelUniqueName:               "testpod",
+		LabelContainerName:            "testcontainer",
+	}, 1)
 }
 
 func expectSuccess(t *testing.T, f func() error) {
@@ -80,6 +107,8 @@ func resetMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
 	metricstest.Unregister(
 		"event_count",
-		"event_dispatch_latencies")
+		"event_dispatch_latencies",
+		"event_queue_depth",
+		"event_dropped_count")
 	register()
 }
@@ -0,0 +1,516 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wal provides a segmented write-ahead log of the events accepted by a channel, along with
+// the offset of the events dispatched to each of its consumers, so that the events not yet
+// dispatched survive the restarts of the dispatcher.
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+const (
+	// DefaultSegmentSize is the size a segment grows to before the log starts a new one.
+	DefaultSegmentSize = 64 << 20
+
+	// DefaultMaxSize is the size the segments of a log can use before the oldest ones are
+	// dropped.
+	DefaultMaxSize = 1 << 30
+
+	segmentSuffix = ".log"
+	offsetsFile   = "offsets.json"
+
+	// flushInterval is how often the committed offsets are stored, the entries committed since
+	// the last flush are dispatched again when the log is opened.
+	flushInterval = time.Second
+)
+
+// Entry is an event stored in the log.
+type Entry struct {
+	Offset  uint64       `json:"offset"`
+	Headers http.Header  `json:"headers,omitempty"`
+	Event   *event.Event `json:"event"`
+}
+
+// Range is the range of offsets from From included to To excluded.
+type Range struct {
+	From uint64
+	To   uint64
+}
+
+// consumer tracks the entries dispatched to a consumer. The entries can be dispatched out of order,
+// the committed offset only moves past the entries dispatched without gap.
+type consumer struct {
+	committed uint64
+	done      map[uint64]struct{}
+}
+
+// advance moves the committed offset past the entries dispatched without gap, and returns whether
+// it moved.
+func (c *consumer) advance() bool {
+	advanced := false
+	for {
+		if _, ok := c.done[c.committed]; !ok {
+			return advanced
+		}
+		delete(c.done, c.committed)
+		c.committed++
+		advanced = true
+	}
+}
+
+// Log is a write-ahead log made of segments, the files named after the offset of their first
+// entry. The entries are appended to the last segment, and the segments are removed once their
+// entries were dispatched to all the consumers, or once the log exceeds its maximum size. The
+// committed offsets are stored periodically.
+type Log struct {
+	dir         string
+	segmentSize int64
+	maxSize     int64
+
+	// flushMu serializes the flushes of the committed offsets, which happen without holding mu.
+	flushMu sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+
+	mu sync.Mutex
+	// dirty is whether the committed offsets changed since they were last stored.
+	dirty bool
+	// bases are the offsets of the first entries of the segments, the last one is the active
+	// segment.
+	bases      []uint64
+	active     *os.File
+	activeSize int64
+	next       uint64
+	consumers  map[string]*consumer
+	// recovered are the entries not yet dispatched to the consumers when the log was opened,
+	// until the consumers are set.
+	recovered map[string]Range
+}
+
+// Open opens the log stored in the directory, creating it when it doesn't exist. The last entry
+// is discarded when it was only partially written. Once its segments exceed maxSize bytes, the
+// oldest ones are dropped even when their entries weren't dispatched to all the consumers.
+func Open(dir string, segmentSize, maxSize int64) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the log directory: %w", err)
+	}
+	l := &Log{
+		dir:         dir,
+		segmentSize: segmentSize,
+		maxSize:     maxSize,
+		consumers:   make(map[string]*consumer),
+		recovered:   make(map[string]Range),
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the log segments: %w", err)
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), segmentSuffix) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		l.bases = append(l.bases, base)
+	}
+	sort.Slice(l.bases, func(i, j int) bool { return l.bases[i] < l.bases[j] })
+
+	if len(l.bases) == 0 {
+		if err := l.openSegment(0); err != nil {
+			return nil, err
+		}
+	} else if err := l.recoverActiveSegment(); err != nil {
+		return nil, err
+	}
+
+	if err := l.loadOffsets(); err != nil {
+		_ = l.active.Close()
+		return nil, err
+	}
+	go l.flushPeriodically()
+	return l, nil
+}
+
+// recoverActiveSegment opens the last segment for appending, after truncating it to its last
+// complete entry.
+func (l *Log) recoverActiveSegment() error {
+	base := l.bases[len(l.bases)-1]
+	path := l.segmentPath(base)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the active segment: %w", err)
+	}
+
+	l.next = base
+	valid := 0
+	for valid < len(data) {
+		end := bytes.IndexByte(data[valid:], '\n')
+		if end < 0 {
+			break
+		}
+		var entry Entry
+		if err := json.Unmarshal(data[valid:valid+end], &entry); err != nil {
+			break
+		}
+		l.next = entry.Offset + 1
+		valid += end + 1
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the active segment: %w", err)
+	}
+	if err := f.Truncate(int64(valid)); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to truncate the active segment: %w", err)
+	}
+	if _, err := f.Seek(int64(valid), io.SeekStart); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to seek the end of the active segment: %w", err)
+	}
+	l.active = f
+	l.activeSize = int64(valid)
+	return nil
+}
+
+func (l *Log) loadOffsets() error {
+	data, err := os.ReadFile(filepath.Join(l.dir, offsetsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the offsets: %w", err)
+	}
+	offsets := make(map[string]uint64)
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return fmt.Errorf("failed to parse the offsets: %w", err)
+	}
+	for name, committed := range offsets {
+		if committed < l.bases[0] {
+			committed = l.bases[0]
+		}
+		if committed > l.next {
+			committed = l.next
+		}
+		l.consumers[name] = &consumer{committed: committed, done: make(map[uint64]struct{})}
+		if committed < l.next {
+			l.recovered[name] = Range{From: committed, To: l.next}
+		}
+	}
+	return nil
+}
+
+func (l *Log) openSegment(base uint64) error {
+	f, err := os.OpenFile(l.segmentPath(base), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create the log segment: %w", err)
+	}
+	l.bases = append(l.bases, base)
+	l.active = f
+	l.activeSize = 0
+	l.next = base
+	return nil
+}
+
+func (l *Log) segmentPath(base uint64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%020d%s", base, segmentSuffix))
+}
+
+// Append durably stores the event and returns its offset.
+func (l *Log) Append(e *event.Event, headers http.Header) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := json.Marshal(Entry{Offset: l.next, Headers: headers, Event: e})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal the entry: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := l.active.Write(data); err != nil {
+		l.discardLocked()
+		return 0, fmt.Errorf("failed to write the entry: %w", err)
+	}
+	if err := l.active.Sync(); err != nil {
+		l.discardLocked()
+		return 0, fmt.Errorf("failed to sync the entry: %w", err)
+	}
+	l.activeSize += int64(len(data))
+
+	offset := l.next
+	l.next++
+	if l.activeSize >= l.segmentSize {
+		if err := l.active.Close(); err != nil {
+			return offset, fmt.Errorf("failed to close the full segment: %w", err)
+		}
+		if err := l.openSegment(l.next); err != nil {
+			return offset, err
+		}
+		if err := l.dropSegmentsLocked(); err != nil {
+			return offset, err
+		}
+	}
+	return offset, nil
+}
+
+// dropSegmentsLocked removes the oldest segments while the log exceeds its maximum size, the
+// consumers which didn't receive their entries skip them.
+func (l *Log) dropSegmentsLocked() error {
+	for len(l.bases) > 1 && int64(len(l.bases))*l.segmentSize > l.maxSize {
+		dropped, next := l.bases[0], l.bases[1]
+		for _, c := range l.consumers {
+			if c.committed >= next {
+				continue
+			}
+			for offset := range c.done {
+				if offset < next {
+					delete(c.done, offset)
+				}
+			}
+			c.committed = next
+			c.advance()
+			l.dirty = true
+		}
+		l.bases = l.bases[1:]
+		if err := os.Remove(l.segmentPath(dropped)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove the oldest segment: %w", err)
+		}
+	}
+	return nil
+}
+
+// discardLocked removes what was written of an entry which failed to be appended, so that the
+// next entries aren't lost when the log is opened again.
+func (l *Log) discardLocked() {
+	_ = l.active.Truncate(l.activeSize)
+	_, _ = l.active.Seek(l.activeSize, io.SeekStart)
+}
+
+// SetConsumers sets the consumers the entries are dispatched to. The new consumers start after
+// the last entry, and the removed consumers are forgotten. It returns the ranges of the entries
+// the existing consumers didn't receive before the log was opened, only the first time they are
+// set. The offsets of the consumers are stored before it returns, so that the entries appended
+// next are recovered for the new consumers.
+func (l *Log) SetConsumers(names []string) (map[string]Range, error) {
+	l.mu.Lock()
+
+	set := make(map[string]struct{}, len(names))
+	pending := make(map[string]Range)
+	for _, name := range names {
+		set[name] = struct{}{}
+		if _, ok := l.consumers[name]; !ok {
+			l.consumers[name] = &consumer{committed: l.next, done: make(map[uint64]struct{})}
+		}
+		if r, ok := l.recovered[name]; ok {
+			pending[name] = r
+			delete(l.recovered, name)
+		}
+	}
+	for name := range l.consumers {
+		if _, ok := set[name]; !ok {
+			delete(l.consumers, name)
+			delete(l.recovered, name)
+		}
+	}
+	l.dirty = true
+	l.mu.Unlock()
+
+	return pending, l.Flush()
+}
+
+// Commit records that the entry was handled by the consumer, whether it was dispatched or not,
+// so that it isn't dispatched again. The committed offset is stored by the next flush.
+func (l *Log) Commit(name string, offset uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, ok := l.consumers[name]
+	if !ok || offset < c.committed {
+		return nil
+	}
+	c.done[offset] = struct{}{}
+	if c.advance() {
+		l.dirty = true
+	}
+	return nil
+}
+
+// Flush stores the committed offsets, when they changed, and removes the segments dispatched to
+// all the consumers.
+func (l *Log) Flush() error {
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+
+	l.mu.Lock()
+	if !l.dirty {
+		l.mu.Unlock()
+		return nil
+	}
+	offsets := make(map[string]uint64, len(l.consumers))
+	low := l.next
+	for name, c := range l.consumers {
+		offsets[name] = c.committed
+		if c.committed < low {
+			low = c.committed
+		}
+	}
+	l.dirty = false
+	l.mu.Unlock()
+
+	if err := l.storeOffsets(offsets); err != nil {
+		l.mu.Lock()
+		l.dirty = true
+		l.mu.Unlock()
+		return err
+	}
+
+	// The active segment is always kept, it holds the offset of the next entry.
+	var dispatched []uint64
+	l.mu.Lock()
+	for len(l.bases) > 1 && l.bases[1] <= low {
+		dispatched = append(dispatched, l.bases[0])
+		l.bases = l.bases[1:]
+	}
+	l.mu.Unlock()
+	for _, base := range dispatched {
+		if err := os.Remove(l.segmentPath(base)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove the dispatched segment: %w", err)
+		}
+	}
+	return nil
+}
+
+func (l *Log) storeOffsets(offsets map[string]uint64) error {
+	data, err := json.Marshal(offsets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the offsets: %w", err)
+	}
+	path := filepath.Join(l.dir, offsetsFile)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write the offsets: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to store the offsets: %w", err)
+	}
+	return nil
+}
+
+// flushPeriodically flushes the committed offsets until the log is closed, a failed flush is
+// retried by the next one.
+func (l *Log) flushPeriodically() {
+	defer close(l.stopped)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			_ = l.Flush()
+		}
+	}
+}
+
+// Read calls fn with the entries of the range, in order.
+func (l *Log) Read(r Range, fn func(Entry) error) error {
+	l.mu.Lock()
+	bases := append([]uint64(nil), l.bases...)
+	l.mu.Unlock()
+
+	for i, base := range bases {
+		if base >= r.To {
+			break
+		}
+		if i+1 < len(bases) && bases[i+1] <= r.From {
+			continue
+		}
+		if err := l.readSegment(base, r, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Log) readSegment(base uint64, r Range, fn func(Entry) error) error {
+	f, err := os.Open(l.segmentPath(base))
+	if errors.Is(err, os.ErrNotExist) {
+		// The segment was dropped meanwhile.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open the log segment: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the log segment: %w", err)
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("failed to parse the log entry: %w", err)
+		}
+		if entry.Offset < r.From {
+			continue
+		}
+		if entry.Offset >= r.To {
+			return nil
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// Close flushes the committed offsets and closes the active segment.
+func (l *Log) Close() error {
+	close(l.stop)
+	<-l.stopped
+	flushErr := l.Flush()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.active.Close(); err != nil {
+		return err
+	}
+	return flushErr
+}
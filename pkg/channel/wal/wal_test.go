@@ -0,0 +1,282 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wal
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, DefaultSegmentSize, DefaultMaxSize)
+	if err != nil {
+		t.Fatal("Open() =", err)
+	}
+	if _, err := l.SetConsumers([]string{"a", "b"}); err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		offset, err := l.Append(newEvent(i), http.Header{"Traceparent": []string{"tp"}})
+		if err != nil {
+			t.Fatal("Append() =", err)
+		}
+		if offset != uint64(i) {
+			t.Errorf("Append() = %d, want %d", offset, i)
+		}
+	}
+
+	// a received all the entries, out of order, b only the second one.
+	for _, offset := range []uint64{2, 0, 1} {
+		if err := l.Commit("a", offset); err != nil {
+			t.Fatal("Commit() =", err)
+		}
+	}
+	if err := l.Commit("b", 1); err != nil {
+		t.Fatal("Commit() =", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+
+	// The entries b didn't receive are recovered.
+	l, err = Open(dir, DefaultSegmentSize, DefaultMaxSize)
+	if err != nil {
+		t.Fatal("Open() =", err)
+	}
+	defer l.Close()
+	pending, err := l.SetConsumers([]string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	if diff := cmp.Diff(map[string]Range{"b": {From: 0, To: 3}}, pending); diff != "" {
+		t.Error("unexpected pending ranges (-want +got):", diff)
+	}
+	var ids []string
+	if err := l.Read(pending["b"], func(e Entry) error {
+		ids = append(ids, e.Event.ID())
+		if e.Headers.Get("Traceparent") != "tp" {
+			t.Errorf("unexpected headers %v", e.Headers)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal("Read() =", err)
+	}
+	if diff := cmp.Diff([]string{"0", "1", "2"}, ids); diff != "" {
+		t.Error("unexpected entries (-want +got):", diff)
+	}
+
+	// The recovered entries are only returned once.
+	pending, err = l.SetConsumers([]string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending ranges, got %v", pending)
+	}
+
+	// New entries continue after the recovered ones.
+	offset, err := l.Append(newEvent(3), nil)
+	if err != nil {
+		t.Fatal("Append() =", err)
+	}
+	if offset != 3 {
+		t.Errorf("Append() = %d, want 3", offset)
+	}
+}
+
+func TestLogSegments(t *testing.T) {
+	dir := t.TempDir()
+	// Every entry fills a segment.
+	l, err := Open(dir, 1, DefaultMaxSize)
+	if err != nil {
+		t.Fatal("Open() =", err)
+	}
+	defer l.Close()
+	if _, err := l.SetConsumers([]string{"a"}); err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := l.Append(newEvent(i), nil); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+	if got := segments(t, dir); got != 4 {
+		t.Errorf("expected 4 segments, got %d", got)
+	}
+
+	var ids []string
+	if err := l.Read(Range{From: 1, To: 3}, func(e Entry) error {
+		ids = append(ids, e.Event.ID())
+		return nil
+	}); err != nil {
+		t.Fatal("Read() =", err)
+	}
+	if diff := cmp.Diff([]string{"1", "2"}, ids); diff != "" {
+		t.Error("unexpected entries (-want +got):", diff)
+	}
+
+	// The dispatched segments are removed.
+	if err := l.Commit("a", 0); err != nil {
+		t.Fatal("Commit() =", err)
+	}
+	if err := l.Commit("a", 1); err != nil {
+		t.Fatal("Commit() =", err)
+	}
+	if got := segments(t, dir); got != 4 {
+		t.Errorf("expected the segments to be kept until the offsets are flushed, got %d", got)
+	}
+	if err := l.Flush(); err != nil {
+		t.Fatal("Flush() =", err)
+	}
+	if got := segments(t, dir); got != 2 {
+		t.Errorf("expected 2 segments, got %d", got)
+	}
+
+	// Without consumers, only the active segment is kept.
+	if _, err := l.SetConsumers(nil); err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	if got := segments(t, dir); got != 1 {
+		t.Errorf("expected 1 segment, got %d", got)
+	}
+}
+
+func TestLogMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	// Every entry fills a segment, and the log keeps 2 segments.
+	l, err := Open(dir, 1, 2)
+	if err != nil {
+		t.Fatal("Open() =", err)
+	}
+	if _, err := l.SetConsumers([]string{"a"}); err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := l.Append(newEvent(i), nil); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+	if got := segments(t, dir); got != 2 {
+		t.Errorf("expected 2 segments, got %d", got)
+	}
+
+	// The consumer skips the dropped entries, even though they were never dispatched to it.
+	if err := l.Commit("a", 2); err != nil {
+		t.Fatal("Commit() =", err)
+	}
+	var ids []string
+	if err := l.Read(Range{From: 0, To: 3}, func(e Entry) error {
+		ids = append(ids, e.Event.ID())
+		return nil
+	}); err != nil {
+		t.Fatal("Read() =", err)
+	}
+	if diff := cmp.Diff([]string{"2"}, ids); diff != "" {
+		t.Error("unexpected entries (-want +got):", diff)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+	offsets, err := os.ReadFile(filepath.Join(dir, offsetsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(offsets) != `{"a":3}` {
+		t.Errorf("expected the offset to skip the dropped entries, got %s", offsets)
+	}
+}
+
+func TestLogPartialEntry(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, DefaultSegmentSize, DefaultMaxSize)
+	if err != nil {
+		t.Fatal("Open() =", err)
+	}
+	if _, err := l.SetConsumers([]string{"a"}); err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	if _, err := l.Append(newEvent(0), nil); err != nil {
+		t.Fatal("Append() =", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+
+	// The dispatcher stopped while writing the second entry.
+	f, err := os.OpenFile(filepath.Join(dir, "00000000000000000000.log"), os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"offset":1,"event":`); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	l, err = Open(dir, DefaultSegmentSize, DefaultMaxSize)
+	if err != nil {
+		t.Fatal("Open() =", err)
+	}
+	defer l.Close()
+	pending, err := l.SetConsumers([]string{"a"})
+	if err != nil {
+		t.Fatal("SetConsumers() =", err)
+	}
+	if diff := cmp.Diff(map[string]Range{"a": {From: 0, To: 1}}, pending); diff != "" {
+		t.Error("unexpected pending ranges (-want +got):", diff)
+	}
+	offset, err := l.Append(newEvent(1), nil)
+	if err != nil {
+		t.Fatal("Append() =", err)
+	}
+	if offset != 1 {
+		t.Errorf("Append() = %d, want 1", offset)
+	}
+	var ids []string
+	if err := l.Read(Range{From: 0, To: 2}, func(e Entry) error {
+		ids = append(ids, e.Event.ID())
+		return nil
+	}); err != nil {
+		t.Fatal("Read() =", err)
+	}
+	if diff := cmp.Diff([]string{"0", "1"}, ids); diff != "" {
+		t.Error("unexpected entries (-want +got):", diff)
+	}
+}
+
+func newEvent(i int) *event.Event {
+	e := event.New()
+	e.SetID(string(rune('0' + i)))
+	e.SetType("type")
+	e.SetSource("source")
+	return &e
+}
+
+func segments(t *testing.T, dir string) int {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(matches)
+}
@@ -28,10 +28,18 @@ type FakeSinksV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeSinksV1alpha1) Aggregators(namespace string) v1alpha1.AggregatorInterface {
+	return &FakeAggregators{c, namespace}
+}
+
 func (c *FakeSinksV1alpha1) JobSinks(namespace string) v1alpha1.JobSinkInterface {
 	return &FakeJobSinks{c, namespace}
 }
 
+func (c *FakeSinksV1alpha1) ReplaySinks(namespace string) v1alpha1.ReplaySinkInterface {
+	return &FakeReplaySinks{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeSinksV1alpha1) RESTClient() rest.Interface {
@@ -18,4 +18,8 @@ limitations under the License.
 
 package v1alpha1
 
+type AggregatorExpansion interface{}
+
 type JobSinkExpansion interface{}
+
+type ReplaySinkExpansion interface{}
@@ -28,7 +28,9 @@ import (
 
 type SinksV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	AggregatorsGetter
 	JobSinksGetter
+	ReplaySinksGetter
 }
 
 // SinksV1alpha1Client is used to interact with features provided by the sinks.knative.dev group.
@@ -36,10 +38,18 @@ type SinksV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *SinksV1alpha1Client) Aggregators(namespace string) AggregatorInterface {
+	return newAggregators(c, namespace)
+}
+
 func (c *SinksV1alpha1Client) JobSinks(namespace string) JobSinkInterface {
 	return newJobSinks(c, namespace)
 }
 
+func (c *SinksV1alpha1Client) ReplaySinks(namespace string) ReplaySinkInterface {
+	return newReplaySinks(c, namespace)
+}
+
 // NewForConfig creates a new SinksV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -98,8 +98,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1().Subscriptions().Informer()}, nil
 
 		// Group=sinks.knative.dev, Version=v1alpha1
+	case sinksv1alpha1.SchemeGroupVersion.WithResource("aggregators"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sinks().V1alpha1().Aggregators().Informer()}, nil
 	case sinksv1alpha1.SchemeGroupVersion.WithResource("jobsinks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Sinks().V1alpha1().JobSinks().Informer()}, nil
+	case sinksv1alpha1.SchemeGroupVersion.WithResource("replaysinks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sinks().V1alpha1().ReplaySinks().Informer()}, nil
 
 		// Group=sources.knative.dev, Version=v1
 	case sourcesv1.SchemeGroupVersion.WithResource("apiserversources"):
@@ -24,8 +24,12 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Aggregators returns a AggregatorInformer.
+	Aggregators() AggregatorInformer
 	// JobSinks returns a JobSinkInformer.
 	JobSinks() JobSinkInformer
+	// ReplaySinks returns a ReplaySinkInformer.
+	ReplaySinks() ReplaySinkInformer
 }
 
 type version struct {
@@ -39,7 +43,17 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Aggregators returns a AggregatorInformer.
+func (v *version) Aggregators() AggregatorInformer {
+	return &aggregatorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // JobSinks returns a JobSinkInformer.
 func (v *version) JobSinks() JobSinkInformer {
 	return &jobSinkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ReplaySinks returns a ReplaySinkInformer.
+func (v *version) ReplaySinks() ReplaySinkInformer {
+	return &replaySinkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
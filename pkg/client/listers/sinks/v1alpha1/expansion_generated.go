@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// AggregatorListerExpansion allows custom methods to be added to
+// AggregatorLister.
+type AggregatorListerExpansion interface{}
+
+// AggregatorNamespaceListerExpansion allows custom methods to be added to
+// AggregatorNamespaceLister.
+type AggregatorNamespaceListerExpansion interface{}
+
 // JobSinkListerExpansion allows custom methods to be added to
 // JobSinkLister.
 type JobSinkListerExpansion interface{}
@@ -25,3 +33,11 @@ type JobSinkListerExpansion interface{}
 // JobSinkNamespaceListerExpansion allows custom methods to be added to
 // JobSinkNamespaceLister.
 type JobSinkNamespaceListerExpansion interface{}
+
+// ReplaySinkListerExpansion allows custom methods to be added to
+// ReplaySinkLister.
+type ReplaySinkListerExpansion interface{}
+
+// ReplaySinkNamespaceListerExpansion allows custom methods to be added to
+// ReplaySinkNamespaceLister.
+type ReplaySinkNamespaceListerExpansion interface{}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	"knative.dev/pkg/ptr"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
+)
+
+// Test Data Filter
+func BenchmarkDataFilter(b *testing.B) {
+	event := cetest.MinEvent()
+	_ = event.SetData(cloudevents.ApplicationJSON, []byte(`{"order": {"id": "1234", "region": "eu-west", "items": [{"sku": "abc"}]}}`))
+
+	RunFilterBenchmarks(b,
+		func(i interface{}) eventfilter.Filter {
+			filter, err := subscriptionsapi.NewDataFilter(i.(eventingv1.SubscriptionsAPIDataFilter))
+			if err != nil {
+				b.Fatalf("failed to create filter: %v", err)
+			}
+			return filter
+		},
+		FilterBenchmark{
+			name:   "Pass with exact match of a nested member",
+			arg:    eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.region", Exact: ptr.String("eu-west")},
+			events: []cloudevents.Event{event},
+		},
+		FilterBenchmark{
+			name:   "Pass with prefix match of an array element",
+			arg:    eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.items[0].sku", Prefix: ptr.String("ab")},
+			events: []cloudevents.Event{event},
+		},
+		FilterBenchmark{
+			name:   "No pass with exists match of a missing member",
+			arg:    eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.customer", Exists: ptr.Bool(true)},
+			events: []cloudevents.Event{event},
+		},
+	)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+	"knative.dev/eventing/pkg/eventfilter/index"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
+)
+
+const benchmarkBroker = "default"
+
+// Avoid DCE
+var Matched int
+
+type triggerFilter struct {
+	trigger *eventingv1.Trigger
+	filter  eventfilter.Filter
+}
+
+// BenchmarkTriggerMatching compares evaluating every Trigger filter of a Broker for each event
+// (which is what happens when the channel fans the event out to one Subscription per Trigger)
+// with looking up the candidate Triggers in the broker-wide index first.
+func BenchmarkTriggerMatching(b *testing.B) {
+	for _, n := range []int{10, 100, 2000} {
+		b.Run(fmt.Sprintf("%d attributes triggers", n), func(b *testing.B) {
+			runTriggerMatchingBenchmarks(b, index.AttributesMode, makeTriggers(n, func(i int) eventingv1.TriggerSpec {
+				return eventingv1.TriggerSpec{
+					Filter: &eventingv1.TriggerFilter{
+						Attributes: map[string]string{"type": fmt.Sprintf("dev.knative.type.%d", i)},
+					},
+				}
+			}))
+		})
+		b.Run(fmt.Sprintf("%d subscriptions API triggers", n), func(b *testing.B) {
+			runTriggerMatchingBenchmarks(b, index.SubscriptionsAPIMode, makeTriggers(n, func(i int) eventingv1.TriggerSpec {
+				var filter eventingv1.SubscriptionsAPIFilter
+				switch i % 3 {
+				case 0:
+					filter.Exact = map[string]string{"type": fmt.Sprintf("dev.knative.type.%d", i)}
+				case 1:
+					filter.Prefix = map[string]string{"type": fmt.Sprintf("dev.knative.type.%d.", i)}
+				default:
+					filter.Suffix = map[string]string{"source": fmt.Sprintf("/sources/%d", i)}
+				}
+				return eventingv1.TriggerSpec{
+					Filters: []eventingv1.SubscriptionsAPIFilter{filter},
+				}
+			}))
+		})
+	}
+}
+
+func runTriggerMatchingBenchmarks(b *testing.B, mode index.Mode, triggers []triggerFilter) {
+	events := []cloudevents.Event{
+		makeBenchmarkEvent("dev.knative.type.0", "/sources/0"),
+		makeBenchmarkEvent(fmt.Sprintf("dev.knative.type.%d", len(triggers)/2), "/sources/none"),
+		makeBenchmarkEvent("dev.knative.type.none", fmt.Sprintf("/sources/%d", len(triggers)-1)),
+		makeBenchmarkEvent("dev.knative.type.none", "/sources/none"),
+	}
+	n := len(events)
+
+	b.Run("Run: per trigger", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Matched = 0
+			for _, t := range triggers {
+				if t.filter.Filter(context.TODO(), events[i%n]) != eventfilter.FailFilter {
+					Matched++
+				}
+			}
+		}
+	})
+
+	idx := index.NewIndex()
+	filters := make(map[types.NamespacedName]eventfilter.Filter, len(triggers))
+	for _, t := range triggers {
+		idx.Set(t.trigger)
+		filters[types.NamespacedName{Namespace: t.trigger.Namespace, Name: t.trigger.Name}] = t.filter
+	}
+	broker := types.NamespacedName{Namespace: "default", Name: benchmarkBroker}
+
+	b.Run("Run: indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Matched = 0
+			for _, t := range idx.Candidates(broker, events[i%n], mode) {
+				f := filters[types.NamespacedName{Namespace: t.Namespace, Name: t.Name}]
+				if f.Filter(context.TODO(), events[i%n]) != eventfilter.FailFilter {
+					Matched++
+				}
+			}
+		}
+	})
+
+	for _, t := range triggers {
+		t.filter.Cleanup()
+	}
+}
+
+func makeTriggers(n int, spec func(i int) eventingv1.TriggerSpec) []triggerFilter {
+	triggers := make([]triggerFilter, 0, n)
+	for i := 0; i < n; i++ {
+		t := &eventingv1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      fmt.Sprintf("trigger-%d", i),
+			},
+			Spec: spec(i),
+		}
+		t.Spec.Broker = benchmarkBroker
+
+		var filter eventfilter.Filter
+		if len(t.Spec.Filters) > 0 {
+			filter = subscriptionsapi.NewAllFilter(materialize(t.Spec.Filters[0]))
+		} else {
+			filter = attributes.NewAttributesFilter(t.Spec.Filter.Attributes)
+		}
+		triggers = append(triggers, triggerFilter{trigger: t, filter: filter})
+	}
+	return triggers
+}
+
+func materialize(f eventingv1.SubscriptionsAPIFilter) eventfilter.Filter {
+	var filter eventfilter.Filter
+	var err error
+	switch {
+	case len(f.Exact) > 0:
+		filter, err = subscriptionsapi.NewExactFilter(f.Exact)
+	case len(f.Prefix) > 0:
+		filter, err = subscriptionsapi.NewPrefixFilter(f.Prefix)
+	default:
+		filter, err = subscriptionsapi.NewSuffixFilter(f.Suffix)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return filter
+}
+
+func makeBenchmarkEvent(eventType, source string) cloudevents.Event {
+	e := cetest.MinEvent()
+	e.SetType(eventType)
+	e.SetSource(source)
+	return e
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	"knative.dev/pkg/ptr"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
+)
+
+// Test Range Filter
+func BenchmarkRangeFilter(b *testing.B) {
+	event := cetest.FullEvent()
+	event.SetExtension("priority", 5)
+
+	RunFilterBenchmarks(b,
+		func(i interface{}) eventfilter.Filter {
+			filter, err := subscriptionsapi.NewRangeFilter(i.(map[string]eventingv1.SubscriptionsAPIFilterRange))
+			if err != nil {
+				b.Fatalf("failed to create filter: %v", err)
+			}
+			return filter
+		},
+		FilterBenchmark{
+			name: "Pass with range match of extension",
+			arg: map[string]eventingv1.SubscriptionsAPIFilterRange{
+				"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)},
+			},
+			events: []cloudevents.Event{event},
+		},
+		FilterBenchmark{
+			name: "No pass with range match of extension",
+			arg: map[string]eventingv1.SubscriptionsAPIFilterRange{
+				"priority": {Min: ptr.Int64(6)},
+			},
+			events: []cloudevents.Event{event},
+		},
+		FilterBenchmark{
+			name: "No pass with range match of non numeric attribute",
+			arg: map[string]eventingv1.SubscriptionsAPIFilterRange{
+				"source": {Max: ptr.Int64(7)},
+			},
+			events: []cloudevents.Event{event},
+		},
+	)
+}
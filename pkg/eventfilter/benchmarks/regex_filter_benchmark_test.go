@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks
+
+import (
+	"regexp"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
+)
+
+// Test Regex Filter
+func BenchmarkRegexFilter(b *testing.B) {
+	event := cetest.FullEvent()
+
+	RunFilterBenchmarks(b,
+		func(i interface{}) eventfilter.Filter {
+			filter, err := subscriptionsapi.NewRegexFilter(i.(map[string]string))
+			if err != nil {
+				b.Fatalf("failed to create filter: %v", err)
+			}
+			return filter
+		},
+		FilterBenchmark{
+			name:   "Pass with regex match of id",
+			arg:    map[string]string{"id": "^" + regexp.QuoteMeta(event.ID()) + "$"},
+			events: []cloudevents.Event{event},
+		},
+		FilterBenchmark{
+			name: "Pass with regex match of type and source",
+			arg: map[string]string{
+				"type":   "^" + regexp.QuoteMeta(event.Type()[0:3]) + ".*",
+				"source": ".+",
+			},
+			events: []cloudevents.Event{event},
+		},
+		FilterBenchmark{
+			name: "No pass with regex match of id and source",
+			arg: map[string]string{
+				"id":     "^qwertyuiopasdfghjklzxcvbnm$",
+				"source": "^qwertyuiopasdfghjklzxcvbnm$",
+			},
+			events: []cloudevents.Event{event},
+		},
+	)
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package index
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+)
+
+// Mode selects which of the Trigger filters are taken into account when looking up candidates.
+// It mirrors the way the broker filter picks between `spec.filters` and `spec.filter`.
+type Mode int
+
+const (
+	// AttributesMode indexes the Triggers by their `spec.filter.attributes` only.
+	AttributesMode Mode = iota
+	// SubscriptionsAPIMode indexes the Triggers by their `spec.filters`, falling back to
+	// `spec.filter.attributes` when no `spec.filters` are set.
+	SubscriptionsAPIMode
+
+	numModes = 2
+)
+
+type matchKind int
+
+const (
+	exactMatch matchKind = iota
+	prefixMatch
+	suffixMatch
+)
+
+// anchor is a single condition that every event matching a Trigger must satisfy.
+type anchor struct {
+	kind      matchKind
+	attribute string
+	value     string
+}
+
+type triggerSet map[types.NamespacedName]*eventingv1.Trigger
+
+// valueIndex maps an attribute name to the values (or prefixes, or suffixes) indexed for it.
+type valueIndex map[string]map[string]triggerSet
+
+type matcher struct {
+	exact     valueIndex
+	prefix    valueIndex
+	suffix    valueIndex
+	unindexed triggerSet
+}
+
+type entry struct {
+	broker  types.NamespacedName
+	anchors [numModes]*anchor
+}
+
+// Index is a broker-wide matching index of Triggers. For every Trigger it picks one necessary
+// condition (an exact, prefix or suffix match on a single attribute) and uses it to narrow down
+// the Triggers that might be interested in an event. Triggers without such a condition are
+// always returned as candidates, so the result of Candidates is a superset of the matching
+// Triggers and each candidate must still be evaluated against its complete filter.
+type Index struct {
+	rwMutex  sync.RWMutex
+	brokers  map[types.NamespacedName]*[numModes]*matcher
+	triggers map[types.NamespacedName]*entry
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		brokers:  make(map[types.NamespacedName]*[numModes]*matcher),
+		triggers: make(map[types.NamespacedName]*entry),
+	}
+}
+
+// Set adds the Trigger to the index of the Broker it refers to, replacing any previous version
+// of the same Trigger.
+func (idx *Index) Set(trigger *eventingv1.Trigger) {
+	key := types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Name}
+	e := &entry{
+		broker: BrokerOf(trigger),
+		anchors: [numModes]*anchor{
+			AttributesMode:       attributesAnchor(trigger.Spec.Filter),
+			SubscriptionsAPIMode: subscriptionsAPIAnchor(trigger),
+		},
+	}
+
+	idx.rwMutex.Lock()
+	defer idx.rwMutex.Unlock()
+
+	idx.remove(key)
+	idx.triggers[key] = e
+
+	matchers, ok := idx.brokers[e.broker]
+	if !ok {
+		matchers = &[numModes]*matcher{}
+		for i := range matchers {
+			matchers[i] = newMatcher()
+		}
+		idx.brokers[e.broker] = matchers
+	}
+	for i, a := range e.anchors {
+		matchers[i].add(key, trigger, a)
+	}
+}
+
+// Delete removes the Trigger from the index.
+func (idx *Index) Delete(trigger *eventingv1.Trigger) {
+	key := types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Name}
+
+	idx.rwMutex.Lock()
+	defer idx.rwMutex.Unlock()
+
+	idx.remove(key)
+}
+
+// Candidates returns the Triggers of the given Broker that might match the event, sorted by
+// namespace and name.
+func (idx *Index) Candidates(broker types.NamespacedName, event cloudevents.Event, mode Mode) []*eventingv1.Trigger {
+	idx.rwMutex.RLock()
+	defer idx.rwMutex.RUnlock()
+
+	matchers, ok := idx.brokers[broker]
+	if !ok {
+		return nil
+	}
+	candidates := matchers[mode].lookup(event)
+
+	res := make([]*eventingv1.Trigger, 0, len(candidates))
+	for _, t := range candidates {
+		res = append(res, t)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Namespace != res[j].Namespace {
+			return res[i].Namespace < res[j].Namespace
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// remove must be called with the write lock held.
+func (idx *Index) remove(key types.NamespacedName) {
+	e, ok := idx.triggers[key]
+	if !ok {
+		return
+	}
+	delete(idx.triggers, key)
+
+	matchers, ok := idx.brokers[e.broker]
+	if !ok {
+		return
+	}
+	empty := true
+	for i, a := range e.anchors {
+		matchers[i].remove(key, a)
+		empty = empty && matchers[i].isEmpty()
+	}
+	if empty {
+		delete(idx.brokers, e.broker)
+	}
+}
+
+// BrokerOf returns the Broker the Trigger is subscribed to.
+func BrokerOf(trigger *eventingv1.Trigger) types.NamespacedName {
+	broker := types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Spec.Broker}
+	if ref := trigger.Spec.BrokerRef; ref != nil {
+		if ref.Name != "" {
+			broker.Name = ref.Name
+		}
+		if ref.Namespace != "" {
+			broker.Namespace = ref.Namespace
+		}
+	}
+	return broker
+}
+
+func newMatcher() *matcher {
+	return &matcher{
+		exact:     make(valueIndex),
+		prefix:    make(valueIndex),
+		suffix:    make(valueIndex),
+		unindexed: make(triggerSet),
+	}
+}
+
+func (m *matcher) valuesFor(kind matchKind) valueIndex {
+	switch kind {
+	case prefixMatch:
+		return m.prefix
+	case suffixMatch:
+		return m.suffix
+	default:
+		return m.exact
+	}
+}
+
+func (m *matcher) add(key types.NamespacedName, trigger *eventingv1.Trigger, a *anchor) {
+	if a == nil {
+		m.unindexed[key] = trigger
+		return
+	}
+	vi := m.valuesFor(a.kind)
+	values, ok := vi[a.attribute]
+	if !ok {
+		values = make(map[string]triggerSet)
+		vi[a.attribute] = values
+	}
+	set, ok := values[a.value]
+	if !ok {
+		set = make(triggerSet)
+		values[a.value] = set
+	}
+	set[key] = trigger
+}
+
+func (m *matcher) remove(key types.NamespacedName, a *anchor) {
+	if a == nil {
+		delete(m.unindexed, key)
+		return
+	}
+	vi := m.valuesFor(a.kind)
+	values, ok := vi[a.attribute]
+	if !ok {
+		return
+	}
+	if set, ok := values[a.value]; ok {
+		delete(set, key)
+		if len(set) == 0 {
+			delete(values, a.value)
+		}
+	}
+	if len(values) == 0 {
+		delete(vi, a.attribute)
+	}
+}
+
+func (m *matcher) isEmpty() bool {
+	return len(m.exact) == 0 && len(m.prefix) == 0 && len(m.suffix) == 0 && len(m.unindexed) == 0
+}
+
+func (m *matcher) lookup(event cloudevents.Event) triggerSet {
+	candidates := make(triggerSet, len(m.unindexed))
+	for k, t := range m.unindexed {
+		candidates[k] = t
+	}
+
+	for attribute, values := range m.exact {
+		if v, ok := lookupString(event, attribute); ok {
+			for k, t := range values[v] {
+				candidates[k] = t
+			}
+		}
+	}
+	for attribute, prefixes := range m.prefix {
+		if v, ok := lookupString(event, attribute); ok {
+			for i := 1; i <= len(v); i++ {
+				for k, t := range prefixes[v[:i]] {
+					candidates[k] = t
+				}
+			}
+		}
+	}
+	for attribute, suffixes := range m.suffix {
+		if v, ok := lookupString(event, attribute); ok {
+			for i := 0; i < len(v); i++ {
+				for k, t := range suffixes[v[i:]] {
+					candidates[k] = t
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+func lookupString(event cloudevents.Event, attribute string) (string, bool) {
+	value, ok := attributes.LookupAttribute(event, attribute)
+	if !ok {
+		return "", false
+	}
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	return fmt.Sprintf("%v", value), true
+}
+
+// attributesAnchor picks the anchor of a legacy attributes filter. Attributes set to the
+// "any" value don't constrain the value, so they can't be used.
+func attributesAnchor(filter *eventingv1.TriggerFilter) *anchor {
+	if filter == nil {
+		return nil
+	}
+	candidates := make([]anchor, 0, len(filter.Attributes))
+	for k, v := range filter.Attributes {
+		if k != "" && v != eventingv1.TriggerAnyFilter {
+			candidates = append(candidates, anchor{kind: exactMatch, attribute: k, value: v})
+		}
+	}
+	return bestAnchor(candidates)
+}
+
+// subscriptionsAPIAnchor picks the anchor of the filter applied by the broker filter when the
+// new trigger filters feature is enabled.
+func subscriptionsAPIAnchor(trigger *eventingv1.Trigger) *anchor {
+	if len(trigger.Spec.Filters) == 0 {
+		return attributesAnchor(trigger.Spec.Filter)
+	}
+	return bestAnchor(collectAnchors(trigger.Spec.Filters, nil))
+}
+
+// collectAnchors returns the conditions implied by the given list of filters, which are all
+// required to pass. Only the dialects which constrain a single attribute value are considered,
+// and `all` is the only nested dialect that is descended into.
+func collectAnchors(filters []eventingv1.SubscriptionsAPIFilter, anchors []anchor) []anchor {
+	for _, f := range filters {
+		switch {
+		case len(f.Exact) > 0:
+			anchors = appendValid(anchors, exactMatch, f.Exact)
+		case len(f.Prefix) > 0:
+			anchors = appendValid(anchors, prefixMatch, f.Prefix)
+		case len(f.Suffix) > 0:
+			anchors = appendValid(anchors, suffixMatch, f.Suffix)
+		case len(f.All) > 0:
+			anchors = collectAnchors(f.All, anchors)
+		}
+	}
+	return anchors
+}
+
+// appendValid appends an anchor for every attribute of the map, unless any of them is empty:
+// in that case the filter is discarded when it is materialized, hence it doesn't constrain
+// the events.
+func appendValid(anchors []anchor, kind matchKind, values map[string]string) []anchor {
+	for k, v := range values {
+		if k == "" || v == "" {
+			return anchors
+		}
+	}
+	for k, v := range values {
+		anchors = append(anchors, anchor{kind: kind, attribute: k, value: v})
+	}
+	return anchors
+}
+
+// bestAnchor prefers exact over prefix over suffix matches, the `type` attribute over the
+// others and longer values over shorter ones, since they are more likely to be selective.
+func bestAnchor(candidates []anchor) *anchor {
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if (a.attribute == "type") != (b.attribute == "type") {
+			return a.attribute == "type"
+		}
+		if len(a.value) != len(b.value) {
+			return len(a.value) > len(b.value)
+		}
+		if a.attribute != b.attribute {
+			return a.attribute < b.attribute
+		}
+		return a.value < b.value
+	})
+	return &candidates[0]
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package index
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+const (
+	testNS     = "test-namespace"
+	testBroker = "default"
+)
+
+func TestIndexCandidates(t *testing.T) {
+	triggers := []*eventingv1.Trigger{
+		makeTrigger("exact-attributes", &eventingv1.TriggerFilter{
+			Attributes: map[string]string{"type": "dev.knative.foo", "source": eventingv1.TriggerAnyFilter},
+		}),
+		makeTrigger("any-attributes", &eventingv1.TriggerFilter{
+			Attributes: map[string]string{"type": eventingv1.TriggerAnyFilter},
+		}),
+		makeTrigger("no-filter", nil),
+		makeTrigger("exact-filters", nil, eventingv1.SubscriptionsAPIFilter{
+			Exact: map[string]string{"type": "dev.knative.foo"},
+		}),
+		makeTrigger("prefix-filters", nil, eventingv1.SubscriptionsAPIFilter{
+			Prefix: map[string]string{"type": "dev.knative."},
+		}),
+		makeTrigger("suffix-filters", nil, eventingv1.SubscriptionsAPIFilter{
+			Suffix: map[string]string{"source": "/bar"},
+		}),
+		makeTrigger("nested-all-filters", nil, eventingv1.SubscriptionsAPIFilter{
+			All: []eventingv1.SubscriptionsAPIFilter{{
+				Exact: map[string]string{"myextension": "baz"},
+			}},
+		}),
+		makeTrigger("cesql-filters", nil, eventingv1.SubscriptionsAPIFilter{
+			CESQL: "type = 'dev.knative.other'",
+		}),
+		makeTrigger("invalid-exact-filters", nil, eventingv1.SubscriptionsAPIFilter{
+			Exact: map[string]string{"type": ""},
+		}),
+		makeTrigger("filters-override-attributes", &eventingv1.TriggerFilter{
+			Attributes: map[string]string{"type": "dev.knative.other"},
+		}, eventingv1.SubscriptionsAPIFilter{
+			Exact: map[string]string{"source": "/foo/bar"},
+		}),
+	}
+
+	tests := []struct {
+		name  string
+		event cloudevents.Event
+		mode  Mode
+		want  []string
+	}{{
+		name:  "attributes mode, matching type",
+		event: makeEvent("dev.knative.foo", "/foo/bar", nil),
+		mode:  AttributesMode,
+		want: []string{
+			"any-attributes",
+			"cesql-filters",
+			"exact-attributes",
+			"exact-filters",
+			"invalid-exact-filters",
+			"nested-all-filters",
+			"no-filter",
+			"prefix-filters",
+			"suffix-filters",
+		},
+	}, {
+		name:  "attributes mode, non matching type",
+		event: makeEvent("dev.knative.bar", "/foo/bar", nil),
+		mode:  AttributesMode,
+		want: []string{
+			"any-attributes",
+			"cesql-filters",
+			"exact-filters",
+			"invalid-exact-filters",
+			"nested-all-filters",
+			"no-filter",
+			"prefix-filters",
+			"suffix-filters",
+		},
+	}, {
+		name:  "subscriptions API mode, matching type and source",
+		event: makeEvent("dev.knative.foo", "/foo/bar", nil),
+		mode:  SubscriptionsAPIMode,
+		want: []string{
+			"any-attributes",
+			"cesql-filters",
+			"exact-attributes",
+			"exact-filters",
+			"filters-override-attributes",
+			"invalid-exact-filters",
+			"no-filter",
+			"prefix-filters",
+			"suffix-filters",
+		},
+	}, {
+		name:  "subscriptions API mode, nothing indexed matches",
+		event: makeEvent("com.example", "/baz", nil),
+		mode:  SubscriptionsAPIMode,
+		want: []string{
+			"any-attributes",
+			"cesql-filters",
+			"invalid-exact-filters",
+			"no-filter",
+		},
+	}, {
+		name:  "subscriptions API mode, matching extension",
+		event: makeEvent("com.example", "/baz", map[string]string{"myextension": "baz"}),
+		mode:  SubscriptionsAPIMode,
+		want: []string{
+			"any-attributes",
+			"cesql-filters",
+			"invalid-exact-filters",
+			"nested-all-filters",
+			"no-filter",
+		},
+	}}
+
+	idx := NewIndex()
+	for _, trigger := range triggers {
+		idx.Set(trigger)
+	}
+	broker := types.NamespacedName{Namespace: testNS, Name: testBroker}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(idx.Candidates(broker, tt.event, tt.mode))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected candidates (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestIndexSetAndDelete(t *testing.T) {
+	idx := NewIndex()
+	broker := types.NamespacedName{Namespace: testNS, Name: testBroker}
+	event := makeEvent("dev.knative.foo", "/foo", nil)
+
+	trigger := makeTrigger("trigger", &eventingv1.TriggerFilter{
+		Attributes: map[string]string{"type": "dev.knative.foo"},
+	})
+	idx.Set(trigger)
+	if got := names(idx.Candidates(broker, event, AttributesMode)); len(got) != 1 {
+		t.Fatalf("expected the trigger to be a candidate, got %v", got)
+	}
+
+	updated := trigger.DeepCopy()
+	updated.Spec.Filter.Attributes["type"] = "dev.knative.bar"
+	idx.Set(updated)
+	if got := names(idx.Candidates(broker, event, AttributesMode)); len(got) != 0 {
+		t.Fatalf("expected no candidates after the update, got %v", got)
+	}
+
+	idx.Delete(updated)
+	if len(idx.brokers) != 0 || len(idx.triggers) != 0 {
+		t.Errorf("expected the index to be empty after deletion, got %d brokers and %d triggers", len(idx.brokers), len(idx.triggers))
+	}
+}
+
+func TestIndexCrossNamespaceBroker(t *testing.T) {
+	idx := NewIndex()
+	trigger := makeTrigger("trigger", nil)
+	trigger.Spec.BrokerRef = &duckv1.KReference{Name: "other-broker", Namespace: "other-namespace"}
+	idx.Set(trigger)
+
+	event := makeEvent("dev.knative.foo", "/foo", nil)
+	if got := idx.Candidates(types.NamespacedName{Namespace: testNS, Name: testBroker}, event, AttributesMode); len(got) != 0 {
+		t.Errorf("expected no candidates for the default broker, got %v", names(got))
+	}
+	if got := idx.Candidates(types.NamespacedName{Namespace: "other-namespace", Name: "other-broker"}, event, AttributesMode); len(got) != 1 {
+		t.Errorf("expected the trigger to be a candidate for the referenced broker, got %v", names(got))
+	}
+}
+
+func makeTrigger(name string, filter *eventingv1.TriggerFilter, filters ...eventingv1.SubscriptionsAPIFilter) *eventingv1.Trigger {
+	return &eventingv1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      name,
+		},
+		Spec: eventingv1.TriggerSpec{
+			Broker:  testBroker,
+			Filter:  filter,
+			Filters: filters,
+		},
+	}
+}
+
+func makeEvent(eventType, source string, extensions map[string]string) cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID("1234")
+	e.SetType(eventType)
+	e.SetSource(source)
+	for k, v := range extensions {
+		e.SetExtension(k, v)
+	}
+	return e
+}
+
+func names(triggers []*eventingv1.Trigger) []string {
+	res := make([]string, 0, len(triggers))
+	for _, t := range triggers {
+		res = append(res, t.Name)
+	}
+	return res
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonpath implements the subset of JSONPath used by the event data filters: a path
+// made of member names and array indexes that selects at most a single value of a document.
+// Wildcards, slices, filter expressions and recursive descent are not supported.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type segment struct {
+	// name is the member name to select, when index is negative.
+	name  string
+	index int
+}
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	expression string
+	segments   []segment
+}
+
+// Compile parses a JSONPath expression such as `$.order.region`, `$['order']['region']` or
+// `$.items[0].sku`.
+func Compile(expression string) (*Path, error) {
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fmt.Errorf("JSONPath expression %q must start with '$'", expression)
+	}
+	p := &Path{expression: expression}
+	rest := expression[1:]
+	for len(rest) > 0 {
+		var s segment
+		var err error
+		switch rest[0] {
+		case '.':
+			s, rest, err = parseDotMember(rest[1:])
+		case '[':
+			s, rest, err = parseBracket(rest[1:])
+		default:
+			err = fmt.Errorf("unexpected character %q", rest[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath expression %q: %w", expression, err)
+		}
+		p.segments = append(p.segments, s)
+	}
+	return p, nil
+}
+
+func parseDotMember(s string) (segment, string, error) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		end = len(s)
+	}
+	name := s[:end]
+	if name == "*" || strings.HasPrefix(s, ".") {
+		return segment{}, "", fmt.Errorf("wildcards and recursive descent are not supported")
+	}
+	if name == "" {
+		return segment{}, "", fmt.Errorf("empty member name")
+	}
+	return segment{name: name, index: -1}, s[end:], nil
+}
+
+func parseBracket(s string) (segment, string, error) {
+	if len(s) > 0 && (s[0] == '\'' || s[0] == '"') {
+		quote := s[0]
+		end := strings.IndexByte(s[1:], quote)
+		if end < 0 {
+			return segment{}, "", fmt.Errorf("unterminated quoted member name")
+		}
+		name := s[1 : end+1]
+		rest := s[end+2:]
+		if !strings.HasPrefix(rest, "]") {
+			return segment{}, "", fmt.Errorf("expected ']' after quoted member name")
+		}
+		return segment{name: name, index: -1}, rest[1:], nil
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return segment{}, "", fmt.Errorf("unterminated '['")
+	}
+	index, err := strconv.Atoi(s[:end])
+	if err != nil || index < 0 {
+		return segment{}, "", fmt.Errorf("array index %q must be a non-negative integer", s[:end])
+	}
+	return segment{index: index}, s[end+1:], nil
+}
+
+// String returns the source expression.
+func (p *Path) String() string {
+	return p.expression
+}
+
+// Lookup returns the value selected by the path in a document returned by Parse and whether
+// the value exists.
+func (p *Path) Lookup(document interface{}) (interface{}, bool) {
+	current := document
+	for _, s := range p.segments {
+		if s.index < 0 {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[s.name]; !ok {
+				return nil, false
+			}
+			continue
+		}
+		array, ok := current.([]interface{})
+		if !ok || s.index >= len(array) {
+			return nil, false
+		}
+		current = array[s.index]
+	}
+	return current, true
+}
+
+// Parse decodes a JSON document. Numbers are decoded as json.Number, so that they keep the
+// representation they have in the payload.
+func Parse(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON document")
+	}
+	return document, nil
+}
+
+// ScalarString returns the string representation of a string, number or boolean value. It
+// returns false for objects, arrays and null.
+func ScalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+import (
+	"testing"
+)
+
+const document = `{
+	"order": {"region": "eu-west", "total": 12.50, "express": true, "note": null},
+	"items": [{"sku": "abc"}, {"sku": "def"}],
+	"dotted.key": "value"
+}`
+
+func TestLookup(t *testing.T) {
+	doc, err := Parse([]byte(document))
+	if err != nil {
+		t.Fatal("failed to parse the document:", err)
+	}
+
+	tests := map[string]struct {
+		path       string
+		wantExists bool
+		wantString string
+		wantScalar bool
+	}{
+		"root":                   {path: "$", wantExists: true},
+		"nested member":          {path: "$.order.region", wantExists: true, wantString: "eu-west", wantScalar: true},
+		"bracket member":         {path: "$['order'][\"region\"]", wantExists: true, wantString: "eu-west", wantScalar: true},
+		"number keeps its form":  {path: "$.order.total", wantExists: true, wantString: "12.50", wantScalar: true},
+		"boolean":                {path: "$.order.express", wantExists: true, wantString: "true", wantScalar: true},
+		"null":                   {path: "$.order.note", wantExists: true},
+		"object":                 {path: "$.order", wantExists: true},
+		"array index":            {path: "$.items[1].sku", wantExists: true, wantString: "def", wantScalar: true},
+		"member with a dot":      {path: "$['dotted.key']", wantExists: true, wantString: "value", wantScalar: true},
+		"missing member":         {path: "$.order.country"},
+		"index out of range":     {path: "$.items[2].sku"},
+		"index on an object":     {path: "$.order[0]"},
+		"member on an array":     {path: "$.items.sku"},
+		"member on a scalar":     {path: "$.order.region.name"},
+		"member on a null value": {path: "$.order.note.name"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := Compile(tt.path)
+			if err != nil {
+				t.Fatal("failed to compile the path:", err)
+			}
+			value, exists := p.Lookup(doc)
+			if exists != tt.wantExists {
+				t.Fatalf("Lookup() exists = %v, want %v", exists, tt.wantExists)
+			}
+			s, scalar := ScalarString(value)
+			if scalar != tt.wantScalar || s != tt.wantString {
+				t.Errorf("ScalarString() = (%q, %v), want (%q, %v)", s, scalar, tt.wantString, tt.wantScalar)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"order.region",
+		"$.",
+		"$..region",
+		"$.*",
+		"$[*]",
+		"$[-1]",
+		"$['order'",
+		"$['order",
+		"$[0",
+		"$order",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := Compile(expression); err == nil {
+				t.Errorf("expected an error compiling %q", expression)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, data := range []string{"", "{", `{"a": 1} {"b": 2}`, "<xml/>"} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("expected an error parsing %q", data)
+		}
+	}
+}
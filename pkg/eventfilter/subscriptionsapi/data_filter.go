@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptionsapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/jsonpath"
+)
+
+type dataFilter struct {
+	path   *jsonpath.Path
+	exact  *string
+	prefix *string
+	exists *bool
+}
+
+// NewDataFilter returns an event filter which passes if the value selected by the JSONPath
+// expression in the JSON payload of the CloudEvent satisfies the operator of the filter.
+// Events whose payload isn't JSON never pass.
+func NewDataFilter(filter eventingv1.SubscriptionsAPIDataFilter) (eventfilter.Filter, error) {
+	path, err := jsonpath.Compile(filter.Path)
+	if err != nil {
+		return nil, err
+	}
+	operators := 0
+	for _, set := range []bool{filter.Exact != nil, filter.Prefix != nil, filter.Exists != nil} {
+		if set {
+			operators++
+		}
+	}
+	if operators != 1 {
+		return nil, fmt.Errorf("invalid arguments, exactly one of exact, prefix or exists must be set")
+	}
+	if filter.Prefix != nil && *filter.Prefix == "" {
+		return nil, fmt.Errorf("invalid arguments, prefix can't be empty")
+	}
+	return &dataFilter{
+		path:   path,
+		exact:  filter.Exact,
+		prefix: filter.Prefix,
+		exists: filter.Exists,
+	}, nil
+}
+
+func (filter *dataFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	if filter == nil {
+		return eventfilter.NoFilter
+	}
+	logger := logging.FromContext(ctx)
+	logger.Debugw("Performing a data match ", zap.Stringer("path", filter.path), zap.Any("event", event))
+
+	document, err := parsePayload(ctx, event)
+	if err != nil {
+		logger.Debugw("Couldn't parse the event payload as JSON. Data match failed.", zap.Error(err), zap.Any("event", event))
+		return eventfilter.FailFilter
+	}
+
+	value, exists := filter.path.Lookup(document)
+	if filter.exists != nil {
+		if exists == *filter.exists {
+			return eventfilter.PassFilter
+		}
+		return eventfilter.FailFilter
+	}
+	if !exists {
+		return eventfilter.FailFilter
+	}
+	s, ok := jsonpath.ScalarString(value)
+	if !ok {
+		return eventfilter.FailFilter
+	}
+	if filter.exact != nil && s != *filter.exact {
+		return eventfilter.FailFilter
+	}
+	if filter.prefix != nil && !strings.HasPrefix(s, *filter.prefix) {
+		return eventfilter.FailFilter
+	}
+	return eventfilter.PassFilter
+}
+
+func (filter *dataFilter) Cleanup() {}
+
+type parsedPayloadKey struct{}
+
+type parsedPayload struct {
+	once     sync.Once
+	id       string
+	source   string
+	document interface{}
+	err      error
+}
+
+// WithParsedPayload returns a context in which every data filter evaluating the event shares
+// a single parsed copy of its JSON payload, instead of parsing it once per filter. The
+// returned context must only be used to filter the given event.
+func WithParsedPayload(ctx context.Context, event cloudevents.Event) context.Context {
+	return context.WithValue(ctx, parsedPayloadKey{}, &parsedPayload{
+		id:     event.ID(),
+		source: event.Source(),
+	})
+}
+
+// parsePayload returns the parsed JSON payload of the event, reusing the one shared through
+// the context when it belongs to the same event.
+func parsePayload(ctx context.Context, event cloudevents.Event) (interface{}, error) {
+	shared, ok := ctx.Value(parsedPayloadKey{}).(*parsedPayload)
+	if !ok || shared.id != event.ID() || shared.source != event.Source() {
+		return parseJSONPayload(event)
+	}
+	shared.once.Do(func() {
+		shared.document, shared.err = parseJSONPayload(event)
+	})
+	return shared.document, shared.err
+}
+
+func parseJSONPayload(event cloudevents.Event) (interface{}, error) {
+	mediaType := event.DataMediaType()
+	if mediaType != cloudevents.ApplicationJSON && !strings.HasSuffix(mediaType, "+json") {
+		return nil, fmt.Errorf("unsupported data content type %q", event.DataContentType())
+	}
+	return jsonpath.Parse(event.Data())
+}
+
+var _ eventfilter.Filter = &dataFilter{}
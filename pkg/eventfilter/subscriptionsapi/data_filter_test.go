@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptionsapi
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"knative.dev/pkg/ptr"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+const orderPayload = `{"order": {"region": "eu-west", "total": 12.5, "note": null, "items": ["abc"]}}`
+
+func TestDataFilter(t *testing.T) {
+	tests := map[string]struct {
+		filter eventingv1.SubscriptionsAPIDataFilter
+		event  *cloudevents.Event
+		want   eventfilter.FilterResult
+	}{
+		"Exact match": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.region", Exact: ptr.String("eu-west")},
+			want:   eventfilter.PassFilter,
+		},
+		"Exact mismatch": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.region", Exact: ptr.String("eu")},
+			want:   eventfilter.FailFilter,
+		},
+		"Exact number": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.total", Exact: ptr.String("12.5")},
+			want:   eventfilter.PassFilter,
+		},
+		"Exact on an array": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.items", Exact: ptr.String("abc")},
+			want:   eventfilter.FailFilter,
+		},
+		"Exact on a null value": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.note", Exact: ptr.String("")},
+			want:   eventfilter.FailFilter,
+		},
+		"Prefix match": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.items[0]", Prefix: ptr.String("ab")},
+			want:   eventfilter.PassFilter,
+		},
+		"Prefix mismatch": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.region", Prefix: ptr.String("us-")},
+			want:   eventfilter.FailFilter,
+		},
+		"Prefix on a missing value": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.country", Prefix: ptr.String("eu")},
+			want:   eventfilter.FailFilter,
+		},
+		"Exists": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.note", Exists: ptr.Bool(true)},
+			want:   eventfilter.PassFilter,
+		},
+		"Exists on a missing value": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.country", Exists: ptr.Bool(true)},
+			want:   eventfilter.FailFilter,
+		},
+		"Not exists": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.country", Exists: ptr.Bool(false)},
+			want:   eventfilter.PassFilter,
+		},
+		"Structured syntax suffix": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.region", Exact: ptr.String("eu-west")},
+			event:  makeEventWithData("application/vnd.order+json; charset=utf-8", orderPayload),
+			want:   eventfilter.PassFilter,
+		},
+		"Non JSON payload": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.country", Exists: ptr.Bool(false)},
+			event:  makeEventWithData("text/plain", orderPayload),
+			want:   eventfilter.FailFilter,
+		},
+		"Malformed JSON payload": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.country", Exists: ptr.Bool(false)},
+			event:  makeEventWithData(cloudevents.ApplicationJSON, `{"order": `),
+			want:   eventfilter.FailFilter,
+		},
+		"No payload": {
+			filter: eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.country", Exists: ptr.Bool(false)},
+			event:  makeEvent(),
+			want:   eventfilter.FailFilter,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := tt.event
+			if e == nil {
+				e = makeEventWithData(cloudevents.ApplicationJSON, orderPayload)
+			}
+			f, err := NewDataFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("error while creating data filter %v", err)
+			}
+			if got := f.Filter(context.TODO(), *e); got != tt.want {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+			if got := f.Filter(WithParsedPayload(context.TODO(), *e), *e); got != tt.want {
+				t.Errorf("Filter() with a parsed payload = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataFilterInvalidArguments(t *testing.T) {
+	tests := map[string]eventingv1.SubscriptionsAPIDataFilter{
+		"Invalid path":       {Path: "order.region", Exists: ptr.Bool(true)},
+		"No operator":        {Path: "$.order.region"},
+		"Multiple operators": {Path: "$.order.region", Exact: ptr.String("a"), Prefix: ptr.String("a")},
+		"Empty prefix":       {Path: "$.order.region", Prefix: ptr.String("")},
+	}
+	for name, filter := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewDataFilter(filter); err == nil {
+				t.Error("expected an error while creating data filter")
+			}
+		})
+	}
+}
+
+func TestDataFiltersShareParsedPayload(t *testing.T) {
+	e := makeEventWithData(cloudevents.ApplicationJSON, orderPayload)
+	ctx := WithParsedPayload(context.TODO(), *e)
+
+	f := NewAllFilter(
+		mustDataFilter(t, eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.region", Exact: ptr.String("eu-west")}),
+		mustDataFilter(t, eventingv1.SubscriptionsAPIDataFilter{Path: "$.order.total", Exists: ptr.Bool(true)}),
+	)
+	defer f.Cleanup()
+	if got := f.Filter(ctx, *e); got != eventfilter.PassFilter {
+		t.Fatalf("Filter() = %v, want %v", got, eventfilter.PassFilter)
+	}
+	shared := ctx.Value(parsedPayloadKey{}).(*parsedPayload)
+	if shared.document == nil {
+		t.Error("expected the parsed payload to be stored in the context")
+	}
+
+	// A different event must not see the payload parsed for the first one.
+	other := makeEventWithData(cloudevents.ApplicationJSON, `{"order": {"region": "us-east"}}`)
+	other.SetID("other")
+	if got := f.Filter(ctx, *other); got != eventfilter.FailFilter {
+		t.Errorf("Filter() of another event = %v, want %v", got, eventfilter.FailFilter)
+	}
+}
+
+func mustDataFilter(t *testing.T, filter eventingv1.SubscriptionsAPIDataFilter) eventfilter.Filter {
+	f, err := NewDataFilter(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func makeEventWithData(contentType, data string) *cloudevents.Event {
+	e := makeEvent()
+	_ = e.SetData(contentType, []byte(data))
+	return e
+}
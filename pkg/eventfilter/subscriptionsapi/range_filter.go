@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptionsapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+)
+
+type rangeFilter struct {
+	filters map[string]eventingv1.SubscriptionsAPIFilterRange
+}
+
+// NewRangeFilter returns an event filter which passes if the value of the context
+// attribute in the CloudEvent is a number within the (inclusive) bounds of the range.
+func NewRangeFilter(filters map[string]eventingv1.SubscriptionsAPIFilterRange) (eventfilter.Filter, error) {
+	for attribute, r := range filters {
+		if attribute == "" {
+			return nil, fmt.Errorf("invalid arguments, attribute can't be empty")
+		}
+		if r.Min == nil && r.Max == nil {
+			return nil, fmt.Errorf("invalid arguments, range for attribute %q must have at least one bound", attribute)
+		}
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return nil, fmt.Errorf("invalid arguments, range for attribute %q has min %d greater than max %d", attribute, *r.Min, *r.Max)
+		}
+	}
+	return &rangeFilter{
+		filters: filters,
+	}, nil
+}
+
+func (filter *rangeFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	if filter == nil {
+		return eventfilter.NoFilter
+	}
+	logger := logging.FromContext(ctx)
+	logger.Debugw("Performing a range match ", zap.Any("filters", filter.filters), zap.Any("event", event))
+	for k, r := range filter.filters {
+		value, ok := attributes.LookupAttribute(event, k)
+		if !ok {
+			logger.Debugw("Couldn't find attribute in event. Range match failed.", zap.String("attribute", k), zap.Any("range", r),
+				zap.Any("event", event))
+			return eventfilter.FailFilter
+		}
+		n, ok := toNumber(value)
+		if !ok {
+			logger.Debugw("Attribute is not a number. Range match failed.", zap.String("attribute", k), zap.Any("value", value))
+			return eventfilter.FailFilter
+		}
+		if (r.Min != nil && n < float64(*r.Min)) || (r.Max != nil && n > float64(*r.Max)) {
+			return eventfilter.FailFilter
+		}
+	}
+	return eventfilter.PassFilter
+}
+
+func (filter *rangeFilter) Cleanup() {}
+
+// toNumber converts the value of an attribute to a number. Extensions can either be integers or,
+// when they are received in binary mode, strings. NaN and infinite values are not numbers within
+// any range, since NaN compares as false against both bounds.
+func toNumber(value interface{}) (float64, bool) {
+	var n float64
+	switch v := value.(type) {
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		n = v
+	case string:
+		var err error
+		if n, err = strconv.ParseFloat(v, 64); err != nil {
+			return 0, false
+		}
+	default:
+		var err error
+		if n, err = strconv.ParseFloat(fmt.Sprintf("%v", v), 64); err != nil {
+			return 0, false
+		}
+	}
+	return n, !math.IsNaN(n) && !math.IsInf(n, 0)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptionsapi
+
+import (
+	"context"
+	"testing"
+
+	"knative.dev/pkg/ptr"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+func TestRangeFilter(t *testing.T) {
+	tests := map[string]struct {
+		attribute string
+		min       *int64
+		max       *int64
+		value     interface{}
+		want      eventfilter.FilterResult
+	}{
+		"Missing attribute": {
+			attribute: "some-other-attribute",
+			min:       ptr.Int64(3),
+			want:      eventfilter.FailFilter,
+		},
+		"Not a number": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			value:     "high",
+			want:      eventfilter.FailFilter,
+		},
+		"NaN": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			value:     "NaN",
+			want:      eventfilter.FailFilter,
+		},
+		"Infinity without upper bound": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			value:     "+Inf",
+			want:      eventfilter.FailFilter,
+		},
+		"Negative infinity without lower bound": {
+			attribute: "priority",
+			max:       ptr.Int64(7),
+			value:     "-Infinity",
+			want:      eventfilter.FailFilter,
+		},
+		"Below min": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			max:       ptr.Int64(7),
+			value:     int32(2),
+			want:      eventfilter.FailFilter,
+		},
+		"Above max": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			max:       ptr.Int64(7),
+			value:     "8",
+			want:      eventfilter.FailFilter,
+		},
+		"Match lower bound": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			max:       ptr.Int64(7),
+			value:     int32(3),
+			want:      eventfilter.PassFilter,
+		},
+		"Match upper bound": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			max:       ptr.Int64(7),
+			value:     "7",
+			want:      eventfilter.PassFilter,
+		},
+		"Match decimal value": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			max:       ptr.Int64(7),
+			value:     "4.5",
+			want:      eventfilter.PassFilter,
+		},
+		"Match without upper bound": {
+			attribute: "priority",
+			min:       ptr.Int64(3),
+			value:     int32(1000),
+			want:      eventfilter.PassFilter,
+		},
+		"Match without lower bound": {
+			attribute: "priority",
+			max:       ptr.Int64(7),
+			value:     int32(-1000),
+			want:      eventfilter.PassFilter,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := makeEvent()
+			if tt.value != nil {
+				e.SetExtension("priority", tt.value)
+			}
+			f, err := NewRangeFilter(map[string]eventingv1.SubscriptionsAPIFilterRange{
+				tt.attribute: {Min: tt.min, Max: tt.max},
+			})
+			if err != nil {
+				t.Errorf("error while creating range filter %v", err)
+			} else {
+				if got := f.Filter(context.TODO(), *e); got != tt.want {
+					t.Errorf("Filter() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRangeFilterInvalidArguments(t *testing.T) {
+	tests := map[string]map[string]eventingv1.SubscriptionsAPIFilterRange{
+		"Empty attribute":  {"": {Min: ptr.Int64(3)}},
+		"No bounds":        {"priority": {}},
+		"Unordered bounds": {"priority": {Min: ptr.Int64(7), Max: ptr.Int64(3)}},
+	}
+	for name, filters := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewRangeFilter(filters); err == nil {
+				t.Error("expected an error while creating range filter")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptionsapi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+)
+
+type regexFilter struct {
+	filters map[string]*regexp.Regexp
+}
+
+// NewRegexFilter returns an event filter which passes if the value of the context
+// attribute in the CloudEvent matches the regular expression.
+func NewRegexFilter(filters map[string]string) (eventfilter.Filter, error) {
+	compiled := make(map[string]*regexp.Regexp, len(filters))
+	for attribute, expression := range filters {
+		if attribute == "" || expression == "" {
+			return nil, fmt.Errorf("invalid arguments, attribute and expression can't be empty")
+		}
+		re, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression for attribute %q: %w", attribute, err)
+		}
+		compiled[attribute] = re
+	}
+	return &regexFilter{
+		filters: compiled,
+	}, nil
+}
+
+func (filter *regexFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	if filter == nil {
+		return eventfilter.NoFilter
+	}
+	logger := logging.FromContext(ctx)
+	logger.Debugw("Performing a regex match ", zap.Any("filters", filter.filters), zap.Any("event", event))
+	for k, re := range filter.filters {
+		value, ok := attributes.LookupAttribute(event, k)
+		if !ok {
+			logger.Debugw("Couldn't find attribute in event. Regex match failed.", zap.String("attribute", k), zap.Stringer("regex", re),
+				zap.Any("event", event))
+			return eventfilter.FailFilter
+		}
+		var s string
+		if s, ok = value.(string); !ok {
+			s = fmt.Sprintf("%v", value)
+		}
+		if !re.MatchString(s) {
+			return eventfilter.FailFilter
+		}
+	}
+	return eventfilter.PassFilter
+}
+
+func (filter *regexFilter) Cleanup() {}
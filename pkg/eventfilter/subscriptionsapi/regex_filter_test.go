@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptionsapi
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+func TestRegexFilter(t *testing.T) {
+	tests := map[string]struct {
+		attribute string
+		regex     string
+		event     *cloudevents.Event
+		want      eventfilter.FilterResult
+	}{
+		"Missing attribute": {
+			attribute: "some-other-attribute",
+			regex:     ".*",
+			want:      eventfilter.FailFilter,
+		},
+		"Wrong type": {
+			attribute: "type",
+			regex:     `^dev\.knative\.other$`,
+			want:      eventfilter.FailFilter,
+		},
+		"Wrong extension": {
+			attribute: extensionName,
+			regex:     "^wrong",
+			event:     makeEventWithExtension(extensionName, extensionValue),
+			want:      eventfilter.FailFilter,
+		},
+		"Match type": {
+			attribute: "type",
+			regex:     `^dev\.knative\.[a-z]+$`,
+			want:      eventfilter.PassFilter,
+		},
+		"Match source substring": {
+			attribute: "source",
+			regex:     "native",
+			want:      eventfilter.PassFilter,
+		},
+		"Match extension": {
+			attribute: extensionName,
+			regex:     "^my-[a-z]+-value$",
+			event:     makeEventWithExtension(extensionName, extensionValue),
+			want:      eventfilter.PassFilter,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := tt.event
+			if e == nil {
+				e = makeEvent()
+			}
+			f, err := NewRegexFilter(map[string]string{
+				tt.attribute: tt.regex,
+			})
+			if err != nil {
+				t.Errorf("error while creating regex filter %v", err)
+			} else {
+				if got := f.Filter(context.TODO(), *e); got != tt.want {
+					t.Errorf("Filter() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegexFilterInvalidArguments(t *testing.T) {
+	tests := map[string]map[string]string{
+		"Empty attribute":    {"": "abc"},
+		"Empty expression":   {"type": ""},
+		"Invalid expression": {"type": "[a-z"},
+	}
+	for name, filters := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewRegexFilter(filters); err == nil {
+				t.Error("expected an error while creating regex filter")
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventtype
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"k8s.io/client-go/tools/cache"
+
+	"knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta3"
+	eventtypeinformer "knative.dev/eventing/pkg/client/informers/externalversions/eventing/v1beta2"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+)
+
+const brokerIndex = "broker"
+
+// Validator validates events against the attribute definitions of the EventTypes referencing
+// the Broker they are sent to. The EventTypes are read in the served version, and their attribute
+// definitions are those of their conversion to v1beta3.
+type Validator struct {
+	indexer cache.Indexer
+
+	// schemas caches the compiled attribute definitions by EventType UID.
+	schemas sync.Map
+}
+
+// NewValidator returns a Validator looking up the EventTypes in the cache of the informer. It
+// must be called before the informer is started.
+func NewValidator(informer eventtypeinformer.EventTypeInformer) (*Validator, error) {
+	v := &Validator{}
+	if err := informer.Informer().AddIndexers(cache.Indexers{brokerIndex: indexByBroker}); err != nil {
+		return nil, fmt.Errorf("failed to index the EventTypes by broker: %w", err)
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if et, ok := obj.(*v1beta2.EventType); ok {
+				v.schemas.Delete(et.UID)
+			}
+		},
+	})
+	v.indexer = informer.Informer().GetIndexer()
+	return v, nil
+}
+
+// Validate validates the event against the EventTypes referencing the Broker whose type attribute
+// matches the type of the event. It returns false when there is no such EventType, in which case
+// the event isn't validated. Otherwise, an error describing the violation is returned when the
+// event doesn't satisfy the attribute definitions of any of them.
+func (v *Validator) Validate(event event.Event, brokerNamespace, brokerName string) (bool, error) {
+	objs, err := v.indexer.ByIndex(brokerIndex, brokerNamespace+"/"+brokerName)
+	if err != nil {
+		return false, err
+	}
+
+	eventTypes := make([]*v1beta2.EventType, 0, len(objs))
+	for _, obj := range objs {
+		eventTypes = append(eventTypes, obj.(*v1beta2.EventType))
+	}
+	// Report the violation of the same EventType every time.
+	sort.Slice(eventTypes, func(i, j int) bool {
+		return eventTypes[i].Name < eventTypes[j].Name
+	})
+
+	validated := false
+	var violation error
+	for _, et := range eventTypes {
+		s, err := v.schemaFor(et)
+		if err != nil {
+			// EventTypes with an invalid value pattern can't be enforced.
+			continue
+		}
+		if !s.matchesType(event.Type()) {
+			continue
+		}
+		validated = true
+		err = s.validate(event)
+		if err == nil {
+			return true, nil
+		}
+		if violation == nil {
+			violation = fmt.Errorf("event doesn't conform to EventType %s: %w", et.Name, err)
+		}
+	}
+	return validated, violation
+}
+
+func (v *Validator) schemaFor(et *v1beta2.EventType) (*schema, error) {
+	if cached, ok := v.schemas.Load(et.UID); ok {
+		if s := cached.(*schema); s.resourceVersion == et.ResourceVersion {
+			return s, s.err
+		}
+	}
+	s := compileSchema(et)
+	v.schemas.Store(et.UID, s)
+	return s, s.err
+}
+
+// indexByBroker indexes the EventTypes by the namespace and name of the Broker they reference.
+func indexByBroker(obj interface{}) ([]string, error) {
+	et, ok := obj.(*v1beta2.EventType)
+	if !ok {
+		return nil, nil
+	}
+	if et.Spec.Reference == nil {
+		if et.Spec.Broker == "" {
+			return nil, nil
+		}
+		return []string{et.Namespace + "/" + et.Spec.Broker}, nil
+	}
+	if et.Spec.Reference.Kind != "Broker" {
+		return nil, nil
+	}
+	namespace := et.Spec.Reference.Namespace
+	if namespace == "" {
+		namespace = et.Namespace
+	}
+	return []string{namespace + "/" + et.Spec.Reference.Name}, nil
+}
+
+type attributeSchema struct {
+	name     string
+	required bool
+	pattern  string
+	// value is nil when any value is allowed.
+	value *regexp.Regexp
+}
+
+type schema struct {
+	resourceVersion string
+	attributes      []attributeSchema
+	err             error
+}
+
+func compileSchema(et *v1beta2.EventType) *schema {
+	s := &schema{resourceVersion: et.ResourceVersion}
+
+	// The conversion is done on a copy, as the EventType is shared with the informer cache.
+	converted := &v1beta3.EventType{}
+	if err := et.DeepCopy().ConvertTo(context.Background(), converted); err != nil {
+		s.err = fmt.Errorf("failed to convert the EventType: %w", err)
+		return s
+	}
+
+	s.attributes = make([]attributeSchema, 0, len(converted.Spec.Attributes))
+	for _, attr := range converted.Spec.Attributes {
+		as := attributeSchema{name: attr.Name, required: attr.Required, pattern: attr.Value}
+		if attr.Value != "" {
+			re, err := compileAttributeValue(attr.Value)
+			if err != nil {
+				s.err = fmt.Errorf("invalid value of attribute %q: %w", attr.Name, err)
+				return s
+			}
+			as.value = re
+		}
+		s.attributes = append(s.attributes, as)
+	}
+	return s
+}
+
+func (s *schema) matchesType(eventType string) bool {
+	for _, attr := range s.attributes {
+		if attr.name == "type" {
+			return attr.value == nil || attr.value.MatchString(eventType)
+		}
+	}
+	return false
+}
+
+func (s *schema) validate(e event.Event) error {
+	for _, attr := range s.attributes {
+		value, ok := lookupAttribute(e, attr.name)
+		if !ok {
+			if attr.required {
+				return fmt.Errorf("missing required attribute %q", attr.name)
+			}
+			continue
+		}
+		if attr.value != nil && !attr.value.MatchString(value) {
+			return fmt.Errorf("value %q of attribute %q doesn't match %q", value, attr.name, attr.pattern)
+		}
+	}
+	return nil
+}
+
+// lookupAttribute returns the value of the attribute of the event, considering the optional
+// attributes with an empty value as missing.
+func lookupAttribute(e event.Event, name string) (string, bool) {
+	if name == "time" && e.Time().IsZero() {
+		return "", false
+	}
+	value, ok := attributes.LookupAttribute(e, name)
+	if !ok {
+		return "", false
+	}
+	s, err := types.Format(value)
+	if err != nil || s == "" {
+		return "", false
+	}
+	return s, true
+}
+
+// compileAttributeValue compiles the value of an EventType attribute into a regular expression
+// matching the values it allows. Variables between curly brackets match any non-empty value,
+// and escaped curly brackets match themselves.
+func compileAttributeValue(value string) (*regexp.Regexp, error) {
+	var pattern, literal strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value) && (value[i+1] == '{' || value[i+1] == '}'):
+			literal.WriteByte(value[i+1])
+			i++
+		case value[i] == '{':
+			offset := strings.IndexByte(value[i:], '}')
+			if offset == -1 {
+				return nil, fmt.Errorf("no closing bracket for variable")
+			}
+			pattern.WriteString(regexp.QuoteMeta(literal.String()))
+			pattern.WriteString(".+")
+			literal.Reset()
+			i += offset
+		case value[i] == '}':
+			return nil, fmt.Errorf("no opening bracket for a closing bracket")
+		default:
+			literal.WriteByte(value[i])
+		}
+	}
+	pattern.WriteString(regexp.QuoteMeta(literal.String()))
+	pattern.WriteString("$")
+	return regexp.Compile(pattern.String())
+}
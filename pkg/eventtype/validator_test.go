@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventtype
+
+import (
+	"testing"
+
+	v2 "github.com/cloudevents/sdk-go/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	fakeeventingclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	"knative.dev/eventing/pkg/client/informers/externalversions"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		eventTypes    []*v1beta2.EventType
+		event         func(e *v2.Event)
+		wantValidated bool
+		wantErr       bool
+	}{
+		{
+			name:       "No EventType",
+			eventTypes: nil,
+		},
+		{
+			name: "EventType of another broker",
+			eventTypes: []*v1beta2.EventType{
+				newEventType("et", "other", "my.type", ""),
+			},
+		},
+		{
+			name: "EventType of another type",
+			eventTypes: []*v1beta2.EventType{
+				newEventType("et", "broker", "other.type", ""),
+			},
+		},
+		{
+			name: "Conforming event",
+			eventTypes: []*v1beta2.EventType{
+				newEventType("et", "broker", "my.type", "/apis/v1/namespaces/default/pingsources/ps"),
+			},
+			wantValidated: true,
+		},
+		{
+			name: "EventType referencing the broker by name",
+			eventTypes: []*v1beta2.EventType{
+				func() *v1beta2.EventType {
+					et := newEventType("et", "", "my.type", "/apis/v1/namespaces/default/pingsources/other")
+					et.Spec.Reference = nil
+					et.Spec.Broker = "broker"
+					return et
+				}(),
+			},
+			wantValidated: true,
+			wantErr:       true,
+		},
+		{
+			name: "Value not matching the pattern",
+			eventTypes: []*v1beta2.EventType{
+				newEventType("et", "broker", "my.type", "/apis/v1/namespaces/default/pingsources/ps"),
+			},
+			event: func(e *v2.Event) {
+				e.SetSource("/apis/v1/namespaces/default/apiserversources/src")
+			},
+			wantValidated: true,
+			wantErr:       true,
+		},
+		{
+			name: "Type with a variable",
+			eventTypes: []*v1beta2.EventType{
+				newEventType("et", "broker", "my.{kind}", "/other"),
+			},
+			wantValidated: true,
+			wantErr:       true,
+		},
+		{
+			name: "Conforming to one of the EventTypes",
+			eventTypes: []*v1beta2.EventType{
+				newEventType("et-1", "broker", "my.type", "/other"),
+				newEventType("et-2", "broker", "my.type", "/apis/v1/namespaces/default/pingsources/ps"),
+			},
+			wantValidated: true,
+		},
+		{
+			name: "EventType with an invalid pattern",
+			eventTypes: []*v1beta2.EventType{
+				newEventType("et", "broker", "my.{type", ""),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			informer := externalversions.NewSharedInformerFactory(fakeeventingclientset.NewSimpleClientset(), 0).Eventing().V1beta2().EventTypes()
+			validator, err := NewValidator(informer)
+			if err != nil {
+				t.Fatal("NewValidator() =", err)
+			}
+			for _, et := range tc.eventTypes {
+				if err := informer.Informer().GetIndexer().Add(et); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			e := v2.NewEvent()
+			e.SetID("id")
+			e.SetType("my.type")
+			e.SetSource("/apis/v1/namespaces/default/pingsources/ps")
+			if tc.event != nil {
+				tc.event(&e)
+			}
+
+			validated, err := validator.Validate(e, "default", "broker")
+			if validated != tc.wantValidated {
+				t.Errorf("Validate() validated = %v, want %v", validated, tc.wantValidated)
+			}
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			for _, et := range tc.eventTypes {
+				if et.Spec.Broker != "" && et.Spec.Reference != nil {
+					t.Error("Validate() modified the EventType in the informer cache")
+				}
+			}
+		})
+	}
+}
+
+func TestCompileAttributeValue(t *testing.T) {
+	testCases := []struct {
+		value   string
+		matches []string
+		fails   []string
+		wantErr bool
+	}{
+		{
+			value:   "my.type",
+			matches: []string{"my.type"},
+			fails:   []string{"myxtype", "my.type.v2"},
+		},
+		{
+			value:   "/namespaces/{namespace}/sources/{name}",
+			matches: []string{"/namespaces/default/sources/ps"},
+			fails:   []string{"/namespaces//sources/ps", "/namespaces/default/sinks/ps"},
+		},
+		{
+			value:   "\\{literal\\}",
+			matches: []string{"{literal}"},
+			fails:   []string{"literal"},
+		},
+		{
+			value:   "{unclosed",
+			wantErr: true,
+		},
+		{
+			value:   "unopened}",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			re, err := compileAttributeValue(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("compileAttributeValue() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			for _, v := range tc.matches {
+				if !re.MatchString(v) {
+					t.Errorf("expected %q to match %q", v, tc.value)
+				}
+			}
+			for _, v := range tc.fails {
+				if re.MatchString(v) {
+					t.Errorf("expected %q not to match %q", v, tc.value)
+				}
+			}
+		})
+	}
+}
+
+func newEventType(name, broker, eventType, source string) *v1beta2.EventType {
+	et := &v1beta2.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID("uid-" + name),
+			ResourceVersion: "1",
+		},
+		Spec: v1beta2.EventTypeSpec{
+			Reference: &duckv1.KReference{
+				APIVersion: "eventing.knative.dev/v1",
+				Kind:       "Broker",
+				Name:       broker,
+			},
+			Type: eventType,
+		},
+	}
+	if source != "" {
+		et.Spec.Source, _ = apis.ParseURL(source)
+	}
+	return et
+}
@@ -198,10 +198,23 @@ func (g *Graph) AddSubscription(subscription messagingv1.Subscription) error {
 }
 
 func getTransformForTrigger(trigger eventingv1.Trigger) Transform {
+	filter := getFilterTransformForTrigger(trigger)
+	if trigger.Spec.Transform == nil {
+		return filter
+	}
+
+	return ChainTransform{Transforms: []Transform{filter, TriggerTransformTransform{Transform: trigger.Spec.Transform}}}
+}
+
+func getFilterTransformForTrigger(trigger eventingv1.Trigger) Transform {
 	if len(trigger.Spec.Filters) == 0 && trigger.Spec.Filter != nil {
 		return &AttributesFilterTransform{Filter: trigger.Spec.Filter}
 	}
 
+	if len(trigger.Spec.Filters) > 0 {
+		return SubscriptionsAPIFiltersTransform{Filters: trigger.Spec.Filters}
+	}
+
 	return NoTransform{}
 }
 
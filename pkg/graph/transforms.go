@@ -18,9 +18,14 @@ package graph
 
 import (
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
 
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	eventingv1beta3 "knative.dev/eventing/pkg/apis/eventing/v1beta3"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -133,6 +138,283 @@ func buildRegexForAttribute(attribute string) (*regexp.Regexp, error) {
 	return regexp.Compile(strings.Join(chunks, ""))
 }
 
+type SubscriptionsAPIFiltersTransform struct {
+	Filters []eventingv1.SubscriptionsAPIFilter
+}
+
+var _ Transform = SubscriptionsAPIFiltersTransform{}
+
+// Apply will narrow the eventtype to represent only events which could pass all the filters. Only the dialects which
+// constrain the value of attributes (exact, regex and range, also when nested in all) are taken into account, the
+// others could let any event through as far as the eventtype is concerned, so they leave the eventtype as is.
+func (sft SubscriptionsAPIFiltersTransform) Apply(et *eventingv1beta3.EventType, tfc TransformFunctionContext) (*eventingv1beta3.EventType, TransformFunctionContext) {
+	for _, filter := range sft.Filters {
+		var transform Transform
+		switch {
+		case len(filter.Exact) > 0:
+			transform = &AttributesFilterTransform{Filter: &eventingv1.TriggerFilter{Attributes: filter.Exact}}
+		case len(filter.Regex) > 0:
+			transform = RegexFilterTransform{Regex: filter.Regex}
+		case len(filter.Range) > 0:
+			transform = RangeFilterTransform{Range: filter.Range}
+		case len(filter.All) > 0:
+			transform = SubscriptionsAPIFiltersTransform{Filters: filter.All}
+		default:
+			continue
+		}
+		if et, tfc = transform.Apply(et, tfc); et == nil {
+			return nil, tfc
+		}
+	}
+	return et, tfc
+}
+
+func (sft SubscriptionsAPIFiltersTransform) Name() string {
+	return "subscriptions-api-filters"
+}
+
+type RegexFilterTransform struct {
+	Regex map[string]string
+}
+
+var _ Transform = RegexFilterTransform{}
+
+// Apply will return nil if the eventtype has a fixed value for one of the filtered attributes which doesn't match the
+// regular expression. Since a regular expression can't be represented as an attribute value, attributes which are not
+// set or contain variables are left as they are.
+func (rft RegexFilterTransform) Apply(et *eventingv1beta3.EventType, tfc TransformFunctionContext) (*eventingv1beta3.EventType, TransformFunctionContext) {
+	for i := range et.Spec.Attributes {
+		attribute := &et.Spec.Attributes[i]
+		expression, ok := rft.Regex[attribute.Name]
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, tfc
+		}
+		value, ok := fixedAttributeValue(attribute.Value)
+		if !ok {
+			continue
+		}
+		if !re.MatchString(value) {
+			return nil, tfc
+		}
+		attribute.Required = true
+	}
+
+	return et, tfc
+}
+
+func (rft RegexFilterTransform) Name() string {
+	return "regex-filter"
+}
+
+type RangeFilterTransform struct {
+	Range map[string]eventingv1.SubscriptionsAPIFilterRange
+}
+
+var _ Transform = RangeFilterTransform{}
+
+// Apply will return nil if the eventtype has a fixed value for one of the filtered attributes which is either not a
+// finite number or out of the range. Attributes which are not set or contain variables are left as they are.
+func (rft RangeFilterTransform) Apply(et *eventingv1beta3.EventType, tfc TransformFunctionContext) (*eventingv1beta3.EventType, TransformFunctionContext) {
+	for i := range et.Spec.Attributes {
+		attribute := &et.Spec.Attributes[i]
+		r, ok := rft.Range[attribute.Name]
+		if !ok {
+			continue
+		}
+		value, ok := fixedAttributeValue(attribute.Value)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) ||
+			(r.Min != nil && n < float64(*r.Min)) || (r.Max != nil && n > float64(*r.Max)) {
+			return nil, tfc
+		}
+		attribute.Required = true
+	}
+
+	return et, tfc
+}
+
+func (rft RangeFilterTransform) Name() string {
+	return "range-filter"
+}
+
+// fixedAttributeValue returns the value of an eventtype attribute with the escaped curly brackets unescaped. If the
+// value is empty or contains variables, it can take more than one value and false is returned.
+func fixedAttributeValue(attribute string) (string, bool) {
+	if attribute == "" {
+		return "", false
+	}
+
+	var value strings.Builder
+	for i := 0; i < len(attribute); i++ {
+		if attribute[i] == '\\' && i+1 < len(attribute) && (attribute[i+1] == '{' || attribute[i+1] == '}') {
+			value.WriteByte(attribute[i+1])
+			i++
+			continue
+		} else if attribute[i] == '{' || attribute[i] == '}' {
+			return "", false
+		}
+		value.WriteByte(attribute[i])
+	}
+	return value.String(), true
+}
+
+type ChainTransform struct {
+	Transforms []Transform
+}
+
+var _ Transform = ChainTransform{}
+
+// Apply applies the transforms one after the other, stopping as soon as one of them returns nil.
+func (ct ChainTransform) Apply(et *eventingv1beta3.EventType, tfc TransformFunctionContext) (*eventingv1beta3.EventType, TransformFunctionContext) {
+	for _, transform := range ct.Transforms {
+		if et, tfc = transform.Apply(et, tfc); et == nil {
+			return nil, tfc
+		}
+	}
+	return et, tfc
+}
+
+func (ct ChainTransform) Name() string {
+	return "chain-transform"
+}
+
+type TriggerTransformTransform struct {
+	Transform *eventingv1.TriggerTransform
+}
+
+var _ Transform = TriggerTransformTransform{}
+
+// Apply rewrites the eventtype the same way the trigger transform rewrites the events. Templated values are evaluated
+// against the attributes of the eventtype, so that for example "{{ .Type }}.v2" appends ".v2" to the type of the
+// eventtype. The attributes the eventtype doesn't define are represented by variables named after them, and the values
+// which can't be evaluated become a variable named after the attribute being set.
+func (ttt TriggerTransformTransform) Apply(et *eventingv1beta3.EventType, tfc TransformFunctionContext) (*eventingv1beta3.EventType, TransformFunctionContext) {
+	etAttributes := make(map[string]eventingv1beta3.EventAttributeDefinition, len(et.Spec.Attributes))
+	for _, attribute := range et.Spec.Attributes {
+		etAttributes[attribute.Name] = attribute
+	}
+	// templates are evaluated against the eventtype as it was before the transform
+	event := eventForTemplates(etAttributes)
+
+	for from, to := range ttt.Transform.Rename {
+		if attribute, ok := etAttributes[from]; ok {
+			delete(etAttributes, from)
+			attribute.Name = to
+			etAttributes[to] = attribute
+		}
+	}
+
+	for name, value := range ttt.Transform.Set {
+		etAttributes[name] = eventingv1beta3.EventAttributeDefinition{
+			Name:     name,
+			Value:    evaluateTemplateForAttribute(name, value, event),
+			Required: true,
+		}
+	}
+
+	for _, name := range ttt.Transform.Remove {
+		delete(etAttributes, name)
+	}
+
+	updatedAttributes := make([]eventingv1beta3.EventAttributeDefinition, 0, len(etAttributes))
+	for _, v := range etAttributes {
+		updatedAttributes = append(updatedAttributes, v)
+	}
+
+	et.Spec.Attributes = updatedAttributes
+
+	return et, tfc
+}
+
+func (ttt TriggerTransformTransform) Name() string {
+	return "trigger-transform"
+}
+
+// templateEvent exposes the attributes of an eventtype with the same names the event has in the templates.
+type templateEvent struct {
+	ID              string
+	Source          string
+	SpecVersion     string
+	Type            string
+	DataContentType string
+	DataSchema      string
+	Subject         string
+	Extensions      map[string]interface{}
+}
+
+// eventForTemplates builds an event whose attributes are the (possibly variable) values of the eventtype attributes.
+func eventForTemplates(etAttributes map[string]eventingv1beta3.EventAttributeDefinition) templateEvent {
+	valueOf := func(name string) string {
+		if attribute, ok := etAttributes[name]; ok && attribute.Value != "" {
+			return attribute.Value
+		}
+		return "{" + name + "}"
+	}
+
+	event := templateEvent{
+		ID:              valueOf("id"),
+		Source:          valueOf("source"),
+		SpecVersion:     valueOf("specversion"),
+		Type:            valueOf("type"),
+		DataContentType: valueOf("datacontenttype"),
+		DataSchema:      valueOf("dataschema"),
+		Subject:         valueOf("subject"),
+		Extensions:      make(map[string]interface{}),
+	}
+	for name, attribute := range etAttributes {
+		if spec.V1.Attribute(name) == nil && attribute.Value != "" {
+			event.Extensions[name] = attribute.Value
+		}
+	}
+	return event
+}
+
+// evaluateTemplateForAttribute returns the eventtype value of an attribute set to the given template. The literal text
+// of the template is escaped, while the values coming from the eventtype are already in the eventtype syntax.
+func evaluateTemplateForAttribute(name, text string, event templateEvent) string {
+	variable := "{" + name + "}"
+	tmpl, err := eventingduckv1.ParseSubstitutionTemplate(template.New(name).Option("missingkey=error"), escapeTemplateText(text))
+	if err != nil {
+		return variable
+	}
+	b, err := eventingduckv1.ExecuteTemplate(tmpl, event, eventingv1.TriggerTransformValueMaxSize)
+	if err != nil {
+		return variable
+	}
+	return string(b)
+}
+
+// escapeTemplateText escapes the curly brackets of the literal text of a template, leaving its actions as they are.
+func escapeTemplateText(text string) string {
+	var b strings.Builder
+	for len(text) > 0 {
+		start := strings.Index(text, "{{")
+		literal := text
+		if start >= 0 {
+			literal = text[:start]
+		}
+		b.WriteString(strings.NewReplacer("{", "\\{", "}", "\\}").Replace(literal))
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			b.WriteString(text[start:])
+			break
+		}
+		b.WriteString(text[start : start+end+2])
+		text = text[start+end+2:]
+	}
+	return b.String()
+}
+
 type EventTypeTransform struct {
 	EventType *eventingv1beta3.EventType
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"knative.dev/pkg/ptr"
 
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	eventingv1beta3 "knative.dev/eventing/pkg/apis/eventing/v1beta3"
@@ -194,3 +195,283 @@ func TestAttributeFilterTransform(t *testing.T) {
 		})
 	}
 }
+
+func TestRegexFilterTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []eventingv1beta3.EventAttributeDefinition
+		expected []eventingv1beta3.EventAttributeDefinition
+		regex    map[string]string
+	}{
+		{
+			name:     "attribute not set",
+			input:    []eventingv1beta3.EventAttributeDefinition{},
+			expected: []eventingv1beta3.EventAttributeDefinition{},
+			regex:    map[string]string{"type": `^example\.`},
+		},
+		{
+			name: "attribute matching",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.event.type"},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.event.type", Required: true},
+			},
+			regex: map[string]string{"type": `^example\.`},
+		},
+		{
+			name: "attribute not matching",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "sample.event.type", Required: true},
+			},
+			regex: map[string]string{"type": `^example\.`},
+		},
+		{
+			name: "attribute with variable",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "{prefix}.event.type", Required: true},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "{prefix}.event.type", Required: true},
+			},
+			regex: map[string]string{"type": `^example\.`},
+		},
+		{
+			name: "attribute with escaped brackets",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: `example.\{event\}`, Required: true},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: `example.\{event\}`, Required: true},
+			},
+			regex: map[string]string{"type": `^example\.\{event\}$`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transform := RegexFilterTransform{Regex: test.regex}
+			out, _ := transform.Apply(&eventingv1beta3.EventType{Spec: eventingv1beta3.EventTypeSpec{Attributes: test.input}}, TransformFunctionContext{})
+			if test.expected == nil {
+				assert.Nil(t, out)
+			} else {
+				assert.ElementsMatch(t, test.expected, out.Spec.Attributes)
+			}
+		})
+	}
+}
+
+func TestRangeFilterTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []eventingv1beta3.EventAttributeDefinition
+		expected []eventingv1beta3.EventAttributeDefinition
+		ranges   map[string]eventingv1.SubscriptionsAPIFilterRange
+	}{
+		{
+			name:     "attribute not set",
+			input:    []eventingv1beta3.EventAttributeDefinition{},
+			expected: []eventingv1beta3.EventAttributeDefinition{},
+			ranges:   map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)}},
+		},
+		{
+			name: "attribute within range",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "5"},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "5", Required: true},
+			},
+			ranges: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)}},
+		},
+		{
+			name: "attribute out of range",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "8"},
+			},
+			ranges: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)}},
+		},
+		{
+			name: "attribute not a number",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "high"},
+			},
+			ranges: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3)}},
+		},
+		{
+			name: "attribute NaN",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "NaN"},
+			},
+			ranges: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3), Max: ptr.Int64(7)}},
+		},
+		{
+			name: "attribute infinite",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "Inf"},
+			},
+			ranges: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3)}},
+		},
+		{
+			name: "attribute with variable",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "{priority}"},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "priority", Value: "{priority}"},
+			},
+			ranges: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Max: ptr.Int64(7)}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transform := RangeFilterTransform{Range: test.ranges}
+			out, _ := transform.Apply(&eventingv1beta3.EventType{Spec: eventingv1beta3.EventTypeSpec{Attributes: test.input}}, TransformFunctionContext{})
+			if test.expected == nil {
+				assert.Nil(t, out)
+			} else {
+				assert.ElementsMatch(t, test.expected, out.Spec.Attributes)
+			}
+		})
+	}
+}
+
+func TestSubscriptionsAPIFiltersTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []eventingv1beta3.EventAttributeDefinition
+		expected []eventingv1beta3.EventAttributeDefinition
+		filters  []eventingv1.SubscriptionsAPIFilter
+	}{
+		{
+			name: "all filters pass",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.{variable}.type", Required: true},
+				{Name: "priority", Value: "5", Required: true},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.event.type", Required: true},
+				{Name: "priority", Value: "5", Required: true},
+				{Name: "source", Value: "/sample/source", Required: true},
+			},
+			filters: []eventingv1.SubscriptionsAPIFilter{
+				{Exact: map[string]string{"type": "example.event.type"}},
+				{All: []eventingv1.SubscriptionsAPIFilter{
+					{Exact: map[string]string{"source": "/sample/source"}},
+					{Range: map[string]eventingv1.SubscriptionsAPIFilterRange{"priority": {Min: ptr.Int64(3)}}},
+				}},
+				{CESQL: "type = 'another.event.type'"},
+			},
+		},
+		{
+			name: "one filter fails",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.event.type", Required: true},
+			},
+			filters: []eventingv1.SubscriptionsAPIFilter{
+				{Prefix: map[string]string{"type": "example"}},
+				{Regex: map[string]string{"type": "^sample"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transform := SubscriptionsAPIFiltersTransform{Filters: test.filters}
+			out, _ := transform.Apply(&eventingv1beta3.EventType{Spec: eventingv1beta3.EventTypeSpec{Attributes: test.input}}, TransformFunctionContext{})
+			if test.expected == nil {
+				assert.Nil(t, out)
+			} else {
+				assert.ElementsMatch(t, test.expected, out.Spec.Attributes)
+			}
+		})
+	}
+}
+
+func TestTriggerTransformTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []eventingv1beta3.EventAttributeDefinition
+		expected  []eventingv1beta3.EventAttributeDefinition
+		transform *eventingv1.TriggerTransform
+	}{
+		{
+			name: "set with templates",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.{variable}.type", Required: true},
+				{Name: "tenant", Value: "acme", Required: true},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.{variable}.type.v2", Required: true},
+				{Name: "tenant", Value: "acme", Required: true},
+				{Name: "owner", Value: "acme", Required: true},
+				{Name: "origin", Value: "{source}", Required: true},
+				{Name: "when", Value: "{when}", Required: true},
+				{Name: "subject", Value: "\\{literal\\}", Required: true},
+				{Name: "missing", Value: "{missing}", Required: true},
+			},
+			transform: &eventingv1.TriggerTransform{
+				Set: map[string]string{
+					"type":    "{{ .Type }}.v2",
+					"owner":   "{{ .Extensions.tenant }}",
+					"origin":  "{{ .Source }}",
+					"when":    "{{ .Time }}",
+					"subject": "{literal}",
+					"missing": "{{ .Extensions.notset }}",
+				},
+			},
+		},
+		{
+			name: "rename and remove",
+			input: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.event.type", Required: true},
+				{Name: "tenant", Value: "acme", Required: false},
+				{Name: "subject", Value: "sample", Required: true},
+			},
+			expected: []eventingv1beta3.EventAttributeDefinition{
+				{Name: "type", Value: "example.event.type", Required: true},
+				{Name: "owner", Value: "acme", Required: false},
+			},
+			transform: &eventingv1.TriggerTransform{
+				Rename: map[string]string{"tenant": "owner", "notset": "other"},
+				Remove: []string{"subject"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transform := TriggerTransformTransform{Transform: test.transform}
+			out, _ := transform.Apply(&eventingv1beta3.EventType{Spec: eventingv1beta3.EventTypeSpec{Attributes: test.input}}, TransformFunctionContext{})
+			assert.ElementsMatch(t, test.expected, out.Spec.Attributes)
+		})
+	}
+}
+
+func TestGetTransformForTrigger(t *testing.T) {
+	trigger := eventingv1.Trigger{
+		Spec: eventingv1.TriggerSpec{
+			Filter: &eventingv1.TriggerFilter{
+				Attributes: map[string]string{"type": "example.event.type"},
+			},
+			Transform: &eventingv1.TriggerTransform{
+				Set: map[string]string{"type": "{{ .Type }}.v2"},
+			},
+		},
+	}
+	transform := getTransformForTrigger(trigger)
+
+	out, _ := transform.Apply(&eventingv1beta3.EventType{Spec: eventingv1beta3.EventTypeSpec{Attributes: []eventingv1beta3.EventAttributeDefinition{
+		{Name: "type", Value: "example.{variable}.type", Required: true},
+	}}}, TransformFunctionContext{})
+	assert.ElementsMatch(t, []eventingv1beta3.EventAttributeDefinition{
+		{Name: "type", Value: "example.event.type.v2", Required: true},
+	}, out.Spec.Attributes)
+
+	// Events filtered out are not transformed.
+	out, _ = transform.Apply(&eventingv1beta3.EventType{Spec: eventingv1beta3.EventTypeSpec{Attributes: []eventingv1beta3.EventAttributeDefinition{
+		{Name: "type", Value: "other.event.type", Required: true},
+	}}}, TransformFunctionContext{})
+	assert.Nil(t, out)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+)
+
+const (
+	KnativeErrorAttemptsExtensionKey = "knativeerrorattempts"
+	KnativeErrorFirstExtensionKey    = "knativeerrorfirst"
+	KnativeErrorLastExtensionKey     = "knativeerrorlast"
+	KnativeErrorHistoryExtensionKey  = "knativeerrorhistory"
+	KnativeErrorResourceExtensionKey = "knativeerrorresource"
+
+	// KnativeErrorHistoryDataMaxLength is the maximum length of the base64 encoded response body
+	// of each attempt of the history.
+	KnativeErrorHistoryDataMaxLength = 256
+	// KnativeErrorHistoryMaxAttempts is the maximum number of attempts of the history, only the
+	// last attempts are kept.
+	KnativeErrorHistoryMaxAttempts = 16
+)
+
+// KnativeErrorAttempt is an attempt to send an event, as listed in the knativeerrorhistory
+// extension.
+type KnativeErrorAttempt struct {
+	// Time is when the attempt started.
+	Time time.Time `json:"time"`
+	// Code is the response status code, -1 when there is no response.
+	Code int `json:"code"`
+	// Data is the base64 encoded response body, truncated to KnativeErrorHistoryDataMaxLength.
+	Data string `json:"data,omitempty"`
+}
+
+// NewKnativeErrorAttempt returns the KnativeErrorAttempt with the given start time, response
+// status code and response body.
+func NewKnativeErrorAttempt(start time.Time, code int, body []byte) KnativeErrorAttempt {
+	// Truncate the body before encoding it, so that the data is still valid base64.
+	if maxLen := base64.StdEncoding.DecodedLen(KnativeErrorHistoryDataMaxLength); len(body) > maxLen {
+		body = body[:maxLen]
+	}
+	return KnativeErrorAttempt{
+		Time: start.UTC(),
+		Code: code,
+		Data: base64.StdEncoding.EncodeToString(body),
+	}
+}
+
+// KnativeErrorHistoryTransformers returns Transformers which add the number of attempts to send
+// the event, the start time of the first and last attempts, the history of the attempts and the
+// resource which failed to deliver the event, when not empty.
+func KnativeErrorHistoryTransformers(attempts []KnativeErrorAttempt, resource string) binding.Transformers {
+	transformers := binding.Transformers{
+		transformer.AddExtension(KnativeErrorAttemptsExtensionKey, len(attempts)),
+	}
+	if len(attempts) > 0 {
+		transformers = append(transformers,
+			transformer.AddExtension(KnativeErrorFirstExtensionKey, attempts[0].Time),
+			transformer.AddExtension(KnativeErrorLastExtensionKey, attempts[len(attempts)-1].Time),
+		)
+	}
+
+	history := attempts
+	if len(history) > KnativeErrorHistoryMaxAttempts {
+		history = history[len(history)-KnativeErrorHistoryMaxAttempts:]
+	}
+	if history == nil {
+		history = []KnativeErrorAttempt{}
+	}
+	// Marshaling the attempts can't fail.
+	data, _ := json.Marshal(history)
+	transformers = append(transformers, transformer.AddExtension(KnativeErrorHistoryExtensionKey, string(data)))
+
+	if resource != "" {
+		transformers = append(transformers, transformer.AddExtension(KnativeErrorResourceExtensionKey, resource))
+	}
+	return transformers
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cebindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKnativeErrorAttempt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	attempt := NewKnativeErrorAttempt(start, 503, []byte("unavailable"))
+	assert.Equal(t, KnativeErrorAttempt{Time: start, Code: 503, Data: base64.StdEncoding.EncodeToString([]byte("unavailable"))}, attempt)
+
+	attempt = NewKnativeErrorAttempt(start, 500, []byte(strings.Repeat("a", KnativeErrorHistoryDataMaxLength)))
+	assert.Len(t, attempt.Data, KnativeErrorHistoryDataMaxLength)
+	data, err := base64.StdEncoding.DecodeString(attempt.Data)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", base64.StdEncoding.DecodedLen(KnativeErrorHistoryDataMaxLength)), string(data))
+
+	// The body isn't truncated in the middle of a base64 block.
+	attempt = NewKnativeErrorAttempt(start, 500, []byte(strings.Repeat("a", KnativeErrorHistoryDataMaxLength-1)))
+	_, err = base64.StdEncoding.DecodeString(attempt.Data)
+	assert.NoError(t, err)
+}
+
+func TestKnativeErrorHistoryTransformers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var manyAttempts []KnativeErrorAttempt
+	for i := 0; i < KnativeErrorHistoryMaxAttempts+2; i++ {
+		manyAttempts = append(manyAttempts, KnativeErrorAttempt{Time: start.Add(time.Duration(i) * time.Second), Code: 500})
+	}
+
+	testCases := []struct {
+		name        string
+		attempts    []KnativeErrorAttempt
+		resource    string
+		wantHistory []KnativeErrorAttempt
+	}{
+		{
+			name:        "No Attempts",
+			resource:    "Trigger/ns/name",
+			wantHistory: []KnativeErrorAttempt{},
+		},
+		{
+			name: "Attempts",
+			attempts: []KnativeErrorAttempt{
+				{Time: start, Code: 503, Data: "dW5hdmFpbGFibGU="},
+				{Time: start.Add(time.Second), Code: -1},
+			},
+			resource: "Subscription/ns/name",
+			wantHistory: []KnativeErrorAttempt{
+				{Time: start, Code: 503, Data: "dW5hdmFpbGFibGU="},
+				{Time: start.Add(time.Second), Code: -1},
+			},
+		},
+		{
+			name:        "More Than Max Attempts",
+			attempts:    manyAttempts,
+			wantHistory: manyAttempts[2:],
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			transformers := KnativeErrorHistoryTransformers(testCase.attempts, testCase.resource)
+
+			inputEvent := cetest.MinEvent()
+			wantEvent := inputEvent.Clone()
+			wantEvent.SetExtension(KnativeErrorAttemptsExtensionKey, len(testCase.attempts))
+			if len(testCase.attempts) > 0 {
+				wantEvent.SetExtension(KnativeErrorFirstExtensionKey, testCase.attempts[0].Time)
+				wantEvent.SetExtension(KnativeErrorLastExtensionKey, testCase.attempts[len(testCase.attempts)-1].Time)
+			}
+			history, err := json.Marshal(testCase.wantHistory)
+			assert.Nil(t, err)
+			wantEvent.SetExtension(KnativeErrorHistoryExtensionKey, string(history))
+			if testCase.resource != "" {
+				wantEvent.SetExtension(KnativeErrorResourceExtensionKey, testCase.resource)
+			}
+
+			cebindingtest.RunTransformerTests(t, context.Background(), []cebindingtest.TransformerTestArgs{
+				{
+					Name:         "Add Extensions To Event",
+					InputEvent:   inputEvent,
+					WantEvent:    wantEvent,
+					Transformers: binding.Transformers{transformers},
+				},
+				{
+					Name:         "Add Extensions To Message",
+					InputMessage: binding.ToMessage(&inputEvent),
+					WantEvent:    wantEvent,
+					Transformers: binding.Transformers{transformers},
+				},
+			})
+		})
+	}
+}
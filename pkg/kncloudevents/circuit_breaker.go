@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rickb777/date/period"
+
+	v1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+const (
+	defaultCircuitBreakerFailurePercentage = 50
+	defaultCircuitBreakerMinimumRequests   = 10
+	defaultCircuitBreakerWindow            = time.Minute
+	defaultCircuitBreakerOpenDuration      = 30 * time.Second
+)
+
+// ErrCircuitBreakerOpen is returned for the events which aren't sent to their destination because
+// its circuit breaker is open.
+var ErrCircuitBreakerOpen = errors.New("circuit breaker open")
+
+// CircuitBreakerState is the state of the circuit breaker of a destination.
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerClosed lets the events through to the destination.
+	CircuitBreakerClosed CircuitBreakerState = "closed"
+	// CircuitBreakerOpen stops sending the events to the destination.
+	CircuitBreakerOpen CircuitBreakerState = "open"
+	// CircuitBreakerHalfOpen lets a single event through to the destination, to probe whether it
+	// recovered.
+	CircuitBreakerHalfOpen CircuitBreakerState = "halfOpen"
+)
+
+type CircuitBreakerConfig struct {
+	// FailureRatio is the ratio of the failed requests within the window opening the circuit breaker.
+	FailureRatio float64
+	// MinimumRequests is the minimum number of requests within the window to open the circuit breaker.
+	MinimumRequests int
+	// Window is the duration over which the failed requests are counted.
+	Window time.Duration
+	// OpenDuration is how long the circuit breaker stays open before probing the destination.
+	OpenDuration time.Duration
+	// FailFast fails the events while the circuit breaker is open, instead of sending them to
+	// the dead letter sink.
+	FailFast bool
+	// OnStateChange, when set, is called when the state of the circuit breaker changes.
+	OnStateChange func(state CircuitBreakerState)
+}
+
+// CircuitBreakerConfigFromDeliverySpec returns the circuit breaker configuration of the delivery
+// spec, or nil when it has no circuit breaker.
+func CircuitBreakerConfigFromDeliverySpec(spec v1.DeliverySpec) (*CircuitBreakerConfig, error) {
+	cb := spec.CircuitBreaker
+	if cb == nil {
+		return nil, nil
+	}
+
+	config := &CircuitBreakerConfig{
+		FailureRatio:    defaultCircuitBreakerFailurePercentage / 100.0,
+		MinimumRequests: defaultCircuitBreakerMinimumRequests,
+		Window:          defaultCircuitBreakerWindow,
+		OpenDuration:    defaultCircuitBreakerOpenDuration,
+		FailFast:        cb.OpenAction != nil && *cb.OpenAction == v1.CircuitBreakerOpenActionFail,
+	}
+	if cb.FailurePercentage != nil {
+		config.FailureRatio = float64(*cb.FailurePercentage) / 100
+	}
+	if cb.MinimumRequests != nil {
+		config.MinimumRequests = int(*cb.MinimumRequests)
+	}
+	if cb.Window != nil {
+		window, err := period.Parse(*cb.Window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Spec.CircuitBreaker.Window: %w", err)
+		}
+		config.Window, _ = window.Duration()
+	}
+	if cb.OpenDuration != nil {
+		openDuration, err := period.Parse(*cb.OpenDuration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Spec.CircuitBreaker.OpenDuration: %w", err)
+		}
+		config.OpenDuration, _ = openDuration.Duration()
+	}
+	return config, nil
+}
+
+// circuitBreaker tracks the failures of the requests to a destination.
+type circuitBreaker struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	state       CircuitBreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	// probing is true while the request probing the destination in the half-open state is
+	// in flight.
+	probing bool
+}
+
+func newCircuitBreaker(now func() time.Time) *circuitBreaker {
+	return &circuitBreaker{
+		now:         now,
+		state:       CircuitBreakerClosed,
+		windowStart: now(),
+	}
+}
+
+// allow returns whether a request can be sent to the destination. The result of the request must
+// then be recorded.
+func (cb *circuitBreaker) allow(config *CircuitBreakerConfig) bool {
+	if cb == nil {
+		return true
+	}
+	cb.mu.Lock()
+	now := cb.now()
+	switch cb.state {
+	case CircuitBreakerOpen:
+		if now.Sub(cb.openedAt) < config.OpenDuration {
+			cb.mu.Unlock()
+			return false
+		}
+		cb.state = CircuitBreakerHalfOpen
+		cb.probing = true
+		cb.mu.Unlock()
+		notifyStateChange(config, CircuitBreakerHalfOpen)
+		return true
+	case CircuitBreakerHalfOpen:
+		allowed := !cb.probing
+		cb.probing = true
+		cb.mu.Unlock()
+		return allowed
+	default:
+		if now.Sub(cb.windowStart) >= config.Window {
+			cb.windowStart = now
+			cb.requests = 0
+			cb.failures = 0
+		}
+		cb.mu.Unlock()
+		return true
+	}
+}
+
+// record records the result of a request allowed by the circuit breaker.
+func (cb *circuitBreaker) record(config *CircuitBreakerConfig, dispatchInfo *DispatchInfo, err error) {
+	if cb == nil {
+		return
+	}
+	failed := isCircuitBreakerFailure(dispatchInfo, err)
+
+	cb.mu.Lock()
+	now := cb.now()
+	var changed CircuitBreakerState
+	switch cb.state {
+	case CircuitBreakerHalfOpen:
+		cb.probing = false
+		if failed {
+			changed = cb.open(now)
+		} else {
+			changed = CircuitBreakerClosed
+			cb.state = CircuitBreakerClosed
+			cb.windowStart = now
+			cb.requests = 0
+			cb.failures = 0
+		}
+	case CircuitBreakerClosed:
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if cb.requests >= config.MinimumRequests && float64(cb.failures) >= config.FailureRatio*float64(cb.requests) {
+			changed = cb.open(now)
+		}
+	}
+	cb.mu.Unlock()
+
+	if changed != "" {
+		notifyStateChange(config, changed)
+	}
+}
+
+func (cb *circuitBreaker) open(now time.Time) CircuitBreakerState {
+	cb.state = CircuitBreakerOpen
+	cb.openedAt = now
+	return CircuitBreakerOpen
+}
+
+func notifyStateChange(config *CircuitBreakerConfig, state CircuitBreakerState) {
+	if config.OnStateChange != nil {
+		config.OnStateChange(state)
+	}
+}
+
+// isCircuitBreakerFailure returns whether the result of a request counts as a failure of the
+// destination, as opposed to a rejection of the event.
+func isCircuitBreakerFailure(dispatchInfo *DispatchInfo, err error) bool {
+	if err == nil {
+		return false
+	}
+	if dispatchInfo == nil {
+		return true
+	}
+	code := dispatchInfo.ResponseCode
+	return code <= 0 || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// circuitBreakerFor returns the circuit breaker of the destination, or nil when the config is nil.
+func (d *Dispatcher) circuitBreakerFor(destination string, config *CircuitBreakerConfig) *circuitBreaker {
+	if config == nil {
+		return nil
+	}
+	if cb, ok := d.circuitBreakers.Load(destination); ok {
+		return cb.(*circuitBreaker)
+	}
+	cb, _ := d.circuitBreakers.LoadOrStore(destination, newCircuitBreaker(time.Now))
+	return cb.(*circuitBreaker)
+}
+
+// DeleteCircuitBreaker removes the circuit breaker of the destination, when it is no longer used.
+// A new circuit breaker, closed, is created if events are sent to the destination again.
+func (d *Dispatcher) DeleteCircuitBreaker(destination string) {
+	d.circuitBreakers.Delete(destination)
+}
+
+// circuitOpenDispatchInfo is the dispatch info of the events which aren't sent to their
+// destination because its circuit breaker is open.
+func circuitOpenDispatchInfo(scheme string) *DispatchInfo {
+	return &DispatchInfo{
+		Duration:       0,
+		ResponseCode:   http.StatusServiceUnavailable,
+		ResponseHeader: make(http.Header),
+		ResponseBody:   []byte(ErrCircuitBreakerOpen.Error()),
+		Scheme:         scheme,
+	}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/injection"
+
+	v1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/auth"
+	"knative.dev/eventing/pkg/eventingtls"
+)
+
+func TestCircuitBreakerConfigFromDeliverySpec(t *testing.T) {
+	tests := map[string]struct {
+		spec    v1.DeliverySpec
+		want    *CircuitBreakerConfig
+		wantErr bool
+	}{
+		"no circuit breaker": {},
+		"defaults": {
+			spec: v1.DeliverySpec{CircuitBreaker: &v1.CircuitBreakerSpec{}},
+			want: &CircuitBreakerConfig{
+				FailureRatio:    0.5,
+				MinimumRequests: 10,
+				Window:          time.Minute,
+				OpenDuration:    30 * time.Second,
+			},
+		},
+		"custom": {
+			spec: v1.DeliverySpec{CircuitBreaker: &v1.CircuitBreakerSpec{
+				FailurePercentage: ptr.To[int32](20),
+				MinimumRequests:   ptr.To[int32](5),
+				Window:            ptr.To("PT10S"),
+				OpenDuration:      ptr.To("PT2M"),
+				OpenAction:        ptr.To(v1.CircuitBreakerOpenActionFail),
+			}},
+			want: &CircuitBreakerConfig{
+				FailureRatio:    0.2,
+				MinimumRequests: 5,
+				Window:          10 * time.Second,
+				OpenDuration:    2 * time.Minute,
+				FailFast:        true,
+			},
+		},
+		"invalid window": {
+			spec:    v1.DeliverySpec{CircuitBreaker: &v1.CircuitBreakerSpec{Window: ptr.To("1m")}},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := CircuitBreakerConfigFromDeliverySpec(tc.spec)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CircuitBreakerConfigFromDeliverySpec() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected config (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	var states []CircuitBreakerState
+	config := &CircuitBreakerConfig{
+		FailureRatio:    0.5,
+		MinimumRequests: 4,
+		Window:          time.Minute,
+		OpenDuration:    30 * time.Second,
+		OnStateChange:   func(state CircuitBreakerState) { states = append(states, state) },
+	}
+	cb := newCircuitBreaker(func() time.Time { return now })
+
+	success := &DispatchInfo{ResponseCode: http.StatusAccepted}
+	rejected := &DispatchInfo{ResponseCode: http.StatusBadRequest}
+	failed := &DispatchInfo{ResponseCode: http.StatusInternalServerError}
+	send := func(info *DispatchInfo) bool {
+		if !cb.allow(config) {
+			return false
+		}
+		var err error
+		if info.ResponseCode >= 300 {
+			err = errors.New("failed")
+		}
+		cb.record(config, info, err)
+		return true
+	}
+
+	// The rejected events don't count as failures.
+	for _, info := range []*DispatchInfo{failed, rejected, rejected, success} {
+		send(info)
+	}
+	if len(states) != 0 {
+		t.Fatalf("unexpected state changes %v", states)
+	}
+
+	// The failures of the previous window are forgotten.
+	now = now.Add(time.Minute)
+	for _, info := range []*DispatchInfo{failed, success, success} {
+		send(info)
+	}
+	if len(states) != 0 {
+		t.Fatalf("unexpected state changes %v", states)
+	}
+	send(failed)
+	if diff := cmp.Diff([]CircuitBreakerState{CircuitBreakerOpen}, states); diff != "" {
+		t.Fatal("unexpected state changes (-want +got):", diff)
+	}
+
+	if send(success) {
+		t.Error("expected the request to be rejected while the circuit breaker is open")
+	}
+
+	// A single request probes the destination once the circuit breaker was open long enough.
+	now = now.Add(30 * time.Second)
+	if !cb.allow(config) {
+		t.Fatal("expected the probe to be allowed")
+	}
+	if cb.allow(config) {
+		t.Error("expected a single probe to be allowed")
+	}
+	cb.record(config, failed, errors.New("failed"))
+
+	now = now.Add(30 * time.Second)
+	send(success)
+
+	want := []CircuitBreakerState{
+		CircuitBreakerOpen,
+		CircuitBreakerHalfOpen,
+		CircuitBreakerOpen,
+		CircuitBreakerHalfOpen,
+		CircuitBreakerClosed,
+	}
+	if diff := cmp.Diff(want, states); diff != "" {
+		t.Error("unexpected state changes (-want +got):", diff)
+	}
+}
+
+func TestSendEventWithCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	var destinationRequests atomic.Int32
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		destinationRequests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer destination.Close()
+
+	var deadLetterRequests atomic.Int32
+	deadLetterSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadLetterRequests.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetterSink.Close()
+
+	tests := map[string]struct {
+		failFast           bool
+		circuitOpenOnly    bool
+		wantErr            bool
+		wantDeadLetterSent int32
+	}{
+		"dead letter": {
+			wantDeadLetterSent: 4,
+		},
+		"fail": {
+			failFast:           true,
+			wantErr:            true,
+			wantDeadLetterSent: 2,
+		},
+		"dead letter while open": {
+			circuitOpenOnly:    true,
+			wantDeadLetterSent: 2,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			destinationRequests.Store(0)
+			deadLetterRequests.Store(0)
+
+			dispatcher := NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx))
+			config := &CircuitBreakerConfig{
+				FailureRatio:    1,
+				MinimumRequests: 2,
+				Window:          time.Minute,
+				OpenDuration:    time.Minute,
+				FailFast:        tc.failFast,
+			}
+
+			deadLetterSinkOption := WithDeadLetterSink
+			if tc.circuitOpenOnly {
+				deadLetterSinkOption = WithCircuitOpenDeadLetterSink
+			}
+
+			for i := 0; i < 4; i++ {
+				info, err := dispatcher.SendEvent(ctx, test.MinEvent(), duckv1.Addressable{URL: apis.HTTP(destination.Listener.Addr().String())},
+					deadLetterSinkOption(&duckv1.Addressable{URL: apis.HTTP(deadLetterSink.Listener.Addr().String())}),
+					WithCircuitBreaker(config),
+				)
+				if i < 2 {
+					continue
+				}
+				if tc.wantErr {
+					if !errors.Is(err, ErrCircuitBreakerOpen) {
+						t.Errorf("expected the circuit breaker open error, got %v", err)
+					}
+					if info.ResponseCode != http.StatusServiceUnavailable {
+						t.Errorf("expected response code %d, got %d", http.StatusServiceUnavailable, info.ResponseCode)
+					}
+				} else if err != nil {
+					t.Error("unexpected error:", err)
+				}
+			}
+
+			if got := destinationRequests.Load(); got != 2 {
+				t.Errorf("expected 2 requests to the destination, got %d", got)
+			}
+			if got := deadLetterRequests.Load(); got != tc.wantDeadLetterSent {
+				t.Errorf("expected %d requests to the dead letter sink, got %d", tc.wantDeadLetterSent, got)
+			}
+		})
+	}
+}
+
+func TestDeleteCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	var destinationRequests atomic.Int32
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		destinationRequests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer destination.Close()
+
+	dispatcher := NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx))
+	config := &CircuitBreakerConfig{
+		FailureRatio:    1,
+		MinimumRequests: 2,
+		Window:          time.Minute,
+		OpenDuration:    time.Minute,
+		FailFast:        true,
+	}
+	addressable := duckv1.Addressable{URL: apis.HTTP(destination.Listener.Addr().String())}
+	send := func() error {
+		_, err := dispatcher.SendEvent(ctx, test.MinEvent(), addressable, WithCircuitBreaker(config))
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		_ = send()
+	}
+	if err := send(); !errors.Is(err, ErrCircuitBreakerOpen) {
+		t.Fatalf("expected the circuit breaker open error, got %v", err)
+	}
+
+	dispatcher.DeleteCircuitBreaker(addressable.URL.String())
+	if err := send(); errors.Is(err, ErrCircuitBreakerOpen) {
+		t.Error("expected the circuit breaker to be closed once deleted")
+	}
+	if got := destinationRequests.Load(); got != 3 {
+		t.Errorf("expected 3 requests to the destination, got %d", got)
+	}
+}
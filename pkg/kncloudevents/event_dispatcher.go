@@ -21,9 +21,12 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding/buffering"
@@ -64,6 +67,8 @@ type DispatchInfo struct {
 	ResponseHeader http.Header
 	ResponseBody   []byte
 	Scheme         string
+	// Attempts are the attempts to send the request, retries included.
+	Attempts []DispatchAttempt
 }
 
 type SendOption func(*senderConfig) error
@@ -84,6 +89,17 @@ func WithDeadLetterSink(dls *duckv1.Addressable) SendOption {
 	}
 }
 
+// WithCircuitOpenDeadLetterSink sends the events to the dead letter sink only when the circuit
+// breaker of the destination is open, for the callers whose upstream sends the events which failed
+// otherwise to the dead letter sink. It is ignored when WithDeadLetterSink is set.
+func WithCircuitOpenDeadLetterSink(dls *duckv1.Addressable) SendOption {
+	return func(sc *senderConfig) error {
+		sc.circuitOpenDeadLetterSink = dls
+
+		return nil
+	}
+}
+
 func WithRetryConfig(retryConfig *RetryConfig) SendOption {
 	return func(sc *senderConfig) error {
 		sc.retryConfig = retryConfig
@@ -108,6 +124,16 @@ func WithTransformers(transformers ...binding.Transformer) SendOption {
 	}
 }
 
+// WithReplyTransformers sets transformers which are applied only to the reply forwarded to the
+// reply destination, on top of the ones set with WithTransformers.
+func WithReplyTransformers(transformers ...binding.Transformer) SendOption {
+	return func(sc *senderConfig) error {
+		sc.replyTransformers = transformers
+
+		return nil
+	}
+}
+
 func WithOIDCAuthentication(serviceAccount *types.NamespacedName) SendOption {
 	return func(sc *senderConfig) error {
 		if serviceAccount != nil && serviceAccount.Name != "" && serviceAccount.Namespace != "" {
@@ -132,21 +158,72 @@ func WithEventTypeAutoHandler(handler *eventtype.EventTypeAutoHandler, ref *duck
 	}
 }
 
+// WithCircuitBreaker stops sending the events to the destination for a while once too many
+// requests to it failed. The circuit breaker is shared by the requests to the same destination URL.
+func WithCircuitBreaker(circuitBreaker *CircuitBreakerConfig) SendOption {
+	return func(sc *senderConfig) error {
+		sc.circuitBreaker = circuitBreaker
+
+		return nil
+	}
+}
+
+// WithThrottle bounds the concurrency and the rate of the requests sent to the destination, including
+// the retries. The limits are shared by the requests to the same destination URL with the same limits.
+func WithThrottle(throttle *ThrottleConfig) SendOption {
+	return func(sc *senderConfig) error {
+		sc.throttle = throttle
+
+		return nil
+	}
+}
+
+// ReplyRouter returns the destination of a reply event, or nil to leave the reply to the
+// destination set with WithReply.
+type ReplyRouter func(ctx context.Context, reply *event.Event) *duckv1.Addressable
+
+// WithReplyRouter sends each reply to the destination returned by the router, falling back to
+// the destination set with WithReply when the router returns nil.
+func WithReplyRouter(router ReplyRouter) SendOption {
+	return func(sc *senderConfig) error {
+		sc.replyRouter = router
+
+		return nil
+	}
+}
+
 type senderConfig struct {
-	reply                *duckv1.Addressable
-	deadLetterSink       *duckv1.Addressable
-	additionalHeaders    http.Header
-	retryConfig          *RetryConfig
-	transformers         binding.Transformers
-	oidcServiceAccount   *types.NamespacedName
-	eventTypeAutoHandler *eventtype.EventTypeAutoHandler
-	eventTypeRef         *duckv1.KReference
-	eventTypeOnwerUID    types.UID
+	reply             *duckv1.Addressable
+	replyRouter       ReplyRouter
+	deadLetterSink    *duckv1.Addressable
+	additionalHeaders http.Header
+	retryConfig       *RetryConfig
+	circuitBreaker    *CircuitBreakerConfig
+	throttle          *ThrottleConfig
+	// failureHistoryResource, when set, is the resource added with the failure history to the
+	// events sent to the dead letter sink.
+	failureHistoryResource *duckv1.KReference
+	transformers           binding.Transformers
+	replyTransformers      binding.Transformers
+	oidcServiceAccount     *types.NamespacedName
+	eventTypeAutoHandler   *eventtype.EventTypeAutoHandler
+	eventTypeRef           *duckv1.KReference
+	eventTypeOnwerUID      types.UID
+
+	// circuitOpenDeadLetterSink is the dead letter sink of the events rejected by an open circuit
+	// breaker, when deadLetterSink isn't set.
+	circuitOpenDeadLetterSink *duckv1.Addressable
 }
 
 type Dispatcher struct {
 	oidcTokenProvider *auth.OIDCTokenProvider
 	clientConfig      eventingtls.ClientConfig
+	// circuitBreakers holds the *circuitBreaker of the destinations, by URL.
+	circuitBreakers sync.Map
+	// throttles holds the *throttle of the destinations, by throttleKey.
+	throttles sync.Map
+	// throttlesEvicted is the time, in Unix nanoseconds, the idle throttles were last evicted.
+	throttlesEvicted atomic.Int64
 }
 
 func NewDispatcher(clientConfig eventingtls.ClientConfig, oidcTokenProvider *auth.OIDCTokenProvider) *Dispatcher {
@@ -204,6 +281,7 @@ func (d *Dispatcher) send(ctx context.Context, message binding.Message, destinat
 	destination = *sanitizeAddressable(&destination)
 	config.reply = sanitizeAddressable(config.reply)
 	config.deadLetterSink = sanitizeAddressable(config.deadLetterSink)
+	config.circuitOpenDeadLetterSink = sanitizeAddressable(config.circuitOpenDeadLetterSink)
 
 	// send to destination
 
@@ -214,14 +292,32 @@ func (d *Dispatcher) send(ctx context.Context, message binding.Message, destinat
 	}
 	additionalHeadersForDestination.Set("Prefer", "reply")
 
-	ctx, responseMessage, dispatchExecutionInfo, err := d.executeRequest(ctx, destination, message, additionalHeadersForDestination, config.retryConfig, config.oidcServiceAccount, config.transformers)
+	var responseMessage binding.Message
+	var err error
+	cb := d.circuitBreakerFor(destination.URL.String(), config.circuitBreaker)
+	if cb.allow(config.circuitBreaker) {
+		th := d.throttleFor(destination.URL.String(), config.throttle)
+		ctx, responseMessage, dispatchExecutionInfo, err = d.executeRequest(ctx, destination, message, additionalHeadersForDestination, config.retryConfig, config.oidcServiceAccount, th, config.transformers)
+		cb.record(config.circuitBreaker, dispatchExecutionInfo, err)
+	} else {
+		dispatchExecutionInfo, err = circuitOpenDispatchInfo(destination.URL.Scheme), ErrCircuitBreakerOpen
+	}
 	if err != nil {
+		circuitOpen := errors.Is(err, ErrCircuitBreakerOpen)
+		failFast := circuitOpen && config.circuitBreaker.FailFast
+		deadLetterSink := config.deadLetterSink
+		if deadLetterSink == nil && circuitOpen {
+			deadLetterSink = config.circuitOpenDeadLetterSink
+		}
 		// If DeadLetter is configured, then send original message with knative error extensions
-		if config.deadLetterSink != nil {
+		if deadLetterSink != nil && !failFast {
 			dispatchTransformers := dispatchExecutionInfoTransformers(destination.URL, dispatchExecutionInfo)
-			_, deadLetterResponse, dispatchExecutionInfo, deadLetterErr := d.executeRequest(ctx, *config.deadLetterSink, message, config.additionalHeaders, config.retryConfig, config.oidcServiceAccount, append(config.transformers, dispatchTransformers))
+			if config.failureHistoryResource != nil {
+				dispatchTransformers = append(dispatchTransformers, failureHistoryTransformers(destination.URL, dispatchExecutionInfo.Attempts, config.failureHistoryResource)...)
+			}
+			_, deadLetterResponse, dispatchExecutionInfo, deadLetterErr := d.executeRequest(ctx, *deadLetterSink, message, config.additionalHeaders, config.retryConfig, config.oidcServiceAccount, nil, append(config.transformers, dispatchTransformers))
 			if deadLetterErr != nil {
-				return dispatchExecutionInfo, fmt.Errorf("unable to complete request to either %s (%v) or %s (%v)", destination.URL, err, config.deadLetterSink.URL, deadLetterErr)
+				return dispatchExecutionInfo, fmt.Errorf("unable to complete request to either %s (%v) or %s (%v)", destination.URL, err, deadLetterSink.URL, deadLetterErr)
 			}
 			if deadLetterResponse != nil {
 				messagesToFinish = append(messagesToFinish, deadLetterResponse)
@@ -257,20 +353,37 @@ func (d *Dispatcher) send(ctx context.Context, message binding.Message, destinat
 		}
 	}
 
-	if config.reply == nil {
+	reply := config.reply
+	if config.replyRouter != nil {
+		// the reply is read once here to be routed, and once again to be sent
+		if buffered, err := buffering.CopyMessage(ctx, responseMessage); err == nil {
+			responseMessage = buffered
+			if replyEvent, err := binding.ToEvent(ctx, buffered); err == nil {
+				if route := config.replyRouter(ctx, replyEvent); route != nil {
+					reply = sanitizeAddressable(route)
+				}
+			}
+		}
+	}
+
+	if reply == nil {
 		return dispatchExecutionInfo, nil
 	}
 
 	// send reply
 
-	ctx, responseResponseMessage, dispatchExecutionInfo, err := d.executeRequest(ctx, *config.reply, responseMessage, responseAdditionalHeaders, config.retryConfig, config.oidcServiceAccount, config.transformers)
+	replyTransformers := append(append(binding.Transformers{}, config.transformers...), config.replyTransformers...)
+	ctx, responseResponseMessage, dispatchExecutionInfo, err := d.executeRequest(ctx, *reply, responseMessage, responseAdditionalHeaders, config.retryConfig, config.oidcServiceAccount, nil, replyTransformers...)
 	if err != nil {
 		// If DeadLetter is configured, then send original message with knative error extensions
 		if config.deadLetterSink != nil {
-			dispatchTransformers := dispatchExecutionInfoTransformers(config.reply.URL, dispatchExecutionInfo)
-			_, deadLetterResponse, dispatchExecutionInfo, deadLetterErr := d.executeRequest(ctx, *config.deadLetterSink, message, responseAdditionalHeaders, config.retryConfig, config.oidcServiceAccount, append(config.transformers, dispatchTransformers))
+			dispatchTransformers := dispatchExecutionInfoTransformers(reply.URL, dispatchExecutionInfo)
+			if config.failureHistoryResource != nil {
+				dispatchTransformers = append(dispatchTransformers, failureHistoryTransformers(reply.URL, dispatchExecutionInfo.Attempts, config.failureHistoryResource)...)
+			}
+			_, deadLetterResponse, dispatchExecutionInfo, deadLetterErr := d.executeRequest(ctx, *config.deadLetterSink, message, responseAdditionalHeaders, config.retryConfig, config.oidcServiceAccount, nil, append(config.transformers, dispatchTransformers))
 			if deadLetterErr != nil {
-				return dispatchExecutionInfo, fmt.Errorf("failed to forward reply to %s (%v) and failed to send it to the dead letter sink %s (%v)", config.reply.URL, err, config.deadLetterSink.URL, deadLetterErr)
+				return dispatchExecutionInfo, fmt.Errorf("failed to forward reply to %s (%v) and failed to send it to the dead letter sink %s (%v)", reply.URL, err, config.deadLetterSink.URL, deadLetterErr)
 			}
 			if deadLetterResponse != nil {
 				messagesToFinish = append(messagesToFinish, deadLetterResponse)
@@ -279,7 +392,7 @@ func (d *Dispatcher) send(ctx context.Context, message binding.Message, destinat
 			return dispatchExecutionInfo, nil
 		}
 		// No DeadLetter, just fail
-		return dispatchExecutionInfo, fmt.Errorf("failed to forward reply to %s: %w", config.reply.URL, err)
+		return dispatchExecutionInfo, fmt.Errorf("failed to forward reply to %s: %w", reply.URL, err)
 	}
 	if responseResponseMessage != nil {
 		messagesToFinish = append(messagesToFinish, responseResponseMessage)
@@ -288,7 +401,7 @@ func (d *Dispatcher) send(ctx context.Context, message binding.Message, destinat
 	return dispatchExecutionInfo, nil
 }
 
-func (d *Dispatcher) executeRequest(ctx context.Context, target duckv1.Addressable, message cloudevents.Message, additionalHeaders http.Header, retryConfig *RetryConfig, oidcServiceAccount *types.NamespacedName, transformers ...binding.Transformer) (context.Context, cloudevents.Message, *DispatchInfo, error) {
+func (d *Dispatcher) executeRequest(ctx context.Context, target duckv1.Addressable, message cloudevents.Message, additionalHeaders http.Header, retryConfig *RetryConfig, oidcServiceAccount *types.NamespacedName, throttle *throttle, transformers ...binding.Transformer) (context.Context, cloudevents.Message, *DispatchInfo, error) {
 	var scheme string
 	if target.URL != nil {
 		scheme = target.URL.Scheme
@@ -320,9 +433,18 @@ func (d *Dispatcher) executeRequest(ctx context.Context, target duckv1.Addressab
 		return ctx, nil, &dispatchInfo, fmt.Errorf("failed to create http client: %w", err)
 	}
 
+	// The throttle lets each attempt through before its timeout starts.
+	recorder := newAttemptsRecorder(client.Transport)
+	var timeout time.Duration
+	if retryConfig != nil {
+		timeout = retryConfig.RequestTimeout
+	}
+	client.Transport = throttle.wrap(withTimeout(recorder, timeout))
+
 	start := time.Now()
 	response, err := client.DoWithRetries(req, retryConfig)
 	dispatchInfo.Duration = time.Since(start)
+	dispatchInfo.Attempts = recorder.attempts
 	if err != nil {
 		dispatchInfo.ResponseCode = http.StatusInternalServerError
 		dispatchInfo.ResponseBody = []byte(fmt.Sprintf("dispatch error: %s", err.Error()))
@@ -365,6 +487,7 @@ func (d *Dispatcher) executeRequest(ctx context.Context, target duckv1.Addressab
 		responseMessage.BodyReader.Close()
 		return ctx, nil, &dispatchInfo, nil
 	}
+	response.Body.Close()
 
 	return ctx, responseMessage, &dispatchInfo, nil
 }
@@ -430,15 +553,8 @@ func (c *client) DoWithRetries(req *http.Request, retryConfig *RetryConfig) (*ht
 		return c.Do(req)
 	}
 
+	// The timeout of the requests is applied by the transport of the client, see withTimeout.
 	client := c.Client
-	if retryConfig.RequestTimeout != 0 {
-		client = http.Client{
-			Transport:     client.Transport,
-			CheckRedirect: client.CheckRedirect,
-			Jar:           client.Jar,
-			Timeout:       retryConfig.RequestTimeout,
-		}
-	}
 
 	retryableClient := retryablehttp.Client{
 		HTTPClient:   &client,
@@ -460,6 +576,37 @@ func (c *client) DoWithRetries(req *http.Request, retryConfig *RetryConfig) (*ht
 	return retryableClient.Do(retryableReq)
 }
 
+// withTimeout returns the transport bounding each request by the timeout, until its response body
+// is closed, or next when the timeout is zero. Unlike http.Client.Timeout, it doesn't include the
+// time the request waits in the transports wrapping it.
+func withTimeout(next http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout == 0 {
+		return next
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		resp, err := next.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	})
+}
+
+// cancelBody cancels the context of the request once the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 // dispatchExecutionTransformer returns Transformers based on the specified destination and DispatchExecutionInfo
 func dispatchExecutionInfoTransformers(destination *apis.URL, dispatchExecutionInfo *DispatchInfo) binding.Transformers {
 	if destination == nil {
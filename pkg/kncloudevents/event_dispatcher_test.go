@@ -33,6 +33,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/cloudevents/sdk-go/v2/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
@@ -927,6 +928,54 @@ func TestSendEvent(t *testing.T) {
 	}
 }
 
+func TestSendEventWithReplyTransformers(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	replyEvent := test.FullEvent()
+
+	destinationExtensions := make(chan map[string]interface{}, 1)
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Error("failed to read event", err)
+		}
+		destinationExtensions <- event.Extensions()
+		if err := cehttp.WriteResponseWriter(r.Context(), binding.ToMessage(&replyEvent), http.StatusOK, w); err != nil {
+			t.Error("failed to write reply", err)
+		}
+	}))
+	defer destServer.Close()
+
+	replyExtensions := make(chan map[string]interface{}, 1)
+	replyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Error("failed to read event", err)
+		}
+		replyExtensions <- event.Extensions()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer replyServer.Close()
+
+	dispatcher := kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx))
+	_, err := dispatcher.SendEvent(ctx, test.MinEvent(), duckv1.Addressable{URL: apis.HTTP(strings.TrimPrefix(destServer.URL, "http://"))},
+		kncloudevents.WithReply(&duckv1.Addressable{URL: apis.HTTP(strings.TrimPrefix(replyServer.URL, "http://"))}),
+		kncloudevents.WithTransformers(transformer.AddExtension("common", "value")),
+		kncloudevents.WithReplyTransformers(transformer.AddExtension("replyonly", "value")),
+	)
+	require.NoError(t, err)
+
+	got := <-destinationExtensions
+	require.Contains(t, got, "common")
+	require.NotContains(t, got, "replyonly")
+
+	got = <-replyExtensions
+	require.Contains(t, got, "common")
+	require.Contains(t, got, "replyonly")
+}
+
 func TestDispatchMessageToTLSEndpoint(t *testing.T) {
 	var wg sync.WaitGroup
 	ctx, _ := rectesting.SetupFakeContext(t)
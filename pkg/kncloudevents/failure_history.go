@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/system"
+
+	"knative.dev/eventing/pkg/broker"
+	"knative.dev/eventing/pkg/kncloudevents/attributes"
+	"knative.dev/eventing/pkg/reconciler/sugar/trigger/path"
+)
+
+// maxAttemptBodyLength is the maximum number of bytes of the response body of a failed attempt
+// kept in its DispatchAttempt.
+const maxAttemptBodyLength = 4096
+
+// DispatchAttempt is a single attempt to send a request, retries included.
+type DispatchAttempt struct {
+	// Time is when the attempt started.
+	Time time.Time
+	// ResponseCode is the response status code, NoResponse when the request failed.
+	ResponseCode int
+	// ResponseBody is the beginning of the response body of the failed attempts, or the error
+	// when the request failed.
+	ResponseBody []byte
+}
+
+// WithFailureHistory adds the history of the attempts to send the event and the given resource
+// to the events sent to the dead letter sink.
+func WithFailureHistory(resource *duckv1.KReference) SendOption {
+	return func(sc *senderConfig) error {
+		if resource == nil {
+			return fmt.Errorf("the resource of the failure history must not be nil")
+		}
+		sc.failureHistoryResource = resource
+
+		return nil
+	}
+}
+
+// attemptsRecorder is an http.RoundTripper recording the attempts to send a request.
+type attemptsRecorder struct {
+	next     http.RoundTripper
+	attempts []DispatchAttempt
+}
+
+func newAttemptsRecorder(next http.RoundTripper) *attemptsRecorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &attemptsRecorder{next: next}
+}
+
+func (r *attemptsRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempt := DispatchAttempt{
+		Time:         time.Now(),
+		ResponseCode: NoResponse,
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		attempt.ResponseBody = []byte(fmt.Sprintf("dispatch error: %s", err.Error()))
+	} else {
+		attempt.ResponseCode = resp.StatusCode
+		if isFailure(resp.StatusCode) && resp.Body != nil {
+			// Keep the beginning of the body, without consuming it.
+			prefix, _ := io.ReadAll(io.LimitReader(resp.Body, maxAttemptBodyLength))
+			attempt.ResponseBody = prefix
+			resp.Body = readCloser{
+				Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body),
+				Closer: resp.Body,
+			}
+		}
+	}
+	r.attempts = append(r.attempts, attempt)
+	return resp, err
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// failureHistoryTransformers returns the Transformers adding the history of the attempts to send
+// the event to the destination, and the resource which failed to deliver it. The Trigger is the
+// resource which failed to deliver the events sent to its subscriber through the broker filter.
+func failureHistoryTransformers(destination *apis.URL, attempts []DispatchAttempt, resource *duckv1.KReference) binding.Transformers {
+	fromBrokerFilter := destination != nil && destination.Host == network.GetServiceHostname("broker-filter", system.Namespace())
+	resourceName := fmt.Sprintf("%s/%s/%s", resource.Kind, resource.Namespace, resource.Name)
+	if fromBrokerFilter {
+		if t, err := path.Parse(destination.Path); err == nil && !t.IsReply && !t.IsDLS {
+			resourceName = fmt.Sprintf("Trigger/%s/%s", t.Namespace, t.Name)
+		}
+	}
+
+	history := make([]attributes.KnativeErrorAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		body := attempt.ResponseBody
+		if fromBrokerFilter {
+			// The broker filter wraps the response of the subscriber.
+			var errExtensionInfo broker.ErrExtensionInfo
+			if err := json.Unmarshal(body, &errExtensionInfo); err == nil {
+				body = errExtensionInfo.ErrResponseBody
+			}
+		}
+		history = append(history, attributes.NewKnativeErrorAttempt(attempt.Time, attempt.ResponseCode, body))
+	}
+
+	return attributes.KnativeErrorHistoryTransformers(history, resourceName)
+}
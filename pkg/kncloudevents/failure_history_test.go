@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/rest"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/system"
+
+	"knative.dev/eventing/pkg/auth"
+	"knative.dev/eventing/pkg/eventingtls"
+	"knative.dev/eventing/pkg/kncloudevents/attributes"
+)
+
+func TestSendEventWithFailureHistory(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	var destinationRequests atomic.Int32
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if destinationRequests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("unavailable"))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed"))
+	}))
+	defer destination.Close()
+
+	headers := make(chan http.Header, 1)
+	deadLetterSink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetterSink.Close()
+
+	dispatcher := NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx))
+	start := time.Now()
+	info, err := dispatcher.SendEvent(ctx, test.MinEvent(), duckv1.Addressable{URL: apis.HTTP(destination.Listener.Addr().String())},
+		WithDeadLetterSink(&duckv1.Addressable{URL: apis.HTTP(deadLetterSink.Listener.Addr().String())}),
+		WithRetryConfig(&RetryConfig{
+			RetryMax:   2,
+			CheckRetry: SelectiveRetry,
+			Backoff: func(attemptNum int, resp *http.Response) time.Duration {
+				return time.Millisecond
+			},
+		}),
+		WithFailureHistory(&duckv1.KReference{Kind: "Trigger", Namespace: "ns", Name: "name"}),
+	)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if info.ResponseCode != http.StatusAccepted {
+		t.Errorf("expected the dead letter sink response code, got %d", info.ResponseCode)
+	}
+
+	h := <-headers
+	if got := h.Get("ce-" + attributes.KnativeErrorAttemptsExtensionKey); got != "3" {
+		t.Errorf("expected 3 attempts, got %q", got)
+	}
+	if got := h.Get("ce-" + attributes.KnativeErrorResourceExtensionKey); got != "Trigger/ns/name" {
+		t.Errorf("unexpected resource %q", got)
+	}
+	first, err := time.Parse(time.RFC3339Nano, h.Get("ce-"+attributes.KnativeErrorFirstExtensionKey))
+	if err != nil {
+		t.Fatal("unexpected first attempt time:", err)
+	}
+	last, err := time.Parse(time.RFC3339Nano, h.Get("ce-"+attributes.KnativeErrorLastExtensionKey))
+	if err != nil {
+		t.Fatal("unexpected last attempt time:", err)
+	}
+	if first.Before(start.Truncate(time.Second)) || last.Before(first) {
+		t.Errorf("unexpected attempt times, first %v last %v", first, last)
+	}
+
+	var history []attributes.KnativeErrorAttempt
+	if err := json.Unmarshal([]byte(h.Get("ce-"+attributes.KnativeErrorHistoryExtensionKey)), &history); err != nil {
+		t.Fatal("unexpected history:", err)
+	}
+	type attempt struct {
+		Code int
+		Data string
+	}
+	var got []attempt
+	for _, a := range history {
+		data, _ := base64.StdEncoding.DecodeString(a.Data)
+		got = append(got, attempt{Code: a.Code, Data: string(data)})
+	}
+	want := []attempt{
+		{Code: http.StatusServiceUnavailable, Data: "unavailable"},
+		{Code: http.StatusInternalServerError, Data: "failed"},
+		{Code: http.StatusInternalServerError, Data: "failed"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("unexpected history (-want +got):", diff)
+	}
+
+	// The last attempt is still reported with the existing extensions.
+	if got := h.Get("ce-" + attributes.KnativeErrorCodeExtensionKey); got != "500" {
+		t.Errorf("expected the last error code, got %q", got)
+	}
+}
+
+func TestFailureHistoryTransformersResource(t *testing.T) {
+	subscription := &duckv1.KReference{Kind: "Subscription", Namespace: "ns", Name: "subscription"}
+	brokerFilter := network.GetServiceHostname("broker-filter", system.Namespace())
+
+	tests := map[string]struct {
+		destination *apis.URL
+		want        string
+	}{
+		"subscriber": {
+			destination: apis.HTTP("subscriber.ns.svc.cluster.local"),
+			want:        "Subscription/ns/subscription",
+		},
+		"trigger": {
+			destination: &apis.URL{Scheme: "http", Host: brokerFilter, Path: "/triggers/ns/trigger/uid"},
+			want:        "Trigger/ns/trigger",
+		},
+		"broker": {
+			destination: &apis.URL{Scheme: "http", Host: brokerFilter, Path: "/brokers/ns/broker"},
+			want:        "Subscription/ns/subscription",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			minEvent := test.MinEvent()
+			transformers := failureHistoryTransformers(tc.destination, []DispatchAttempt{{Time: time.Now(), ResponseCode: http.StatusInternalServerError}}, subscription)
+			event, err := binding.ToEvent(context.Background(), binding.ToMessage(&minEvent), transformers)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			if got := event.Extensions()[attributes.KnativeErrorResourceExtensionKey]; got != tc.want {
+				t.Errorf("expected the resource %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
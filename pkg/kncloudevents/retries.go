@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -58,11 +60,14 @@ type Backoff func(attemptNum int, resp *http.Response) time.Duration
 type RetryConfig struct {
 	// Maximum number of retries
 	RetryMax int
-	// These next two variables are just copied from the original DeliverySpec so
+	// These next variables are just copied from the original DeliverySpec so
 	// we can detect if anything has changed. We can not do that with the CheckRetry
 	// Backoff (at least not easily).
 	BackoffDelay  *string
+	BackoffMax    *string
 	BackoffPolicy *v1.BackoffPolicyType
+	RetryOn       []string
+	NoRetryOn     []string
 
 	CheckRetry CheckRetry
 	Backoff    Backoff
@@ -82,16 +87,43 @@ func NoRetries() RetryConfig {
 }
 
 func RetryConfigFromDeliverySpec(spec v1.DeliverySpec) (RetryConfig, error) {
+	return retryConfigFromDeliverySpec(spec, rand.Float64)
+}
+
+// retryConfigFromDeliverySpec returns the retry config of the delivery spec, where jitterRand
+// returns the random numbers in [0.0, 1.0) used to jitter the backoff delays of the
+// exponentialJitter backoff policy.
+func retryConfigFromDeliverySpec(spec v1.DeliverySpec, jitterRand func() float64) (RetryConfig, error) {
 
 	retryConfig := NoRetries()
 
 	retryConfig.CheckRetry = SelectiveRetry
 
+	retryConfig.RetryOn = spec.RetryOn
+	retryConfig.NoRetryOn = spec.NoRetryOn
+	if len(spec.RetryOn) > 0 || len(spec.NoRetryOn) > 0 {
+		checkRetry, err := statusCodesRetry(spec.RetryOn, spec.NoRetryOn)
+		if err != nil {
+			return retryConfig, err
+		}
+		retryConfig.CheckRetry = checkRetry
+	}
+
 	if spec.Retry != nil {
 		retryConfig.RetryMax = int(*spec.Retry)
 	}
 	retryConfig.BackoffPolicy = spec.BackoffPolicy
 	retryConfig.BackoffDelay = spec.BackoffDelay
+	retryConfig.BackoffMax = spec.BackoffMax
+
+	var backoffMax time.Duration
+	if spec.BackoffMax != nil {
+		maxPeriod, err := period.Parse(*spec.BackoffMax)
+		if err != nil {
+			return retryConfig, fmt.Errorf("failed to parse Spec.BackoffMax: %w", err)
+		}
+		backoffMax, _ = maxPeriod.Duration()
+	}
 
 	if spec.BackoffPolicy != nil && spec.BackoffDelay != nil {
 
@@ -106,11 +138,31 @@ func RetryConfigFromDeliverySpec(spec v1.DeliverySpec) (RetryConfig, error) {
 			retryConfig.Backoff = func(attemptNum int, resp *http.Response) time.Duration {
 				return delayDuration * time.Duration(math.Exp2(float64(attemptNum)))
 			}
+		case v1.BackoffPolicyExponentialJitter:
+			retryConfig.Backoff = func(attemptNum int, resp *http.Response) time.Duration {
+				// Full jitter, the delay is picked at random up to the exponential backoff delay.
+				ceiling := math.Min(float64(delayDuration)*math.Exp2(float64(attemptNum)), math.MaxInt64)
+				if backoffMax > 0 {
+					ceiling = math.Min(ceiling, float64(backoffMax))
+				}
+				return time.Duration(jitterRand() * ceiling)
+			}
 		case v1.BackoffPolicyLinear:
 			retryConfig.Backoff = func(attemptNum int, resp *http.Response) time.Duration {
 				return delayDuration * time.Duration(attemptNum)
 			}
 		}
+
+		if backoffMax > 0 {
+			backoff := retryConfig.Backoff
+			retryConfig.Backoff = func(attemptNum int, resp *http.Response) time.Duration {
+				// A negative delay is an overflow of the exponential backoff delay.
+				if d := backoff(attemptNum, resp); d >= 0 && d < backoffMax {
+					return d
+				}
+				return backoffMax
+			}
+		}
 	}
 
 	if spec.Timeout != nil {
@@ -183,6 +235,68 @@ func SelectiveRetry(_ context.Context, response *http.Response, err error) (bool
 	return false, nil
 }
 
+// statusCodesRetry returns a CheckRetry retrying the responses with the status codes of retryOn,
+// and not retrying the ones with the status codes of noRetryOn, which takes precedence. The other
+// responses are retried according to SelectiveRetry.
+func statusCodesRetry(retryOn, noRetryOn []string) (CheckRetry, error) {
+	retry, err := parseStatusCodeRanges(retryOn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Spec.RetryOn: %w", err)
+	}
+	noRetry, err := parseStatusCodeRanges(noRetryOn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Spec.NoRetryOn: %w", err)
+	}
+
+	return func(ctx context.Context, response *http.Response, err error) (bool, error) {
+		if response != nil && err == nil {
+			if matchStatusCode(noRetry, response.StatusCode) {
+				return false, nil
+			}
+			if matchStatusCode(retry, response.StatusCode) {
+				return true, nil
+			}
+		}
+		return SelectiveRetry(ctx, response, err)
+	}, nil
+}
+
+// statusCodeRange is an inclusive range of status codes.
+type statusCodeRange struct {
+	min int
+	max int
+}
+
+// parseStatusCodeRanges parses status codes, e.g. 503, and classes of status codes, e.g. 5xx.
+func parseStatusCodeRanges(codes []string) ([]statusCodeRange, error) {
+	ranges := make([]statusCodeRange, 0, len(codes))
+	for _, code := range codes {
+		if class, ok := strings.CutSuffix(code, "xx"); ok && len(class) == 1 {
+			c, err := strconv.Atoi(class)
+			if err != nil {
+				return nil, fmt.Errorf("invalid status code class %q", code)
+			}
+			ranges = append(ranges, statusCodeRange{min: c * 100, max: c*100 + 99})
+			continue
+		}
+		c, err := strconv.Atoi(code)
+		if err != nil || len(code) != 3 {
+			return nil, fmt.Errorf("invalid status code %q", code)
+		}
+		ranges = append(ranges, statusCodeRange{min: c, max: c})
+	}
+	return ranges, nil
+}
+
+func matchStatusCode(ranges []statusCodeRange, code int) bool {
+	for _, r := range ranges {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
 // generateBackoffFunction returns a valid retryablehttp.Backoff implementation which
 // wraps the provided RetryConfig.Backoff implementation with optional "Retry-After"
 // header support.
@@ -67,8 +67,10 @@ func TestRetryConfigFromDeliverySpec(t *testing.T) {
 		name                     string
 		backoffPolicy            v1.BackoffPolicyType
 		backoffDelay             string
+		backoffMax               *string
 		timeout                  *string
 		retryAfterMax            *string
+		jitter                   float64
 		expectedBackoffDurations []time.Duration
 		wantErr                  bool
 	}{{
@@ -104,6 +106,61 @@ func TestRetryConfigFromDeliverySpec(t *testing.T) {
 			8 * time.Second,
 			16 * time.Second,
 		},
+	}, {
+		name:          "Successful Exponential Backoff 500ms with 10s max, 5 retries",
+		backoffPolicy: v1.BackoffPolicyExponential,
+		backoffDelay:  "PT0.5S",
+		backoffMax:    ptr.String("PT10S"),
+		expectedBackoffDurations: []time.Duration{
+			1 * time.Second,
+			2 * time.Second,
+			4 * time.Second,
+			8 * time.Second,
+			10 * time.Second,
+		},
+	}, {
+		name:          "Successful Linear Backoff 2500ms with 6s max, 5 retries",
+		backoffPolicy: v1.BackoffPolicyLinear,
+		backoffDelay:  "PT2.5S",
+		backoffMax:    ptr.String("PT6S"),
+		expectedBackoffDurations: []time.Duration{
+			2500 * time.Millisecond,
+			5 * time.Second,
+			6 * time.Second,
+			6 * time.Second,
+			6 * time.Second,
+		},
+	}, {
+		name:          "Successful Exponential Jitter Backoff 1s with 10s max, 5 retries",
+		backoffPolicy: v1.BackoffPolicyExponentialJitter,
+		backoffDelay:  "PT1S",
+		backoffMax:    ptr.String("PT10S"),
+		jitter:        0.5,
+		expectedBackoffDurations: []time.Duration{
+			1 * time.Second,
+			2 * time.Second,
+			4 * time.Second,
+			5 * time.Second,
+			5 * time.Second,
+		},
+	}, {
+		name:          "Successful Exponential Jitter Backoff 1s without max, 5 retries",
+		backoffPolicy: v1.BackoffPolicyExponentialJitter,
+		backoffDelay:  "PT1S",
+		jitter:        0.25,
+		expectedBackoffDurations: []time.Duration{
+			500 * time.Millisecond,
+			1 * time.Second,
+			2 * time.Second,
+			4 * time.Second,
+			8 * time.Second,
+		},
+	}, {
+		name:          "Invalid Backoff Max",
+		backoffPolicy: v1.BackoffPolicyExponential,
+		backoffDelay:  "PT0.5S",
+		backoffMax:    &invalidISO8601DurationString,
+		wantErr:       true,
 	}, {
 		name:          "Invalid Backoff Delay",
 		backoffPolicy: v1.BackoffPolicyLinear,
@@ -149,19 +206,19 @@ func TestRetryConfigFromDeliverySpec(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-
 			// Create The DeliverySpec To Test
 			deliverySpec := v1.DeliverySpec{
 				DeadLetterSink: nil,
 				Retry:          ptr.Int32(retry),
 				BackoffPolicy:  &tc.backoffPolicy,
 				BackoffDelay:   &tc.backoffDelay,
+				BackoffMax:     tc.backoffMax,
 				Timeout:        tc.timeout,
 				RetryAfterMax:  tc.retryAfterMax,
 			}
 
 			// Create the RetryConfig from the deliverySpec
-			retryConfig, err := RetryConfigFromDeliverySpec(deliverySpec)
+			retryConfig, err := retryConfigFromDeliverySpec(deliverySpec, func() float64 { return tc.jitter })
 			assert.Equal(t, tc.wantErr, err != nil)
 
 			// If successful then validate the retryConfig (Max & Backoff calculations).
@@ -375,6 +432,52 @@ func TestRetryConfigFromDeliverySpecCheckRetry(t *testing.T) {
 	}
 }
 
+func TestRetryConfigFromDeliverySpecStatusCodes(t *testing.T) {
+	spec := v1.DeliverySpec{
+		Retry:     pointer.Int32(3),
+		RetryOn:   []string{"400", "3xx"},
+		NoRetryOn: []string{"503", "304"},
+	}
+	retryConfig, err := RetryConfigFromDeliverySpec(spec)
+	if err != nil {
+		t.Fatal("RetryConfigFromDeliverySpec() unexpected error:", err)
+	}
+
+	tests := []struct {
+		statusCode int
+		want       bool
+	}{
+		{statusCode: 400, want: true},
+		{statusCode: 401, want: false},
+		{statusCode: 302, want: true},
+		{statusCode: 304, want: false},
+		{statusCode: 500, want: true},
+		{statusCode: 503, want: false},
+		{statusCode: 429, want: true},
+		{statusCode: 202, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.statusCode), func(t *testing.T) {
+			got, err := retryConfig.CheckRetry(context.Background(), &http.Response{StatusCode: tt.statusCode}, nil)
+			if err != nil {
+				t.Fatal("CheckRetry() unexpected error:", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Requests without a response are retried.
+	if got, _ := retryConfig.CheckRetry(context.Background(), nil, errors.New("connection refused")); !got {
+		t.Error("expected requests without a response to be retried")
+	}
+
+	if _, err := RetryConfigFromDeliverySpec(v1.DeliverySpec{RetryOn: []string{"4x"}}); err == nil {
+		t.Error("expected an error for an invalid status code")
+	}
+}
+
 /*
  * Test TestGenerateBackoffFnWithRetryAfter
  *
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	v1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// ThrottleConfig bounds the requests sent to a destination.
+type ThrottleConfig struct {
+	// MaxConcurrency is the maximum number of requests in flight to the destination, or zero for
+	// no limit.
+	MaxConcurrency int
+	// RequestsPerSecond is the maximum rate of the requests sent to the destination, or zero for
+	// no limit.
+	RequestsPerSecond int
+	// Burst is the number of requests which can be sent to the destination at once, above the rate.
+	Burst int
+}
+
+// ThrottleConfigFromDeliverySpec returns the throttling of the requests of the delivery spec, or nil
+// when it has none.
+func ThrottleConfigFromDeliverySpec(spec v1.DeliverySpec) *ThrottleConfig {
+	if spec.MaxConcurrency == nil && spec.RateLimit == nil {
+		return nil
+	}
+
+	config := &ThrottleConfig{}
+	if spec.MaxConcurrency != nil {
+		config.MaxConcurrency = int(*spec.MaxConcurrency)
+	}
+	if spec.RateLimit != nil {
+		config.RequestsPerSecond = int(spec.RateLimit.RequestsPerSecond)
+		config.Burst = config.RequestsPerSecond
+		if spec.RateLimit.Burst != nil {
+			config.Burst = int(*spec.RateLimit.Burst)
+		}
+	}
+	return config
+}
+
+// throttleIdleTimeout is how long the throttle of a destination is kept without requests, the
+// throttles of the removed destinations are evicted after it.
+const throttleIdleTimeout = 10 * time.Minute
+
+// throttle bounds the requests sent to a destination by the replica.
+type throttle struct {
+	// slots has a buffered element per request in flight, when the concurrency is bounded.
+	slots   chan struct{}
+	limiter *rate.Limiter
+	// lastUsed is the time, in Unix nanoseconds, the throttle was last returned by throttleFor.
+	lastUsed atomic.Int64
+}
+
+func newThrottle(config ThrottleConfig) *throttle {
+	th := &throttle{}
+	if config.MaxConcurrency > 0 {
+		th.slots = make(chan struct{}, config.MaxConcurrency)
+	}
+	if config.RequestsPerSecond > 0 {
+		th.limiter = rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.Burst)
+	}
+	return th
+}
+
+// wrap returns the transport sending the requests once the throttle lets them through. A request
+// is in flight until its response headers are received. The throttle is the outermost transport,
+// so that the time a request waits for it doesn't count against its timeout.
+func (th *throttle) wrap(next http.RoundTripper) http.RoundTripper {
+	if th == nil {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		if th.slots != nil {
+			select {
+			case th.slots <- struct{}{}:
+				defer func() { <-th.slots }()
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if th.limiter != nil {
+			if err := th.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// throttleKey identifies the throttle shared by the requests to a destination with the same limits.
+type throttleKey struct {
+	destination string
+	config      ThrottleConfig
+}
+
+// throttleFor returns the throttle of the destination, or nil when the config is nil. The throttles
+// idle for throttleIdleTimeout are evicted.
+func (d *Dispatcher) throttleFor(destination string, config *ThrottleConfig) *throttle {
+	if config == nil {
+		return nil
+	}
+	now := time.Now()
+	d.evictIdleThrottles(now)
+
+	key := throttleKey{destination: destination, config: *config}
+	th, ok := d.throttles.Load(key)
+	if !ok {
+		th, _ = d.throttles.LoadOrStore(key, newThrottle(*config))
+	}
+	th.(*throttle).lastUsed.Store(now.UnixNano())
+	return th.(*throttle)
+}
+
+// evictIdleThrottles removes the throttles without requests in flight which weren't used since
+// throttleIdleTimeout, at most once per throttleIdleTimeout.
+func (d *Dispatcher) evictIdleThrottles(now time.Time) {
+	last := d.throttlesEvicted.Load()
+	if now.UnixNano()-last < int64(throttleIdleTimeout) || !d.throttlesEvicted.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	d.throttles.Range(func(key, value any) bool {
+		th := value.(*throttle)
+		if now.UnixNano()-th.lastUsed.Load() >= int64(throttleIdleTimeout) && len(th.slots) == 0 {
+			d.throttles.Delete(key)
+		}
+		return true
+	})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/injection"
+
+	v1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/auth"
+	"knative.dev/eventing/pkg/eventingtls"
+)
+
+func TestThrottleConfigFromDeliverySpec(t *testing.T) {
+	tests := map[string]struct {
+		spec v1.DeliverySpec
+		want *ThrottleConfig
+	}{
+		"no throttling": {},
+		"max concurrency": {
+			spec: v1.DeliverySpec{MaxConcurrency: ptr.To[int32](5)},
+			want: &ThrottleConfig{MaxConcurrency: 5},
+		},
+		"rate limit": {
+			spec: v1.DeliverySpec{RateLimit: &v1.RateLimitSpec{RequestsPerSecond: 10}},
+			want: &ThrottleConfig{RequestsPerSecond: 10, Burst: 10},
+		},
+		"rate limit with burst": {
+			spec: v1.DeliverySpec{
+				MaxConcurrency: ptr.To[int32](5),
+				RateLimit:      &v1.RateLimitSpec{RequestsPerSecond: 10, Burst: ptr.To[int32](1)},
+			},
+			want: &ThrottleConfig{MaxConcurrency: 5, RequestsPerSecond: 10, Burst: 1},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ThrottleConfigFromDeliverySpec(tc.spec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected config (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestSendEventWithThrottle(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	var inFlight, maxInFlight, requests atomic.Int32
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer destination.Close()
+
+	dispatcher := NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx))
+	config := &ThrottleConfig{MaxConcurrency: 2, RequestsPerSecond: 20, Burst: 5}
+
+	const events = 10
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < events; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The throttle is shared by the requests to the destination with the same limits.
+			_, err := dispatcher.SendEvent(ctx, test.MinEvent(), duckv1.Addressable{URL: apis.HTTP(destination.Listener.Addr().String())},
+				WithThrottle(&ThrottleConfig{MaxConcurrency: config.MaxConcurrency, RequestsPerSecond: config.RequestsPerSecond, Burst: config.Burst}),
+			)
+			if err != nil {
+				t.Error("unexpected error:", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != events {
+		t.Errorf("expected %d requests to the destination, got %d", events, got)
+	}
+	if got := maxInFlight.Load(); got > int32(config.MaxConcurrency) {
+		t.Errorf("expected at most %d requests in flight, got %d", config.MaxConcurrency, got)
+	}
+	// The requests above the burst wait for the rate limit.
+	if elapsed, want := time.Since(start), (events-5)*time.Second/20; elapsed < want {
+		t.Errorf("expected the requests to take at least %v, took %v", want, elapsed)
+	}
+}
+
+func TestSendEventWithThrottleAndTimeout(t *testing.T) {
+	ctx := context.Background()
+	ctx, _ = fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer destination.Close()
+
+	dispatcher := NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx))
+	for i := 0; i < 3; i++ {
+		// The requests wait for the rate limit longer than their timeout.
+		_, err := dispatcher.SendEvent(ctx, test.MinEvent(), duckv1.Addressable{URL: apis.HTTP(destination.Listener.Addr().String())},
+			WithThrottle(&ThrottleConfig{RequestsPerSecond: 4, Burst: 1}),
+			WithRetryConfig(&RetryConfig{RequestTimeout: 100 * time.Millisecond, CheckRetry: SelectiveRetry}),
+		)
+		if err != nil {
+			t.Error("unexpected error:", err)
+		}
+	}
+}
+
+func TestEvictIdleThrottles(t *testing.T) {
+	dispatcher := &Dispatcher{}
+	config := &ThrottleConfig{MaxConcurrency: 1}
+	idle := dispatcher.throttleFor("idle", config)
+	busy := dispatcher.throttleFor("busy", config)
+	busy.slots <- struct{}{}
+
+	dispatcher.evictIdleThrottles(time.Now().Add(throttleIdleTimeout))
+
+	if got := dispatcher.throttleFor("idle", config); got == idle {
+		t.Error("expected the idle throttle to be evicted")
+	}
+	if got := dispatcher.throttleFor("busy", config); got != busy {
+		t.Error("expected the throttle with requests in flight to be kept")
+	}
+}
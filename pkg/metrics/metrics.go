@@ -51,6 +51,21 @@ const (
 	// LabelEventSource is the label for the name of the event source.
 	LabelEventSource = "event_source"
 
+	// LabelEventValidation is the label for the result of the validation of the event against its EventType.
+	LabelEventValidation = "event_validation"
+
+	// LabelRateLimitKey is the label for what the rate limit rejecting the event applies to.
+	LabelRateLimitKey = "rate_limit_key"
+
+	// LabelDeduplicationResult is the label for whether the event is a duplicate of an event accepted recently.
+	LabelDeduplicationResult = "deduplication_result"
+
+	// LabelCircuitBreakerState is the label for the state of the circuit breaker of a destination.
+	LabelCircuitBreakerState = "circuit_breaker_state"
+
+	// LabelSubscriptionName is the label for the name of the Subscription.
+	LabelSubscriptionName = "subscription_name"
+
 	// LabelFilterType is the label for the Trigger filter attribute "type".
 	LabelFilterType = "filter_type"
 
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	sinks "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+	eventingv1alpha1listers "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+)
+
+// serviceName is the name of the Service of the aggregator component, in the system namespace.
+const serviceName = "aggregator"
+
+func newWarningSinkNotFound(sink *duckv1.Destination) reconciler.Event {
+	b, _ := json.Marshal(sink)
+	return reconciler.NewEvent(corev1.EventTypeWarning, "SinkNotFound", "Sink not found: %s", string(b))
+}
+
+type Reconciler struct {
+	kubeClientSet kubernetes.Interface
+
+	sinkResolver *resolver.URIResolver
+
+	eventPolicyLister    eventingv1alpha1listers.EventPolicyLister
+	serviceAccountLister corev1listers.ServiceAccountLister
+
+	systemNamespace string
+}
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, a *sinks.Aggregator) reconciler.Event {
+	featureFlags := feature.FromContext(ctx)
+
+	if err := auth.SetupOIDCServiceAccount(ctx, featureFlags, r.serviceAccountLister, r.kubeClientSet, sinks.SchemeGroupVersion.WithKind("Aggregator"), a.ObjectMeta, &a.Status, func(as *duckv1.AuthStatus) {
+		a.Status.Auth = as
+	}); err != nil {
+		return err
+	}
+
+	sinkAddr, err := r.sinkResolver.AddressableFromDestinationV1(ctx, a.Spec.Sink, a)
+	if err != nil {
+		a.Status.MarkNoSink("NotFound", "")
+		return newWarningSinkNotFound(&a.Spec.Sink)
+	}
+	a.Status.MarkSink(sinkAddr)
+
+	// The aggregator component exposes every Aggregator on a path of its Service.
+	address := duckv1.Addressable{
+		Name: ptr.To("http"),
+		URL: &apis.URL{
+			Scheme: "http",
+			Host:   network.GetServiceHostname(serviceName, r.systemNamespace),
+			Path:   fmt.Sprintf("/%s/%s", a.GetNamespace(), a.GetName()),
+		},
+	}
+
+	if featureFlags.IsOIDCAuthentication() {
+		audience := auth.GetAudience(sinks.SchemeGroupVersion.WithKind("Aggregator"), a.ObjectMeta)
+
+		logging.FromContext(ctx).Debugw("Setting the audience", zap.String("audience", audience))
+		address.Audience = &audience
+	}
+
+	a.Status.SetAddress(&address)
+
+	err = auth.UpdateStatusWithEventPolicies(featureFlags, &a.Status.AppliedEventPoliciesStatus, &a.Status, r.eventPolicyLister, sinks.SchemeGroupVersion.WithKind("Aggregator"), a.ObjectMeta)
+	if err != nil {
+		return fmt.Errorf("could not update Aggregator status with EventPolicies: %v", err)
+	}
+
+	return nil
+}
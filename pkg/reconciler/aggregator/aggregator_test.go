@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/network"
+	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/tracker"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
+	aggregatorreconciler "knative.dev/eventing/pkg/client/injection/reconciler/sinks/v1alpha1/aggregator"
+	. "knative.dev/eventing/pkg/reconciler/testing/v1"
+	. "knative.dev/eventing/pkg/reconciler/testing/v1alpha1"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	aggregatorName = "test-aggregator"
+	aggregatorUID  = "1234"
+	sinkName       = "testsink"
+)
+
+var (
+	testKey = fmt.Sprintf("%s/%s", testNamespace, aggregatorName)
+
+	aggregatorAddressable = duckv1.Addressable{
+		Name: ptr.To("http"),
+		URL: &apis.URL{
+			Scheme: "http",
+			Host:   network.GetServiceHostname("aggregator", testNamespace),
+			Path:   fmt.Sprintf("/%s/%s", testNamespace, aggregatorName),
+		},
+	}
+
+	aggregatorAudience = "sinks.knative.dev/aggregator/test-namespace/test-aggregator"
+
+	sinkURL         = apis.HTTP("sink.example.com")
+	sinkAddressable = &duckv1.Addressable{
+		URL: sinkURL,
+	}
+
+	uriSpec = sinksv1alpha1.AggregatorSpec{
+		Completion: sinksv1alpha1.AggregatorCompletion{
+			Count: ptr.To[int32](2),
+		},
+		Sink: duckv1.Destination{
+			URI: sinkURL,
+		},
+	}
+
+	refSpec = sinksv1alpha1.AggregatorSpec{
+		Completion: sinksv1alpha1.AggregatorCompletion{
+			Count: ptr.To[int32](2),
+		},
+		Sink: duckv1.Destination{
+			Ref: &duckv1.KReference{
+				Name:       sinkName,
+				Namespace:  testNamespace,
+				Kind:       "Channel",
+				APIVersion: "messaging.knative.dev/v1",
+			},
+		},
+	}
+)
+
+func TestReconcile(t *testing.T) {
+	table := TableTest{
+		{
+			Name: "bad work queue key",
+			Key:  "too/many/parts",
+		}, {
+			Name: "key not found",
+			Key:  "foo/not-found",
+		}, {
+			Name: "Successful reconciliation",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(uriSpec)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(uriSpec),
+					WithInitAggregatorConditions,
+					WithAggregatorOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
+					WithAggregatorSink(sinkAddressable),
+					WithAggregatorAddress(&aggregatorAddressable),
+					WithAggregatorEventPoliciesReadyBecauseOIDCDisabled()),
+			}},
+		}, {
+			Name: "Sink not found",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(refSpec)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(refSpec),
+					WithInitAggregatorConditions,
+					WithAggregatorOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
+					WithAggregatorNoSink),
+			}},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeWarning, "SinkNotFound",
+					`Sink not found: {"ref":{"kind":"Channel","namespace":"test-namespace","name":"testsink","apiVersion":"messaging.knative.dev/v1"}}`),
+			},
+		}, {
+			Name: "Sink resolved from a reference",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(refSpec)),
+				NewChannel(sinkName, testNamespace,
+					WithInitChannelConditions,
+					WithChannelAddress(sinkAddressable)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(refSpec),
+					WithInitAggregatorConditions,
+					WithAggregatorOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
+					WithAggregatorSink(sinkAddressable),
+					WithAggregatorAddress(&aggregatorAddressable),
+					WithAggregatorEventPoliciesReadyBecauseOIDCDisabled()),
+			}},
+		}, {
+			Name: "Successful reconciliation with OIDC",
+			Key:  testKey,
+			Ctx: feature.ToContext(context.Background(), feature.Flags{
+				feature.OIDCAuthentication:       feature.Enabled,
+				feature.AuthorizationDefaultMode: feature.AuthorizationAllowSameNamespace,
+			}),
+			Objects: []runtime.Object{
+				NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(uriSpec)),
+			},
+			WantCreates: []runtime.Object{
+				makeOIDCServiceAccount(),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewAggregator(aggregatorName, testNamespace,
+					WithAggregatorUID(aggregatorUID),
+					WithAggregatorSpec(uriSpec),
+					WithInitAggregatorConditions,
+					WithAggregatorOIDCServiceAccountName(makeOIDCServiceAccount().Name),
+					WithAggregatorOIDCIdentityCreatedSucceeded(),
+					WithAggregatorSink(sinkAddressable),
+					WithAggregatorAddress(&duckv1.Addressable{
+						Name:     aggregatorAddressable.Name,
+						URL:      aggregatorAddressable.URL,
+						Audience: &aggregatorAudience,
+					}),
+					WithAggregatorEventPoliciesReadyBecauseNoPolicyAndOIDCEnabled()),
+			}},
+		},
+	}
+
+	logger := logtesting.TestLogger(t)
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		ctx = addressable.WithDuck(ctx)
+		r := &Reconciler{
+			kubeClientSet:        fakekubeclient.Get(ctx),
+			systemNamespace:      testNamespace,
+			eventPolicyLister:    listers.GetEventPolicyLister(),
+			serviceAccountLister: listers.GetServiceAccountLister(),
+			sinkResolver:         resolver.NewURIResolverFromTracker(ctx, tracker.New(func(types.NamespacedName) {}, 0)),
+		}
+
+		return aggregatorreconciler.NewReconciler(ctx, logger,
+			fakeeventingclient.Get(ctx), listers.GetAggregatorLister(),
+			controller.GetEventRecorder(ctx), r)
+	},
+		false,
+		logger,
+	))
+}
+
+func makeOIDCServiceAccount() *corev1.ServiceAccount {
+	return auth.GetOIDCServiceAccountForResource(sinksv1alpha1.SchemeGroupVersion.WithKind("Aggregator"), metav1.ObjectMeta{
+		Name:      aggregatorName,
+		Namespace: testNamespace,
+		UID:       aggregatorUID,
+	})
+}
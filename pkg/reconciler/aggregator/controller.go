@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	serviceaccountinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount/filtered"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+	"knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/eventpolicy"
+	"knative.dev/eventing/pkg/client/injection/informers/sinks/v1alpha1/aggregator"
+	aggregatorreconciler "knative.dev/eventing/pkg/client/injection/reconciler/sinks/v1alpha1/aggregator"
+	"knative.dev/eventing/pkg/resolver"
+)
+
+// NewController initializes the controller and is called by the generated code.
+// Registers event handlers to enqueue events.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	aggregatorInformer := aggregator.Get(ctx)
+	eventPolicyInformer := eventpolicy.Get(ctx)
+	oidcServiceaccountInformer := serviceaccountinformer.Get(ctx, auth.OIDCLabelSelector)
+
+	r := &Reconciler{
+		kubeClientSet:        kubeclient.Get(ctx),
+		systemNamespace:      system.Namespace(),
+		eventPolicyLister:    eventPolicyInformer.Lister(),
+		serviceAccountLister: oidcServiceaccountInformer.Lister(),
+	}
+
+	var globalResync func(obj interface{})
+
+	featureStore := feature.NewStore(logging.FromContext(ctx).Named("feature-config-store"), func(name string, value interface{}) {
+		if globalResync != nil {
+			globalResync(nil)
+		}
+	})
+	featureStore.WatchConfigs(cmw)
+
+	impl := aggregatorreconciler.NewImpl(ctx, r, func(impl *controller.Impl) controller.Options {
+		return controller.Options{
+			ConfigStore: featureStore,
+		}
+	})
+
+	r.sinkResolver = resolver.NewURIResolver(ctx, cmw, impl.Tracker)
+
+	aggregatorInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	globalResync = func(interface{}) {
+		impl.GlobalResync(aggregatorInformer.Informer())
+	}
+
+	aggregatorGK := sinksv1alpha1.SchemeGroupVersion.WithKind("Aggregator").GroupKind()
+
+	// Enqueue the Aggregator, if we have an EventPolicy which was referencing
+	// or got updated and now is referencing the Aggregator.
+	eventPolicyInformer.Informer().AddEventHandler(auth.EventPolicyEventHandler(
+		aggregatorInformer.Informer().GetIndexer(),
+		aggregatorGK,
+		impl.EnqueueKey,
+	))
+
+	oidcServiceaccountInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterController(&sinksv1alpha1.Aggregator{}),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	return impl
+}
@@ -152,6 +152,13 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.ApiServerSour
 		return err
 	}
 
+	if source.Spec.ResumeWatches {
+		if err := r.reconcileWatchState(ctx, source); err != nil {
+			logging.FromContext(ctx).Errorw("Unable to reconcile the watch state", zap.Error(err))
+			return err
+		}
+	}
+
 	if err := r.propagateTrustBundles(ctx, source); err != nil {
 		return err
 	}
@@ -292,7 +299,7 @@ func (r *Reconciler) podSpecChanged(oldPodSpec corev1.PodSpec, newPodSpec corev1
 }
 
 func (r *Reconciler) runAccessCheck(ctx context.Context, src *v1.ApiServerSource, namespaces []string) error {
-	if src.Spec.Resources == nil || len(src.Spec.Resources) == 0 {
+	if (src.Spec.Resources == nil || len(src.Spec.Resources) == 0) && !src.Spec.ResumeWatches {
 		src.Status.MarkSufficientPermissions()
 		return nil
 	}
@@ -352,6 +359,39 @@ func (r *Reconciler) runAccessCheck(ctx context.Context, src *v1.ApiServerSource
 			}
 		}
 	}
+	if src.Spec.ResumeWatches {
+		// The receive adapter persists the state of its watches in a ConfigMap.
+		missingVerbs := ""
+		sep1 := ""
+		for _, verb := range []string{"get", "update"} {
+			sar := &authorizationv1.SubjectAccessReview{
+				Spec: authorizationv1.SubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: src.Namespace,
+						Verb:      verb,
+						Resource:  "configmaps",
+						Name:      resources.WatchStateConfigMapName(src.Name),
+					},
+					User: user,
+				},
+			}
+
+			response, err := r.kubeClientSet.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+
+			if !response.Status.Allowed {
+				missingVerbs += sep1 + verb
+				sep1 = ", "
+			}
+		}
+
+		if missingVerbs != "" {
+			missing += sep + missingVerbs + ` resource "configmaps" in API group "" in Namespace "` + src.Namespace + `"`
+		}
+	}
+
 	if missing == "" {
 		src.Status.MarkSufficientPermissions()
 		return nil
@@ -361,12 +401,32 @@ func (r *Reconciler) runAccessCheck(ctx context.Context, src *v1.ApiServerSource
 	return fmt.Errorf("insufficient permissions: User %s cannot %s", user, missing)
 }
 
+// reconcileWatchState creates the ConfigMap where the receive adapter persists the state of its
+// watches. Its data is owned by the receive adapter.
+func (r *Reconciler) reconcileWatchState(ctx context.Context, source *v1.ApiServerSource) error {
+	expected := resources.MakeWatchStateConfigMap(source)
+	cm, err := r.kubeClientSet.CoreV1().ConfigMaps(source.Namespace).Get(ctx, expected.Name, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		if _, err := r.kubeClientSet.CoreV1().ConfigMaps(source.Namespace).Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("could not create the watch state ConfigMap %s/%s: %w", source.Namespace, expected.Name, err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not get the watch state ConfigMap %s/%s: %w", source.Namespace, expected.Name, err)
+	} else if !metav1.IsControlledBy(cm, source) {
+		return fmt.Errorf("ConfigMap %q is not owned by ApiServerSource %q", cm.Name, source.Name)
+	}
+	return nil
+}
+
 func (r *Reconciler) createCloudEventAttributes(src *v1.ApiServerSource) ([]duckv1.CloudEventAttributes, error) {
 	var eventTypes []string
 	if src.Spec.EventMode == v1.ReferenceMode {
 		eventTypes = apisources.ApiServerSourceEventReferenceModeTypes
 	} else if src.Spec.EventMode == v1.ResourceMode {
 		eventTypes = apisources.ApiServerSourceEventResourceModeTypes
+	} else if src.Spec.EventMode == v1.DiffMode {
+		eventTypes = apisources.ApiServerSourceEventDiffModeTypes
 	} else {
 		return []duckv1.CloudEventAttributes{}, fmt.Errorf("no EventType available for EventMode: %s", src.Spec.EventMode)
 	}
@@ -126,9 +126,13 @@ func makeEnv(args *ReceiveAdapterArgs) ([]corev1.EnvVar, error) {
 		Resources:     make([]apiserver.ResourceWatch, 0, len(args.Source.Spec.Resources)),
 		ResourceOwner: args.Source.Spec.ResourceOwner,
 		EventMode:     args.Source.Spec.EventMode,
+		Diff:          args.Source.Spec.Diff,
 		AllNamespaces: args.AllNamespaces,
 		Filters:       args.Source.Spec.Filters,
 	}
+	if args.Source.Spec.ResumeWatches {
+		cfg.WatchStateConfigMap = WatchStateConfigMapName(args.Source.Name)
+	}
 
 	for _, r := range args.Source.Spec.Resources {
 		gv, err := schema.ParseGroupVersion(r.APIVersion)
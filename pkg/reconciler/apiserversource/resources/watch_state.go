@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+// WatchStateConfigMapName returns the name of the ConfigMap where the receive adapter persists
+// the state of its watches.
+func WatchStateConfigMapName(sourceName string) string {
+	return kmeta.ChildName(sourceName, "-watch-state")
+}
+
+// MakeWatchStateConfigMap returns the ConfigMap where the receive adapter of the ApiServerSource
+// persists the state of its watches, so that they resume after a restart.
+func MakeWatchStateConfigMap(source *v1.ApiServerSource) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WatchStateConfigMapName(source.Name),
+			Namespace: source.Namespace,
+			Labels:    Labels(source.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(source),
+			},
+		},
+	}
+}
@@ -18,6 +18,7 @@ package broker
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -42,6 +43,7 @@ import (
 	"knative.dev/pkg/network"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracker"
 
 	duckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/apis/eventing"
@@ -49,6 +51,8 @@ import (
 	"knative.dev/eventing/pkg/apis/feature"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	"knative.dev/eventing/pkg/auth"
+	pkgbroker "knative.dev/eventing/pkg/broker"
+	"knative.dev/eventing/pkg/broker/filter"
 	clientset "knative.dev/eventing/pkg/client/clientset/versioned"
 	brokerreconciler "knative.dev/eventing/pkg/client/injection/reconciler/eventing/v1/broker"
 	eventingv1alpha1listers "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
@@ -57,6 +61,7 @@ import (
 	"knative.dev/eventing/pkg/eventingtls"
 	"knative.dev/eventing/pkg/reconciler/broker/resources"
 	"knative.dev/eventing/pkg/reconciler/names"
+	"knative.dev/eventing/pkg/reconciler/sugar/trigger/path"
 )
 
 const (
@@ -75,6 +80,7 @@ type Reconciler struct {
 	secretLister       corev1listers.SecretLister
 
 	channelableTracker ducklib.ListableTracker
+	configmapTracker   tracker.Interface
 
 	uriResolver *resolver.URIResolver
 
@@ -160,6 +166,12 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, b *eventingv1.Broker) pk
 	b.Status.Annotations[eventing.BrokerChannelAPIVersionStatusAnnotationKey] = chanMan.ref.APIVersion
 	b.Status.Annotations[eventing.BrokerChannelNameStatusAnnotationKey] = chanMan.ref.Name
 
+	if err := r.reconcileIngressConfig(b); err != nil {
+		logging.FromContext(ctx).Errorw("Problem reconciling the ingress config", zap.Error(err))
+		b.Status.MarkIngressFailed("IngressConfigFailure", "%v", err)
+		return err
+	}
+
 	if caCerts := triggerChan.Status.Address.CACerts; caCerts != nil && *caCerts != "" {
 		b.Status.Annotations[eventing.BrokerChannelCACertsStatusAnnotationKey] = *caCerts
 	}
@@ -206,6 +218,11 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, b *eventingv1.Broker) pk
 		b.Status.MarkDeadLetterSinkNotConfigured()
 	}
 
+	if err := r.reconcileIndexedSubscription(ctx, b, &chanMan.ref); err != nil {
+		logging.FromContext(ctx).Errorw("Problem reconciling the indexed dispatch subscription", zap.Error(err))
+		return fmt.Errorf("failed to reconcile the indexed dispatch subscription: %w", err)
+	}
+
 	// Route everything to shared ingress, just tack on the namespace/name as path
 	// so we can route there appropriately.
 	featureFlags := feature.FromContext(ctx)
@@ -269,6 +286,68 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, b *eventingv1.Broker) pk
 	return nil
 }
 
+// reconcileIngressConfig attaches the ingress settings set in the config ConfigMap of the Broker
+// as status annotations, so that the ingress doesn't need to watch the ConfigMaps.
+func (r *Reconciler) reconcileIngressConfig(b *eventingv1.Broker) error {
+	delete(b.Status.Annotations, eventing.BrokerIngressRateLimitStatusAnnotationKey)
+	delete(b.Status.Annotations, eventing.BrokerIngressDeduplicationStatusAnnotationKey)
+	delete(b.Status.Annotations, eventing.BrokerIngressSchedulingStatusAnnotationKey)
+	if b.Spec.Config == nil || b.Spec.Config.Kind != "ConfigMap" {
+		return nil
+	}
+
+	// Reconcile the Broker when the settings change.
+	if err := r.configmapTracker.TrackReference(tracker.Reference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Namespace:  b.Spec.Config.Namespace,
+		Name:       b.Spec.Config.Name,
+	}, b); err != nil {
+		return fmt.Errorf("unable to track changes to the config ConfigMap: %w", err)
+	}
+
+	cm, err := r.configmapLister.ConfigMaps(b.Spec.Config.Namespace).Get(b.Spec.Config.Name)
+	if err != nil {
+		return err
+	}
+
+	rateLimit, err := pkgbroker.NewRateLimitFromConfigMap(cm.Data)
+	if err != nil {
+		return err
+	}
+	if err := setJSONAnnotation(b, eventing.BrokerIngressRateLimitStatusAnnotationKey, rateLimit); err != nil {
+		return err
+	}
+
+	dedup, err := pkgbroker.NewDeduplicationFromConfigMap(cm.Data)
+	if err != nil {
+		return err
+	}
+	if err := setJSONAnnotation(b, eventing.BrokerIngressDeduplicationStatusAnnotationKey, dedup); err != nil {
+		return err
+	}
+
+	scheduling, err := pkgbroker.NewSchedulingFromConfigMap(cm.Data)
+	if err != nil {
+		return err
+	}
+	return setJSONAnnotation(b, eventing.BrokerIngressSchedulingStatusAnnotationKey, scheduling)
+}
+
+// setJSONAnnotation sets the status annotation of the Broker to the JSON encoding of the value,
+// unless the value is nil.
+func setJSONAnnotation[T any](b *eventingv1.Broker, key string, value *T) error {
+	if value == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	b.Status.Annotations[key] = string(encoded)
+	return nil
+}
+
 type channelTemplate struct {
 	ref      corev1.ObjectReference
 	inf      dynamic.ResourceInterface
@@ -428,6 +507,79 @@ func (r *Reconciler) reconcileChannel(ctx context.Context, channelResourceInterf
 	return channelable, nil
 }
 
+// reconcileIndexedSubscription subscribes the broker filter to the trigger channel once for all
+// the Triggers of the Broker when it uses the indexed dispatch mode, and removes that Subscription
+// otherwise. The Triggers of such a Broker don't have a Subscription of their own.
+func (r *Reconciler) reconcileIndexedSubscription(ctx context.Context, b *eventingv1.Broker, triggerChan *corev1.ObjectReference) error {
+	name := resources.BrokerSubscriptionName(b)
+	sub, err := r.subscriptionLister.Subscriptions(b.Namespace).Get(name)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	if sub != nil && !metav1.IsControlledBy(sub, b) {
+		return fmt.Errorf("broker %q does not own subscription %q", b.Name, sub.Name)
+	}
+
+	if b.GetAnnotations()[eventingv1.BrokerDispatchModeAnnotationKey] != eventingv1.BrokerDispatchModeIndexed {
+		if sub == nil {
+			return nil
+		}
+		logging.FromContext(ctx).Infow("Deleting the indexed dispatch subscription", zap.String("name", name))
+		err := r.eventingClientSet.MessagingV1().Subscriptions(b.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	dest := &pkgduckv1.Destination{
+		URI: apis.HTTP(network.GetServiceHostname(names.BrokerFilterName, system.Namespace())),
+	}
+	featureFlags := feature.FromContext(ctx)
+	if featureFlags.IsPermissiveTransportEncryption() || featureFlags.IsStrictTransportEncryption() {
+		caCerts, err := r.getFilterCaCerts()
+		if err != nil {
+			return err
+		}
+		dest.URI.Scheme = "https"
+		dest.CACerts = caCerts
+	}
+	dest.URI.Path = path.GenerateBroker(b)
+	if featureFlags.IsOIDCAuthentication() {
+		dest.Audience = pointer.String(filter.FilterAudience)
+	}
+
+	expected := resources.NewBrokerSubscription(b, triggerChan, dest)
+	if sub == nil {
+		logging.FromContext(ctx).Infow("Creating the indexed dispatch subscription", zap.String("name", name))
+		_, err := r.eventingClientSet.MessagingV1().Subscriptions(b.Namespace).Create(ctx, expected, metav1.CreateOptions{})
+		return err
+	}
+	if equality.Semantic.DeepDerivative(expected.Spec, sub.Spec) {
+		return nil
+	}
+
+	// Given that spec.channel is immutable, the Subscription is re-created rather than updated.
+	logging.FromContext(ctx).Infow("Re-creating the indexed dispatch subscription", zap.Any("expected", expected.Spec), zap.Any("actual", sub.Spec))
+	if err := r.eventingClientSet.MessagingV1().Subscriptions(b.Namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	_, err = r.eventingClientSet.MessagingV1().Subscriptions(b.Namespace).Create(ctx, expected, metav1.CreateOptions{})
+	return err
+}
+
+func (r *Reconciler) getFilterCaCerts() (*string, error) {
+	secret, err := r.secretLister.Secrets(system.Namespace()).Get(eventingtls.BrokerFilterServerTLSSecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CA certs from %s/%s: %w", system.Namespace(), eventingtls.BrokerFilterServerTLSSecretName, err)
+	}
+	caCerts, ok := secret.Data[caCertsSecretKey]
+	if !ok {
+		return nil, nil
+	}
+	return pointer.String(string(caCerts)), nil
+}
+
 // TriggerChannelLabels are all the labels placed on the Trigger Channel for the given brokerName. This
 // should only be used by Broker and Trigger code.
 func TriggerChannelLabels(brokerName, brokerNamespace string) map[string]string {
@@ -22,6 +22,7 @@ import (
 	"knative.dev/eventing/pkg/auth"
 
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/apis"
 	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
@@ -116,6 +117,7 @@ func NewController(
 
 	r.channelableTracker = duck.NewListableTrackerFromTracker(ctx, channelable.Get, impl.Tracker)
 	r.uriResolver = resolver.NewURIResolverFromTracker(ctx, impl.Tracker)
+	r.configmapTracker = impl.Tracker
 
 	brokerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: brokerFilter,
@@ -145,11 +147,23 @@ func NewController(
 			pkgreconciler.NameFilterFunc(names.BrokerIngressName)),
 		Handler: controller.HandleAll(globalResync),
 	})
+	// Reconcile the Brokers whose config ConfigMap changed, to update their ingress rate limit.
+	configmapInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(
+			r.configmapTracker.OnChanged,
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		)))
 	secretInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterWithName(ingressServerTLSSecretName),
 		Handler:    controller.HandleAll(globalResync),
 	})
 
+	// Reconcile the Broker when its indexed dispatch Subscription changes.
+	subscriptionInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterController(&eventingv1.Broker{}),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
 	brokerGK := eventingv1.SchemeGroupVersion.WithKind("Broker").GroupKind()
 
 	// Enqueue the Broker, if we have an EventPolicy which was referencing
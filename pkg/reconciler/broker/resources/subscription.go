@@ -35,18 +35,14 @@ import (
 // NewSubscription returns a placeholder subscription for trigger 't', from brokerTrigger to 'dest'
 // replying to brokerIngress.
 func NewSubscription(ctx context.Context, t *eventingv1.Trigger, brokerTrigger *corev1.ObjectReference, dest, reply *duckv1.Destination, delivery *eventingduckv1.DeliverySpec) *messagingv1.Subscription {
-	var broker, channelNamespace string
+	var channelNamespace string
 	if t.Spec.BrokerRef != nil && feature.FromContext(ctx).IsEnabled(feature.CrossNamespaceEventLinks) {
-		broker = t.Spec.BrokerRef.Name
 		channelNamespace = t.Spec.BrokerRef.Namespace
-	} else {
-		broker = t.Spec.Broker
-		channelNamespace = ""
 	}
 	return &messagingv1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: t.Namespace,
-			Name:      kmeta.ChildName(fmt.Sprintf("%s-%s-", broker, t.Name), string(t.GetUID())),
+			Name:      SubscriptionName(ctx, t),
 			OwnerReferences: []metav1.OwnerReference{
 				*kmeta.NewControllerRef(t),
 			},
@@ -66,6 +62,16 @@ func NewSubscription(ctx context.Context, t *eventingv1.Trigger, brokerTrigger *
 	}
 }
 
+// SubscriptionName returns the name of the Subscription linking the Trigger 't' to the Broker's
+// Channels.
+func SubscriptionName(ctx context.Context, t *eventingv1.Trigger) string {
+	broker := t.Spec.Broker
+	if t.Spec.BrokerRef != nil && feature.FromContext(ctx).IsEnabled(feature.CrossNamespaceEventLinks) {
+		broker = t.Spec.BrokerRef.Name
+	}
+	return kmeta.ChildName(fmt.Sprintf("%s-%s-", broker, t.Name), string(t.GetUID()))
+}
+
 // SubscriptionLabels generates the labels present on the Subscription linking this Trigger to the
 // Broker's Channels.
 func SubscriptionLabels(ctx context.Context, t *eventingv1.Trigger) map[string]string {
@@ -80,3 +86,35 @@ func SubscriptionLabels(ctx context.Context, t *eventingv1.Trigger) map[string]s
 		"eventing.knative.dev/trigger": t.Name,
 	}
 }
+
+// BrokerSubscriptionName returns the name of the Subscription dispatching the events of the
+// Broker 'b' to every of its Triggers at once, when it uses the indexed dispatch mode.
+func BrokerSubscriptionName(b *eventingv1.Broker) string {
+	return kmeta.ChildName(b.Name+"-indexed-", string(b.GetUID()))
+}
+
+// NewBrokerSubscription returns a placeholder subscription for broker 'b', from brokerTrigger to
+// 'dest'. The Triggers' retries, replies and dead letter sinks are handled by 'dest', so the
+// Subscription has no delivery spec.
+func NewBrokerSubscription(b *eventingv1.Broker, brokerTrigger *corev1.ObjectReference, dest *duckv1.Destination) *messagingv1.Subscription {
+	return &messagingv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: b.Namespace,
+			Name:      BrokerSubscriptionName(b),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(b),
+			},
+			Labels: map[string]string{
+				eventing.BrokerLabelKey: b.Name,
+			},
+		},
+		Spec: messagingv1.SubscriptionSpec{
+			Channel: duckv1.KReference{
+				APIVersion: brokerTrigger.APIVersion,
+				Kind:       brokerTrigger.Kind,
+				Name:       brokerTrigger.Name,
+			},
+			Subscriber: dest,
+		},
+	}
+}
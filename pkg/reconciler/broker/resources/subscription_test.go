@@ -110,3 +110,53 @@ func TestNewSubscription(t *testing.T) {
 		t.Error("unexpected diff (-want, +got) =", diff)
 	}
 }
+
+func TestNewBrokerSubscription(t *testing.T) {
+	var TrueValue = true
+	broker := &eventingv1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "b-namespace",
+			Name:      "b-name",
+		},
+	}
+	triggerChannelRef := &corev1.ObjectReference{
+		Name:       "tc-name",
+		Kind:       "tc-kind",
+		APIVersion: "tc-apiVersion",
+	}
+	dest := &duckv1.Destination{
+		URI: apis.HTTP("example.com"),
+	}
+	got := NewBrokerSubscription(broker, triggerChannelRef, dest)
+	want := &messagingv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "b-namespace",
+			Name:      "b-name-indexed-",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "eventing.knative.dev/v1",
+				Kind:               "Broker",
+				Name:               "b-name",
+				Controller:         &TrueValue,
+				BlockOwnerDeletion: &TrueValue,
+			}},
+			Labels: map[string]string{
+				eventing.BrokerLabelKey: "b-name",
+			},
+		},
+		Spec: messagingv1.SubscriptionSpec{
+			Channel: duckv1.KReference{
+				Name:       "tc-name",
+				Kind:       "tc-kind",
+				APIVersion: "tc-apiVersion",
+			},
+			Subscriber: &duckv1.Destination{
+				URI: apis.HTTP("example.com"),
+			},
+			// The retries and dead letter sinks of the Triggers are handled by the broker filter.
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("unexpected diff (-want, +got) =", diff)
+	}
+}
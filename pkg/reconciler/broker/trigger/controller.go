@@ -36,10 +36,12 @@ import (
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	apiseventing "knative.dev/eventing/pkg/apis/eventing"
 	eventing "knative.dev/eventing/pkg/apis/eventing/v1"
 	"knative.dev/eventing/pkg/apis/feature"
+	"knative.dev/eventing/pkg/broker/filter"
 	eventingclient "knative.dev/eventing/pkg/client/injection/client"
 	brokerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker"
 	triggerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/trigger"
@@ -117,6 +119,45 @@ func NewController(
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	// Reconcile the Triggers of a Broker when the Subscription it uses for the indexed dispatch changes
+	subscriptionInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterController(&eventing.Broker{}),
+		Handler: controller.HandleAll(func(obj interface{}) {
+			sub, ok := obj.(metav1.Object)
+			if !ok {
+				return
+			}
+			owner := metav1.GetControllerOf(sub)
+			if owner == nil {
+				return
+			}
+			broker, err := r.brokerLister.Brokers(sub.GetNamespace()).Get(owner.Name)
+			if err != nil {
+				return
+			}
+			for _, t := range getTriggersForBroker(logger, triggerLister, broker, featureStore.Load()) {
+				impl.Enqueue(t)
+			}
+		}),
+	})
+
+	// Reconcile the Triggers with a circuit breaker when the broker filter replicas record its state
+	configmapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), filter.CircuitBreakersConfigMapName),
+		Handler: controller.HandleAll(func(interface{}) {
+			triggers, err := triggerLister.List(labels.Everything())
+			if err != nil {
+				logger.Warnw("Failed to list the triggers", zap.Error(err))
+				return
+			}
+			for _, t := range triggers {
+				if t.Spec.Delivery != nil && t.Spec.Delivery.CircuitBreaker != nil {
+					impl.Enqueue(t)
+				}
+			}
+		}),
+	})
+
 	// Reconciler Trigger when the OIDC service account changes
 	oidcServiceaccountInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: filterOIDCServiceAccounts(featureStore, triggerInformer.Lister(), brokerInformer.Lister()),
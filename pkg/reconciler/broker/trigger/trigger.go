@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -161,6 +162,18 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, t *eventingv1.Trigger) p
 		return err
 	}
 
+	if b.GetAnnotations()[eventingv1.BrokerDispatchModeAnnotationKey] == eventingv1.BrokerDispatchModeIndexed {
+		if err := r.useBrokerSubscription(ctx, b, t); err != nil {
+			logging.FromContext(ctx).Errorw("Unable to use the Broker's Subscription", zap.Error(err))
+			t.Status.MarkNotSubscribed("NotSubscribed", "%v", err)
+			return err
+		}
+		if err := r.checkDependencyAnnotation(ctx, t); err != nil {
+			return err
+		}
+		return r.reconcileSubscriberCircuit(t)
+	}
+
 	sub, err := r.subscribeToBrokerChannel(ctx, b, t, brokerTrigger)
 	if err != nil {
 		logging.FromContext(ctx).Errorw("Unable to Subscribe", zap.Error(err))
@@ -173,6 +186,25 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, t *eventingv1.Trigger) p
 		return err
 	}
 
+	return r.reconcileSubscriberCircuit(t)
+}
+
+// reconcileSubscriberCircuit reports whether the circuit breaker of the subscriber is open in
+// some broker filter replicas, as recorded by them in the circuit breakers ConfigMap. The Trigger
+// is checked again while it is open, so that the records of the replicas which are gone expire.
+func (r *Reconciler) reconcileSubscriberCircuit(t *eventingv1.Trigger) pkgreconciler.Event {
+	if t.Spec.Delivery == nil || t.Spec.Delivery.CircuitBreaker == nil {
+		t.Status.ClearSubscriberCircuitCondition()
+		return nil
+	}
+
+	// The ConfigMap doesn't exist until a circuit breaker opens.
+	cm, _ := r.configmapLister.ConfigMaps(system.Namespace()).Get(filter.CircuitBreakersConfigMapName)
+	if replicas := filter.CircuitBreakerOpenReplicas(cm, t.UID, time.Now()); replicas > 0 {
+		t.Status.MarkSubscriberCircuitOpen("CircuitBreakerOpen", "The circuit breaker of the subscriber is open in %d broker filter replica(s)", replicas)
+		return controller.NewRequeueAfter(filter.CircuitBreakerStatesTTL)
+	}
+	t.Status.MarkSubscriberCircuitClosed()
 	return nil
 }
 
@@ -272,6 +304,26 @@ func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1
 	delivery := t.Spec.Delivery.DeepCopy() // copy object to avoid in-place update bugs
 	if delivery == nil {
 		delivery = b.Spec.Delivery.DeepCopy() // copy object to avoid in-place update bugs
+	} else if delivery.PartitionKey() != "" {
+		// The broker filter retries the events of the ordered Triggers itself, while the
+		// partition of the event is locked, the Subscription only sends them to the dead
+		// letter sink. Subscriptions don't support the ordering.
+		delivery.Ordering = nil
+		delivery.PartitionKeyAttribute = nil
+		delivery.Retry = nil
+		delivery.BackoffPolicy = nil
+		delivery.BackoffDelay = nil
+		delivery.RetryOn = nil
+		delivery.NoRetryOn = nil
+		delivery.RetryAfterMax = nil
+		delivery.Timeout = nil
+	} else {
+		delivery.Ordering = nil
+	}
+	if delivery != nil {
+		// The broker filter applies the circuit breaker of the Trigger, Subscriptions don't
+		// support it.
+		delivery.CircuitBreaker = nil
 	}
 
 	recorder := controller.GetEventRecorder(ctx)
@@ -327,6 +379,36 @@ func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1
 	return sub, nil
 }
 
+// useBrokerSubscription makes the Trigger rely on the Subscription dispatching the events to every
+// Trigger of the Broker at once, for the Brokers using the indexed dispatch mode. The Subscription
+// of the Trigger itself is deleted, otherwise its subscriber would receive the events twice.
+func (r *Reconciler) useBrokerSubscription(ctx context.Context, b *eventingv1.Broker, t *eventingv1.Trigger) error {
+	name := resources.SubscriptionName(ctx, t)
+	sub, err := r.subscriptionLister.Subscriptions(t.Namespace).Get(name)
+	if err != nil && !apierrs.IsNotFound(err) {
+		controller.GetEventRecorder(ctx).Eventf(t, corev1.EventTypeWarning, subscriptionGetFailed, "Getting the Trigger's Subscription failed: %v", err)
+		return err
+	}
+	if err == nil && metav1.IsControlledBy(sub, t) {
+		logging.FromContext(ctx).Infow("Deleting subscription", zap.String("namespace", sub.Namespace), zap.String("name", sub.Name))
+		if err := r.eventingClientSet.MessagingV1().Subscriptions(t.Namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			controller.GetEventRecorder(ctx).Eventf(t, corev1.EventTypeWarning, subscriptionDeleteFailed, "Delete Trigger's subscription failed: %v", err)
+			return err
+		}
+	}
+
+	brokerSub, err := r.subscriptionLister.Subscriptions(b.Namespace).Get(resources.BrokerSubscriptionName(b))
+	if apierrs.IsNotFound(err) {
+		// Once the Broker creates its Subscription, we get requeued.
+		t.Status.MarkNotSubscribed("BrokerSubscriptionNotFound", "Broker %q has no indexed dispatch Subscription yet", b.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	t.Status.PropagateSubscriptionCondition(brokerSub.Status.GetTopLevelCondition())
+	return nil
+}
+
 func (r *Reconciler) reconcileSubscription(ctx context.Context, t *eventingv1.Trigger, expected, actual *messagingv1.Subscription) (*messagingv1.Subscription, error) {
 	// Update Subscription if it has changed.
 	if equality.Semantic.DeepDerivative(expected.Spec, actual.Spec) {
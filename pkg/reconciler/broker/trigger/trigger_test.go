@@ -18,8 +18,10 @@ package mttrigger
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	authenticationv1 "k8s.io/api/authentication/v1"
@@ -395,6 +397,43 @@ func TestReconcile(t *testing.T) {
 					WithTriggerStatusSubscriberURI(subscriberURI),
 					WithTriggerOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled()),
 			}},
+		}, {
+			Name: "Creates subscription without retry for an ordered trigger",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(eventing.MTChannelBrokerClassValue),
+					WithBrokerConfig(config()),
+					WithInitBrokerConditions,
+					WithBrokerReady,
+					WithChannelAddressAnnotation(triggerChannelURL),
+					WithChannelAPIVersionAnnotation(triggerChannelAPIVersion),
+					WithChannelKindAnnotation(triggerChannelKind),
+					WithChannelNameAnnotation(triggerChannelName)),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerRetry(5, nil, nil),
+					WithTriggerPartitionKeyOrdering()),
+			},
+			WantCreates: []runtime.Object{
+				// The broker filter retries the events of the ordered triggers.
+				resources.NewSubscription(ctx, makeTrigger(testNS), createTriggerChannelRef(), makeServiceURI(), makeBrokerRef(), makeDelivery(nil, nil, nil, nil)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerRetry(5, nil, nil),
+					WithTriggerPartitionKeyOrdering(),
+					WithTriggerBrokerReady(),
+					WithTriggerDependencyReady(),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerDeadLetterSinkNotConfigured(),
+					WithTriggerSubscribedUnknown("SubscriptionNotConfigured", "Subscription has not yet been reconciled."),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled()),
+			}},
 		}, {
 			Name: "Creates subscription with dls from trigger",
 			Key:  testKey,
@@ -1367,6 +1406,116 @@ func TestReconcile(t *testing.T) {
 					WithTriggerOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
 				),
 			}},
+		}, {
+			Name: "Subscription ready, subscriber circuit closed",
+			Key:  testKey,
+			Objects: allBrokerObjectsReadyPlus([]runtime.Object{
+				makeReadySubscriptionWithoutCircuitBreaker(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreaker(),
+					WithInitTriggerConditions,
+				)}...),
+			WantErr: false,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreaker(),
+					WithTriggerBrokerReady(),
+					// The first reconciliation will initialize the status conditions.
+					WithInitTriggerConditions,
+					WithTriggerDependencyReady(),
+					WithTriggerSubscribed(),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerDeadLetterSinkNotConfigured(),
+					WithTriggerOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
+					WithTriggerSubscriberCircuitClosed(),
+				),
+			}},
+		}, {
+			Name: "Subscription ready, subscriber circuit open in a filter replica",
+			Key:  testKey,
+			Objects: allBrokerObjectsReadyPlus([]runtime.Object{
+				makeReadySubscriptionWithoutCircuitBreaker(),
+				makeCircuitBreakersConfigMap(map[string][]types.UID{
+					"filter-1": {triggerUID},
+					"filter-2": {"other"},
+				}),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreaker(),
+					WithInitTriggerConditions,
+				)}...),
+			// The Trigger is checked again while the circuit is open.
+			WantErr: true,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreaker(),
+					WithTriggerBrokerReady(),
+					// The first reconciliation will initialize the status conditions.
+					WithInitTriggerConditions,
+					WithTriggerDependencyReady(),
+					WithTriggerSubscribed(),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerDeadLetterSinkNotConfigured(),
+					WithTriggerOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
+					WithTriggerSubscriberCircuitOpen("CircuitBreakerOpen", "The circuit breaker of the subscriber is open in 1 broker filter replica(s)"),
+				),
+			}},
+		}, {
+			Name: "Indexed dispatch, trigger subscription deleted and broker subscription used",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(eventing.MTChannelBrokerClassValue),
+					WithBrokerDispatchMode(eventingv1.BrokerDispatchModeIndexed),
+					WithBrokerConfig(config()),
+					WithInitBrokerConditions,
+					WithBrokerReady,
+					WithBrokerResourceVersion(""),
+					WithBrokerAddressURI(brokerAddress),
+					WithChannelAddressAnnotation(triggerChannelURL),
+					WithChannelAPIVersionAnnotation(triggerChannelAPIVersion),
+					WithChannelKindAnnotation(triggerChannelKind),
+					WithChannelNameAnnotation(triggerChannelName)),
+				createChannel(testNS, true),
+				imcConfigMap(),
+				makeReadyBrokerSubscription(),
+				makeReadySubscription(testNS),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithInitTriggerConditions,
+				)},
+			WantErr: false,
+			WantDeletes: []clientgotesting.DeleteActionImpl{{
+				ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS,
+					Resource:  messagingv1.SchemeGroupVersion.WithResource("subscriptions"),
+				},
+				Name: makeReadySubscription(testNS).Name,
+			}},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerBrokerReady(),
+					WithInitTriggerConditions,
+					WithTriggerDependencyReady(),
+					WithTriggerSubscribed(),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerDeadLetterSinkNotConfigured(),
+					WithTriggerOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
+				),
+			}},
 		}, {
 			Name: "Dependency doesn't exist",
 			Key:  testKey,
@@ -2078,6 +2227,26 @@ func makeReadySubscription(subscriberNamespace string) *messagingv1.Subscription
 	return s
 }
 
+// makeReadySubscriptionWithoutCircuitBreaker is the Subscription of a Trigger with a circuit
+// breaker, which is applied by the broker filter.
+func makeReadySubscriptionWithoutCircuitBreaker() *messagingv1.Subscription {
+	s := makeReadySubscription(testNS)
+	s.Spec.Delivery = &eventingduckv1.DeliverySpec{}
+	return s
+}
+
+func makeReadyBrokerSubscription() *messagingv1.Subscription {
+	s := resources.NewBrokerSubscription(NewBroker(brokerName, testNS), createTriggerChannelRef(), &duckv1.Destination{
+		URI: &apis.URL{
+			Scheme: "http",
+			Host:   network.GetServiceHostname("broker-filter", systemNS),
+			Path:   fmt.Sprintf("/brokers/%s/%s", testNS, brokerName),
+		},
+	})
+	s.Status = *eventingv1.TestHelper.ReadySubscriptionStatus()
+	return s
+}
+
 func makeReadySubscriptionWithAudience(subscriberNamespace string) *messagingv1.Subscription {
 	s := makeReadySubscription(subscriberNamespace)
 	s.Spec.Subscriber.Audience = ptr.String(filter.FilterAudience)
@@ -2253,3 +2422,18 @@ func makeSubjectAccessReview(username string, action authv1.ResourceAttributes)
 		},
 	}
 }
+
+func makeCircuitBreakersConfigMap(open map[string][]types.UID) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      filter.CircuitBreakersConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: make(map[string]string, len(open)),
+	}
+	for replica, uids := range open {
+		recorded, _ := json.Marshal(filter.CircuitBreakerStates{Updated: time.Now(), Open: uids})
+		cm.Data[replica] = string(recorded)
+	}
+	return cm
+}
@@ -32,6 +32,7 @@ import (
 
 	"knative.dev/eventing/pkg/auth"
 	"knative.dev/eventing/pkg/channel/multichannelfanout"
+	"knative.dev/eventing/pkg/channel/wal"
 	"knative.dev/eventing/pkg/eventingtls"
 	"knative.dev/eventing/pkg/kncloudevents"
 
@@ -77,6 +78,16 @@ type envConfig struct {
 	MaxIdleConns int `envconfig:"MAX_IDLE_CONNS" required:"true"`
 	// MaxIdleConnsPerHost refers to the max idle connections per host, as in net/http/transport.
 	MaxIdleConnsPerHost int `envconfig:"MAX_IDLE_CONNS_PER_HOST" required:"true"`
+
+	// DataDir is where the write-ahead logs of the persistent channels are stored.
+	DataDir string `envconfig:"DATA_DIR" default:"/var/lib/knative/imc"`
+
+	// SubscriptionQueueDepth is the number of events waiting to be dispatched to each subscriber
+	// before the channel rejects the events. The queues are disabled when it is not set.
+	SubscriptionQueueDepth int `envconfig:"SUBSCRIPTION_QUEUE_DEPTH"`
+	// SubscriptionQueueWorkers is the number of events dispatched concurrently to each subscriber,
+	// defaults to fanout.DefaultQueueWorkers.
+	SubscriptionQueueWorkers int `envconfig:"SUBSCRIPTION_QUEUE_WORKERS"`
 }
 
 // NewController initializes the controller and is called by the generated code.
@@ -138,6 +149,10 @@ func NewController(
 		eventDispatcher:          kncloudevents.NewDispatcher(clientConfig, oidcTokenProvider),
 		tokenVerifier:            auth.NewOIDCTokenVerifier(ctx),
 		clientConfig:             clientConfig,
+		dataDir:                  env.DataDir,
+		logs:                     make(map[types.NamespacedName]*wal.Log),
+		queueDepth:               env.SubscriptionQueueDepth,
+		queueWorkers:             env.SubscriptionQueueWorkers,
 	}
 
 	var globalResync func(obj interface{})
@@ -19,6 +19,9 @@ package dispatcher
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -40,6 +43,7 @@ import (
 	"knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/channel/fanout"
 	"knative.dev/eventing/pkg/channel/multichannelfanout"
+	"knative.dev/eventing/pkg/channel/wal"
 	eventingv1beta2 "knative.dev/eventing/pkg/client/clientset/versioned/typed/eventing/v1beta2"
 	messagingv1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/messaging/v1"
 	reconcilerv1 "knative.dev/eventing/pkg/client/injection/reconciler/messaging/v1/inmemorychannel"
@@ -61,6 +65,17 @@ type Reconciler struct {
 	tokenVerifier            *auth.OIDCTokenVerifier
 
 	clientConfig eventingtls.ClientConfig
+
+	// dataDir is where the write-ahead logs of the persistent channels are stored.
+	dataDir string
+	// logs are the open write-ahead logs, by channel.
+	logsMu sync.Mutex
+	logs   map[types.NamespacedName]*wal.Log
+
+	// queueDepth and queueWorkers bound the events waiting to be dispatched to each subscriber,
+	// and the events dispatched concurrently to it.
+	queueDepth   int
+	queueWorkers int
 }
 
 // Check the interfaces Reconciler should implement
@@ -119,9 +134,18 @@ func (r *Reconciler) reconcile(ctx context.Context, imc *v1.InMemoryChannel) rec
 		return r.featureStore.ToContext(ctx)
 	}
 
+	log, logChanged, err := r.reconcileLog(imc)
+	if err != nil {
+		logging.FromContext(ctx).Error("Error reconciling the write-ahead log", zap.Error(err))
+		return err
+	}
+	config.FanoutConfig.Log = log
+	config.FanoutConfig.QueueDepth = r.queueDepth
+	config.FanoutConfig.QueueWorkers = r.queueWorkers
+
 	// First grab the host based MultiChannelFanoutMessage httpHandler
 	httpHandler := r.multiChannelEventHandler.GetChannelHandler(config.HostName)
-	if httpHandler == nil {
+	if httpHandler == nil || logChanged {
 		// No handler yet, create one.
 		fanoutHandler, err := fanout.NewFanoutEventHandler(
 			logging.FromContext(ctx).Desugar(),
@@ -152,7 +176,7 @@ func (r *Reconciler) reconcile(ctx context.Context, imc *v1.InMemoryChannel) rec
 
 	// Look for an https handler that's configured to use paths
 	httpsHandler := r.multiChannelEventHandler.GetChannelHandler(config.Path)
-	if httpsHandler == nil {
+	if httpsHandler == nil || logChanged {
 		// No handler yet, create one.
 		fanoutHandler, err := fanout.NewFanoutEventHandler(
 			logging.FromContext(ctx).Desugar(),
@@ -189,6 +213,52 @@ func (r *Reconciler) reconcile(ctx context.Context, imc *v1.InMemoryChannel) rec
 	return nil
 }
 
+// reconcileLog opens the write-ahead log of the persistent channel, or closes and removes it when
+// the channel isn't persistent anymore. It returns the log of the channel, if any, and whether it
+// changed so that the handlers of the channel are created again.
+func (r *Reconciler) reconcileLog(imc *v1.InMemoryChannel) (*wal.Log, bool, error) {
+	key := types.NamespacedName{Namespace: imc.Namespace, Name: imc.Name}
+
+	r.logsMu.Lock()
+	defer r.logsMu.Unlock()
+
+	log, ok := r.logs[key]
+	if imc.Spec.IsPersistent() {
+		if ok {
+			return log, false, nil
+		}
+		log, err := wal.Open(r.logDir(key), wal.DefaultSegmentSize, wal.DefaultMaxSize)
+		if err != nil {
+			return nil, false, err
+		}
+		r.logs[key] = log
+		return log, true, nil
+	}
+
+	if !ok {
+		return nil, false, r.removeLogLocked(key)
+	}
+	return nil, true, r.removeLogLocked(key)
+}
+
+// removeLogLocked closes the write-ahead log of the channel, if open, and removes its files.
+func (r *Reconciler) removeLogLocked(key types.NamespacedName) error {
+	if log, ok := r.logs[key]; ok {
+		delete(r.logs, key)
+		if err := log.Close(); err != nil {
+			return err
+		}
+	}
+	if r.dataDir == "" {
+		return nil
+	}
+	return os.RemoveAll(r.logDir(key))
+}
+
+func (r *Reconciler) logDir(key types.NamespacedName) string {
+	return filepath.Join(r.dataDir, key.Namespace, key.Name)
+}
+
 func (r *Reconciler) patchSubscriberStatus(ctx context.Context, imc *v1.InMemoryChannel) error {
 	after := imc.DeepCopy()
 
@@ -275,6 +345,12 @@ func (r *Reconciler) deleteFunc(obj interface{}) {
 		}
 	}
 
+	r.logsMu.Lock()
+	if err := r.removeLogLocked(types.NamespacedName{Namespace: imc.Namespace, Name: imc.Name}); err != nil {
+		logging.FromContext(context.Background()).Warnw("Failed to remove the write-ahead log", zap.Error(err))
+	}
+	r.logsMu.Unlock()
+
 	handleSubscribers(imc.Spec.Subscribers, kncloudevents.DeleteAddressableHandler)
 }
 
@@ -19,6 +19,8 @@ package dispatcher
 import (
 	"context"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -42,6 +44,7 @@ import (
 	v1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	"knative.dev/eventing/pkg/auth"
 	"knative.dev/eventing/pkg/channel/fanout"
+	"knative.dev/eventing/pkg/channel/wal"
 	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
 	"knative.dev/eventing/pkg/client/injection/reconciler/messaging/v1/inmemorychannel"
 	"knative.dev/eventing/pkg/eventingtls"
@@ -554,6 +557,47 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 	}
 }
 
+func TestReconciler_ReconcileLog(t *testing.T) {
+	dataDir := t.TempDir()
+	r := &Reconciler{
+		dataDir: dataDir,
+		logs:    make(map[types.NamespacedName]*wal.Log),
+	}
+	logDir := filepath.Join(dataDir, testNS, imcName)
+
+	imc := NewInMemoryChannel(imcName, testNS, WithInMemoryChannelPersistence(v1.InMemoryChannelPersistenceWriteAheadLog))
+	log, changed, err := r.reconcileLog(imc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log == nil || !changed {
+		t.Fatalf("Expected a new log, got %v (changed %v)", log, changed)
+	}
+	if _, err := os.Stat(logDir); err != nil {
+		t.Fatal("Expected the log directory to exist:", err)
+	}
+
+	again, changed, err := r.reconcileLog(imc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != log || changed {
+		t.Error("Expected the same log to be kept")
+	}
+
+	imc = NewInMemoryChannel(imcName, testNS, WithInMemoryChannelPersistence(v1.InMemoryChannelPersistenceNone))
+	log, changed, err = r.reconcileLog(imc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log != nil || !changed {
+		t.Errorf("Expected the log to be removed, got %v (changed %v)", log, changed)
+	}
+	if _, err := os.Stat(logDir); !os.IsNotExist(err) {
+		t.Error("Expected the log directory to be removed:", err)
+	}
+}
+
 func TestReconciler_InvalidInputs(t *testing.T) {
 	testCases := map[string]struct {
 		imc interface{}
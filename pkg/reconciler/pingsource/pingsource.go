@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	v1 "k8s.io/client-go/listers/core/v1"
 
@@ -58,6 +59,10 @@ const (
 	component     = "pingsource"
 	mtadapterName = "pingsource-mt-adapter"
 	containerName = "dispatcher"
+
+	// completionCheckInterval is how long after its schedule the completion of a PingSource
+	// firing once is checked again.
+	completionCheckInterval = time.Minute
 )
 
 func newWarningSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
@@ -146,7 +151,31 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1.PingSo
 		Source: sourcesv1.PingSourceSource(source.Namespace, source.Name),
 	}}
 
-	return nil
+	return r.reconcileCompletion(ctx, source)
+}
+
+// reconcileCompletion reports whether a PingSource firing once has sent its event, from the
+// last schedule time recorded by the adapter in its checkpoints. The PingSources which haven't
+// sent their event yet are checked again after it is scheduled.
+func (r *Reconciler) reconcileCompletion(ctx context.Context, source *sourcesv1.PingSource) pkgreconciler.Event {
+	if source.Spec.At == nil {
+		source.Status.ClearCompleted()
+		return nil
+	}
+	at := source.Spec.At.Time.Truncate(time.Second)
+
+	cm, err := r.kubeClientSet.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, mtping.CheckpointsConfigMapName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the checkpoints of the adapter: %w", err)
+	}
+	if err == nil {
+		if last, err := mtping.LastSchedule(cm, source); err == nil && !last.Before(at) {
+			source.Status.MarkCompleted()
+			return nil
+		}
+	}
+	source.Status.MarkNotCompleted("Scheduled", "The event is scheduled at %s", at.UTC().Format(time.RFC3339))
+	return controller.NewRequeueAfter(max(time.Until(at), 0) + completionCheckInterval)
 }
 
 func (r *Reconciler) FinalizeKind(ctx context.Context, source *sourcesv1.PingSource) pkgreconciler.Event {
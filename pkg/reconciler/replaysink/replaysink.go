@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replaysink
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/reconciler"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	sinks "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+)
+
+// serviceName is the name of the Service of the replay component, in the system namespace.
+const serviceName = "replay"
+
+type Reconciler struct {
+	systemNamespace string
+}
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, rs *sinks.ReplaySink) reconciler.Event {
+	featureFlags := feature.FromContext(ctx)
+
+	// The replay component exposes every ReplaySink on a path of its Service.
+	address := duckv1.Addressable{
+		Name: ptr.To("http"),
+		URL: &apis.URL{
+			Scheme: "http",
+			Host:   network.GetServiceHostname(serviceName, r.systemNamespace),
+			Path:   fmt.Sprintf("/%s/%s", rs.GetNamespace(), rs.GetName()),
+		},
+	}
+
+	if featureFlags.IsOIDCAuthentication() {
+		audience := auth.GetAudience(sinks.SchemeGroupVersion.WithKind("ReplaySink"), rs.ObjectMeta)
+
+		logging.FromContext(ctx).Debugw("Setting the audience", zap.String("audience", audience))
+		address.Audience = &audience
+	}
+
+	rs.Status.SetAddress(&address)
+	return nil
+}
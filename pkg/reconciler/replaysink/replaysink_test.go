@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replaysink
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/network"
+	. "knative.dev/pkg/reconciler/testing"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
+	replaysinkreconciler "knative.dev/eventing/pkg/client/injection/reconciler/sinks/v1alpha1/replaysink"
+	. "knative.dev/eventing/pkg/reconciler/testing/v1"
+	. "knative.dev/eventing/pkg/reconciler/testing/v1alpha1"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	replaySinkName = "test-replaysink"
+)
+
+var (
+	testKey = fmt.Sprintf("%s/%s", testNamespace, replaySinkName)
+
+	replaySinkAddressable = duckv1.Addressable{
+		Name: ptr.To("http"),
+		URL: &apis.URL{
+			Scheme: "http",
+			Host:   network.GetServiceHostname("replay", testNamespace),
+			Path:   fmt.Sprintf("/%s/%s", testNamespace, replaySinkName),
+		},
+	}
+
+	replaySinkAudience = "sinks.knative.dev/replaysink/test-namespace/test-replaysink"
+)
+
+func TestReconcile(t *testing.T) {
+	table := TableTest{
+		{
+			Name: "bad work queue key",
+			Key:  "too/many/parts",
+		}, {
+			Name: "key not found",
+			Key:  "foo/not-found",
+		}, {
+			Name: "Successful reconciliation",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewReplaySink(replaySinkName, testNamespace,
+					WithInitReplaySinkConditions),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplaySink(replaySinkName, testNamespace,
+					WithInitReplaySinkConditions,
+					WithReplaySinkAddress(&replaySinkAddressable)),
+			}},
+		}, {
+			Name: "Successful reconciliation with OIDC",
+			Key:  testKey,
+			Ctx: feature.ToContext(context.Background(), feature.Flags{
+				feature.OIDCAuthentication: feature.Enabled,
+			}),
+			Objects: []runtime.Object{
+				NewReplaySink(replaySinkName, testNamespace,
+					WithInitReplaySinkConditions),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewReplaySink(replaySinkName, testNamespace,
+					WithInitReplaySinkConditions,
+					WithReplaySinkAddress(&duckv1.Addressable{
+						Name:     replaySinkAddressable.Name,
+						URL:      replaySinkAddressable.URL,
+						Audience: &replaySinkAudience,
+					})),
+			}},
+		},
+	}
+
+	logger := logtesting.TestLogger(t)
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		r := &Reconciler{
+			systemNamespace: testNamespace,
+		}
+
+		return replaysinkreconciler.NewReconciler(ctx, logger,
+			fakeeventingclient.Get(ctx), listers.GetReplaySinkLister(),
+			controller.GetEventRecorder(ctx), r)
+	},
+		false,
+		logger,
+	))
+}
@@ -57,7 +57,8 @@ func NewSubscription(stepNumber int, s *v1.Sequence) *messagingv1.Subscription {
 				Audience: s.Spec.Steps[stepNumber].Destination.Audience,
 				CACerts:  s.Spec.Steps[stepNumber].Destination.CACerts,
 			},
-			Delivery: s.Spec.Steps[stepNumber].Delivery,
+			Delivery:    s.Spec.Steps[stepNumber].Delivery,
+			ReplyRoutes: s.Spec.Steps[stepNumber].ReplyRoutes,
 		},
 	}
 	// If it's not the last step, use the next channel as the reply to, if it's the very
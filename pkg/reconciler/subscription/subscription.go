@@ -18,6 +18,7 @@ package subscription
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"go.uber.org/zap"
@@ -58,6 +59,7 @@ const (
 	channelReferenceFailed              = "ChannelReferenceFailed"
 	subscriberResolveFailed             = "SubscriberResolveFailed"
 	replyResolveFailed                  = "ReplyResolveFailed"
+	replyRouteResolveFailed             = "ReplyRouteResolveFailed"
 	deadLetterSinkResolveFailed         = "DeadLetterSinkResolveFailed"
 )
 
@@ -200,6 +202,10 @@ func (r *Reconciler) resolveSubscriptionURIs(ctx context.Context, subscription *
 		return err
 	}
 
+	if err := r.resolveReplyRoutes(ctx, subscription); err != nil {
+		return err
+	}
+
 	if err := r.resolveDeadLetterSink(ctx, subscription, channel); err != nil {
 		return err
 	}
@@ -285,6 +291,37 @@ func (r *Reconciler) resolveReply(ctx context.Context, subscription *v1.Subscrip
 	return nil
 }
 
+func (r *Reconciler) resolveReplyRoutes(ctx context.Context, subscription *v1.Subscription) pkgreconciler.Event {
+	ctx = apis.WithinParent(ctx, subscription.ObjectMeta)
+
+	var routes []duckv1.Addressable
+	for i := range subscription.Spec.ReplyRoutes {
+		destination := subscription.Spec.ReplyRoutes[i].Destination.DeepCopy()
+		// This is done in the webhook too, but we need it here for backwards
+		// compatibility for subscriptions with ref.namespace = "".
+		destination.SetDefaults(ctx)
+
+		routeAddr, err := r.destinationResolver.AddressableFromDestinationV1(ctx, *destination, subscription)
+		if err != nil {
+			logging.FromContext(ctx).Warnw("Failed to resolve reply route",
+				zap.Error(err),
+				zap.Int("index", i),
+				zap.Any("destination", destination))
+			subscription.Status.MarkReferencesNotResolved(replyRouteResolveFailed, "Failed to resolve spec.replyRoutes[%d].destination: %v", i, err)
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, replyRouteResolveFailed, "Failed to resolve spec.replyRoutes[%d].destination: %w", i, err)
+		}
+		routes = append(routes, duckv1.Addressable{
+			URL:      routeAddr.URL,
+			CACerts:  routeAddr.CACerts,
+			Audience: routeAddr.Audience,
+		})
+	}
+
+	logging.FromContext(ctx).Debugw("Resolved reply routes", zap.Any("replyRoutes", routes))
+	subscription.Status.PhysicalSubscription.ReplyRoutes = routes
+	return nil
+}
+
 func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, subscription *v1.Subscription, channel *eventingduckv1.Channelable) pkgreconciler.Event {
 	// resolve the Subscription's dls first, fall back to the Channels's
 	if subscription.Spec.Delivery != nil && subscription.Spec.Delivery.DeadLetterSink != nil {
@@ -532,6 +569,7 @@ func (r *Reconciler) updateChannelAddSubscriptionSpec(channel *eventingduckv1.Ch
 			channel.Spec.Subscribers[i].ReplyURI = sub.Status.PhysicalSubscription.ReplyURI
 			channel.Spec.Subscribers[i].ReplyCACerts = sub.Status.PhysicalSubscription.ReplyCACerts
 			channel.Spec.Subscribers[i].ReplyAudience = sub.Status.PhysicalSubscription.ReplyAudience
+			channel.Spec.Subscribers[i].ReplyRoutes = replyRoutes(sub)
 			channel.Spec.Subscribers[i].Delivery = deliverySpec(sub, channel)
 			channel.Spec.Subscribers[i].Auth = sub.Status.Auth
 			return
@@ -548,6 +586,7 @@ func (r *Reconciler) updateChannelAddSubscriptionSpec(channel *eventingduckv1.Ch
 		ReplyURI:           sub.Status.PhysicalSubscription.ReplyURI,
 		ReplyCACerts:       sub.Status.PhysicalSubscription.ReplyCACerts,
 		ReplyAudience:      sub.Status.PhysicalSubscription.ReplyAudience,
+		ReplyRoutes:        replyRoutes(sub),
 		Delivery:           deliverySpec(sub, channel),
 		Auth:               sub.Status.Auth,
 	}
@@ -556,6 +595,31 @@ func (r *Reconciler) updateChannelAddSubscriptionSpec(channel *eventingduckv1.Ch
 	channel.Spec.Subscribers = append(channel.Spec.Subscribers, toAdd)
 }
 
+// replyRoutes pairs the filters of the Subscription reply routes with their
+// resolved addresses.
+func replyRoutes(sub *v1.Subscription) []eventingduckv1.SubscriberReplyRoute {
+	resolved := sub.Status.PhysicalSubscription.ReplyRoutes
+	if len(sub.Spec.ReplyRoutes) == 0 || len(sub.Spec.ReplyRoutes) != len(resolved) {
+		return nil
+	}
+	routes := make([]eventingduckv1.SubscriberReplyRoute, 0, len(resolved))
+	for i, route := range sub.Spec.ReplyRoutes {
+		filters := make([]runtime.RawExtension, 0, len(route.Filters))
+		for _, f := range route.Filters {
+			// Filters only hold strings and maps, marshalling cannot fail.
+			raw, _ := json.Marshal(f)
+			filters = append(filters, runtime.RawExtension{Raw: raw})
+		}
+		routes = append(routes, eventingduckv1.SubscriberReplyRoute{
+			Filters:  filters,
+			URI:      resolved[i].URL,
+			CACerts:  resolved[i].CACerts,
+			Audience: resolved[i].Audience,
+		})
+	}
+	return routes
+}
+
 func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (delivery *eventingduckv1.DeliverySpec) {
 	if sub.Spec.Delivery == nil && channel.Spec.Delivery != nil {
 		// Default to the channel spec
@@ -566,9 +630,15 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 			}
 		}
 		if channel.Spec.Delivery.BackoffDelay != nil ||
+			channel.Spec.Delivery.BackoffMax != nil ||
 			channel.Spec.Delivery.Retry != nil ||
 			channel.Spec.Delivery.BackoffPolicy != nil ||
 			channel.Spec.Delivery.Timeout != nil ||
+			len(channel.Spec.Delivery.RetryOn) > 0 ||
+			len(channel.Spec.Delivery.NoRetryOn) > 0 ||
+			channel.Spec.Delivery.DeadLetterFormat != nil ||
+			channel.Spec.Delivery.MaxConcurrency != nil ||
+			channel.Spec.Delivery.RateLimit != nil ||
 			channel.Spec.Delivery.RetryAfterMax != nil {
 			if delivery == nil {
 				delivery = &eventingduckv1.DeliverySpec{}
@@ -576,8 +646,14 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 			delivery.BackoffPolicy = channel.Spec.Delivery.BackoffPolicy
 			delivery.Retry = channel.Spec.Delivery.Retry
 			delivery.BackoffDelay = channel.Spec.Delivery.BackoffDelay
+			delivery.BackoffMax = channel.Spec.Delivery.BackoffMax
 			delivery.Timeout = channel.Spec.Delivery.Timeout
 			delivery.RetryAfterMax = channel.Spec.Delivery.RetryAfterMax
+			delivery.RetryOn = channel.Spec.Delivery.RetryOn
+			delivery.NoRetryOn = channel.Spec.Delivery.NoRetryOn
+			delivery.DeadLetterFormat = channel.Spec.Delivery.DeadLetterFormat
+			delivery.MaxConcurrency = channel.Spec.Delivery.MaxConcurrency
+			delivery.RateLimit = channel.Spec.Delivery.RateLimit
 		}
 		return
 	}
@@ -592,9 +668,15 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 	}
 	if sub.Spec.Delivery != nil &&
 		(sub.Spec.Delivery.BackoffDelay != nil ||
+			sub.Spec.Delivery.BackoffMax != nil ||
 			sub.Spec.Delivery.Retry != nil ||
 			sub.Spec.Delivery.BackoffPolicy != nil ||
 			sub.Spec.Delivery.Timeout != nil ||
+			len(sub.Spec.Delivery.RetryOn) > 0 ||
+			len(sub.Spec.Delivery.NoRetryOn) > 0 ||
+			sub.Spec.Delivery.DeadLetterFormat != nil ||
+			sub.Spec.Delivery.MaxConcurrency != nil ||
+			sub.Spec.Delivery.RateLimit != nil ||
 			sub.Spec.Delivery.RetryAfterMax != nil) {
 		if delivery == nil {
 			delivery = &eventingduckv1.DeliverySpec{}
@@ -602,8 +684,14 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 		delivery.BackoffPolicy = sub.Spec.Delivery.BackoffPolicy
 		delivery.Retry = sub.Spec.Delivery.Retry
 		delivery.BackoffDelay = sub.Spec.Delivery.BackoffDelay
+		delivery.BackoffMax = sub.Spec.Delivery.BackoffMax
 		delivery.Timeout = sub.Spec.Delivery.Timeout
 		delivery.RetryAfterMax = sub.Spec.Delivery.RetryAfterMax
+		delivery.RetryOn = sub.Spec.Delivery.RetryOn
+		delivery.NoRetryOn = sub.Spec.Delivery.NoRetryOn
+		delivery.DeadLetterFormat = sub.Spec.Delivery.DeadLetterFormat
+		delivery.MaxConcurrency = sub.Spec.Delivery.MaxConcurrency
+		delivery.RateLimit = sub.Spec.Delivery.RateLimit
 	}
 	return
 }
@@ -49,6 +49,7 @@ import (
 	"knative.dev/pkg/tracker"
 
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	"knative.dev/eventing/pkg/apis/feature"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	"knative.dev/eventing/pkg/auth"
@@ -135,6 +136,20 @@ Vw==
 	}
 	replyURI = reply.URL
 
+	replyRoute = messagingv1.ReplyRoute{
+		Filters: []eventingv1.SubscriptionsAPIFilter{{
+			Exact: map[string]string{"type": "order.rejected"},
+		}},
+		Destination: duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: "messaging.knative.dev/v1",
+				Kind:       "InMemoryChannel",
+				Name:       replyName,
+				Namespace:  testNS,
+			},
+		},
+	}
+
 	serviceDNS = serviceName + "." + testNS + ".svc." + network.GetClusterDomainName()
 	serviceURI = apis.HTTP(serviceDNS)
 	service    = duckv1.Addressable{
@@ -1661,6 +1676,60 @@ func TestAllCases(t *testing.T) {
 				}),
 				patchFinalizers(testNS, subscriptionName),
 			},
+		}, {
+			Name: "v1 imc+subscriber+reply routes",
+			Objects: []runtime.Object{
+				NewSubscription(subscriptionName, testNS,
+					WithSubscriptionUID(subscriptionUID),
+					WithSubscriptionChannel(imcV1GVK, channelName),
+					WithSubscriptionSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithSubscriptionReplyRoutes(replyRoute),
+				),
+				NewUnstructured(subscriberGVK, subscriberName, testNS,
+					WithUnstructuredAddressable(subscriber),
+				),
+				NewInMemoryChannel(channelName, testNS,
+					WithInitInMemoryChannelConditions,
+					WithInMemoryChannelAddress(channelDNS),
+					WithInMemoryChannelReadySubscriber(subscriptionUID),
+				),
+				NewInMemoryChannel(replyName, testNS,
+					WithInitInMemoryChannelConditions,
+					WithInMemoryChannelAddress(reply),
+				),
+			},
+			Key:     testNS + "/" + subscriptionName,
+			WantErr: false,
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", subscriptionName),
+				Eventf(corev1.EventTypeNormal, "SubscriberSync", "Subscription was synchronized to channel %q", channelName),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewSubscription(subscriptionName, testNS,
+					WithSubscriptionUID(subscriptionUID),
+					WithSubscriptionChannel(imcV1GVK, channelName),
+					WithSubscriptionSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithSubscriptionReplyRoutes(replyRoute),
+					// The first reconciliation will initialize the status conditions.
+					WithInitSubscriptionConditions,
+					MarkReferencesResolved,
+					MarkAddedToChannel,
+					WithSubscriptionPhysicalSubscriptionSubscriber(&subscriber),
+					WithSubscriptionPhysicalSubscriptionReplyRoutes(reply),
+					WithSubscriptionOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
+				),
+			}},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchSubscribers(testNS, channelName, []eventingduck.SubscriberSpec{
+					{UID: subscriptionUID, SubscriberURI: subscriberURI, Name: pointer.String(subscriptionName),
+						ReplyRoutes: []eventingduck.SubscriberReplyRoute{{
+							Filters: []runtime.RawExtension{{Raw: []byte(`{"exact":{"type":"order.rejected"}}`)}},
+							URI:     replyURI,
+						}},
+					},
+				}),
+				patchFinalizers(testNS, subscriptionName),
+			},
 		}, {
 			Name: "v1 imc+valid remove reply",
 			Objects: []runtime.Object{
@@ -2041,6 +2110,7 @@ func TestAllCases(t *testing.T) {
 					WithInMemoryChannelDelivery(&eventingduck.DeliverySpec{
 						Timeout:       pointer.String("PT1S"),
 						RetryAfterMax: pointer.String("PT2S"),
+						BackoffMax:    pointer.String("PT1M"),
 					}),
 					WithInMemoryChannelStatusDLS(dlcStatus),
 				),
@@ -2073,6 +2143,7 @@ func TestAllCases(t *testing.T) {
 						Delivery: &eventingduck.DeliverySpec{
 							Timeout:       pointer.String("PT1S"),
 							RetryAfterMax: pointer.String("PT2S"),
+							BackoffMax:    pointer.String("PT1M"),
 						},
 						Name: pointer.String("a-" + subscriptionName),
 					},
@@ -2294,8 +2365,12 @@ func TestAllCases(t *testing.T) {
 					WithSubscriptionChannel(imcV1GVK, channelName),
 					WithSubscriptionSubscriberRef(serviceGVK, serviceName, testNS),
 					WithSubscriptionDeliverySpec(&eventingduck.DeliverySpec{
-						Timeout:       pointer.String("PT1S"),
-						RetryAfterMax: pointer.String("PT2S"),
+						Timeout:        pointer.String("PT1S"),
+						RetryAfterMax:  pointer.String("PT2S"),
+						RetryOn:        []string{"400"},
+						NoRetryOn:      []string{"503"},
+						MaxConcurrency: pointer.Int32(5),
+						RateLimit:      &eventingduck.RateLimitSpec{RequestsPerSecond: 10},
 					}),
 				),
 				NewUnstructured(subscriberGVK, dlsName, testNS,
@@ -2334,8 +2409,12 @@ func TestAllCases(t *testing.T) {
 					WithSubscriptionPhysicalSubscriptionSubscriber(&service),
 					WithSubscriptionOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled(),
 					WithSubscriptionDeliverySpec(&eventingduck.DeliverySpec{
-						Timeout:       pointer.String("PT1S"),
-						RetryAfterMax: pointer.String("PT2S"),
+						Timeout:        pointer.String("PT1S"),
+						RetryAfterMax:  pointer.String("PT2S"),
+						RetryOn:        []string{"400"},
+						NoRetryOn:      []string{"503"},
+						MaxConcurrency: pointer.Int32(5),
+						RateLimit:      &eventingduck.RateLimitSpec{RequestsPerSecond: 10},
 					}),
 				),
 			}},
@@ -2345,8 +2424,12 @@ func TestAllCases(t *testing.T) {
 						UID:           "a-" + subscriptionUID,
 						SubscriberURI: serviceURI,
 						Delivery: &eventingduck.DeliverySpec{
-							Timeout:       pointer.String("PT1S"),
-							RetryAfterMax: pointer.String("PT2S"),
+							Timeout:        pointer.String("PT1S"),
+							RetryAfterMax:  pointer.String("PT2S"),
+							RetryOn:        []string{"400"},
+							NoRetryOn:      []string{"503"},
+							MaxConcurrency: pointer.Int32(5),
+							RateLimit:      &eventingduck.RateLimitSpec{RequestsPerSecond: 10},
 						},
 						Name: pointer.String("a-" + subscriptionName),
 					},
@@ -26,9 +26,10 @@ import (
 )
 
 const (
-	prefix      = "triggers"
-	replySuffix = "reply"
-	dlsSuffix   = "dls"
+	prefix       = "triggers"
+	brokerPrefix = "brokers"
+	replySuffix  = "reply"
+	dlsSuffix    = "dls"
 )
 
 // Generate generates the Path portion of a URI to send events to the given Trigger.
@@ -44,6 +45,12 @@ func GenerateDLS(t *v1.Trigger) string {
 	return path.Join(Generate(t), dlsSuffix)
 }
 
+// GenerateBroker generates the Path portion of a URI to send events to every Trigger of the
+// given Broker at once.
+func GenerateBroker(b *v1.Broker) string {
+	return fmt.Sprintf("/%s/%s/%s", brokerPrefix, b.Namespace, b.Name)
+}
+
 type NamespacedNameUID struct {
 	types.NamespacedName
 	UID     types.UID
@@ -75,3 +82,31 @@ func Parse(path string) (NamespacedNameUID, error) {
 		IsDLS:   len(parts) == 6 && parts[5] == dlsSuffix,
 	}, nil
 }
+
+// IsBroker returns whether the Path portion of a URI refers to a Broker rather than to a Trigger.
+func IsBroker(path string) bool {
+	return strings.HasPrefix(path, "/"+brokerPrefix+"/")
+}
+
+// ParseBroker parses the Path portion of a URI to determine which Broker the request corresponds to.
+// It is expected to be in the form "/brokers/namespace/name".
+func ParseBroker(path string) (types.NamespacedName, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 {
+		return types.NamespacedName{}, fmt.Errorf("incorrect number of parts in the path, expected 4, actual %d, '%s'", len(parts), path)
+	}
+	if parts[0] != "" {
+		return types.NamespacedName{}, fmt.Errorf("text before the first slash, actual '%s'", path)
+	}
+	if parts[1] != brokerPrefix {
+		return types.NamespacedName{}, fmt.Errorf("incorrect prefix, expected '%s', actual '%s'", brokerPrefix, path)
+	}
+	if parts[2] == "" || parts[3] == "" {
+		return types.NamespacedName{}, fmt.Errorf("namespace and name can't be empty, actual '%s'", path)
+	}
+
+	return types.NamespacedName{
+		Namespace: parts[2],
+		Name:      parts[3],
+	}, nil
+}
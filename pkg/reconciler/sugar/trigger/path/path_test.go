@@ -81,3 +81,49 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBroker(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    types.NamespacedName
+		wantErr bool
+	}{
+		{
+			path: "/brokers/namespace/name",
+			want: types.NamespacedName{
+				Name:      "name",
+				Namespace: "namespace",
+			},
+		},
+		{
+			path:    "/brokers/namespace",
+			wantErr: true,
+		},
+		{
+			path:    "/brokers/namespace/name/extra",
+			wantErr: true,
+		},
+		{
+			path:    "/triggers/namespace/name",
+			wantErr: true,
+		},
+		{
+			path:    "/brokers//name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseBroker(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBroker() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("unexpected diff (-want, +got) =", diff)
+			}
+		})
+	}
+}
@@ -114,6 +114,19 @@ func WithApiServerSourceResourceModeEventTypes(source string) ApiServerSourceOpt
 	}
 }
 
+func WithApiServerSourceDiffModeEventTypes(source string) ApiServerSourceOption {
+	return func(s *v1.ApiServerSource) {
+		ceAttributes := make([]duckv1.CloudEventAttributes, 0, len(apisources.ApiServerSourceEventDiffModeTypes))
+		for _, apiServerSourceType := range apisources.ApiServerSourceEventDiffModeTypes {
+			ceAttributes = append(ceAttributes, duckv1.CloudEventAttributes{
+				Type:   apiServerSourceType,
+				Source: source,
+			})
+		}
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
 func WithApiServerSourceSufficientPermissions(s *v1.ApiServerSource) {
 	s.Status.MarkSufficientPermissions()
 }
@@ -169,6 +169,18 @@ func WithBrokerClass(bc string) BrokerOption {
 	}
 }
 
+// WithBrokerDispatchMode sets the dispatch mode annotation of the Broker.
+func WithBrokerDispatchMode(mode string) BrokerOption {
+	return func(b *v1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[v1.BrokerDispatchModeAnnotationKey] = mode
+		b.SetAnnotations(annotations)
+	}
+}
+
 func WithChannelAddressAnnotation(address string) BrokerOption {
 	return func(b *v1.Broker) {
 		if b.Status.Annotations == nil {
@@ -229,6 +241,33 @@ func WithChannelNameAnnotation(name string) BrokerOption {
 	}
 }
 
+func WithIngressRateLimitAnnotation(rateLimit string) BrokerOption {
+	return func(b *v1.Broker) {
+		if b.Status.Annotations == nil {
+			b.Status.Annotations = make(map[string]string, 1)
+		}
+		b.Status.Annotations[eventing.BrokerIngressRateLimitStatusAnnotationKey] = rateLimit
+	}
+}
+
+func WithIngressDeduplicationAnnotation(dedup string) BrokerOption {
+	return func(b *v1.Broker) {
+		if b.Status.Annotations == nil {
+			b.Status.Annotations = make(map[string]string, 1)
+		}
+		b.Status.Annotations[eventing.BrokerIngressDeduplicationStatusAnnotationKey] = dedup
+	}
+}
+
+func WithIngressSchedulingAnnotation(scheduling string) BrokerOption {
+	return func(b *v1.Broker) {
+		if b.Status.Annotations == nil {
+			b.Status.Annotations = make(map[string]string, 1)
+		}
+		b.Status.Annotations[eventing.BrokerIngressSchedulingStatusAnnotationKey] = scheduling
+	}
+}
+
 func WithChannelNamespaceAnnotation(namespace string) BrokerOption {
 	return func(b *v1.Broker) {
 		if b.Status.Annotations == nil {
@@ -226,6 +226,12 @@ func WithInMemoryChannelDelivery(d *eventingv1.DeliverySpec) InMemoryChannelOpti
 	}
 }
 
+func WithInMemoryChannelPersistence(p v1.InMemoryChannelPersistence) InMemoryChannelOption {
+	return func(imc *v1.InMemoryChannel) {
+		imc.Spec.Persistence = &p
+	}
+}
+
 func WithInMemoryChannelStatusSubscribers(subscriberStatuses []eventingv1.SubscriberStatus) InMemoryChannelOption {
 	return func(imc *v1.InMemoryChannel) {
 		imc.Status.Subscribers = subscriberStatuses
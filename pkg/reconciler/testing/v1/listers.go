@@ -126,6 +126,14 @@ func (l *Listers) GetJobSinkLister() sinkslisters.JobSinkLister {
 	return sinkslisters.NewJobSinkLister(l.indexerFor(&sinksv1alpha1.JobSink{}))
 }
 
+func (l *Listers) GetReplaySinkLister() sinkslisters.ReplaySinkLister {
+	return sinkslisters.NewReplaySinkLister(l.indexerFor(&sinksv1alpha1.ReplaySink{}))
+}
+
+func (l *Listers) GetAggregatorLister() sinkslisters.AggregatorLister {
+	return sinkslisters.NewAggregatorLister(l.indexerFor(&sinksv1alpha1.Aggregator{}))
+}
+
 func (l *Listers) GetPingSourceLister() sourcelisters.PingSourceLister {
 	return sourcelisters.NewPingSourceLister(l.indexerFor(&sourcesv1.PingSource{}))
 }
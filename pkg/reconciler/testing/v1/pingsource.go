@@ -138,3 +138,13 @@ func WithPingSourceOIDCServiceAccountName(name string) PingSourceOption {
 		c.Status.Auth.ServiceAccountName = &name
 	}
 }
+
+func WithPingSourceCompleted(c *v1.PingSource) {
+	c.Status.MarkCompleted()
+}
+
+func WithPingSourceNotCompleted(at string) PingSourceOption {
+	return func(c *v1.PingSource) {
+		c.Status.MarkNotCompleted("Scheduled", "The event is scheduled at %s", at)
+	}
+}
@@ -259,6 +259,12 @@ func WithSubscriptionPhysicalSubscriptionReply(reply *duckv1.Addressable) Subscr
 	}
 }
 
+func WithSubscriptionPhysicalSubscriptionReplyRoutes(routes ...duckv1.Addressable) SubscriptionOption {
+	return func(s *v1.Subscription) {
+		s.Status.PhysicalSubscription.ReplyRoutes = routes
+	}
+}
+
 func WithSubscriptionDeadLetterSink(dls *duckv1.Addressable) SubscriptionOption {
 	return func(s *v1.Subscription) {
 		if dls == nil {
@@ -319,6 +325,12 @@ func WithSubscriptionReply(gvk metav1.GroupVersionKind, name, namespace string)
 	}
 }
 
+func WithSubscriptionReplyRoutes(routes ...v1.ReplyRoute) SubscriptionOption {
+	return func(s *v1.Subscription) {
+		s.Spec.ReplyRoutes = routes
+	}
+}
+
 func WithSubscriptionOIDCIdentityCreatedSucceeded() SubscriptionOption {
 	return func(s *v1.Subscription) {
 		s.Status.MarkOIDCIdentityCreatedSucceeded()
@@ -116,6 +116,27 @@ func WithTriggerRetry(count int32, backoffPolicy *eventingv1.BackoffPolicyType,
 	}
 }
 
+// WithTriggerCircuitBreaker enables the circuit breaker of the Trigger subscriber.
+func WithTriggerCircuitBreaker() TriggerOption {
+	return func(t *v1.Trigger) {
+		if t.Spec.Delivery == nil {
+			t.Spec.Delivery = new(eventingv1.DeliverySpec)
+		}
+		t.Spec.Delivery.CircuitBreaker = &eventingv1.CircuitBreakerSpec{}
+	}
+}
+
+// WithTriggerPartitionKeyOrdering orders the delivery of the events of the Trigger by partition key.
+func WithTriggerPartitionKeyOrdering() TriggerOption {
+	return func(t *v1.Trigger) {
+		if t.Spec.Delivery == nil {
+			t.Spec.Delivery = new(eventingv1.DeliverySpec)
+		}
+		ordering := eventingv1.DeliveryOrderingPartitionKey
+		t.Spec.Delivery.Ordering = &ordering
+	}
+}
+
 func WithTriggerSubscriberRef(gvk metav1.GroupVersionKind, name, namespace string) TriggerOption {
 	return func(t *v1.Trigger) {
 		t.Spec.Subscriber = duckv1.Destination{
@@ -270,6 +291,18 @@ func WithDependencyAnnotation(dependencyAnnotation string) TriggerOption {
 	}
 }
 
+func WithTriggerSubscriberCircuitClosed() TriggerOption {
+	return func(t *v1.Trigger) {
+		t.Status.MarkSubscriberCircuitClosed()
+	}
+}
+
+func WithTriggerSubscriberCircuitOpen(reason, message string) TriggerOption {
+	return func(t *v1.Trigger) {
+		t.Status.MarkSubscriberCircuitOpen(reason, "%s", message)
+	}
+}
+
 func WithTriggerDependencyReady() TriggerOption {
 	return func(t *v1.Trigger) {
 		t.Status.MarkDependencySucceeded()
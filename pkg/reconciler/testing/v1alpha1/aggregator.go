@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+)
+
+// AggregatorOption enables further configuration of an Aggregator.
+type AggregatorOption func(*sinksv1alpha1.Aggregator)
+
+// NewAggregator creates an Aggregator with AggregatorOptions.
+func NewAggregator(name, namespace string, o ...AggregatorOption) *sinksv1alpha1.Aggregator {
+	a := &sinksv1alpha1.Aggregator{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+	for _, opt := range o {
+		opt(a)
+	}
+	a.SetDefaults(context.Background())
+	return a
+}
+
+// WithInitAggregatorConditions initializes the Aggregator's conditions.
+func WithInitAggregatorConditions(a *sinksv1alpha1.Aggregator) {
+	a.Status.InitializeConditions()
+}
+
+// WithAggregatorUID sets the Aggregator's UID.
+func WithAggregatorUID(uid string) AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.UID = types.UID(uid)
+	}
+}
+
+// WithAggregatorSpec sets the Aggregator's spec.
+func WithAggregatorSpec(spec sinksv1alpha1.AggregatorSpec) AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.Spec = spec
+	}
+}
+
+// WithAggregatorAddress sets the Aggregator's address.
+func WithAggregatorAddress(addr *duckv1.Addressable) AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.Status.SetAddress(addr)
+	}
+}
+
+// WithAggregatorSink sets the Aggregator's resolved sink.
+func WithAggregatorSink(addr *duckv1.Addressable) AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.Status.MarkSink(addr)
+	}
+}
+
+// WithAggregatorNoSink marks the Aggregator's sink as not found.
+func WithAggregatorNoSink(a *sinksv1alpha1.Aggregator) {
+	a.Status.MarkNoSink("NotFound", "")
+}
+
+// WithAggregatorEventPoliciesReadyBecauseOIDCDisabled sets the Aggregator's EventPoliciesReady condition to true with reason.
+func WithAggregatorEventPoliciesReadyBecauseOIDCDisabled() AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.Status.MarkEventPoliciesTrueWithReason("OIDCDisabled", "Feature %q must be enabled to support Authorization", feature.OIDCAuthentication)
+	}
+}
+
+// WithAggregatorEventPoliciesReadyBecauseNoPolicyAndOIDCEnabled sets the Aggregator's EventPoliciesReady condition to true with reason.
+func WithAggregatorEventPoliciesReadyBecauseNoPolicyAndOIDCEnabled() AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.Status.MarkEventPoliciesTrueWithReason("DefaultAuthorizationMode", "Default authz mode is %q", feature.AuthorizationAllowSameNamespace)
+	}
+}
+
+// WithAggregatorOIDCIdentityCreatedSucceeded marks the Aggregator's OIDC identity as created.
+func WithAggregatorOIDCIdentityCreatedSucceeded() AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.Status.MarkOIDCIdentityCreatedSucceeded()
+	}
+}
+
+// WithAggregatorOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled marks the Aggregator's OIDC identity
+// as not needed.
+func WithAggregatorOIDCIdentityCreatedSucceededBecauseOIDCFeatureDisabled() AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		a.Status.MarkOIDCIdentityCreatedSucceededWithReason(fmt.Sprintf("%s feature disabled", feature.OIDCAuthentication), "")
+	}
+}
+
+// WithAggregatorOIDCServiceAccountName sets the name of the Aggregator's OIDC service account.
+func WithAggregatorOIDCServiceAccountName(name string) AggregatorOption {
+	return func(a *sinksv1alpha1.Aggregator) {
+		if a.Status.Auth == nil {
+			a.Status.Auth = &duckv1.AuthStatus{}
+		}
+		a.Status.Auth.ServiceAccountName = &name
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+)
+
+// ReplaySinkOption enables further configuration of a ReplaySink.
+type ReplaySinkOption func(*sinksv1alpha1.ReplaySink)
+
+// NewReplaySink creates a ReplaySink with ReplaySinkOptions.
+func NewReplaySink(name, namespace string, o ...ReplaySinkOption) *sinksv1alpha1.ReplaySink {
+	rs := &sinksv1alpha1.ReplaySink{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+	for _, opt := range o {
+		opt(rs)
+	}
+	rs.SetDefaults(context.Background())
+	return rs
+}
+
+// WithInitReplaySinkConditions initializes the ReplaySink's conditions.
+func WithInitReplaySinkConditions(rs *sinksv1alpha1.ReplaySink) {
+	rs.Status.InitializeConditions()
+}
+
+// WithReplaySinkAddress sets the ReplaySink's address.
+func WithReplaySinkAddress(addr *duckv1.Addressable) ReplaySinkOption {
+	return func(rs *sinksv1alpha1.ReplaySink) {
+		rs.Status.SetAddress(addr)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/logging"
+
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+)
+
+const (
+	// readVerb is the verb on the ReplaySink allowing to list and inspect its events.
+	readVerb = "get"
+	// replayVerb is the verb on the ReplaySink allowing to replay its events.
+	replayVerb = "replay"
+)
+
+// authorize checks that the bearer token of the request authenticates a user allowed to perform
+// the verb on the ReplaySink, and otherwise responds to the request.
+func (h *Handler) authorize(ctx context.Context, w http.ResponseWriter, r *http.Request, ref types.NamespacedName, verb string) bool {
+	logger := logging.FromContext(ctx).Desugar()
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+
+	review, err := h.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error("Failed to review the token of the request", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+	if !review.Status.Authenticated {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+
+	user := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	access, err := h.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+				Verb:      verb,
+				Group:     sinksv1alpha1.SchemeGroupVersion.Group,
+				Resource:  "replaysinks",
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error("Failed to review the access of the request", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return false
+	}
+	if !access.Status.Allowed {
+		logger.Info("Request not allowed", zap.String("user", user.Username), zap.String("verb", verb), zap.String("ref", ref.String()))
+		w.WriteHeader(http.StatusForbidden)
+		return false
+	}
+	return true
+}
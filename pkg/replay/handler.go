@@ -0,0 +1,415 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/rickb777/date/period"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/resolver"
+
+	"knative.dev/eventing/pkg/apis/feature"
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+	sinkslister "knative.dev/eventing/pkg/client/listers/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/kncloudevents/attributes"
+)
+
+// knativeErrorExtensionsPrefix is the prefix of the extensions added to the dead-lettered events,
+// which are removed from the replayed events.
+const knativeErrorExtensionsPrefix = "knativeerror"
+
+// RecordSummary describes a record in the listings of the events of a ReplaySink.
+type RecordSummary struct {
+	ID          uint64     `json:"id"`
+	Time        time.Time  `json:"time"`
+	EventID     string     `json:"eventId"`
+	Type        string     `json:"type"`
+	Source      string     `json:"source"`
+	Destination string     `json:"destination,omitempty"`
+	ErrorCode   string     `json:"errorCode,omitempty"`
+	ReplayedAt  *time.Time `json:"replayedAt,omitempty"`
+}
+
+// ReplayRequest selects the events to replay, when they aren't selected with the filter of the
+// query parameters.
+type ReplayRequest struct {
+	// IDs are the IDs of the records to replay.
+	IDs []uint64 `json:"ids,omitempty"`
+	// Target is the Addressable the events are sent to, e.g. the Broker or Channel which
+	// dispatched them. It must be in the namespace of the ReplaySink. The original destination
+	// recorded in the knativeerrordest extension is set by the sender of the event, so it is
+	// only used to select the events and never to send them.
+	Target *duckv1.KReference `json:"target,omitempty"`
+}
+
+// ReplayResult is the outcome of replaying a record.
+type ReplayResult struct {
+	ID           uint64 `json:"id"`
+	Target       string `json:"target,omitempty"`
+	ResponseCode int    `json:"responseCode,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Handler receives the dead-lettered events of the ReplaySinks and serves the API to list,
+// inspect and replay them:
+//
+//	POST /<namespace>/<name>             stores the event
+//	GET  /<namespace>/<name>/events      lists the stored events
+//	GET  /<namespace>/<name>/events/<id> returns a stored event
+//	POST /<namespace>/<name>/replay      sends the selected events again
+//
+// The events are listed and replayed according to the type, source, destination, since, until
+// and replayed query parameters. The requests to the API are authenticated with the Kubernetes
+// bearer token of the caller, who must be allowed to get the ReplaySink to list and inspect its
+// events, and to replay the ReplaySink to replay them.
+type Handler struct {
+	store             *Store
+	lister            sinkslister.ReplaySinkLister
+	dispatcher        *kncloudevents.Dispatcher
+	kubeClient        kubernetes.Interface
+	uriResolver       *resolver.URIResolver
+	withContext       func(ctx context.Context) context.Context
+	oidcTokenVerifier *auth.OIDCTokenVerifier
+	now               func() time.Time
+}
+
+func NewHandler(store *Store, lister sinkslister.ReplaySinkLister, dispatcher *kncloudevents.Dispatcher, kubeClient kubernetes.Interface, uriResolver *resolver.URIResolver, oidcTokenVerifier *auth.OIDCTokenVerifier, withContext func(ctx context.Context) context.Context) *Handler {
+	return &Handler{
+		store:             store,
+		lister:            lister,
+		dispatcher:        dispatcher,
+		kubeClient:        kubeClient,
+		uriResolver:       uriResolver,
+		withContext:       withContext,
+		oidcTokenVerifier: oidcTokenVerifier,
+		now:               time.Now,
+	}
+}
+
+// healthzPath is the path of the endpoint probed by the kubelet.
+const healthzPath = "/healthz"
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := h.withContext(r.Context())
+	logger := logging.FromContext(ctx).Desugar()
+
+	if r.URL.Path == healthzPath {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || len(parts) > 4 {
+		logger.Info("Malformed uri", zap.String("URI", r.RequestURI))
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	ref := types.NamespacedName{
+		Namespace: parts[0],
+		Name:      parts[1],
+	}
+
+	rs, err := h.lister.ReplaySinks(ref.Namespace).Get(ref.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		logger.Warn("Failed to retrieve the replaysink", zap.String("ref", ref.String()), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		if feature.FromContext(ctx).IsOIDCAuthentication() {
+			audience := auth.GetAudienceDirect(sinksv1alpha1.SchemeGroupVersion.WithKind("ReplaySink"), ref.Namespace, ref.Name)
+			if err := h.oidcTokenVerifier.VerifyJWTFromRequest(ctx, r, &audience, w); err != nil {
+				logger.Warn("Error when validating the JWT token in the request", zap.Error(err))
+				return
+			}
+		}
+		h.receive(ctx, w, r, ref)
+	case len(parts) == 3 && parts[2] == "events" && r.Method == http.MethodGet:
+		if h.authorize(ctx, w, r, ref, readVerb) {
+			h.list(ctx, w, r, ref)
+		}
+	case len(parts) == 4 && parts[2] == "events" && r.Method == http.MethodGet:
+		if h.authorize(ctx, w, r, ref, readVerb) {
+			h.get(ctx, w, ref, parts[3])
+		}
+	case len(parts) == 3 && parts[2] == "replay" && r.Method == http.MethodPost:
+		if h.authorize(ctx, w, r, ref, replayVerb) {
+			h.replay(ctx, w, r, rs)
+		}
+	case len(parts) == 2, len(parts) == 3 && (parts[2] == "events" || parts[2] == "replay"), len(parts) == 4 && parts[2] == "events":
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (h *Handler) receive(ctx context.Context, w http.ResponseWriter, r *http.Request, ref types.NamespacedName) {
+	logger := logging.FromContext(ctx).Desugar()
+
+	message := cehttp.NewMessageFromHttpRequest(r)
+	defer message.Finish(nil)
+
+	e, err := binding.ToEvent(ctx, message)
+	if err != nil {
+		logger.Warn("failed to extract event from request", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := e.Validate(); err != nil {
+		logger.Info("failed to validate event from request", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	record, err := h.store.Append(ref, *e, h.now())
+	if err != nil {
+		logger.Error("Failed to store the event", zap.String("ref", ref.String()), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/%s/%s/events/%d", ref.Namespace, ref.Name, record.ID))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) list(ctx context.Context, w http.ResponseWriter, r *http.Request, ref types.NamespacedName) {
+	filter, err := filterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summaries := []RecordSummary{}
+	for _, record := range h.store.List(ref, filter) {
+		summaries = append(summaries, summarize(record))
+	}
+	writeJSON(ctx, w, summaries)
+}
+
+func (h *Handler) get(ctx context.Context, w http.ResponseWriter, ref types.NamespacedName, rawID string) {
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid id %q", rawID), http.StatusBadRequest)
+		return
+	}
+	record, ok := h.store.Get(ref, id)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(ctx, w, record)
+}
+
+func (h *Handler) replay(ctx context.Context, w http.ResponseWriter, r *http.Request, rs *sinksv1alpha1.ReplaySink) {
+	logger := logging.FromContext(ctx).Desugar()
+	ref := types.NamespacedName{Namespace: rs.Namespace, Name: rs.Name}
+
+	filter, err := filterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req ReplayRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid replay request: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if len(req.IDs) == 0 && filter.IsZero() {
+		http.Error(w, "either ids or a filter are required", http.StatusBadRequest)
+		return
+	}
+	if req.Target == nil {
+		http.Error(w, "a target is required", http.StatusBadRequest)
+		return
+	}
+	// The events are only sent to the Addressables of the namespace of the ReplaySink.
+	if req.Target.Namespace != "" && req.Target.Namespace != rs.Namespace {
+		http.Error(w, fmt.Sprintf("the target must be in the namespace %s", rs.Namespace), http.StatusBadRequest)
+		return
+	}
+	targetRef := req.Target.DeepCopy()
+	targetRef.Namespace = rs.Namespace
+	target, err := h.uriResolver.AddressableFromDestinationV1(ctx, duckv1.Destination{Ref: targetRef}, rs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid target: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var records []Record
+	if len(req.IDs) > 0 {
+		for _, id := range req.IDs {
+			record, ok := h.store.Get(ref, id)
+			if !ok {
+				http.Error(w, fmt.Sprintf("record %d not found", id), http.StatusNotFound)
+				return
+			}
+			if filter.matches(&record) {
+				records = append(records, record)
+			}
+		}
+	} else {
+		records = h.store.List(ref, filter)
+	}
+
+	results := make([]ReplayResult, 0, len(records))
+	for _, record := range records {
+		result := h.replayRecord(ctx, record, *target)
+		if result.Error == "" {
+			if err := h.store.MarkReplayed(ref, record.ID, h.now()); err != nil {
+				logger.Warn("Failed to mark the event as replayed", zap.String("ref", ref.String()), zap.Uint64("id", record.ID), zap.Error(err))
+			}
+		}
+		results = append(results, result)
+	}
+	writeJSON(ctx, w, results)
+}
+
+// replayRecord sends the event of the record to the target.
+func (h *Handler) replayRecord(ctx context.Context, record Record, target duckv1.Addressable) ReplayResult {
+	result := ReplayResult{ID: record.ID, Target: target.URL.String()}
+
+	e := record.Event.Clone()
+	for name := range e.Extensions() {
+		if strings.HasPrefix(name, knativeErrorExtensionsPrefix) {
+			e.SetExtension(name, nil)
+		}
+	}
+
+	info, err := h.dispatcher.SendEvent(ctx, e, target)
+	if info != nil && info.ResponseCode > 0 {
+		result.ResponseCode = info.ResponseCode
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Compact drops the events of the ReplaySinks older than their retention, and the events of the
+// ReplaySinks which were deleted.
+func (h *Handler) Compact(ctx context.Context) {
+	logger := logging.FromContext(ctx).Desugar()
+
+	now := h.now()
+	for _, ref := range h.store.Refs() {
+		rs, err := h.lister.ReplaySinks(ref.Namespace).Get(ref.Name)
+		if apierrors.IsNotFound(err) {
+			if err := h.store.Delete(ref); err != nil {
+				logger.Warn("Failed to delete the events of the replaysink", zap.String("ref", ref.String()), zap.Error(err))
+			}
+			continue
+		}
+		if err != nil {
+			logger.Warn("Failed to retrieve the replaysink", zap.String("ref", ref.String()), zap.Error(err))
+			continue
+		}
+
+		retention := sinksv1alpha1.DefaultReplaySinkRetention
+		if rs.Spec.Retention != nil {
+			retention = *rs.Spec.Retention
+		}
+		p, err := period.Parse(retention)
+		if err != nil {
+			logger.Warn("Invalid retention of the replaysink", zap.String("ref", ref.String()), zap.Error(err))
+			continue
+		}
+		if err := h.store.Compact(ref, now.Add(-p.DurationApprox())); err != nil {
+			logger.Warn("Failed to compact the events of the replaysink", zap.String("ref", ref.String()), zap.Error(err))
+		}
+	}
+}
+
+func filterFromQuery(r *http.Request) (Filter, error) {
+	q := r.URL.Query()
+	f := Filter{
+		Type:        q.Get("type"),
+		Source:      q.Get("source"),
+		Destination: q.Get("destination"),
+	}
+	var err error
+	if v := q.Get("since"); v != "" {
+		if f.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return Filter{}, fmt.Errorf("invalid since %q: %w", v, err)
+		}
+	}
+	if v := q.Get("until"); v != "" {
+		if f.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return Filter{}, fmt.Errorf("invalid until %q: %w", v, err)
+		}
+	}
+	if v := q.Get("replayed"); v != "" {
+		replayed, err := strconv.ParseBool(v)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid replayed %q: %w", v, err)
+		}
+		f.Replayed = &replayed
+	}
+	return f, nil
+}
+
+func summarize(record Record) RecordSummary {
+	s := RecordSummary{
+		ID:          record.ID,
+		Time:        record.Time,
+		EventID:     record.Event.ID(),
+		Type:        record.Event.Type(),
+		Source:      record.Event.Source(),
+		Destination: record.Destination(),
+		ReplayedAt:  record.ReplayedAt,
+	}
+	if v, ok := record.Event.Extensions()[attributes.KnativeErrorCodeExtensionKey]; ok {
+		s.ErrorCode, _ = cetypes.Format(v)
+	}
+	return s
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to marshal the response", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
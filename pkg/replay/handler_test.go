@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	"github.com/google/go-cmp/cmp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/injection"
+	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/tracker"
+
+	sinksv1alpha1 "knative.dev/eventing/pkg/apis/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/auth"
+	sinkslister "knative.dev/eventing/pkg/client/listers/sinks/v1alpha1"
+	"knative.dev/eventing/pkg/eventingtls"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/kncloudevents/attributes"
+)
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	ctx, kubeClient := fakekubeclient.With(ctx)
+	ctx = injection.WithConfig(ctx, &rest.Config{})
+
+	received := make(chan event.Event, 10)
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Error("failed to read the replayed event:", err)
+		} else {
+			received <- *e
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer destination.Close()
+
+	// The token "admin" authenticates a user allowed to get and replay the replaysinks, and the
+	// token "viewer" a user only allowed to get them.
+	kubeClient.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		review := action.(clientgotesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch review.Spec.Token {
+		case "admin", "viewer":
+			review.Status.Authenticated = true
+			review.Status.User.Username = review.Spec.Token
+		}
+		return true, review, nil
+	})
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		review := action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = attrs.Namespace == "ns" && attrs.Resource == "replaysinks" &&
+			(review.Spec.User == "admin" || review.Spec.User == "viewer" && attrs.Verb == "get")
+		return true, review, nil
+	})
+
+	broker := func(namespace string) runtime.Object {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "eventing.knative.dev/v1",
+			"kind":       "Broker",
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      "default",
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"url": destination.URL + "/" + namespace,
+				},
+			},
+		}}
+	}
+	ctx, _ = fakedynamicclient.With(ctx, runtime.NewScheme(), broker("ns"), broker("other"))
+	ctx = addressable.WithDuck(ctx)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = indexer.Add(&sinksv1alpha1.ReplaySink{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "replay"},
+		Spec:       sinksv1alpha1.ReplaySinkSpec{Retention: ptr.To("PT1H")},
+	})
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal("NewStore() =", err)
+	}
+	defer store.Close()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewHandler(store, sinkslister.NewReplaySinkLister(indexer),
+		kncloudevents.NewDispatcher(eventingtls.NewDefaultClientConfig(), auth.NewOIDCTokenProvider(ctx)),
+		kubeClient, resolver.NewURIResolverFromTracker(ctx, tracker.New(func(types.NamespacedName) {}, 0)),
+		nil, func(ctx context.Context) context.Context { return ctx })
+	h.now = func() time.Time { return now }
+
+	do := func(method, target string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodGet, "/healthz", nil, nil); w.Code != http.StatusOK {
+		t.Errorf("expected the health endpoint to be ok, got %d", w.Code)
+	}
+
+	// Dead-lettered events are stored.
+	for _, typ := range []string{"a", "b"} {
+		e := cetest.FullEvent()
+		e.SetType(typ)
+		e.SetExtension(attributes.KnativeErrorDestExtensionKey, destination.URL)
+		e.SetExtension(attributes.KnativeErrorCodeExtensionKey, 500)
+		b, _ := json.Marshal(e)
+		w := do(http.MethodPost, "/ns/replay", b, http.Header{"Content-Type": []string{event.ApplicationCloudEventsJSON}})
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("expected the event to be accepted, got %d", w.Code)
+		}
+	}
+	if w := do(http.MethodPost, "/ns/unknown", nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected the unknown replaysink to be not found, got %d", w.Code)
+	}
+
+	// The API requires an authorized user.
+	admin := http.Header{"Authorization": []string{"Bearer admin"}}
+	viewer := http.Header{"Authorization": []string{"Bearer viewer"}}
+	if w := do(http.MethodGet, "/ns/replay/events", nil, nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected the listing without token to be unauthorized, got %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/ns/replay/events", nil, http.Header{"Authorization": []string{"Bearer unknown"}}); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected the listing with an unknown token to be unauthorized, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/ns/replay/replay", nil, viewer); w.Code != http.StatusForbidden {
+		t.Errorf("expected the replay of the viewer to be forbidden, got %d", w.Code)
+	}
+
+	// They are listed and inspected.
+	w := do(http.MethodGet, "/ns/replay/events?type=b", nil, viewer)
+	var summaries []RecordSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &summaries); err != nil {
+		t.Fatal("failed to read the listing:", err)
+	}
+	wantSummaries := []RecordSummary{{
+		ID:          1,
+		Time:        now,
+		EventID:     cetest.FullEvent().ID(),
+		Type:        "b",
+		Source:      cetest.FullEvent().Source(),
+		Destination: destination.URL,
+		ErrorCode:   "500",
+	}}
+	if diff := cmp.Diff(wantSummaries, summaries); diff != "" {
+		t.Error("unexpected listing (-want, +got) =", diff)
+	}
+	w = do(http.MethodGet, "/ns/replay/events/0", nil, viewer)
+	var record Record
+	if err := json.Unmarshal(w.Body.Bytes(), &record); err != nil {
+		t.Fatal("failed to read the record:", err)
+	}
+	if record.ID != 0 || record.Event.Type() != "a" {
+		t.Errorf("unexpected record %+v", record)
+	}
+	if w := do(http.MethodGet, "/ns/replay/events/7", nil, viewer); w.Code != http.StatusNotFound {
+		t.Errorf("expected the unknown record to be not found, got %d", w.Code)
+	}
+
+	// Replaying requires a selection.
+	if w := do(http.MethodPost, "/ns/replay/replay", nil, admin); w.Code != http.StatusBadRequest {
+		t.Errorf("expected the replay without selection to be rejected, got %d", w.Code)
+	}
+
+	// The events aren't sent to the destination set by their sender.
+	body, _ := json.Marshal(ReplayRequest{IDs: []uint64{1}})
+	if w := do(http.MethodPost, "/ns/replay/replay", body, admin); w.Code != http.StatusBadRequest {
+		t.Errorf("expected the replay without target to be rejected, got %d", w.Code)
+	}
+
+	// The selected events are sent again to the target, without the error extensions.
+	body, _ = json.Marshal(ReplayRequest{IDs: []uint64{1}, Target: &duckv1.KReference{APIVersion: "eventing.knative.dev/v1", Kind: "Broker", Name: "default"}})
+	w = do(http.MethodPost, "/ns/replay/replay", body, admin)
+	var results []ReplayResult
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+		t.Fatal("failed to read the replay results:", err)
+	}
+	wantResults := []ReplayResult{{ID: 1, Target: destination.URL + "/ns", ResponseCode: http.StatusAccepted}}
+	if diff := cmp.Diff(wantResults, results); diff != "" {
+		t.Error("unexpected replay results (-want, +got) =", diff)
+	}
+	replayed := <-received
+	if replayed.Type() != "b" {
+		t.Errorf("expected the event b to be replayed, got %s", replayed.Type())
+	}
+	for name := range replayed.Extensions() {
+		if name == attributes.KnativeErrorDestExtensionKey || name == attributes.KnativeErrorCodeExtensionKey {
+			t.Errorf("unexpected extension %s on the replayed event", name)
+		}
+	}
+
+	// The events are sent to the Addressables of the namespace of the replaysink only.
+	body, _ = json.Marshal(ReplayRequest{IDs: []uint64{1}, Target: &duckv1.KReference{APIVersion: "eventing.knative.dev/v1", Kind: "Broker", Namespace: "other", Name: "default"}})
+	if w := do(http.MethodPost, "/ns/replay/replay", body, admin); w.Code != http.StatusBadRequest {
+		t.Errorf("expected the replay to another namespace to be rejected, got %d", w.Code)
+	}
+
+	// The events replayed are tracked.
+	w = do(http.MethodGet, "/ns/replay/events?replayed=false", nil, viewer)
+	summaries = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &summaries); err != nil {
+		t.Fatal("failed to read the listing:", err)
+	}
+	if len(summaries) != 1 || summaries[0].ID != 0 {
+		t.Errorf("expected only the event 0 not to be replayed, got %+v", summaries)
+	}
+
+	// The events older than the retention are dropped.
+	h.now = func() time.Time { return now.Add(2 * time.Hour) }
+	h.Compact(ctx)
+	if got := store.List(types.NamespacedName{Namespace: "ns", Name: "replay"}, Filter{}); len(got) != 0 {
+		t.Errorf("expected the events to be dropped, got %d", len(got))
+	}
+}
@@ -0,0 +1,431 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replay provides the storage and the HTTP API of the ReplaySinks, which keep the
+// dead-lettered events so that they can be sent again to their destination.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing/pkg/kncloudevents/attributes"
+)
+
+const (
+	logFileSuffix = ".log"
+
+	// defaultMaxRecords is the maximum number of events stored per ReplaySink, beyond which the
+	// oldest events are dropped.
+	defaultMaxRecords = 100000
+)
+
+// Record is an event stored by a ReplaySink.
+type Record struct {
+	// ID identifies the record within its ReplaySink.
+	ID uint64 `json:"id"`
+	// Time is when the event was received.
+	Time time.Time `json:"time"`
+	// Event is the dead-lettered event.
+	Event event.Event `json:"event"`
+	// ReplayedAt is when the event was last replayed, if it ever was.
+	ReplayedAt *time.Time `json:"replayedAt,omitempty"`
+}
+
+// Destination returns the original destination of the event, as recorded in its
+// knativeerrordest extension, or the empty string.
+func (r *Record) Destination() string {
+	v, ok := r.Event.Extensions()[attributes.KnativeErrorDestExtensionKey]
+	if !ok {
+		return ""
+	}
+	dest, _ := cetypes.Format(v)
+	return dest
+}
+
+// Filter selects the records of a ReplaySink. The zero value selects every record.
+type Filter struct {
+	Type        string
+	Source      string
+	Destination string
+	Since       time.Time
+	Until       time.Time
+	Replayed    *bool
+}
+
+// IsZero returns whether the filter selects every record.
+func (f Filter) IsZero() bool {
+	return f.Type == "" && f.Source == "" && f.Destination == "" && f.Since.IsZero() && f.Until.IsZero() && f.Replayed == nil
+}
+
+func (f Filter) matches(r *Record) bool {
+	if f.Type != "" && r.Event.Type() != f.Type {
+		return false
+	}
+	if f.Source != "" && r.Event.Source() != f.Source {
+		return false
+	}
+	if f.Destination != "" && r.Destination() != f.Destination {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !r.Time.Before(f.Until) {
+		return false
+	}
+	if f.Replayed != nil && (r.ReplayedAt != nil) != *f.Replayed {
+		return false
+	}
+	return true
+}
+
+// entry is a line of the log of a ReplaySink. It either stores an event or records that the
+// event with the given ID was replayed.
+type entry struct {
+	ID       uint64       `json:"id"`
+	Time     time.Time    `json:"time"`
+	Event    *event.Event `json:"event,omitempty"`
+	Replayed bool         `json:"replayed,omitempty"`
+}
+
+// Store durably stores the events of the ReplaySinks, in an append-only log file per ReplaySink
+// under its directory, which is expected to be backed by a PersistentVolume. The events are kept
+// until they are compacted, or until their ReplaySink stores too many of them.
+type Store struct {
+	dir        string
+	maxRecords int
+
+	mu   sync.Mutex
+	logs map[types.NamespacedName]*sinkLog
+}
+
+type sinkLog struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	records []*Record
+	nextID  uint64
+}
+
+// NewStore returns the Store keeping its logs under the given directory, loading the existing ones.
+func NewStore(dir string) (*Store, error) {
+	s := &Store{
+		dir:        dir,
+		maxRecords: defaultMaxRecords,
+		logs:       make(map[types.NamespacedName]*sinkLog),
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*"+logFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		ref := types.NamespacedName{
+			Namespace: filepath.Base(filepath.Dir(path)),
+			Name:      strings.TrimSuffix(filepath.Base(path), logFileSuffix),
+		}
+		l, err := openLog(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the log of %s: %w", ref, err)
+		}
+		s.logs[ref] = l
+	}
+	return s, nil
+}
+
+// Append stores the event received by the ReplaySink.
+func (s *Store) Append(ref types.NamespacedName, e event.Event, now time.Time) (*Record, error) {
+	l, err := s.log(ref, true)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := &Record{ID: l.nextID, Time: now.UTC(), Event: e}
+	if err := l.write(entry{ID: r.ID, Time: r.Time, Event: &r.Event}); err != nil {
+		return nil, err
+	}
+	l.nextID++
+	l.records = append(l.records, r)
+
+	if len(l.records) > s.maxRecords {
+		// Drop a tenth of the records at once, rather than rewriting the log on every event.
+		keep := s.maxRecords - s.maxRecords/10
+		if err := l.rewrite(l.records[len(l.records)-keep:]); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// List returns the records of the ReplaySink selected by the filter, oldest first.
+func (s *Store) List(ref types.NamespacedName, f Filter) []Record {
+	l, _ := s.log(ref, false)
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var records []Record
+	for _, r := range l.records {
+		if f.matches(r) {
+			records = append(records, *r)
+		}
+	}
+	return records
+}
+
+// Get returns the record of the ReplaySink with the given ID.
+func (s *Store) Get(ref types.NamespacedName, id uint64) (Record, bool) {
+	l, _ := s.log(ref, false)
+	if l == nil {
+		return Record{}, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r := l.find(id); r != nil {
+		return *r, true
+	}
+	return Record{}, false
+}
+
+// MarkReplayed records that the event with the given ID was replayed.
+func (s *Store) MarkReplayed(ref types.NamespacedName, id uint64, now time.Time) error {
+	l, _ := s.log(ref, false)
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.find(id)
+	if r == nil {
+		return nil
+	}
+	now = now.UTC()
+	if err := l.write(entry{ID: id, Time: now, Replayed: true}); err != nil {
+		return err
+	}
+	r.ReplayedAt = &now
+	return nil
+}
+
+// Compact drops the records of the ReplaySink received before the given time.
+func (s *Store) Compact(ref types.NamespacedName, before time.Time) error {
+	l, _ := s.log(ref, false)
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	i := sort.Search(len(l.records), func(i int) bool {
+		return !l.records[i].Time.Before(before)
+	})
+	if i == 0 {
+		return nil
+	}
+	return l.rewrite(l.records[i:])
+}
+
+// Delete drops the log of the ReplaySink.
+func (s *Store) Delete(ref types.NamespacedName) error {
+	s.mu.Lock()
+	l, ok := s.logs[ref]
+	delete(s.logs, ref)
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.file.Close()
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Refs returns the ReplaySinks having a log.
+func (s *Store) Refs() []types.NamespacedName {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refs := make([]types.NamespacedName, 0, len(s.logs))
+	for ref := range s.logs {
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// Close closes the log files.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for _, l := range s.logs {
+		l.mu.Lock()
+		if cerr := l.file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		l.mu.Unlock()
+	}
+	return err
+}
+
+func (s *Store) log(ref types.NamespacedName, create bool) (*sinkLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.logs[ref]; ok || !create {
+		return l, nil
+	}
+	if err := os.MkdirAll(filepath.Join(s.dir, ref.Namespace), 0o700); err != nil {
+		return nil, err
+	}
+	l, err := openLog(filepath.Join(s.dir, ref.Namespace, ref.Name+logFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	s.logs[ref] = l
+	return l, nil
+}
+
+// openLog opens the log file at the given path, creating it if needed, and loads its records.
+func openLog(path string) (*sinkLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l := &sinkLog{path: path, file: f}
+
+	byID := make(map[uint64]*Record)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var e entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// A partially written entry, the write failed.
+			continue
+		}
+		if e.Replayed {
+			if r, ok := byID[e.ID]; ok {
+				t := e.Time
+				r.ReplayedAt = &t
+			}
+			continue
+		}
+		if e.Event == nil {
+			continue
+		}
+		r := &Record{ID: e.ID, Time: e.Time, Event: *e.Event}
+		byID[r.ID] = r
+		l.records = append(l.records, r)
+		if r.ID >= l.nextID {
+			l.nextID = r.ID + 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *sinkLog) find(id uint64) *Record {
+	i := sort.Search(len(l.records), func(i int) bool {
+		return l.records[i].ID >= id
+	})
+	if i < len(l.records) && l.records[i].ID == id {
+		return l.records[i]
+	}
+	return nil
+}
+
+// write appends the entry to the log file, and syncs it to the disk.
+func (l *sinkLog) write(e entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// rewrite replaces the log file with one holding the given records only.
+func (l *sinkLog) rewrite(records []*Record) error {
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(entry{ID: r.ID, Time: r.Time, Event: &r.Event}); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		if r.ReplayedAt != nil {
+			if err := enc.Encode(entry{ID: r.ID, Time: *r.ReplayedAt, Replayed: true}); err != nil {
+				_ = tmp.Close()
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	_ = l.file.Close()
+	l.file = f
+	l.records = append([]*Record(nil), records...)
+	return nil
+}
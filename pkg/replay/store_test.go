@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	ref := types.NamespacedName{Namespace: "ns", Name: "replay"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatal("NewStore() =", err)
+	}
+	for i, typ := range []string{"a", "b", "a"} {
+		e := cetest.FullEvent()
+		e.SetType(typ)
+		if _, err := s.Append(ref, e, now.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+	if err := s.MarkReplayed(ref, 1, now.Add(3*time.Hour)); err != nil {
+		t.Fatal("MarkReplayed() =", err)
+	}
+
+	ids := func(records []Record) []uint64 {
+		var ids []uint64
+		for _, r := range records {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+	filters := map[string]struct {
+		filter Filter
+		want   []uint64
+	}{
+		"all":          {want: []uint64{0, 1, 2}},
+		"type":         {filter: Filter{Type: "a"}, want: []uint64{0, 2}},
+		"since":        {filter: Filter{Since: now.Add(time.Hour)}, want: []uint64{1, 2}},
+		"until":        {filter: Filter{Until: now.Add(time.Hour)}, want: []uint64{0}},
+		"replayed":     {filter: Filter{Replayed: ptr.To(true)}, want: []uint64{1}},
+		"not replayed": {filter: Filter{Replayed: ptr.To(false)}, want: []uint64{0, 2}},
+		"other source": {filter: Filter{Source: "other"}},
+	}
+	for name, tc := range filters {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ids(s.List(ref, tc.filter))); diff != "" {
+				t.Error("unexpected records (-want, +got) =", diff)
+			}
+		})
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+
+	// The records are loaded back from the disk.
+	s, err = NewStore(dir)
+	if err != nil {
+		t.Fatal("NewStore() =", err)
+	}
+	defer s.Close()
+	if diff := cmp.Diff([]types.NamespacedName{ref}, s.Refs()); diff != "" {
+		t.Error("unexpected refs (-want, +got) =", diff)
+	}
+	r, ok := s.Get(ref, 1)
+	if !ok {
+		t.Fatal("expected the record 1 to be loaded")
+	}
+	if r.Event.Type() != "b" || r.ReplayedAt == nil || !r.ReplayedAt.Equal(now.Add(3*time.Hour)) {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if _, err := s.Append(ref, cetest.FullEvent(), now.Add(4*time.Hour)); err != nil {
+		t.Fatal("Append() =", err)
+	}
+
+	if err := s.Compact(ref, now.Add(90*time.Minute)); err != nil {
+		t.Fatal("Compact() =", err)
+	}
+	if diff := cmp.Diff([]uint64{2, 3}, ids(s.List(ref, Filter{}))); diff != "" {
+		t.Error("unexpected records after compaction (-want, +got) =", diff)
+	}
+	if _, err := s.Append(ref, cetest.FullEvent(), now.Add(5*time.Hour)); err != nil {
+		t.Fatal("Append() after compaction =", err)
+	}
+	reloaded, err := NewStore(dir)
+	if err != nil {
+		t.Fatal("NewStore() =", err)
+	}
+	if diff := cmp.Diff([]uint64{2, 3, 4}, ids(reloaded.List(ref, Filter{}))); diff != "" {
+		t.Error("unexpected reloaded records (-want, +got) =", diff)
+	}
+	_ = reloaded.Close()
+
+	if err := s.Delete(ref); err != nil {
+		t.Fatal("Delete() =", err)
+	}
+	if got := s.List(ref, Filter{}); len(got) != 0 {
+		t.Errorf("expected no records after deletion, got %d", len(got))
+	}
+	if s, err = NewStore(dir); err != nil || len(s.Refs()) != 0 {
+		t.Errorf("expected no log after deletion, got %v (%v)", s.Refs(), err)
+	}
+}
+
+func TestStoreMaxRecords(t *testing.T) {
+	dir := t.TempDir()
+	ref := types.NamespacedName{Namespace: "ns", Name: "replay"}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatal("NewStore() =", err)
+	}
+	defer s.Close()
+	s.maxRecords = 20
+
+	for i := 0; i < 21; i++ {
+		if _, err := s.Append(ref, cetest.FullEvent(), now); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+
+	// The oldest records are dropped, from the disk too.
+	records := s.List(ref, Filter{})
+	if len(records) != 18 || records[0].ID != 3 {
+		t.Errorf("expected the records 3 to 20, got %d records from %d", len(records), records[0].ID)
+	}
+	reloaded, err := NewStore(dir)
+	if err != nil {
+		t.Fatal("NewStore() =", err)
+	}
+	defer reloaded.Close()
+	if got := len(reloaded.List(ref, Filter{})); got != 18 {
+		t.Errorf("expected 18 reloaded records, got %d", got)
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+)
+
+// OnceSchedule is a cron.Schedule activating a single time, at the given time.
+type OnceSchedule time.Time
+
+// Next returns the time of the activation until it is reached, and the zero time afterwards so
+// that the cron never activates it again. The cron only activates it when it is added before it.
+func (s OnceSchedule) Next(t time.Time) time.Time {
+	if at := time.Time(s); t.Before(at) {
+		return at
+	}
+	return time.Time{}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var _ cron.Schedule = OnceSchedule{}
+
+func TestOnceSchedule(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	schedule := OnceSchedule(at)
+
+	if got := schedule.Next(at.Add(-time.Hour)); !got.Equal(at) {
+		t.Errorf("Expected the next activation at %s, got %s", at, got)
+	}
+	if got := schedule.Next(at); !got.IsZero() {
+		t.Errorf("Expected no next activation, got %s", got)
+	}
+}